@@ -5,6 +5,7 @@
 package client
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"crypto/ed25519"
@@ -15,6 +16,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"time"
 
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/slog"
@@ -119,6 +121,188 @@ func (c *Client) TicketStatus(ctx context.Context, req types.TicketStatusRequest
 	return resp, nil
 }
 
+// TicketStatusBatch retrieves the status of several tickets with a single
+// request signed by addr, which should be the commitment address or the
+// alternate signing address of every ticket. Errors which only affect one
+// ticket are returned in its result rather than as an error.
+func (c *Client) TicketStatusBatch(ctx context.Context, ticketHashes []string,
+	addr stdaddr.Address) (*types.TicketStatusBatchResponse, error) {
+
+	req := types.TicketStatusBatchRequest{
+		Timestamp:    time.Now().Unix(),
+		Address:      addr.String(),
+		TicketHashes: ticketHashes,
+	}
+
+	requestBody, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *types.TicketStatusBatchResponse
+	err = c.post(ctx, "/api/v3/ticketstatusbatch", addr, &resp, json.RawMessage(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	// verify initial request matches server
+	if !bytes.Equal(requestBody, resp.Request) {
+		return nil, fmt.Errorf("server response contains differing request")
+	}
+
+	// verify there is a result for every requested ticket
+	if len(resp.Tickets) != len(ticketHashes) {
+		return nil, fmt.Errorf("server response contains %d results for %d tickets",
+			len(resp.Tickets), len(ticketHashes))
+	}
+	for i, result := range resp.Tickets {
+		if result.TicketHash != ticketHashes[i] {
+			return nil, fmt.Errorf("server response contains result for ticket %s "+
+				"in place of %s", result.TicketHash, ticketHashes[i])
+		}
+	}
+
+	return resp, nil
+}
+
+// TicketStatusStream streams the status of several tickets with a single
+// request signed by addr, which should be the commitment address or the
+// alternate signing address of every ticket. handle is called with the status
+// of every ticket when the stream starts, and then with the status of tickets
+// each time they are changed by the VSP. The stream continues until ctx is
+// canceled, the connection fails, or handle returns an error, and the cause is
+// returned. io.EOF is returned if the VSP ends the stream, for example because
+// it is shutting down. The Timeout of the http.Client must not be set, because
+// it would end the stream.
+func (c *Client) TicketStatusStream(ctx context.Context, ticketHashes []string, addr stdaddr.Address,
+	handle func(*types.TicketStatusBatchResponse) error) error {
+
+	requestBody, err := json.Marshal(types.TicketStatusBatchRequest{
+		Timestamp:    time.Now().Unix(),
+		Address:      addr.String(),
+		TicketHashes: ticketHashes,
+	})
+	if err != nil {
+		return err
+	}
+
+	sig, err := c.Sign(ctx, string(requestBody), addr)
+	if err != nil {
+		return fmt.Errorf("sign request: %w", err)
+	}
+
+	url := c.URL + "/api/v3/ticketstatusstream"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(requestBody))
+	if err != nil {
+		return fmt.Errorf("new request: %w", err)
+	}
+	httpReq.Header.Set("VSP-Client-Signature", base64.StdEncoding.EncodeToString(sig))
+	httpReq.Header.Set("Accept", "text/event-stream")
+
+	reply, err := c.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("POST %s: %w", url, err)
+	}
+	defer reply.Body.Close()
+
+	if reply.StatusCode != http.StatusOK {
+		respBody, err := io.ReadAll(io.LimitReader(reply.Body, 1<<22)) // 4 MiB limit
+		if err != nil {
+			return fmt.Errorf("read response body: %w", err)
+		}
+		return errorResponse(reply.StatusCode, respBody)
+	}
+
+	requested := make(map[string]bool, len(ticketHashes))
+	for _, hash := range ticketHashes {
+		requested[hash] = true
+	}
+
+	err = readEvents(reply.Body, func(event string, data []byte) error {
+		// Only status events are sent by the current version of vspd.
+		if event != "status" {
+			return nil
+		}
+
+		var streamEvent types.TicketStatusStreamEvent
+		err := json.Unmarshal(data, &streamEvent)
+		if err != nil {
+			return fmt.Errorf("unmarshal event: %w", err)
+		}
+		if !ed25519.Verify(c.PubKey, streamEvent.Message, streamEvent.Signature) {
+			return errors.New("authenticate server event: invalid signature")
+		}
+
+		var resp types.TicketStatusBatchResponse
+		err = json.Unmarshal(streamEvent.Message, &resp)
+		if err != nil {
+			return fmt.Errorf("unmarshal event message: %w", err)
+		}
+
+		// verify initial request matches server
+		if !bytes.Equal(requestBody, resp.Request) {
+			return fmt.Errorf("server event contains differing request")
+		}
+
+		// verify every result is for a requested ticket
+		for _, result := range resp.Tickets {
+			if !requested[result.TicketHash] {
+				return fmt.Errorf("server event contains result for unrequested ticket %s",
+					result.TicketHash)
+			}
+		}
+
+		return handle(&resp)
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// readEvents reads server-sent events from r and calls handle with the type
+// and data of each event. It returns io.EOF when r is exhausted, or the first
+// error returned by handle.
+func readEvents(r io.Reader, handle func(event string, data []byte) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<22) // 4 MiB limit
+
+	event := "message"
+	var data [][]byte
+	for scanner.Scan() {
+		line := scanner.Bytes()
+
+		// A blank line dispatches the event.
+		if len(line) == 0 {
+			if len(data) > 0 {
+				err := handle(event, bytes.Join(data, []byte("\n")))
+				if err != nil {
+					return err
+				}
+			}
+			event = "message"
+			data = nil
+			continue
+		}
+
+		field, value, _ := bytes.Cut(line, []byte(":"))
+		value = bytes.TrimPrefix(value, []byte(" "))
+		switch string(field) {
+		case "event":
+			event = string(value)
+		case "data":
+			data = append(data, append([]byte(nil), value...))
+		}
+		// Lines beginning with a colon are comments, and other fields are
+		// ignored.
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("read event stream: %w", err)
+	}
+
+	return io.EOF
+}
+
 func (c *Client) SetVoteChoices(ctx context.Context, req types.SetVoteChoicesRequest,
 	commitmentAddr stdaddr.Address) (*types.SetVoteChoicesResponse, error) {
 
@@ -153,6 +337,91 @@ func (c *Client) SetVoteChoices(ctx context.Context, req types.SetVoteChoicesReq
 	return resp, nil
 }
 
+// SetVoteChoicesBatch updates the vote choices of several tickets with a single
+// request. Each request is signed by the address at the same index of
+// commitmentAddrs, which should be the commitment address or alternate signing
+// address of the ticket. Errors which only affect one ticket are returned in
+// its result rather than as an error.
+func (c *Client) SetVoteChoicesBatch(ctx context.Context, reqs []types.SetVoteChoicesRequest,
+	commitmentAddrs []stdaddr.Address) (*types.SetVoteChoicesBatchResponse, error) {
+
+	if len(reqs) != len(commitmentAddrs) {
+		return nil, fmt.Errorf("%d requests provided with %d addresses",
+			len(reqs), len(commitmentAddrs))
+	}
+
+	batch := types.SetVoteChoicesBatchRequest{
+		Tickets: make([]types.SignedRequest, 0, len(reqs)),
+	}
+	for i, req := range reqs {
+		// VoteChoices, TSpendPolicy and TreasuryPolicy are optional but must be
+		// an empty map rather than nil.
+		if req.VoteChoices == nil {
+			req.VoteChoices = map[string]string{}
+		}
+		if req.TSpendPolicy == nil {
+			req.TSpendPolicy = map[string]string{}
+		}
+		if req.TreasuryPolicy == nil {
+			req.TreasuryPolicy = map[string]string{}
+		}
+
+		requestBody, err := json.Marshal(req)
+		if err != nil {
+			return nil, err
+		}
+
+		sig, err := c.Sign(ctx, string(requestBody), commitmentAddrs[i])
+		if err != nil {
+			return nil, fmt.Errorf("sign request for ticket %s: %w", req.TicketHash, err)
+		}
+
+		batch.Tickets = append(batch.Tickets, types.SignedRequest{
+			Request:   string(requestBody),
+			Signature: base64.StdEncoding.EncodeToString(sig),
+		})
+	}
+
+	requestBody, err := json.Marshal(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp *types.SetVoteChoicesBatchResponse
+	err = c.post(ctx, "/api/v3/setvotechoicesbatch", nil, &resp, json.RawMessage(requestBody))
+	if err != nil {
+		return nil, err
+	}
+
+	// verify initial request matches server
+	if !bytes.Equal(requestBody, resp.Request) {
+		return nil, fmt.Errorf("server response contains differing request")
+	}
+
+	// verify there is a result for every ticket, and that the response for
+	// each updated ticket is signed by the server
+	if len(resp.Tickets) != len(reqs) {
+		return nil, fmt.Errorf("server response contains %d results for %d tickets",
+			len(resp.Tickets), len(reqs))
+	}
+	for i, result := range resp.Tickets {
+		if result.Error != nil {
+			continue
+		}
+		if result.TicketHash != reqs[i].TicketHash {
+			return nil, fmt.Errorf("server response contains result for ticket %s "+
+				"in place of %s", result.TicketHash, reqs[i].TicketHash)
+		}
+		sig, err := base64.StdEncoding.DecodeString(result.ResponseSignature)
+		if err != nil || !ed25519.Verify(c.PubKey, []byte(result.Response), sig) {
+			return nil, fmt.Errorf("server response for ticket %s has invalid signature",
+				result.TicketHash)
+		}
+	}
+
+	return resp, nil
+}
+
 func (c *Client) post(ctx context.Context, path string, addr stdaddr.Address, resp, req any) error {
 	return c.do(ctx, http.MethodPost, path, addr, resp, req)
 }
@@ -171,9 +440,13 @@ func (c *Client) do(ctx context.Context, method, path string, addr stdaddr.Addre
 		if err != nil {
 			return fmt.Errorf("marshal request: %w", err)
 		}
-		sig, err = c.Sign(ctx, string(body), addr)
-		if err != nil {
-			return fmt.Errorf("sign request: %w", err)
+		// Batch requests contain their own signatures, so the request body is
+		// only signed if an address is provided.
+		if addr != nil {
+			sig, err = c.Sign(ctx, string(body), addr)
+			if err != nil {
+				return fmt.Errorf("sign request: %w", err)
+			}
 		}
 		reqBody = bytes.NewReader(body)
 	}
@@ -215,31 +488,8 @@ func (c *Client) do(ctx context.Context, method, path string, addr stdaddr.Addre
 		return fmt.Errorf("read response body: %w", err)
 	}
 
-	status := reply.StatusCode
-
-	if status != http.StatusOK {
-		// If no response body, return an error with just the HTTP status.
-		if len(respBody) == 0 {
-			return fmt.Errorf("http status %d (%s) with no body",
-				status, http.StatusText(status))
-		}
-
-		// Try to unmarshal the response body to a known vspd error.
-		d := json.NewDecoder(bytes.NewReader(respBody))
-		d.DisallowUnknownFields()
-
-		var apiError types.ErrorResponse
-		err = d.Decode(&apiError)
-		if err == nil {
-			return apiError
-		}
-
-		// If the response body could not be unmarshalled it might not have come
-		// from vspd (eg. it could be from an nginx reverse proxy or some other
-		// intermediary server). Return an error with the HTTP status and the
-		// full body so that it may be investigated.
-		return fmt.Errorf("http status %d (%s) with body %q",
-			status, http.StatusText(status), respBody)
+	if reply.StatusCode != http.StatusOK {
+		return errorResponse(reply.StatusCode, respBody)
 	}
 
 	err = ValidateServerSignature(reply, respBody, c.PubKey)
@@ -255,6 +505,33 @@ func (c *Client) do(ctx context.Context, method, path string, addr stdaddr.Addre
 	return nil
 }
 
+// errorResponse returns the error described by the body of a response which
+// does not have HTTP status 200.
+func errorResponse(status int, respBody []byte) error {
+	// If no response body, return an error with just the HTTP status.
+	if len(respBody) == 0 {
+		return fmt.Errorf("http status %d (%s) with no body",
+			status, http.StatusText(status))
+	}
+
+	// Try to unmarshal the response body to a known vspd error.
+	d := json.NewDecoder(bytes.NewReader(respBody))
+	d.DisallowUnknownFields()
+
+	var apiError types.ErrorResponse
+	err := d.Decode(&apiError)
+	if err == nil {
+		return apiError
+	}
+
+	// If the response body could not be unmarshalled it might not have come
+	// from vspd (eg. it could be from an nginx reverse proxy or some other
+	// intermediary server). Return an error with the HTTP status and the
+	// full body so that it may be investigated.
+	return fmt.Errorf("http status %d (%s) with body %q",
+		status, http.StatusText(status), respBody)
+}
+
 func ValidateServerSignature(resp *http.Response, body []byte, serverPubkey []byte) error {
 	sigBase64 := resp.Header.Get("VSP-Server-Signature")
 	if sigBase64 == "" {
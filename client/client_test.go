@@ -1,4 +1,4 @@
-// Copyright (c) 2022-2024 The Decred developers
+// Copyright (c) 2022-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,11 +8,15 @@ import (
 	"context"
 	"crypto/ed25519"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/slog"
 	"github.com/decred/vspd/types/v3"
 )
@@ -190,3 +194,121 @@ func TestSignatureValidation(t *testing.T) {
 		})
 	}
 }
+
+// testAddr is an address which is only used to identify the signer of
+// requests in tests.
+type testAddr string
+
+func (a testAddr) String() string                  { return string(a) }
+func (a testAddr) PaymentScript() (uint16, []byte) { return 0, nil }
+
+// TestTicketStatusStream ensures events of a ticket status stream are
+// authenticated before they are handled.
+func TestTicketStatusStream(t *testing.T) {
+	privKey := ed25519.NewKeyFromSeed([]byte("00000000000000000000000000000000"))
+	pubKey, _ := privKey.Public().(ed25519.PublicKey)
+
+	// statusEvent returns a status event for request containing the provided
+	// tickets, signed by key.
+	statusEvent := func(key ed25519.PrivateKey, request []byte, tickets ...string) string {
+		resp := types.TicketStatusBatchResponse{Request: request}
+		for _, hash := range tickets {
+			resp.Tickets = append(resp.Tickets, types.TicketStatusResult{
+				TicketHash: hash,
+				Status:     &types.TicketStatusResponse{FeeTxStatus: "broadcast"},
+			})
+		}
+		msg, err := json.Marshal(resp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(types.TicketStatusStreamEvent{
+			Message:   msg,
+			Signature: ed25519.Sign(key, msg),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Sprintf("event: status\ndata: %s\n\n", data)
+	}
+
+	otherKey := ed25519.NewKeyFromSeed([]byte("11111111111111111111111111111111"))
+
+	tests := map[string]struct {
+		events       func(request []byte) string
+		expectErrStr string
+		expectCalls  int
+	}{
+		"valid events": {
+			events: func(request []byte) string {
+				return ": keepalive\n\n" +
+					"event: other\ndata: ignored\n\n" +
+					statusEvent(privKey, request, "a", "b") +
+					statusEvent(privKey, request, "b")
+			},
+			expectErrStr: io.EOF.Error(),
+			expectCalls:  2,
+		},
+		"invalid signature": {
+			events: func(request []byte) string {
+				return statusEvent(otherKey, request, "a")
+			},
+			expectErrStr: "authenticate server event: invalid signature",
+		},
+		"differing request": {
+			events: func(request []byte) string {
+				return statusEvent(privKey, []byte("{}"), "a")
+			},
+			expectErrStr: "server event contains differing request",
+		},
+		"unrequested ticket": {
+			events: func(request []byte) string {
+				return statusEvent(privKey, request, "c")
+			},
+			expectErrStr: "server event contains result for unrequested ticket c",
+		},
+	}
+
+	for testName, testData := range tests {
+		t.Run(testName, func(t *testing.T) {
+
+			testServer := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
+				request, err := io.ReadAll(req.Body)
+				if err != nil {
+					t.Errorf("reading request body failed: %v", err)
+					return
+				}
+				res.Header().Set("Content-Type", "text/event-stream")
+				_, err = io.WriteString(res, testData.events(request))
+				if err != nil {
+					t.Errorf("writing response body failed: %v", err)
+				}
+			}))
+			defer testServer.Close()
+
+			client := Client{
+				URL:    testServer.URL,
+				PubKey: pubKey,
+				Sign: func(context.Context, string, stdaddr.Address) ([]byte, error) {
+					return []byte("signature"), nil
+				},
+				Log: slog.Disabled,
+			}
+
+			calls := 0
+			err := client.TicketStatusStream(context.TODO(), []string{"a", "b"}, testAddr("addr"),
+				func(*types.TicketStatusBatchResponse) error {
+					calls++
+					return nil
+				})
+
+			if err == nil || err.Error() != testData.expectErrStr {
+				t.Fatalf("client.TicketStatusStream returned incorrect error, expected %q, got %v",
+					testData.expectErrStr, err)
+			}
+			if calls != testData.expectCalls {
+				t.Fatalf("expected %d calls of handler, got %d", testData.expectCalls, calls)
+			}
+		})
+	}
+}
@@ -25,3 +25,5 @@ require (
 	golang.org/x/sys v0.30.0 // indirect
 	lukechampine.com/blake3 v1.3.0 // indirect
 )
+
+replace github.com/decred/vspd/types/v3 => ../types
@@ -1,4 +1,4 @@
-// Copyright (c) 2022-2024 The Decred developers
+// Copyright (c) 2022-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -31,6 +31,7 @@ var cfg struct {
 	Testnet      bool   `short:"t" long:"testnet" description:"Run testnet instead of mainnet"`
 	ToCheck      int    `short:"n" long:"tickets_to_check" required:"true" description:"Validate votes of the n most recently voted tickets"`
 	DatabaseFile string `short:"f" long:"database_file" required:"true" description:"Full path of database file"`
+	DBBackend    string `long:"dbbackend" default:"bolt" choice:"bolt" choice:"sqlite" description:"Storage backend of the database file"`
 }
 
 type votedTicket struct {
@@ -74,7 +75,7 @@ func run() int {
 
 	// Open database.
 	log := slog.NewBackend(os.Stdout).Logger("")
-	vdb, err := database.Open(cfg.DatabaseFile, log, 999)
+	vdb, err := database.Backend(cfg.DBBackend).Open(cfg.DatabaseFile, log, 999)
 	if err != nil {
 		log.Error(err)
 		return 1
@@ -0,0 +1,80 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+)
+
+func exportDatabase(homeDir string, network *config.Network, backend database.Backend, exportFile string,
+	includeSecrets bool) error {
+
+	// Don't overwrite existing files, they may be the only copy of a previous
+	// export.
+	if fileExists(exportFile) {
+		return fmt.Errorf("%s already exists", exportFile)
+	}
+
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	// The export may contain the signing key and cookie secret, so it should
+	// only be readable by the current user.
+	f, err := os.OpenFile(exportFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+
+	err = db.Export(f, includeSecrets)
+	if err != nil {
+		f.Close()
+		os.Remove(exportFile)
+		return fmt.Errorf("db.Export failed: %w", err)
+	}
+
+	err = f.Close()
+	if err != nil {
+		return fmt.Errorf("failed to close export file: %w", err)
+	}
+
+	return nil
+}
+
+func importDatabase(homeDir string, network *config.Network, backend database.Backend, exportFile string) error {
+	dbFile := databaseFile(homeDir, network, backend)
+	dataDir := filepath.Dir(dbFile)
+
+	// Return error if database already exists.
+	if fileExists(dbFile) {
+		return fmt.Errorf("%s database already exists in %s", network.Name, dataDir)
+	}
+
+	f, err := os.Open(exportFile)
+	if err != nil {
+		return fmt.Errorf("failed to open export file: %w", err)
+	}
+	defer f.Close()
+
+	// Ensure the data directory exists.
+	err = os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	err = backend.Import(dbFile, f)
+	if err != nil {
+		return fmt.Errorf("import into %s database failed: %w", backend, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+)
+
+func checkFeeAddresses(homeDir string, network *config.Network, backend database.Backend) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	report, err := database.CheckFeeAddresses(db, network.Params)
+	if err != nil {
+		return err
+	}
+
+	log("Checked the fee addresses of %d tickets", report.Checked)
+
+	for _, gap := range report.Gaps {
+		if gap.From == gap.To {
+			log("Unused fee address index of xpub %d: %d", gap.XPubID, gap.From)
+		} else {
+			log("Unused fee address indexes of xpub %d: %d to %d", gap.XPubID, gap.From, gap.To)
+		}
+	}
+
+	if len(report.Problems) > 0 {
+		for _, problem := range report.Problems {
+			log("%s", problem)
+		}
+		return fmt.Errorf("%d problem(s) found", len(report.Problems))
+	}
+
+	return nil
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2024 The Decred developers
+// Copyright (c) 2024-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/hdkeychain/v3"
@@ -20,18 +21,29 @@ import (
 )
 
 const (
-	configFilename = "vspd.conf"
-	dbFilename     = "vspd.db"
+	configFilename   = "vspd.conf"
+	dbFilename       = "vspd.db"
+	sqliteDBFilename = "vspd.sqlite"
 )
 
 type conf struct {
-	HomeDir string `long:"homedir" description:"Path to application home directory."`
-	Network string `long:"network" description:"Decred network to use." choice:"mainnet" choice:"testnet" choice:"simnet"`
+	HomeDir       string `long:"homedir" description:"Path to application home directory."`
+	Network       string `long:"network" description:"Decred network to use." choice:"mainnet" choice:"testnet" choice:"simnet"`
+	DBBackend     string `long:"dbbackend" description:"Database storage backend." choice:"bolt" choice:"sqlite"`
+	WIFKeyFile    string `long:"wifkeyfile" description:"Path to a file containing the passphrase used to encrypt voting WIFs."`
+	NewWIFKeyFile string `long:"newwifkeyfile" description:"Path to a file containing the new passphrase when rotating the voting WIF key."`
+	DryRun        bool   `long:"dry-run" description:"Upgrade a temporary copy of the database and report the result without modifying the database."`
+	DcrdHost      string `long:"dcrdhost" description:"The ip:port to establish a JSON-RPC connection with dcrd."`
+	DcrdUser      string `long:"dcrduser" description:"Username for dcrd RPC connections."`
+	DcrdPass      string `long:"dcrdpass" description:"Password for dcrd RPC connections."`
+	DcrdCert      string `long:"dcrdcert" description:"The dcrd RPC certificate file."`
 }
 
 var defaultConf = conf{
-	HomeDir: dcrutil.AppDataDir("vspd", false),
-	Network: "mainnet",
+	HomeDir:   dcrutil.AppDataDir("vspd", false),
+	Network:   "mainnet",
+	DBBackend: string(database.BoltBackend),
+	DcrdHost:  "127.0.0.1",
 }
 
 func log(format string, a ...any) {
@@ -61,9 +73,19 @@ func validatePubkey(key string, network *config.Network) error {
 	return nil
 }
 
-func createDatabase(homeDir string, feeXPub string, network *config.Network) error {
-	dataDir := filepath.Join(homeDir, "data", network.Name)
-	dbFile := filepath.Join(dataDir, dbFilename)
+// databaseFile returns the path of the database file used by the provided
+// storage backend.
+func databaseFile(homeDir string, network *config.Network, backend database.Backend) string {
+	filename := dbFilename
+	if backend == database.SQLiteBackend {
+		filename = sqliteDBFilename
+	}
+	return filepath.Join(homeDir, "data", network.Name, filename)
+}
+
+func createDatabase(homeDir string, feeXPub string, network *config.Network, backend database.Backend) error {
+	dbFile := databaseFile(homeDir, network, backend)
+	dataDir := filepath.Dir(dbFile)
 
 	// Return error if database already exists.
 	if fileExists(dbFile) {
@@ -83,7 +105,7 @@ func createDatabase(homeDir string, feeXPub string, network *config.Network) err
 	}
 
 	// Create new database.
-	err = database.CreateNew(dbFile, feeXPub)
+	err = backend.CreateNew(dbFile, feeXPub)
 	if err != nil {
 		return fmt.Errorf("error creating db file %s: %w", dbFile, err)
 	}
@@ -117,9 +139,8 @@ func writeConfig(homeDir string) error {
 	return nil
 }
 
-func retireXPub(homeDir string, feeXPub string, network *config.Network) error {
-	dataDir := filepath.Join(homeDir, "data", network.Name)
-	dbFile := filepath.Join(dataDir, dbFilename)
+func retireXPub(homeDir string, feeXPub string, network *config.Network, backend database.Backend) error {
+	dbFile := databaseFile(homeDir, network, backend)
 
 	// Ensure provided xpub is a valid key for the selected network.
 	err := validatePubkey(feeXPub, network)
@@ -127,7 +148,7 @@ func retireXPub(homeDir string, feeXPub string, network *config.Network) error {
 		return err
 	}
 
-	db, err := database.Open(dbFile, slog.Disabled, 999)
+	db, err := backend.Open(dbFile, slog.Disabled, 999)
 	if err != nil {
 		return fmt.Errorf("error opening db file %s: %w", dbFile, err)
 	}
@@ -140,6 +161,42 @@ func retireXPub(homeDir string, feeXPub string, network *config.Network) error {
 	return nil
 }
 
+func addXPub(homeDir string, feeXPub string, network *config.Network, backend database.Backend) error {
+	// Ensure provided xpub is a valid key for the selected network.
+	err := validatePubkey(feeXPub, network)
+	if err != nil {
+		return err
+	}
+
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	err = db.AddXPub(feeXPub)
+	if err != nil {
+		return fmt.Errorf("db.AddXPub failed: %w", err)
+	}
+
+	return nil
+}
+
+func retireXPubID(homeDir string, id uint32, network *config.Network, backend database.Backend) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	err = db.RetireXPubID(id)
+	if err != nil {
+		return fmt.Errorf("db.RetireXPubID failed: %w", err)
+	}
+
+	return nil
+}
+
 // run is the real main function for vspadmin. It is necessary to work around
 // the fact that deferred functions do not run when os.Exit() is called.
 func run() int {
@@ -163,6 +220,12 @@ func run() int {
 		return 1
 	}
 
+	backend, err := database.ParseBackend(cfg.DBBackend)
+	if err != nil {
+		log("%v", err)
+		return 1
+	}
+
 	if len(remainingArgs) < 1 {
 		log("No command specified")
 		return 1
@@ -177,7 +240,7 @@ func run() int {
 
 		feeXPub := remainingArgs[1]
 
-		err = createDatabase(cfg.HomeDir, feeXPub, network)
+		err = createDatabase(cfg.HomeDir, feeXPub, network, backend)
 		if err != nil {
 			log("createdatabase failed: %v", err)
 			return 1
@@ -203,13 +266,243 @@ func run() int {
 
 		feeXPub := remainingArgs[1]
 
-		err = retireXPub(cfg.HomeDir, feeXPub, network)
+		err = retireXPub(cfg.HomeDir, feeXPub, network, backend)
 		if err != nil {
 			log("retirexpub failed: %v", err)
 			return 1
 		}
 
-		log("Xpub successfully retired, all future tickets will use the new xpub")
+		log("Active xpubs successfully retired, all future tickets will use the new xpub")
+
+	case "addxpub":
+		if len(remainingArgs) != 2 {
+			log("addxpub has one required argument, fee xpub")
+			return 1
+		}
+
+		feeXPub := remainingArgs[1]
+
+		err = addXPub(cfg.HomeDir, feeXPub, network, backend)
+		if err != nil {
+			log("addxpub failed: %v", err)
+			return 1
+		}
+
+		log("Xpub successfully added, future tickets will use it alongside the other active xpubs")
+
+	case "retirexpubid":
+		if len(remainingArgs) != 2 {
+			log("retirexpubid has one required argument, xpub ID")
+			return 1
+		}
+
+		id, err := strconv.ParseUint(remainingArgs[1], 10, 32)
+		if err != nil {
+			log("retirexpubid failed: invalid xpub ID %q", remainingArgs[1])
+			return 1
+		}
+
+		err = retireXPubID(cfg.HomeDir, uint32(id), network, backend)
+		if err != nil {
+			log("retirexpubid failed: %v", err)
+			return 1
+		}
+
+		log("Xpub %d successfully retired, future tickets will use the remaining active xpubs", id)
+
+	case "listtickets":
+		filter, err := parseTicketFilter(remainingArgs[1:])
+		if err != nil {
+			log("listtickets failed: %v", err)
+			return 1
+		}
+
+		err = listTickets(cfg.HomeDir, network, backend, filter)
+		if err != nil {
+			log("listtickets failed: %v", err)
+			return 1
+		}
+
+	case "showticket":
+		if len(remainingArgs) != 2 {
+			log("showticket has one required argument, ticket hash")
+			return 1
+		}
+
+		err = showTicket(cfg.HomeDir, network, backend, remainingArgs[1])
+		if err != nil {
+			log("showticket failed: %v", err)
+			return 1
+		}
+
+	case "deleteticket":
+		if len(remainingArgs) != 2 {
+			log("deleteticket has one required argument, ticket hash")
+			return 1
+		}
+
+		hash := remainingArgs[1]
+
+		err = deleteTicket(cfg.HomeDir, network, backend, hash)
+		if err != nil {
+			log("deleteticket failed: %v", err)
+			return 1
+		}
+
+		log("Ticket %s deleted", hash)
+
+	case "setfeestatus":
+		if len(remainingArgs) != 3 {
+			log("setfeestatus has two required arguments, ticket hash and fee status")
+			return 1
+		}
+
+		hash := remainingArgs[1]
+
+		status, err := parseFeeStatus(remainingArgs[2])
+		if err != nil {
+			log("setfeestatus failed: %v", err)
+			return 1
+		}
+
+		err = setFeeStatus(cfg.HomeDir, network, backend, hash, status)
+		if err != nil {
+			log("setfeestatus failed: %v", err)
+			return 1
+		}
+
+		log("Fee status of ticket %s set to %s", hash, status)
+
+	case "export":
+		if len(remainingArgs) < 2 || len(remainingArgs) > 3 ||
+			(len(remainingArgs) == 3 && remainingArgs[2] != "secrets") {
+			log("export has one required argument, file path, and one optional argument, \"secrets\"")
+			return 1
+		}
+
+		exportFile := remainingArgs[1]
+		includeSecrets := len(remainingArgs) == 3
+
+		err = exportDatabase(cfg.HomeDir, network, backend, exportFile, includeSecrets)
+		if err != nil {
+			log("export failed: %v", err)
+			return 1
+		}
+
+		log("%s database exported to %s", network.Name, exportFile)
+		if includeSecrets {
+			log("The export includes secrets and should be stored securely")
+		}
+
+	case "import":
+		if len(remainingArgs) != 2 {
+			log("import has one required argument, file path")
+			return 1
+		}
+
+		exportFile := remainingArgs[1]
+
+		err = importDatabase(cfg.HomeDir, network, backend, exportFile)
+		if err != nil {
+			log("import failed: %v", err)
+			return 1
+		}
+
+		log("New %s vspd database imported from %s", network.Name, exportFile)
+
+	case "upgrade":
+		if len(remainingArgs) != 1 {
+			log("upgrade has no arguments")
+			return 1
+		}
+
+		err = upgradeDatabase(cfg.HomeDir, network, backend, cfg.DryRun)
+		if err != nil {
+			log("upgrade failed: %v", err)
+			return 1
+		}
+
+	case "checkfeeaddresses":
+		if len(remainingArgs) != 1 {
+			log("checkfeeaddresses has no arguments")
+			return 1
+		}
+
+		err = checkFeeAddresses(cfg.HomeDir, network, backend)
+		if err != nil {
+			log("checkfeeaddresses failed: %v", err)
+			return 1
+		}
+
+		log("Every fee address matches the xpub and index recorded with its ticket")
+
+	case "reconcilefees":
+		if len(remainingArgs) != 1 {
+			log("reconcilefees has no arguments")
+			return 1
+		}
+
+		err = reconcileFees(cfg, network, backend)
+		if err != nil {
+			log("reconcilefees failed: %v", err)
+			return 1
+		}
+
+		log("Every mined fee tx paid at least the fee expected")
+
+	case "verifybackup":
+		if len(remainingArgs) != 2 {
+			log("verifybackup has one required argument, file path")
+			return 1
+		}
+
+		backupFile := remainingArgs[1]
+
+		err = verifyBackup(network, backend, backupFile)
+		if err != nil {
+			log("verifybackup failed: %v", err)
+			return 1
+		}
+
+		log("%s is a valid %s %s database", backupFile, network.Name, backend)
+
+	case "encryptwifs":
+		passphrase, err := requirePassphrase(cfg.WIFKeyFile, config.WIFPassEnv, "wifkeyfile")
+		if err != nil {
+			log("encryptwifs failed: %v", err)
+			return 1
+		}
+
+		err = encryptWIFs(cfg.HomeDir, network, backend, passphrase)
+		if err != nil {
+			log("encryptwifs failed: %v", err)
+			return 1
+		}
+
+		log("Voting WIFs in the %s database are now encrypted", network.Name)
+		log("Existing backups still contain plaintext voting WIFs and should be securely deleted")
+
+	case "rotatewifkey":
+		oldPassphrase, err := requirePassphrase(cfg.WIFKeyFile, config.WIFPassEnv, "wifkeyfile")
+		if err != nil {
+			log("rotatewifkey failed: current passphrase: %v", err)
+			return 1
+		}
+
+		newPassphrase, err := requirePassphrase(cfg.NewWIFKeyFile, config.NewWIFPassEnv, "newwifkeyfile")
+		if err != nil {
+			log("rotatewifkey failed: new passphrase: %v", err)
+			return 1
+		}
+
+		err = rotateWIFKey(cfg.HomeDir, network, backend, oldPassphrase, newPassphrase)
+		if err != nil {
+			log("rotatewifkey failed: %v", err)
+			return 1
+		}
+
+		log("Voting WIFs in the %s database are now encrypted with the new passphrase", network.Name)
+		log("Update the vspd configuration to use the new passphrase before restarting it")
 
 	default:
 		log("%q is not a valid command", remainingArgs[0])
@@ -0,0 +1,89 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+	"github.com/decred/vspd/internal/reconcile"
+	"github.com/decred/vspd/rpc"
+)
+
+func reconcileFees(cfg conf, network *config.Network, backend database.Backend) error {
+	if cfg.DcrdUser == "" || cfg.DcrdPass == "" || cfg.DcrdCert == "" {
+		return errors.New("the dcrduser, dcrdpass and dcrdcert options must be set")
+	}
+
+	cert, err := os.ReadFile(cfg.DcrdCert)
+	if err != nil {
+		return fmt.Errorf("failed to read dcrd cert file: %w", err)
+	}
+
+	// Add default port for the network if there is no port specified.
+	host := cfg.DcrdHost
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, network.DcrdRPCServerPort)
+	}
+
+	db, err := openDatabase(cfg.HomeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	dcrd := rpc.SetupDcrd(cfg.DcrdUser, cfg.DcrdPass, host, cert, network.Params, slog.Disabled, nil)
+	defer dcrd.Close()
+
+	client, _, err := dcrd.Client()
+	if err != nil {
+		return err
+	}
+
+	report, err := reconcile.Fees(context.Background(), db, client, network.Params)
+	if err != nil {
+		return err
+	}
+
+	for _, period := range report.Periods {
+		log("Xpub %d %s: %d tickets, expected %v, received %v", period.XPubID, period.Month,
+			period.Tickets, dcrutil.Amount(period.Expected), dcrutil.Amount(period.Received))
+	}
+	log("Total: expected %v, received %v",
+		dcrutil.Amount(report.Expected), dcrutil.Amount(report.Received))
+
+	for _, p := range report.Overpaid {
+		log("Overpaid: ticket %s, fee tx %s, expected %v, received %v", p.TicketHash, p.FeeTxHash,
+			dcrutil.Amount(p.Expected), dcrutil.Amount(p.Received))
+	}
+	for _, p := range report.Unconfirmed {
+		log("Unconfirmed: ticket %s, fee tx %s, expected %v, paying %v", p.TicketHash, p.FeeTxHash,
+			dcrutil.Amount(p.Expected), dcrutil.Amount(p.Received))
+	}
+	for _, p := range report.Underpaid {
+		log("Underpaid: ticket %s, fee tx %s, expected %v, received %v", p.TicketHash, p.FeeTxHash,
+			dcrutil.Amount(p.Expected), dcrutil.Amount(p.Received))
+	}
+	for _, p := range report.Missing {
+		log("Missing: ticket %s, fee tx %s is unknown to dcrd", p.TicketHash, p.FeeTxHash)
+	}
+	for _, p := range report.Failed {
+		log("Failed: ticket %s, fee tx %s could not be checked: %s", p.TicketHash, p.FeeTxHash, p.Error)
+	}
+
+	if len(report.Underpaid) > 0 || len(report.Missing) > 0 || len(report.Failed) > 0 {
+		return fmt.Errorf("%d underpaid, %d missing and %d unchecked fee txs found",
+			len(report.Underpaid), len(report.Missing), len(report.Failed))
+	}
+
+	return nil
+}
@@ -0,0 +1,273 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+)
+
+// writeBackup is false for all vspadmin commands because the backup file is
+// written periodically by vspd, and vspd must be stopped before vspadmin can
+// open the database.
+const writeBackup = false
+
+// validFeeStatuses and validOutcomes are the values accepted by the ticket
+// filters of listtickets and by setfeestatus.
+var (
+	validFeeStatuses = []database.FeeStatus{
+		database.NoFee,
+		database.FeeReceieved,
+		database.FeeBroadcast,
+		database.FeeConfirmed,
+		database.FeeError,
+	}
+	validOutcomes = []database.TicketOutcome{
+		database.Expired,
+		database.Missed,
+		database.Voted,
+		database.Revoked,
+	}
+)
+
+func parseFeeStatus(s string) (database.FeeStatus, error) {
+	for _, status := range validFeeStatuses {
+		if string(status) == s {
+			return status, nil
+		}
+	}
+	return "", fmt.Errorf("invalid fee status %q, expected one of %v", s, validFeeStatuses)
+}
+
+func parseOutcome(s string) (database.TicketOutcome, error) {
+	for _, outcome := range validOutcomes {
+		if string(outcome) == s {
+			return outcome, nil
+		}
+	}
+	return "", fmt.Errorf("invalid outcome %q, expected one of %v", s, validOutcomes)
+}
+
+// parseTicketFilter parses a list of key=value arguments into a ticket filter.
+func parseTicketFilter(args []string) (database.TicketFilter, error) {
+	var filter database.TicketFilter
+
+	for _, arg := range args {
+		key, value, ok := strings.Cut(arg, "=")
+		if !ok {
+			return filter, fmt.Errorf("invalid filter %q, expected key=value", arg)
+		}
+
+		var err error
+		switch key {
+		case "feestatus":
+			filter.FeeStatus, err = parseFeeStatus(value)
+		case "outcome":
+			filter.Outcome, err = parseOutcome(value)
+		case "minheight":
+			filter.MinHeight, err = strconv.ParseInt(value, 10, 64)
+		case "maxheight":
+			filter.MaxHeight, err = strconv.ParseInt(value, 10, 64)
+		default:
+			err = fmt.Errorf("unknown filter %q", key)
+		}
+		if err != nil {
+			return filter, err
+		}
+	}
+
+	return filter, nil
+}
+
+// openDatabase opens the existing vspd database for the provided network. With
+// the bolt backend, an error is returned if the database is in use by a running
+// vspd process.
+func openDatabase(homeDir string, network *config.Network, backend database.Backend) (database.Database, error) {
+	dbFile := databaseFile(homeDir, network, backend)
+
+	if !fileExists(dbFile) {
+		return nil, fmt.Errorf("no %s database exists in %s", network.Name, homeDir)
+	}
+
+	db, err := backend.Open(dbFile, slog.Disabled, 999)
+	if err != nil {
+		return nil, fmt.Errorf("error opening db file %s: %w", dbFile, err)
+	}
+
+	return db, nil
+}
+
+func listTickets(homeDir string, network *config.Network, backend database.Backend, filter database.TicketFilter) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	tickets, err := db.ListTickets(filter)
+	if err != nil {
+		return fmt.Errorf("db.ListTickets failed: %w", err)
+	}
+
+	tickets.SortByPurchaseHeight()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "HASH\tHEIGHT\tCONFIRMED\tFEE STATUS\tOUTCOME")
+	for _, t := range tickets {
+		fmt.Fprintf(tw, "%s\t%d\t%t\t%s\t%s\n",
+			t.Hash, t.PurchaseHeight, t.Confirmed, t.FeeTxStatus, t.Outcome)
+	}
+	err = tw.Flush()
+	if err != nil {
+		return err
+	}
+
+	log("%d tickets found", len(tickets))
+
+	return nil
+}
+
+// ticketDetails is everything stored in the database about a single ticket,
+// written as JSON by showticket. Only one of Ticket and Archived is set.
+type ticketDetails struct {
+	Ticket      *database.Ticket                     `json:"ticket,omitempty"`
+	Archived    *database.ArchivedTicket             `json:"archived,omitempty"`
+	VoteChanges map[uint32]database.VoteChangeRecord `json:"votechanges"`
+	AltSignAddr *database.AltSignAddrData            `json:"altsignaddr"`
+	Events      []database.TicketEvent               `json:"events"`
+}
+
+func showTicket(homeDir string, network *config.Network, backend database.Backend, hash string) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	var details ticketDetails
+
+	ticket, found, err := db.GetTicketByHash(hash)
+	if err != nil {
+		return fmt.Errorf("db.GetTicketByHash failed: %w", err)
+	}
+	if found {
+		details.Ticket = &ticket
+	} else {
+		// The ticket may have been archived.
+		archived, found, err := db.GetArchivedTicket(hash)
+		if err != nil {
+			return fmt.Errorf("db.GetArchivedTicket failed: %w", err)
+		}
+		if !found {
+			return fmt.Errorf("no ticket found with hash %s", hash)
+		}
+		details.Archived = &archived
+	}
+
+	details.VoteChanges, err = db.GetVoteChanges(hash)
+	if err != nil {
+		return fmt.Errorf("db.GetVoteChanges failed: %w", err)
+	}
+
+	details.AltSignAddr, err = db.AltSignAddrData(hash)
+	if err != nil {
+		return fmt.Errorf("db.AltSignAddrData failed: %w", err)
+	}
+
+	details.Events, err = db.TicketEvents(hash)
+	if err != nil {
+		return fmt.Errorf("db.TicketEvents failed: %w", err)
+	}
+
+	detailsJSON, err := json.MarshalIndent(details, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal ticket: %w", err)
+	}
+
+	log("%s", detailsJSON)
+
+	return nil
+}
+
+func deleteTicket(homeDir string, network *config.Network, backend database.Backend, hash string) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	ticket, found, err := db.GetTicketByHash(hash)
+	if err != nil {
+		return fmt.Errorf("db.GetTicketByHash failed: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("no ticket found with hash %s", hash)
+	}
+
+	err = db.PurgeTicket(ticket)
+	if err != nil {
+		return fmt.Errorf("db.PurgeTicket failed: %w", err)
+	}
+
+	return nil
+}
+
+func setFeeStatus(homeDir string, network *config.Network, backend database.Backend, hash string, status database.FeeStatus) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	ticket, found, err := db.GetTicketByHash(hash)
+	if err != nil {
+		return fmt.Errorf("db.GetTicketByHash failed: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("no ticket found with hash %s", hash)
+	}
+
+	// vspd will attempt to broadcast the fee tx of tickets with status
+	// received, which is only possible if the fee tx is still available.
+	if status == database.FeeReceieved && ticket.FeeTxHex == "" {
+		return errors.New("ticket does not have a fee tx")
+	}
+
+	oldStatus := ticket.FeeTxStatus
+	ticket.FeeTxStatus = status
+
+	// Allow vspd to retry the fee tx even if it was previously considered to
+	// have permanently failed.
+	ticket.FeeErrorPermanent = false
+	if status != database.FeeError {
+		ticket.FeeErrorReason = ""
+	}
+
+	err = db.UpdateTicket(ticket)
+	if err != nil {
+		return fmt.Errorf("db.UpdateTicket failed: %w", err)
+	}
+
+	err = db.AppendTicketEvent(hash, database.TicketEvent{
+		Timestamp: time.Now().Unix(),
+		Type:      database.EventAdminChange,
+		Detail:    fmt.Sprintf("Fee status changed from %s to %s by vspadmin", oldStatus, status),
+	})
+	if err != nil {
+		return fmt.Errorf("db.AppendTicketEvent failed: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+)
+
+func upgradeDatabase(homeDir string, network *config.Network, backend database.Backend, dryRun bool) error {
+	dbFile := databaseFile(homeDir, network, backend)
+
+	if !fileExists(dbFile) {
+		return fmt.Errorf("no %s database exists in %s", network.Name, homeDir)
+	}
+
+	// Messages logged by the upgrades describe the changes they make.
+	upgradeLog := slog.NewBackend(os.Stdout).Logger("")
+	upgradeLog.SetLevel(slog.LevelInfo)
+
+	if dryRun {
+		report, err := backend.DryRunUpgrade(dbFile, network.Params, upgradeLog)
+		if err != nil {
+			return err
+		}
+
+		if report.FromVersion == report.ToVersion {
+			log("Database is already the latest version (%d), no upgrades required", report.ToVersion)
+		} else {
+			log("Database would be upgraded from version %d to %d", report.FromVersion, report.ToVersion)
+		}
+		log("Upgraded database verified (%d fee xpubs, %d tickets)",
+			report.Verify.XPubs, report.Verify.Tickets)
+
+		return nil
+	}
+
+	report, err := backend.Upgrade(dbFile, upgradeLog)
+	if err != nil {
+		return err
+	}
+
+	if report.FromVersion == report.ToVersion {
+		log("Database is already the latest version (%d), no upgrades required", report.ToVersion)
+		return nil
+	}
+
+	log("Database upgraded from version %d to %d", report.FromVersion, report.ToVersion)
+	log("A copy of the database from before the upgrade was written to %s", report.Backup)
+
+	return nil
+}
@@ -0,0 +1,23 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+)
+
+func verifyBackup(network *config.Network, backend database.Backend, backupFile string) error {
+	report, err := backend.VerifyBackup(backupFile, network.Params)
+	if err != nil {
+		return err
+	}
+
+	log("Database version: %d", report.Version)
+	log("Fee xpubs:        %d", report.XPubs)
+	log("Tickets:          %d", report.Tickets)
+
+	return nil
+}
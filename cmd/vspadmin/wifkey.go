@@ -0,0 +1,62 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/config"
+)
+
+// requirePassphrase returns the voting WIF passphrase read from keyFile or
+// envVar, returning an error if neither is set.
+func requirePassphrase(keyFile, envVar, keyFileOption string) ([]byte, error) {
+	passphrase, err := config.WIFPassphrase("", keyFile, envVar)
+	if err != nil {
+		return nil, err
+	}
+	if passphrase == nil {
+		return nil, fmt.Errorf("provide the passphrase with --%s or the %s environment variable",
+			keyFileOption, envVar)
+	}
+	return passphrase, nil
+}
+
+func encryptWIFs(homeDir string, network *config.Network, backend database.Backend, passphrase []byte) error {
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	err = db.EncryptWIFs(passphrase)
+	if err != nil {
+		return fmt.Errorf("db.EncryptWIFs failed: %w", err)
+	}
+
+	return nil
+}
+
+func rotateWIFKey(homeDir string, network *config.Network, backend database.Backend,
+	oldPassphrase, newPassphrase []byte) error {
+
+	db, err := openDatabase(homeDir, network, backend)
+	if err != nil {
+		return err
+	}
+	defer db.Close(writeBackup)
+
+	err = db.RotateWIFKey(oldPassphrase, newPassphrase)
+	if errors.Is(err, database.ErrWIFsNotEncrypted) {
+		return errors.New("voting WIFs are not encrypted, use encryptwifs instead")
+	}
+	if err != nil {
+		return fmt.Errorf("db.RotateWIFKey failed: %w", err)
+	}
+
+	return nil
+}
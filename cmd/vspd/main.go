@@ -7,18 +7,23 @@ package main
 import (
 	"fmt"
 	"os"
+	"reflect"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/slog"
-	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/backup"
 	"github.com/decred/vspd/internal/config"
+	"github.com/decred/vspd/internal/metrics"
 	"github.com/decred/vspd/internal/signal"
+	"github.com/decred/vspd/internal/ticketfeed"
 	"github.com/decred/vspd/internal/version"
 	"github.com/decred/vspd/internal/vspd"
 	"github.com/decred/vspd/internal/webapi"
+	"github.com/decred/vspd/internal/webhook"
 	"github.com/decred/vspd/rpc"
 )
 
@@ -56,6 +61,63 @@ func initLogging(cfg *vspd.Config) (func(subsystem string) slog.Logger, error) {
 	}, nil
 }
 
+// reloadConfig loads the config again and applies the options which can be
+// changed while vspd is running to the webapi and vspd. Options which can only
+// be changed by restarting vspd are compared against startCfg, the config vspd
+// was started with, and a warning is logged for each one which differs. It
+// returns the config which is now in use, which is current if the reloaded
+// config could not be applied.
+func reloadConfig(log slog.Logger, startCfg, current *vspd.Config, api *webapi.WebAPI,
+	v *vspd.Vspd) *vspd.Config {
+	newCfg, err := vspd.LoadConfig()
+	if err != nil {
+		log.Errorf("Failed to reload config, keeping current config: %v", err)
+		return current
+	}
+
+	err = api.Reconfigure(webapi.Config{
+		VSPFee:       newCfg.VSPFee,
+		FeeSchedule:  newCfg.FeeSchedule,
+		SupportEmail: newCfg.SupportEmail,
+		AdminPass:    newCfg.AdminPass,
+	})
+	if err != nil {
+		log.Errorf("Failed to apply reloaded config, keeping current config: %v", err)
+		return current
+	}
+
+	// Editing vspclosed or vspclosedmsg opens or closes the VSP in the same way
+	// as the admin page, replacing any status set there. The status is left
+	// alone if neither option was changed.
+	if current.VspClosed != newCfg.VspClosed || current.VspClosedMsg != newCfg.VspClosedMsg {
+		err = api.SetVspStatus(newCfg.VspClosed, newCfg.VspClosedMsg, "config reload")
+		if err != nil {
+			log.Errorf("Failed to set VSP status: %v", err)
+		}
+	}
+
+	// Wallet certs are compared as well as the options so that replaced cert
+	// files are also picked up.
+	if !reflect.DeepEqual(current.WalletDetails(), newCfg.WalletDetails()) {
+		wd := newCfg.WalletDetails()
+		v.UpdateWallets(wd.Users, wd.Passwords, wd.Hosts, wd.Certs)
+		log.Infof("Voting wallet connections rebuilt for %d wallets", len(wd.Hosts))
+	}
+
+	if changed := current.LiveChanges(newCfg); len(changed) > 0 {
+		log.Infof("Applied changes to config options: %s", strings.Join(changed, ", "))
+	} else {
+		log.Infof("No changes to config options which can be applied while running")
+	}
+
+	if changed := startCfg.RestartRequired(newCfg); len(changed) > 0 {
+		log.Warnf("Changes to config options %s will not be applied until vspd is restarted",
+			strings.Join(changed, ", "))
+	}
+
+	return newCfg
+}
+
 // run is the real main function for vspd. It is necessary to work around the
 // fact that deferred functions do not run when os.Exit() is called.
 func run() int {
@@ -109,7 +171,7 @@ func run() int {
 	}
 
 	// Open database.
-	db, err := database.Open(cfg.DatabaseFile(), makeLogger(" DB"), maxVoteChangeRecords)
+	db, err := cfg.DatabaseBackend().Open(cfg.DatabaseFile(), makeLogger(" DB"), maxVoteChangeRecords)
 	if err != nil {
 		log.Errorf("Failed to open database: %v", err)
 		return 1
@@ -117,6 +179,27 @@ func run() int {
 	const writeBackup = true
 	defer db.Close(writeBackup)
 
+	// Voting WIFs must be decrypted to add tickets to the voting wallets, so
+	// refuse to start without the passphrase if they are encrypted.
+	wifPass := cfg.WIFPassphrase()
+	switch {
+	case db.WIFsEncrypted() && wifPass == nil:
+		log.Errorf("Voting WIFs in the database are encrypted, provide the passphrase with "+
+			"--wifpass, --wifkeyfile or the %s environment variable", config.WIFPassEnv)
+		return 1
+	case db.WIFsEncrypted():
+		err = db.UnlockWIFs(wifPass)
+		if err != nil {
+			log.Errorf("Failed to unlock voting WIFs: %v", err)
+			return 1
+		}
+		log.Infof("Voting WIFs unlocked")
+	case wifPass != nil:
+		log.Errorf("A voting WIF passphrase is configured but voting WIFs in the database " +
+			"are not encrypted, run vspadmin encryptwifs to encrypt them")
+		return 1
+	}
+
 	rpcLog := makeLogger("RPC")
 
 	// Create a channel to receive blockConnected notifications from dcrd.
@@ -134,10 +217,41 @@ func run() int {
 	wallets := rpc.SetupWallet(wd.Users, wd.Passwords, wd.Hosts, wd.Certs, network.Params, rpcLog)
 	defer wallets.Close()
 
+	// Metrics are shared between the webapi and vspd so they can all be served
+	// from a single endpoint.
+	vspdMetrics := metrics.New()
+
+	// Webhook notifications are sent by both the webapi and vspd.
+	notifier, err := webhook.New(db, makeLogger("WHK"), cfg.WebhookURLs())
+	if err != nil {
+		log.Errorf("Failed to initialize webhook notifier: %v", err)
+		return 1
+	}
+
+	// Changes to tickets made by vspd are pushed to clients of the webapi
+	// which are streaming ticket status.
+	feed := ticketfeed.New()
+
+	// Timestamped copies of the backup file are kept according to the
+	// retention policy, and copied to any configured off-host sinks.
+	// Every timestamped copy is verified first, using the same checks as
+	// vspadmin verifybackup.
+	verifyBackup := func(file string) error {
+		_, err := cfg.DatabaseBackend().VerifyBackup(file, network.Params)
+		return err
+	}
+	backups, err := backup.New(makeLogger("BAK"), cfg.DatabaseFile(), cfg.BackupRetention(),
+		cfg.BackupSinks(), verifyBackup)
+	if err != nil {
+		log.Errorf("Failed to initialize backups: %v", err)
+		return 1
+	}
+
 	// Create webapi server.
 	apiCfg := webapi.Config{
 		Listen:               cfg.Listen,
 		VSPFee:               cfg.VSPFee,
+		FeeSchedule:          cfg.FeeSchedule,
 		Network:              network,
 		SupportEmail:         cfg.SupportEmail,
 		VspClosed:            cfg.VspClosed,
@@ -147,8 +261,10 @@ func run() int {
 		Designation:          cfg.Designation,
 		MaxVoteChangeRecords: maxVoteChangeRecords,
 		VspdVersion:          version.String(),
+		FeeXPubPolicy:        webapi.XPubPolicy(cfg.FeeXPubPolicy),
+		FeeXPubWeights:       cfg.XPubWeights(),
 	}
-	api, err := webapi.New(db, makeLogger("API"), dcrd, wallets, apiCfg)
+	api, err := webapi.New(db, makeLogger("API"), dcrd, wallets, vspdMetrics, notifier, feed, apiCfg)
 	if err != nil {
 		log.Errorf("Failed to initialize webapi: %v", err)
 		return 1
@@ -163,11 +279,32 @@ func run() int {
 	})
 
 	// Start vspd.
-	vspd := vspd.New(network, log, db, dcrd, wallets, blockNotifChan)
+	vspd := vspd.New(network, log, db, dcrd, wallets, vspdMetrics, notifier, feed, cfg.ArchiveAfter,
+		blockNotifChan)
 	wg.Go(func() {
 		vspd.Run(ctx)
 	})
 
+	// Reload the config every time a reload signal such as SIGHUP is
+	// received.
+	reload := signal.ReloadListener(ctx, log)
+	wg.Go(func() {
+		current := cfg
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-reload:
+				current = reloadConfig(log, cfg, current, api, vspd)
+			}
+		}
+	})
+
+	// Start delivering webhook notifications.
+	wg.Go(func() {
+		notifier.Run(ctx)
+	})
+
 	// Periodically write a database backup file.
 	wg.Go(func() {
 		for {
@@ -178,7 +315,10 @@ func run() int {
 				err := db.WriteHotBackupFile()
 				if err != nil {
 					log.Errorf("Failed to write database backup: %v", err)
+					continue
 				}
+
+				backups.Snapshot(ctx)
 			}
 		}
 	})
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2021 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -43,13 +43,9 @@ type AltSignAddrData struct {
 //
 // Passed data must have no empty fields.
 func (vdb *VspDatabase) InsertAltSignAddr(ticketHash string, data *AltSignAddrData) error {
-	if data == nil {
-		return errors.New("alt sign addr data must not be nil for inserts")
-	}
-
-	if data.AltSignAddr == "" || len(data.Req) == 0 || data.ReqSig == "" ||
-		len(data.Resp) == 0 || data.RespSig == "" {
-		return errors.New("alt sign addr data has empty parameters")
+	err := checkAltSignAddrData(data)
+	if err != nil {
+		return err
 	}
 
 	return vdb.db.Update(func(tx *bolt.Tx) error {
@@ -62,23 +58,44 @@ func (vdb *VspDatabase) InsertAltSignAddr(ticketHash string, data *AltSignAddrDa
 			return fmt.Errorf("could not create bucket for alt sign addr: %w", err)
 		}
 
-		if err := bkt.Put(altSignAddrK, []byte(data.AltSignAddr)); err != nil {
-			return err
-		}
+		return putAltSignAddrInBucket(bkt, data)
+	})
+}
 
-		if err := bkt.Put(reqK, []byte(data.Req)); err != nil {
-			return err
-		}
+// checkAltSignAddrData returns an error if the provided data is nil or any of
+// its fields are empty.
+func checkAltSignAddrData(data *AltSignAddrData) error {
+	if data == nil {
+		return errors.New("alt sign addr data must not be nil for inserts")
+	}
 
-		if err := bkt.Put(reqSigK, []byte(data.ReqSig)); err != nil {
-			return err
-		}
+	if data.AltSignAddr == "" || len(data.Req) == 0 || data.ReqSig == "" ||
+		len(data.Resp) == 0 || data.RespSig == "" {
+		return errors.New("alt sign addr data has empty parameters")
+	}
 
-		if err := bkt.Put(respK, []byte(data.Resp)); err != nil {
-			return err
-		}
-		return bkt.Put(respSigK, []byte(data.RespSig))
-	})
+	return nil
+}
+
+// putAltSignAddrInBucket stores each of the fields of the provided alternate
+// signing address data as values within the provided db bucket.
+func putAltSignAddrInBucket(bkt *bolt.Bucket, data *AltSignAddrData) error {
+	if err := bkt.Put(altSignAddrK, []byte(data.AltSignAddr)); err != nil {
+		return err
+	}
+
+	if err := bkt.Put(reqK, []byte(data.Req)); err != nil {
+		return err
+	}
+
+	if err := bkt.Put(reqSigK, []byte(data.ReqSig)); err != nil {
+		return err
+	}
+
+	if err := bkt.Put(respK, []byte(data.Resp)); err != nil {
+		return err
+	}
+	return bkt.Put(respSigK, []byte(data.RespSig))
 }
 
 // DeleteAltSignAddr deletes an alternate signing address from the database.
@@ -111,13 +128,17 @@ func (vdb *VspDatabase) AltSignAddrData(ticketHash string) (*AltSignAddrData, er
 		if bkt == nil {
 			return nil
 		}
-		h = &AltSignAddrData{
-			AltSignAddr: string(bkt.Get(altSignAddrK)),
-			Req:         string(bkt.Get(reqK)),
-			ReqSig:      string(bkt.Get(reqSigK)),
-			Resp:        string(bkt.Get(respK)),
-			RespSig:     string(bkt.Get(respSigK)),
-		}
+		h = getAltSignAddrFromBkt(bkt)
 		return nil
 	})
 }
+
+func getAltSignAddrFromBkt(bkt *bolt.Bucket) *AltSignAddrData {
+	return &AltSignAddrData{
+		AltSignAddr: string(bkt.Get(altSignAddrK)),
+		Req:         string(bkt.Get(reqK)),
+		ReqSig:      string(bkt.Get(reqSigK)),
+		Resp:        string(bkt.Get(respK)),
+		RespSig:     string(bkt.Get(respSigK)),
+	}
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// The keys of the running totals stored in the archive bucket, which allow
+// TicketStats to include archived tickets without iterating over them.
+var (
+	archivedVotedK   = []byte("voted")
+	archivedExpiredK = []byte("expired")
+	archivedMissedK  = []byte("missed")
+	archivedRevenueK = []byte("revenue")
+)
+
+// ArchivedTicket is the compact record kept of a ticket after it has been
+// archived. The voting key and raw fee tx are discarded, but enough is kept to
+// account for the fee which was paid and the way the ticket was voted.
+type ArchivedTicket struct {
+	Hash              string
+	PurchaseHeight    int64
+	CommitmentAddress string
+	FeeAddressXPubID  uint32
+	FeeAddressIndex   uint32
+	FeeAddress        string
+	FeeAmount         int64
+	FeePolicy         string
+	FeeTxHash         string
+	VoteChoices       map[string]string
+	TSpendPolicy      map[string]string
+	TreasuryPolicy    map[string]string
+	Outcome           TicketOutcome
+	OutcomeHeight     int64
+}
+
+// archivedTicket returns the compact archive record of the provided ticket.
+func archivedTicket(ticket Ticket) ArchivedTicket {
+	return ArchivedTicket{
+		Hash:              ticket.Hash,
+		PurchaseHeight:    ticket.PurchaseHeight,
+		CommitmentAddress: ticket.CommitmentAddress,
+		FeeAddressXPubID:  ticket.FeeAddressXPubID,
+		FeeAddressIndex:   ticket.FeeAddressIndex,
+		FeeAddress:        ticket.FeeAddress,
+		FeeAmount:         ticket.FeeAmount,
+		FeePolicy:         ticket.FeePolicy,
+		FeeTxHash:         ticket.FeeTxHash,
+		VoteChoices:       ticket.VoteChoices,
+		TSpendPolicy:      ticket.TSpendPolicy,
+		TreasuryPolicy:    ticket.TreasuryPolicy,
+		Outcome:           ticket.Outcome,
+		OutcomeHeight:     ticket.OutcomeHeight,
+	}
+}
+
+// Ticket returns the archived ticket as a Ticket. Fields which are not
+// retained in the archive are left empty.
+func (a ArchivedTicket) Ticket() Ticket {
+	return Ticket{
+		Hash:              a.Hash,
+		PurchaseHeight:    a.PurchaseHeight,
+		CommitmentAddress: a.CommitmentAddress,
+		FeeAddressXPubID:  a.FeeAddressXPubID,
+		FeeAddressIndex:   a.FeeAddressIndex,
+		FeeAddress:        a.FeeAddress,
+		FeeAmount:         a.FeeAmount,
+		FeePolicy:         a.FeePolicy,
+		Confirmed:         true,
+		VoteChoices:       a.VoteChoices,
+		TSpendPolicy:      a.TSpendPolicy,
+		TreasuryPolicy:    a.TreasuryPolicy,
+		FeeTxHash:         a.FeeTxHash,
+		FeeTxStatus:       FeeConfirmed,
+		Outcome:           a.Outcome,
+		OutcomeHeight:     a.OutcomeHeight,
+	}
+}
+
+// archivable returns true if the ticket can be archived at the provided block
+// height, ie. it has a confirmed fee and its outcome was set at least retention
+// blocks ago. Tickets purchased in the last 28 days are never archivable so
+// that TicketStats can calculate recent revenue from the tickets which have not
+// been archived. If the outcome height was not recorded, the purchase height is
+// used instead.
+func (t *Ticket) archivable(blockHeight, retention int64) bool {
+	if t.FeeTxStatus != FeeConfirmed || t.Outcome == "" || t.PurchaseHeight == 0 {
+		return false
+	}
+
+	if t.PurchaseHeight >= blockHeight-blocksIn28Days {
+		return false
+	}
+
+	outcomeHeight := t.OutcomeHeight
+	if outcomeHeight == 0 {
+		outcomeHeight = t.PurchaseHeight
+	}
+
+	return outcomeHeight <= blockHeight-retention
+}
+
+// createArchiveBucket creates the empty archive bucket within the provided vsp
+// bucket.
+func createArchiveBucket(vspBkt *bolt.Bucket) error {
+	archiveBkt, err := vspBkt.CreateBucket(archiveBktK)
+	if err != nil {
+		return fmt.Errorf("failed to create %s bucket: %w", archiveBktK, err)
+	}
+
+	for _, k := range [][]byte{archivedVotedK, archivedExpiredK, archivedMissedK, archivedRevenueK} {
+		err = archiveBkt.Put(k, int64ToBytes(0))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// addToArchiveTotal adds n to the running total stored with key k in the
+// archive bucket.
+func addToArchiveTotal(archiveBkt *bolt.Bucket, k []byte, n int64) error {
+	return archiveBkt.Put(k, int64ToBytes(bytesToInt64(archiveBkt.Get(k))+n))
+}
+
+// putArchivedTicket stores the provided archived ticket in its own bucket
+// within the archive bucket, and adds it to the running totals. Returns an
+// error if the ticket is already archived.
+func putArchivedTicket(archiveBkt *bolt.Bucket, ticket ArchivedTicket) error {
+	// Revoked tickets are counted as expired, as they are by TicketStats.
+	var totalK []byte
+	switch ticket.Outcome {
+	case Voted:
+		totalK = archivedVotedK
+	case Expired, Revoked:
+		totalK = archivedExpiredK
+	case Missed:
+		totalK = archivedMissedK
+	default:
+		return fmt.Errorf("cannot archive ticket with outcome %q", ticket.Outcome)
+	}
+
+	bkt, err := archiveBkt.CreateBucket([]byte(ticket.Hash))
+	if err != nil {
+		return fmt.Errorf("could not create bucket for archived ticket: %w", err)
+	}
+
+	if err = bkt.Put(hashK, []byte(ticket.Hash)); err != nil {
+		return err
+	}
+	if err = bkt.Put(purchaseHeightK, int64ToBytes(ticket.PurchaseHeight)); err != nil {
+		return err
+	}
+	if err = bkt.Put(commitmentAddressK, []byte(ticket.CommitmentAddress)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeAddressXPubIDK, uint32ToBytes(ticket.FeeAddressXPubID)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeAddressIndexK, uint32ToBytes(ticket.FeeAddressIndex)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeAddressK, []byte(ticket.FeeAddress)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeAmountK, int64ToBytes(ticket.FeeAmount)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feePolicyK, []byte(ticket.FeePolicy)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeTxHashK, []byte(ticket.FeeTxHash)); err != nil {
+		return err
+	}
+	if err = bkt.Put(voteChoicesK, stringMapToBytes(ticket.VoteChoices)); err != nil {
+		return err
+	}
+	if err = bkt.Put(tSpendPolicyK, stringMapToBytes(ticket.TSpendPolicy)); err != nil {
+		return err
+	}
+	if err = bkt.Put(treasuryPolicyK, stringMapToBytes(ticket.TreasuryPolicy)); err != nil {
+		return err
+	}
+	if err = bkt.Put(outcomeK, []byte(ticket.Outcome)); err != nil {
+		return err
+	}
+	if err = bkt.Put(outcomeHeightK, int64ToBytes(ticket.OutcomeHeight)); err != nil {
+		return err
+	}
+
+	err = addToArchiveTotal(archiveBkt, totalK, 1)
+	if err != nil {
+		return err
+	}
+
+	return addToArchiveTotal(archiveBkt, archivedRevenueK, ticket.FeeAmount)
+}
+
+func getArchivedTicketFromBkt(bkt *bolt.Bucket) (ArchivedTicket, error) {
+	var ticket ArchivedTicket
+
+	ticket.Hash = string(bkt.Get(hashK))
+	ticket.PurchaseHeight = bytesToInt64(bkt.Get(purchaseHeightK))
+	ticket.CommitmentAddress = string(bkt.Get(commitmentAddressK))
+	ticket.FeeAddressXPubID = bytesToUint32(bkt.Get(feeAddressXPubIDK))
+	ticket.FeeAddressIndex = bytesToUint32(bkt.Get(feeAddressIndexK))
+	ticket.FeeAddress = string(bkt.Get(feeAddressK))
+	ticket.FeeAmount = bytesToInt64(bkt.Get(feeAmountK))
+	ticket.FeePolicy = string(bkt.Get(feePolicyK))
+	ticket.FeeTxHash = string(bkt.Get(feeTxHashK))
+	ticket.Outcome = TicketOutcome(bkt.Get(outcomeK))
+	ticket.OutcomeHeight = bytesToInt64(bkt.Get(outcomeHeightK))
+
+	var err error
+	ticket.VoteChoices, err = bytesToStringMap(bkt.Get(voteChoicesK))
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal VoteChoices err: %w", err)
+	}
+
+	ticket.TSpendPolicy, err = bytesToStringMap(bkt.Get(tSpendPolicyK))
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal TSpendPolicy err: %w", err)
+	}
+
+	ticket.TreasuryPolicy, err = bytesToStringMap(bkt.Get(treasuryPolicyK))
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal TreasuryPolicy err: %w", err)
+	}
+
+	return ticket, nil
+}
+
+// addArchiveStats adds the running totals of the archive bucket to stats.
+// Archived tickets were all purchased more than 28 days ago, so they only count
+// towards lifetime revenue.
+func addArchiveStats(archiveBkt *bolt.Bucket, stats *TicketStats) {
+	stats.Voted += bytesToInt64(archiveBkt.Get(archivedVotedK))
+	stats.Expired += bytesToInt64(archiveBkt.Get(archivedExpiredK))
+	stats.Missed += bytesToInt64(archiveBkt.Get(archivedMissedK))
+	stats.RevenueLifetime += bytesToInt64(archiveBkt.Get(archivedRevenueK))
+}
+
+// ArchiveTickets moves every ticket which had its outcome set at least
+// retention blocks before the provided block height into the archive,
+// discarding its voting key and raw fee tx. Tickets purchased in the last 28
+// days are not archived. Returns the number of tickets archived.
+func (vdb *VspDatabase) ArchiveTickets(blockHeight, retention int64) (int, error) {
+	var archived int
+	err := vdb.db.Update(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+		archiveBkt := vspBkt.Bucket(archiveBktK)
+
+		// Find archivable tickets before modifying anything, because buckets
+		// must not be modified while they are being iterated.
+		var tickets TicketList
+		for _, outcome := range []TicketOutcome{Voted, Expired, Missed, Revoked} {
+			err := forEachIndexedTicket(tx, outcomeIdxBktK, []byte(outcome), func(tBkt *bolt.Bucket) error {
+				ticket, err := getTicketFromBkt(tBkt)
+				if err != nil {
+					return fmt.Errorf("could not get ticket: %w", err)
+				}
+				if ticket.archivable(blockHeight, retention) {
+					tickets = append(tickets, ticket)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, ticket := range tickets {
+			err := unindexTicket(ticketBkt.Bucket([]byte(ticket.Hash)))
+			if err != nil {
+				return err
+			}
+
+			err = ticketBkt.DeleteBucket([]byte(ticket.Hash))
+			if err != nil {
+				return fmt.Errorf("could not delete ticket: %w", err)
+			}
+
+			err = putArchivedTicket(archiveBkt, archivedTicket(ticket))
+			if err != nil {
+				return fmt.Errorf("could not archive ticket %s: %w", ticket.Hash, err)
+			}
+		}
+
+		archived = len(tickets)
+		return nil
+	})
+
+	return archived, err
+}
+
+// GetArchivedTicket returns the archive record of the ticket with the provided
+// hash. The returned bool is false if the ticket has not been archived.
+func (vdb *VspDatabase) GetArchivedTicket(ticketHash string) (ArchivedTicket, bool, error) {
+	var ticket ArchivedTicket
+	var found bool
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(vspBktK).Bucket(archiveBktK).Bucket([]byte(ticketHash))
+		if bkt == nil {
+			return nil
+		}
+
+		var err error
+		ticket, err = getArchivedTicketFromBkt(bkt)
+		if err != nil {
+			return fmt.Errorf("could not get archived ticket: %w", err)
+		}
+
+		found = true
+
+		return nil
+	})
+
+	return ticket, found, err
+}
@@ -0,0 +1,129 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testArchiveTickets(t *testing.T) {
+	const (
+		blockHeight = 100000
+		retention   = 500
+	)
+
+	newTicket := func(purchaseHeight, outcomeHeight int64, outcome TicketOutcome) Ticket {
+		ticket := exampleTicket()
+		ticket.Confirmed = true
+		ticket.FeeTxStatus = FeeConfirmed
+		ticket.PurchaseHeight = purchaseHeight
+		ticket.Outcome = outcome
+		ticket.OutcomeHeight = outcomeHeight
+		return ticket
+	}
+
+	tests := map[string]struct {
+		ticket        Ticket
+		expectArchive bool
+	}{
+		"voted before retention period": {
+			ticket:        newTicket(90000, 90100, Voted),
+			expectArchive: true,
+		},
+		"missed at end of retention period": {
+			ticket:        newTicket(90000, blockHeight-retention, Missed),
+			expectArchive: true,
+		},
+		"expired with no outcome height": {
+			ticket:        newTicket(80000, 0, Expired),
+			expectArchive: true,
+		},
+		"voted during retention period": {
+			ticket:        newTicket(90000, blockHeight-retention+1, Voted),
+			expectArchive: false,
+		},
+		"purchased in last 28 days": {
+			ticket:        newTicket(blockHeight-blocksIn28Days, blockHeight-blocksIn28Days+10, Voted),
+			expectArchive: false,
+		},
+		"votable": {
+			ticket:        newTicket(80000, 0, ""),
+			expectArchive: false,
+		},
+		"fee not confirmed": {
+			ticket: func() Ticket {
+				ticket := newTicket(80000, 80100, Expired)
+				ticket.FeeTxStatus = FeeError
+				return ticket
+			}(),
+			expectArchive: false,
+		},
+	}
+
+	expectArchived := 0
+	for _, test := range tests {
+		err := db.InsertNewTicket(test.ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+		if test.expectArchive {
+			expectArchived++
+		}
+	}
+
+	statsBefore, err := db.TicketStats(blockHeight)
+	if err != nil {
+		t.Fatalf("error getting ticket stats: %v", err)
+	}
+
+	archived, err := db.ArchiveTickets(blockHeight, retention)
+	if err != nil {
+		t.Fatalf("error archiving tickets: %v", err)
+	}
+	if archived != expectArchived {
+		t.Fatalf("expected %d tickets to be archived, got %d", expectArchived, archived)
+	}
+
+	for testName, test := range tests {
+		_, found, err := db.GetTicketByHash(test.ticket.Hash)
+		if err != nil {
+			t.Fatalf("%s: error retrieving ticket: %v", testName, err)
+		}
+		if found == test.expectArchive {
+			t.Fatalf("%s: expected ticket found=%t, got %t", testName, !test.expectArchive, found)
+		}
+
+		archivedTkt, found, err := db.GetArchivedTicket(test.ticket.Hash)
+		if err != nil {
+			t.Fatalf("%s: error retrieving archived ticket: %v", testName, err)
+		}
+		if found != test.expectArchive {
+			t.Fatalf("%s: expected archived ticket found=%t, got %t", testName, test.expectArchive, found)
+		}
+		if found && !reflect.DeepEqual(archivedTicket(test.ticket), archivedTkt) {
+			t.Fatalf("%s: expected archived ticket %v, got %v",
+				testName, archivedTicket(test.ticket), archivedTkt)
+		}
+	}
+
+	// Archived tickets must still be included in stats.
+	statsAfter, err := db.TicketStats(blockHeight)
+	if err != nil {
+		t.Fatalf("error getting ticket stats: %v", err)
+	}
+	if statsBefore != statsAfter {
+		t.Fatalf("expected ticket stats %+v after archiving, got %+v", statsBefore, statsAfter)
+	}
+
+	// Running again should not archive anything else.
+	archived, err = db.ArchiveTickets(blockHeight, retention)
+	if err != nil {
+		t.Fatalf("error archiving tickets: %v", err)
+	}
+	if archived != 0 {
+		t.Fatalf("expected no tickets to be archived, got %d", archived)
+	}
+}
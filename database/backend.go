@@ -0,0 +1,180 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/decred/slog"
+)
+
+// Database is implemented by every storage backend supported by vspd. It
+// contains everything vspd needs to store and retrieve, and each backend must
+// behave identically.
+type Database interface {
+	// Tickets.
+	InsertNewTicket(ticket Ticket) error
+	UpdateTicket(ticket Ticket) error
+	DeleteTicket(ticket Ticket) error
+	PurgeTicket(ticket Ticket) error
+	GetTicketByHash(ticketHash string) (Ticket, bool, error)
+	TicketStats(blockHeight int64) (TicketStats, error)
+	FeePayments() ([]FeePayment, error)
+	ListTickets(filter TicketFilter) (TicketList, error)
+	GetUnconfirmedTickets() (TicketList, error)
+	GetPendingFees() (TicketList, error)
+	GetUnconfirmedFees() (TicketList, error)
+	GetRecoverableFees() (TicketList, error)
+	GetVotableTickets() (TicketList, error)
+	GetVotedTickets() (TicketList, error)
+	GetRevokedTickets() (TicketList, error)
+	GetMissingPurchaseHeight() (TicketList, error)
+	GetMissedTickets() (TicketList, error)
+
+	// Archived tickets.
+	ArchiveTickets(blockHeight, retention int64) (int, error)
+	GetArchivedTicket(ticketHash string) (ArchivedTicket, bool, error)
+
+	// Vote changes.
+	SaveVoteChange(ticketHash string, record VoteChangeRecord) error
+	GetVoteChanges(ticketHash string) (map[uint32]VoteChangeRecord, error)
+	UpdateVoteChoices(updates []VoteChoicesUpdate) error
+	DeleteVoteChanges(ticketHash string) error
+
+	// Alternate signing addresses.
+	InsertAltSignAddr(ticketHash string, data *AltSignAddrData) error
+	AltSignAddrData(ticketHash string) (*AltSignAddrData, error)
+	DeleteAltSignAddr(ticketHash string) error
+
+	// Ticket event history.
+	AppendTicketEvent(ticketHash string, event TicketEvent) error
+	TicketEvents(ticketHash string) ([]TicketEvent, error)
+	DeleteTicketEvents(ticketHash string) error
+
+	// Fee xpubs.
+	FeeXPub() (FeeXPub, error)
+	RetireXPub(xpub string) error
+	AddXPub(xpub string) error
+	RetireXPubID(id uint32) error
+	AllXPubs() (map[uint32]FeeXPub, error)
+	SetLastAddressIndex(xpubID, idx uint32) error
+	FeeAddresses() ([]FeeAddressRecord, error)
+
+	// Key material.
+	KeyPair() (ed25519.PrivateKey, ed25519.PublicKey, error)
+	CookieSecret() ([]byte, error)
+
+	// Voting WIF encryption.
+	WIFsEncrypted() bool
+	UnlockWIFs(passphrase []byte) error
+	EncryptWIFs(passphrase []byte) error
+	RotateWIFKey(oldPassphrase, newPassphrase []byte) error
+
+	// Webhook queue.
+	QueueWebhook(delivery WebhookDelivery) (uint64, error)
+	UpdateWebhook(id uint64, delivery WebhookDelivery) error
+	DeleteWebhook(id uint64) error
+	PendingWebhooks() (map[uint64]WebhookDelivery, error)
+
+	// VSP status.
+	SetVspStatus(change VspStatusChange) error
+	VspStatus() (VspStatusChange, bool, error)
+	VspStatusHistory() ([]VspStatusChange, error)
+
+	// Maintenance windows.
+	AddMaintenanceWindow(window MaintenanceWindow) (uint64, error)
+	DeleteMaintenanceWindow(id uint64) error
+	MaintenanceWindows() (map[uint64]MaintenanceWindow, error)
+
+	// Backup and housekeeping.
+	Version() (uint32, error)
+	Size() (uint64, error)
+	BackupDB(w http.ResponseWriter) error
+	WriteHotBackupFile() error
+	Export(w io.Writer, includeSecrets bool) error
+	Close(writeBackup bool)
+}
+
+// Backend identifies one of the storage backends which implement Database.
+type Backend string
+
+const (
+	// BoltBackend stores the database in a single bbolt file. This is the
+	// default backend.
+	BoltBackend Backend = "bolt"
+	// SQLiteBackend stores the database in a single SQLite file, which can also
+	// be queried directly using standard SQLite tools.
+	SQLiteBackend Backend = "sqlite"
+)
+
+// Backends lists every supported storage backend.
+var Backends = []Backend{BoltBackend, SQLiteBackend}
+
+// ParseBackend returns the storage backend with the provided name.
+func ParseBackend(name string) (Backend, error) {
+	for _, backend := range Backends {
+		if string(backend) == name {
+			return backend, nil
+		}
+	}
+	return "", fmt.Errorf("unknown database backend %q, expected one of %v", name, Backends)
+}
+
+// CreateNew initializes a new database file using the storage backend. See the
+// package level CreateNew for details.
+func (b Backend) CreateNew(dbFile, feeXPub string) error {
+	switch b {
+	case BoltBackend:
+		return CreateNew(dbFile, feeXPub)
+	case SQLiteBackend:
+		return CreateNewSQLite(dbFile, feeXPub)
+	default:
+		return fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// Open opens an existing database file using the storage backend.
+func (b Backend) Open(dbFile string, log slog.Logger, maxVoteChangeRecords int) (Database, error) {
+	// Avoid returning a non-nil interface containing a nil pointer when the
+	// backend returns an error.
+	switch b {
+	case BoltBackend:
+		vdb, err := Open(dbFile, log, maxVoteChangeRecords)
+		if err != nil {
+			return nil, err
+		}
+		return vdb, nil
+	case SQLiteBackend:
+		sdb, err := OpenSQLite(dbFile, log, maxVoteChangeRecords)
+		if err != nil {
+			return nil, err
+		}
+		return sdb, nil
+	default:
+		return nil, fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// Import creates a new database file using the storage backend, containing the
+// data read from an export. See the package level Import for details.
+func (b Backend) Import(dbFile string, r io.Reader) error {
+	switch b {
+	case BoltBackend:
+		return Import(dbFile, r)
+	case SQLiteBackend:
+		return ImportSQLite(dbFile, r)
+	default:
+		return fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// Ensure both backends implement Database.
+var (
+	_ Database = (*VspDatabase)(nil)
+	_ Database = (*SQLiteDatabase)(nil)
+)
@@ -26,6 +26,7 @@ type VspDatabase struct {
 	db                   *bolt.DB
 	maxVoteChangeRecords int
 	log                  slog.Logger
+	wifs                 wifEncryption
 }
 
 // The keys used in the database.
@@ -48,6 +49,24 @@ var (
 	privateKeyK = []byte("privatekey")
 	// altSignAddrBktK stores alternate signing addresses.
 	altSignAddrBktK = []byte("altsigbkt")
+	// webhookBktK stores webhook deliveries which have not yet succeeded.
+	webhookBktK = []byte("webhookbkt")
+	// ticketEventBktK stores the history of state changes of each ticket.
+	ticketEventBktK = []byte("ticketeventbkt")
+	// ticketIdxBktK stores indexes of tickets by fee status, outcome and
+	// confirmed flag.
+	ticketIdxBktK = []byte("ticketidxbkt")
+	// archiveBktK stores compact records of tickets which have been archived,
+	// and running totals used to calculate ticket stats.
+	archiveBktK = []byte("archivebkt")
+	// vspStatusBktK stores the history of changes to the open or closed status
+	// of the VSP made while vspd is running.
+	vspStatusBktK = []byte("vspstatusbkt")
+	// maintenanceBktK stores maintenance windows scheduled by an admin.
+	maintenanceBktK = []byte("maintenancebkt")
+	// wifKeyK stores the parameters of the key used to encrypt voting WIFs.
+	// It is only present if voting WIFs are encrypted.
+	wifKeyK = []byte("wifkey")
 )
 
 const (
@@ -58,13 +77,19 @@ const (
 // backupMtx should be held when writing to the database backup file.
 var backupMtx sync.Mutex
 
+// HotBackupFile returns the path of the backup file which is periodically
+// written for the database at dbFile.
+func HotBackupFile(dbFile string) string {
+	return dbFile + "-backup"
+}
+
 // WriteHotBackupFile writes a backup of the database file while the database
 // is still open.
 func (vdb *VspDatabase) WriteHotBackupFile() error {
 	backupMtx.Lock()
 	defer backupMtx.Unlock()
 
-	backupPath := vdb.db.Path() + "-backup"
+	backupPath := HotBackupFile(vdb.db.Path())
 	tempPath := backupPath + "~"
 
 	// Write backup to temporary file.
@@ -165,6 +190,42 @@ func CreateNew(dbFile, feeXPub string) error {
 			return fmt.Errorf("failed to create %s bucket: %w", altSignAddrBktK, err)
 		}
 
+		// Create webhook queue bucket (added in upgrade to v6).
+		_, err = vspBkt.CreateBucket(webhookBktK)
+		if err != nil {
+			return fmt.Errorf("failed to create %s bucket: %w", webhookBktK, err)
+		}
+
+		// Create ticket event bucket (added in upgrade to v7).
+		_, err = vspBkt.CreateBucket(ticketEventBktK)
+		if err != nil {
+			return fmt.Errorf("failed to create %s bucket: %w", ticketEventBktK, err)
+		}
+
+		// Create ticket index buckets (added in upgrade to v8).
+		err = createTicketIndexBuckets(vspBkt)
+		if err != nil {
+			return err
+		}
+
+		// Create archive bucket (added in upgrade to v9).
+		err = createArchiveBucket(vspBkt)
+		if err != nil {
+			return err
+		}
+
+		// Create VSP status bucket (added in upgrade to v10).
+		err = createVspStatusBucket(vspBkt)
+		if err != nil {
+			return err
+		}
+
+		// Create maintenance window bucket (added in upgrade to v11).
+		err = createMaintenanceBucket(vspBkt)
+		if err != nil {
+			return err
+		}
+
 		return nil
 	})
 
@@ -221,6 +282,15 @@ func Open(dbFile string, log slog.Logger, maxVoteChangeRecords int) (*VspDatabas
 		return nil, fmt.Errorf("upgrade failed: %w", err)
 	}
 
+	err = vdb.loadWIFKey()
+	if err != nil {
+		closeErr := vdb.db.Close()
+		if closeErr != nil {
+			log.Errorf("Error closing database: %v", closeErr)
+		}
+		return nil, fmt.Errorf("unable to load voting WIF key: %w", err)
+	}
+
 	return vdb, nil
 }
 
@@ -250,7 +320,7 @@ func (vdb *VspDatabase) Close(writeBackup bool) {
 	}
 
 	// Ensure the database backup file is up-to-date.
-	backupPath := dbPath + "-backup"
+	backupPath := HotBackupFile(dbPath)
 	tempPath := backupPath + "~"
 
 	backupMtx.Lock()
@@ -309,6 +379,12 @@ func (vdb *VspDatabase) KeyPair() (ed25519.PrivateKey, ed25519.PublicKey, error)
 		return nil, nil, err
 	}
 
+	return keyPairFromSeed(seed)
+}
+
+// keyPairFromSeed derives the ed25519 keypair used to sign API responses from
+// the seed stored in the database.
+func keyPairFromSeed(seed []byte) (ed25519.PrivateKey, ed25519.PublicKey, error) {
 	signKey := ed25519.NewKeyFromSeed(seed)
 
 	// Derive pubKey from signKey
@@ -317,7 +393,7 @@ func (vdb *VspDatabase) KeyPair() (ed25519.PrivateKey, ed25519.PublicKey, error)
 		return nil, nil, fmt.Errorf("failed to cast signing key: %T", pubKey)
 	}
 
-	return signKey, pubKey, err
+	return signKey, pubKey, nil
 }
 
 // CookieSecret retrieves the generated cookie store secret key from the
@@ -33,7 +33,8 @@ const (
 )
 
 var (
-	db         *VspDatabase
+	db         Database
+	backend    Backend
 	seededRand = rand.New(rand.NewSource(time.Now().UnixNano()))
 )
 
@@ -63,54 +64,100 @@ func stdoutLogger() slog.Logger {
 	return log
 }
 
-// TestDatabase runs all database tests.
+// TestDatabase runs all database tests against every storage backend.
 func TestDatabase(t *testing.T) {
 	// Ensure we are starting with a clean environment.
 	os.Remove(testDb)
 
 	// All sub-tests to run.
 	tests := map[string]func(*testing.T){
-		"testCreateNew":          testCreateNew,
-		"testInsertNewTicket":    testInsertNewTicket,
-		"testGetTicketByHash":    testGetTicketByHash,
-		"testUpdateTicket":       testUpdateTicket,
-		"testTicketFeeExpired":   testTicketFeeExpired,
-		"testFilterTickets":      testFilterTickets,
-		"testTicketStatsCounts":  testTicketStatsCounts,
-		"testTicketStatsRevenue": testTicketStatsRevenue,
-		"testFeeXPub":            testFeeXPub,
-		"testRetireFeeXPub":      testRetireFeeXPub,
-		"testDeleteTicket":       testDeleteTicket,
-		"testVoteChangeRecords":  testVoteChangeRecords,
-		"testDeleteVoteChanges":  testDeleteVoteChanges,
-		"testHTTPBackup":         testHTTPBackup,
-		"testAltSignAddrData":    testAltSignAddrData,
-		"testInsertAltSignAddr":  testInsertAltSignAddr,
-		"testDeleteAltSignAddr":  testDeleteAltSignAddr,
+		"testCreateNew":             testCreateNew,
+		"testInsertNewTicket":       testInsertNewTicket,
+		"testGetTicketByHash":       testGetTicketByHash,
+		"testUpdateTicket":          testUpdateTicket,
+		"testTicketFeeExpired":      testTicketFeeExpired,
+		"testListTickets":           testListTickets,
+		"testGetRecoverableFees":    testGetRecoverableFees,
+		"testTicketStatsCounts":     testTicketStatsCounts,
+		"testTicketStatsRevenue":    testTicketStatsRevenue,
+		"testFeeXPub":               testFeeXPub,
+		"testRetireFeeXPub":         testRetireFeeXPub,
+		"testMultipleActiveXPubs":   testMultipleActiveXPubs,
+		"testDeleteTicket":          testDeleteTicket,
+		"testPurgeTicket":           testPurgeTicket,
+		"testVoteChangeRecords":     testVoteChangeRecords,
+		"testDeleteVoteChanges":     testDeleteVoteChanges,
+		"testUpdateVoteChoices":     testUpdateVoteChoices,
+		"testHTTPBackup":            testHTTPBackup,
+		"testHotBackup":             testHotBackup,
+		"testAltSignAddrData":       testAltSignAddrData,
+		"testInsertAltSignAddr":     testInsertAltSignAddr,
+		"testDeleteAltSignAddr":     testDeleteAltSignAddr,
+		"testWebhookQueue":          testWebhookQueue,
+		"testTicketEvents":          testTicketEvents,
+		"testArchiveTickets":        testArchiveTickets,
+		"testExportImport":          testExportImport,
+		"testExportImportNoSecrets": testExportImportNoSecrets,
+		"testImportInvalid":         testImportInvalid,
+		"testWIFEncryption":         testWIFEncryption,
+		"testVerifyBackup":          testVerifyBackup,
+		"testCheckFeeAddresses":     testCheckFeeAddresses,
+		"testFeePayments":           testFeePayments,
+		"testVspStatus":             testVspStatus,
+		"testMaintenanceWindows":    testMaintenanceWindows,
 	}
 
-	log := stdoutLogger()
+	// Sub-tests which depend on the implementation of a single backend.
+	backendTests := map[Backend]map[string]func(*testing.T){
+		BoltBackend: {
+			"testFilterTickets": testFilterTickets,
+			"testTicketIndexes": testTicketIndexes,
+			"testUpgrade":       testUpgrade,
+		},
+		SQLiteBackend: {
+			"testSQLiteUpgrade": testSQLiteUpgrade,
+		},
+	}
 
-	for testName, test := range tests {
+	log := stdoutLogger()
 
-		// Create a new blank database for each sub-test.
-		err := CreateNew(testDb, feeXPub)
-		if err != nil {
-			t.Fatalf("error creating test database: %v", err)
+	for _, backend = range Backends {
+		for testName, test := range tests {
+			runTest(t, log, string(backend)+"/"+testName, test)
 		}
-
-		// Open the newly created database so it is ready to use.
-		db, err = Open(testDb, log, maxVoteChangeRecords)
-		if err != nil {
-			t.Fatalf("error opening test database: %v", err)
+		for testName, test := range backendTests[backend] {
+			runTest(t, log, string(backend)+"/"+testName, test)
 		}
+	}
+}
+
+// runTest runs a single sub-test against a new blank database created with the
+// current backend.
+func runTest(t *testing.T, log slog.Logger, testName string, test func(*testing.T)) {
+	err := backend.CreateNew(testDb, feeXPub)
+	if err != nil {
+		t.Fatalf("error creating test database: %v", err)
+	}
 
-		// Run the sub-test.
-		t.Run(testName, test)
+	// Open the newly created database so it is ready to use.
+	db, err = backend.Open(testDb, log, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error opening test database: %v", err)
+	}
+
+	// Run the sub-test.
+	t.Run(testName, test)
+
+	writeBackup := false
+	db.Close(writeBackup)
+	removeDatabaseFiles(testDb)
+}
 
-		writeBackup := false
-		db.Close(writeBackup)
-		os.Remove(testDb)
+// removeDatabaseFiles removes a database file along with any other files
+// created alongside it by the backends.
+func removeDatabaseFiles(dbFile string) {
+	for _, suffix := range []string{"", "-wal", "-shm", "-backup"} {
+		os.Remove(dbFile + suffix)
 	}
 }
 
@@ -163,6 +210,9 @@ func testHTTPBackup(t *testing.T) {
 
 	header = "Content-Disposition"
 	expected = `attachment; filename="vspd.db"`
+	if backend == SQLiteBackend {
+		expected = `attachment; filename="vspd.sqlite"`
+	}
 	if actual := rr.Header().Get(header); actual != expected {
 		t.Errorf("wrong %s header: expected %s, got %s",
 			header, expected, actual)
@@ -189,3 +239,44 @@ func testHTTPBackup(t *testing.T) {
 			cLength, len(body))
 	}
 }
+
+func testHotBackup(t *testing.T) {
+	ticket := exampleTicket()
+	err := db.InsertNewTicket(ticket)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+
+	err = db.WriteHotBackupFile()
+	if err != nil {
+		t.Fatalf("error writing backup: %v", err)
+	}
+
+	// The backup should be a complete database which can be opened.
+	const backupDb = testDb + "-backup"
+	copyDb := testDb + "-copy"
+	defer removeDatabaseFiles(copyDb)
+
+	backupBytes, err := os.ReadFile(backupDb)
+	if err != nil {
+		t.Fatalf("error reading backup: %v", err)
+	}
+	err = os.WriteFile(copyDb, backupBytes, 0600)
+	if err != nil {
+		t.Fatalf("error copying backup: %v", err)
+	}
+
+	backup, err := backend.Open(copyDb, slog.Disabled, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error opening backup: %v", err)
+	}
+	defer backup.Close(false)
+
+	_, found, err := backup.GetTicketByHash(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket from backup: %v", err)
+	}
+	if !found {
+		t.Fatal("expected ticket to be found in backup")
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2022-2023 The Decred developers
+// Copyright (c) 2022-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -58,6 +58,18 @@ func bytesToUint32(bytes []byte) uint32 {
 	return binary.LittleEndian.Uint32(bytes)
 }
 
+// uint64ToBytes uses big endian encoding, unlike the other integer encoding
+// funcs, so that bbolt iterates over keys encoded with it in ascending order.
+func uint64ToBytes(i uint64) []byte {
+	bytes := make([]byte, 8)
+	binary.BigEndian.PutUint64(bytes, i)
+	return bytes
+}
+
+func bytesToUint64(bytes []byte) uint64 {
+	return binary.BigEndian.Uint64(bytes)
+}
+
 func bytesToBool(bytes []byte) bool {
 	return bytes[0] == 1
 }
@@ -0,0 +1,632 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// ExportVersion is the version of the format written by Export. It must be
+// incremented whenever a change is made to the format which would prevent older
+// versions of Import from reading it correctly.
+const ExportVersion = 6
+
+// exportFormat identifies files written by Export.
+const exportFormat = "vspd-export"
+
+// ExportRecordType identifies the kind of data held in a single ExportRecord.
+type ExportRecordType string
+
+const (
+	// RecordHeader is always the first record of an export. Its data is an
+	// ExportHeader.
+	RecordHeader ExportRecordType = "header"
+	// RecordSigningKey holds the hex encoded seed of the ed25519 key used to
+	// sign API responses. Only exported if secrets are requested.
+	RecordSigningKey ExportRecordType = "signingkey"
+	// RecordCookieSecret holds the hex encoded secret used to initialize the
+	// cookie store. Only exported if secrets are requested.
+	RecordCookieSecret ExportRecordType = "cookiesecret"
+	// RecordFeeXPub holds a FeeXPub.
+	RecordFeeXPub ExportRecordType = "feexpub"
+	// RecordTicket holds a Ticket.
+	RecordTicket ExportRecordType = "ticket"
+	// RecordVoteChange holds an ExportVoteChange.
+	RecordVoteChange ExportRecordType = "votechange"
+	// RecordAltSignAddr holds an ExportAltSignAddr.
+	RecordAltSignAddr ExportRecordType = "altsignaddr"
+	// RecordTicketEvent holds an ExportTicketEvent.
+	RecordTicketEvent ExportRecordType = "ticketevent"
+	// RecordArchivedTicket holds an ArchivedTicket. Added in version 2.
+	RecordArchivedTicket ExportRecordType = "archivedticket"
+	// RecordWIFKey holds an ExportWIFKey. Only present if voting WIFs are
+	// encrypted, in which case the voting WIFs of ticket records are exported
+	// in their encrypted form. Added in version 3.
+	RecordWIFKey ExportRecordType = "wifkey"
+	// RecordVspStatusChange holds a VspStatusChange. Records are exported in
+	// the order the changes were made. Added in version 4.
+	RecordVspStatusChange ExportRecordType = "vspstatuschange"
+	// RecordMaintenanceWindow holds a MaintenanceWindow. Windows are given new
+	// IDs when imported. Added in version 5.
+	RecordMaintenanceWindow ExportRecordType = "maintenancewindow"
+)
+
+// ExportRecord is a single line of an export. Exports are written in the JSON
+// lines format, with each line holding one record.
+type ExportRecord struct {
+	Type ExportRecordType `json:"type"`
+	Data json.RawMessage  `json:"data"`
+}
+
+// ExportHeader describes the contents of an export. Backend was added in
+// version 6, and is required to interpret DatabaseVersion because each storage
+// backend has its own versions.
+type ExportHeader struct {
+	Format          string  `json:"format"`
+	Version         uint32  `json:"version"`
+	Backend         Backend `json:"backend"`
+	DatabaseVersion uint32  `json:"dbversion"`
+	Timestamp       int64   `json:"timestamp"`
+	IncludesSecrets bool    `json:"secrets"`
+}
+
+// ExportVoteChange is a single vote change record of a ticket.
+type ExportVoteChange struct {
+	TicketHash string           `json:"tickethash"`
+	Index      uint32           `json:"index"`
+	Record     VoteChangeRecord `json:"record"`
+}
+
+// ExportAltSignAddr is the alternate signing address of a ticket. The original
+// request and response are base64 encoded so that they are reproduced exactly,
+// which is necessary for their signatures to remain valid.
+type ExportAltSignAddr struct {
+	TicketHash  string `json:"tickethash"`
+	AltSignAddr string `json:"altsignaddr"`
+	Req         []byte `json:"req"`
+	ReqSig      string `json:"reqsig"`
+	Resp        []byte `json:"resp"`
+	RespSig     string `json:"respsig"`
+}
+
+// ExportTicketEvent is a single event from the history of a ticket.
+type ExportTicketEvent struct {
+	TicketHash string      `json:"tickethash"`
+	Event      TicketEvent `json:"event"`
+}
+
+// ExportWIFKey holds the parameters needed to derive the voting WIF encryption
+// key from its passphrase. It does not contain the passphrase or the key.
+type ExportWIFKey struct {
+	Salt  []byte `json:"salt"`
+	Check []byte `json:"check"`
+}
+
+// exportEncoder writes export records to an underlying writer.
+type exportEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *exportEncoder) write(recordType ExportRecordType, data any) error {
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("could not marshal %s: %w", recordType, err)
+	}
+
+	return e.enc.Encode(ExportRecord{Type: recordType, Data: dataBytes})
+}
+
+// Export writes the contents of the database to w in a portable JSON lines
+// format. The signing key, cookie secret and the voting WIFs of tickets are
+// only included if includeSecrets is true. Pending webhook deliveries are not
+// exported.
+func (vdb *VspDatabase) Export(w io.Writer, includeSecrets bool) error {
+	e := &exportEncoder{enc: json.NewEncoder(w)}
+
+	return vdb.db.View(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+
+		err := e.write(RecordHeader, ExportHeader{
+			Format:          exportFormat,
+			Version:         ExportVersion,
+			Backend:         BoltBackend,
+			DatabaseVersion: bytesToUint32(vspBkt.Get(versionK)),
+			Timestamp:       time.Now().Unix(),
+			IncludesSecrets: includeSecrets,
+		})
+		if err != nil {
+			return err
+		}
+
+		if includeSecrets {
+			err = e.write(RecordSigningKey, hex.EncodeToString(vspBkt.Get(privateKeyK)))
+			if err != nil {
+				return err
+			}
+			err = e.write(RecordCookieSecret, hex.EncodeToString(vspBkt.Get(cookieSecretK)))
+			if err != nil {
+				return err
+			}
+		}
+
+		params, err := bytesToWIFKeyParams(vspBkt.Get(wifKeyK))
+		if err != nil {
+			return err
+		}
+		if params != nil {
+			err = e.write(RecordWIFKey, ExportWIFKey(*params))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = vspBkt.Bucket(xPubBktK).ForEach(func(_, v []byte) error {
+			var xpub FeeXPub
+			err := json.Unmarshal(v, &xpub)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal xpub key: %w", err)
+			}
+			return e.write(RecordFeeXPub, xpub)
+		})
+		if err != nil {
+			return err
+		}
+
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+		err = ticketBkt.ForEachBucket(func(k []byte) error {
+			ticket, err := getTicketFromBkt(ticketBkt.Bucket(k))
+			if err != nil {
+				return fmt.Errorf("could not get ticket: %w", err)
+			}
+			if !includeSecrets {
+				ticket.VotingWIF = ""
+			}
+			return e.write(RecordTicket, ticket)
+		})
+		if err != nil {
+			return err
+		}
+
+		archiveBkt := vspBkt.Bucket(archiveBktK)
+		err = archiveBkt.ForEachBucket(func(k []byte) error {
+			ticket, err := getArchivedTicketFromBkt(archiveBkt.Bucket(k))
+			if err != nil {
+				return fmt.Errorf("could not get archived ticket: %w", err)
+			}
+			return e.write(RecordArchivedTicket, ticket)
+		})
+		if err != nil {
+			return err
+		}
+
+		voteChangeBkt := vspBkt.Bucket(voteChangeBktK)
+		err = voteChangeBkt.ForEachBucket(func(hash []byte) error {
+			return voteChangeBkt.Bucket(hash).ForEach(func(k, v []byte) error {
+				var record VoteChangeRecord
+				err := json.Unmarshal(v, &record)
+				if err != nil {
+					return fmt.Errorf("could not unmarshal vote change record: %w", err)
+				}
+				return e.write(RecordVoteChange, ExportVoteChange{
+					TicketHash: string(hash),
+					Index:      bytesToUint32(k),
+					Record:     record,
+				})
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		altSignAddrBkt := vspBkt.Bucket(altSignAddrBktK)
+		err = altSignAddrBkt.ForEachBucket(func(hash []byte) error {
+			data := getAltSignAddrFromBkt(altSignAddrBkt.Bucket(hash))
+			return e.write(RecordAltSignAddr, ExportAltSignAddr{
+				TicketHash:  string(hash),
+				AltSignAddr: data.AltSignAddr,
+				Req:         []byte(data.Req),
+				ReqSig:      data.ReqSig,
+				Resp:        []byte(data.Resp),
+				RespSig:     data.RespSig,
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		eventBkt := vspBkt.Bucket(ticketEventBktK)
+		err = eventBkt.ForEachBucket(func(hash []byte) error {
+			return eventBkt.Bucket(hash).ForEach(func(_, v []byte) error {
+				var event TicketEvent
+				err := json.Unmarshal(v, &event)
+				if err != nil {
+					return fmt.Errorf("could not unmarshal ticket event: %w", err)
+				}
+				return e.write(RecordTicketEvent, ExportTicketEvent{
+					TicketHash: string(hash),
+					Event:      event,
+				})
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		err = vspBkt.Bucket(vspStatusBktK).ForEach(func(_, v []byte) error {
+			var change VspStatusChange
+			err := json.Unmarshal(v, &change)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal VSP status change: %w", err)
+			}
+			return e.write(RecordVspStatusChange, change)
+		})
+		if err != nil {
+			return err
+		}
+
+		return vspBkt.Bucket(maintenanceBktK).ForEach(func(_, v []byte) error {
+			var window MaintenanceWindow
+			err := json.Unmarshal(v, &window)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal maintenance window: %w", err)
+			}
+			return e.write(RecordMaintenanceWindow, window)
+		})
+	})
+}
+
+// importer is implemented by each storage backend to store the records read
+// from an export into a newly created database.
+type importer interface {
+	putSigningKey(seed []byte) error
+	putCookieSecret(secret []byte) error
+	putWIFKey(params *wifKeyParams) error
+	putFeeXPub(xpub FeeXPub) error
+	putTicket(ticket Ticket) error
+	putArchivedTicket(ticket ArchivedTicket) error
+	putVoteChange(ticketHash string, index uint32, record VoteChangeRecord) error
+	putAltSignAddr(ticketHash string, data *AltSignAddrData) error
+	appendTicketEvent(ticketHash string, event TicketEvent) error
+	appendVspStatusChange(change VspStatusChange) error
+	putMaintenanceWindow(window MaintenanceWindow) error
+}
+
+// Import creates a new bbolt database at dbFile containing the data read from
+// r, which must be in the format written by Export. An error is returned if
+// dbFile already exists. If the export does not include secrets, a new signing
+// key and cookie secret are generated, and tickets are imported without their
+// voting WIFs.
+func Import(dbFile string, r io.Reader) error {
+	return importNew(dbFile, r, CreateNew, importBolt)
+}
+
+// importNew creates a new database at dbFile with createNew and then populates
+// it with importRecords, removing the database again if anything fails.
+func importNew(dbFile string, r io.Reader, createNew func(dbFile, feeXPub string) error,
+	importRecords func(dbFile string, r io.Reader) error) error {
+
+	if _, err := os.Stat(dbFile); err == nil {
+		return fmt.Errorf("database file %s already exists", dbFile)
+	}
+
+	// Create a new empty database so that everything required by vspd already
+	// exists. The placeholder xpub is removed during import.
+	err := createNew(dbFile, "")
+	if err != nil {
+		return err
+	}
+
+	err = importRecords(dbFile, r)
+	if err != nil {
+		// Don't leave a partially imported database behind.
+		os.Remove(dbFile)
+		return err
+	}
+
+	return nil
+}
+
+func importBolt(dbFile string, r io.Reader) error {
+	db, err := bolt.Open(dbFile, 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return fmt.Errorf("unable to open db file: %w", err)
+	}
+	defer db.Close()
+
+	return db.Update(func(tx *bolt.Tx) error {
+		// Remove the placeholder xpub inserted by CreateNew.
+		err := tx.Bucket(vspBktK).DeleteBucket(xPubBktK)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s bucket: %w", xPubBktK, err)
+		}
+
+		return importRecords(r, boltImporter{tx})
+	})
+}
+
+// importRecords reads every record of an export from r and stores it using the
+// provided importer.
+func importRecords(r io.Reader, imp importer) error {
+	dec := json.NewDecoder(r)
+	var count, xpubs int
+	for {
+		var record ExportRecord
+		err := dec.Decode(&record)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("could not decode record %d: %w", count, err)
+		}
+
+		// The first record must be a header with a supported version.
+		if count == 0 {
+			if record.Type != RecordHeader {
+				return errors.New("export does not begin with a header")
+			}
+			var header ExportHeader
+			err = json.Unmarshal(record.Data, &header)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal header: %w", err)
+			}
+			if header.Format != exportFormat {
+				return fmt.Errorf("unknown export format %q", header.Format)
+			}
+			if header.Version > ExportVersion {
+				return fmt.Errorf("export version %d is not supported, maximum is %d",
+					header.Version, ExportVersion)
+			}
+			err = checkExportDatabaseVersion(header)
+			if err != nil {
+				return err
+			}
+			count++
+			continue
+		}
+
+		if record.Type == RecordFeeXPub {
+			xpubs++
+		}
+
+		err = importRecord(imp, record)
+		if err != nil {
+			return fmt.Errorf("could not import %s record %d: %w", record.Type, count, err)
+		}
+		count++
+	}
+
+	if count == 0 {
+		return errors.New("export is empty")
+	}
+
+	if xpubs == 0 {
+		return errors.New("export does not contain any fee xpubs")
+	}
+
+	return nil
+}
+
+// latestDatabaseVersion returns the latest database version of a storage
+// backend, which is the version of every database it creates or imports.
+func latestDatabaseVersion(b Backend) (uint32, error) {
+	switch b {
+	case BoltBackend:
+		return latestVersion, nil
+	case SQLiteBackend:
+		return sqliteLatestVersion, nil
+	default:
+		return 0, fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// checkExportDatabaseVersion returns an error if the database described by the
+// header of an export was not at the latest version of its backend. Records are
+// imported without being upgraded, so they must already be in the format of
+// the latest version. An older database can be upgraded by opening it with this
+// version of vspd before exporting it again.
+func checkExportDatabaseVersion(header ExportHeader) error {
+	if header.Backend == "" {
+		return errors.New("export does not identify its database backend, " +
+			"it must be exported again with this version of vspadmin")
+	}
+	latest, err := latestDatabaseVersion(header.Backend)
+	if err != nil {
+		return err
+	}
+	if header.DatabaseVersion != latest {
+		return fmt.Errorf("export of %s database version %d is not supported, "+
+			"the database must be upgraded to version %d and exported again",
+			header.Backend, header.DatabaseVersion, latest)
+	}
+	return nil
+}
+
+func importRecord(imp importer, record ExportRecord) error {
+	switch record.Type {
+	case RecordSigningKey, RecordCookieSecret:
+		var hexStr string
+		err := json.Unmarshal(record.Data, &hexStr)
+		if err != nil {
+			return err
+		}
+		value, err := hex.DecodeString(hexStr)
+		if err != nil {
+			return err
+		}
+		if record.Type == RecordCookieSecret {
+			return imp.putCookieSecret(value)
+		}
+		return imp.putSigningKey(value)
+
+	case RecordWIFKey:
+		var wifKey ExportWIFKey
+		err := json.Unmarshal(record.Data, &wifKey)
+		if err != nil {
+			return err
+		}
+		params := wifKeyParams(wifKey)
+		return imp.putWIFKey(&params)
+
+	case RecordFeeXPub:
+		var xpub FeeXPub
+		err := json.Unmarshal(record.Data, &xpub)
+		if err != nil {
+			return err
+		}
+		return imp.putFeeXPub(xpub)
+
+	case RecordTicket:
+		var ticket Ticket
+		err := json.Unmarshal(record.Data, &ticket)
+		if err != nil {
+			return err
+		}
+		return imp.putTicket(ticket)
+	case RecordArchivedTicket:
+		var ticket ArchivedTicket
+		err := json.Unmarshal(record.Data, &ticket)
+		if err != nil {
+			return err
+		}
+		return imp.putArchivedTicket(ticket)
+
+	case RecordVoteChange:
+		var change ExportVoteChange
+		err := json.Unmarshal(record.Data, &change)
+		if err != nil {
+			return err
+		}
+		return imp.putVoteChange(change.TicketHash, change.Index, change.Record)
+
+	case RecordAltSignAddr:
+		var altSig ExportAltSignAddr
+		err := json.Unmarshal(record.Data, &altSig)
+		if err != nil {
+			return err
+		}
+		return imp.putAltSignAddr(altSig.TicketHash, &AltSignAddrData{
+			AltSignAddr: altSig.AltSignAddr,
+			Req:         string(altSig.Req),
+			ReqSig:      altSig.ReqSig,
+			Resp:        string(altSig.Resp),
+			RespSig:     altSig.RespSig,
+		})
+
+	case RecordTicketEvent:
+		var event ExportTicketEvent
+		err := json.Unmarshal(record.Data, &event)
+		if err != nil {
+			return err
+		}
+		return imp.appendTicketEvent(event.TicketHash, event.Event)
+
+	case RecordVspStatusChange:
+		var change VspStatusChange
+		err := json.Unmarshal(record.Data, &change)
+		if err != nil {
+			return err
+		}
+		return imp.appendVspStatusChange(change)
+
+	case RecordMaintenanceWindow:
+		var window MaintenanceWindow
+		err := json.Unmarshal(record.Data, &window)
+		if err != nil {
+			return err
+		}
+		return imp.putMaintenanceWindow(window)
+
+	default:
+		return fmt.Errorf("unknown record type %q", record.Type)
+	}
+}
+
+// boltImporter stores imported records in a bbolt database.
+type boltImporter struct {
+	tx *bolt.Tx
+}
+
+func (b boltImporter) putSigningKey(seed []byte) error {
+	return b.tx.Bucket(vspBktK).Put(privateKeyK, seed)
+}
+
+func (b boltImporter) putCookieSecret(secret []byte) error {
+	return b.tx.Bucket(vspBktK).Put(cookieSecretK, secret)
+}
+
+func (b boltImporter) putWIFKey(params *wifKeyParams) error {
+	return b.tx.Bucket(vspBktK).Put(wifKeyK, params.bytes())
+}
+
+func (b boltImporter) putFeeXPub(xpub FeeXPub) error {
+	return insertFeeXPub(b.tx, xpub)
+}
+
+func (b boltImporter) putTicket(ticket Ticket) error {
+	bkt, err := b.tx.Bucket(vspBktK).Bucket(ticketBktK).CreateBucket([]byte(ticket.Hash))
+	if err != nil {
+		return fmt.Errorf("could not create bucket for ticket %s: %w", ticket.Hash, err)
+	}
+	return putTicketInBucket(bkt, ticket)
+}
+
+func (b boltImporter) putArchivedTicket(ticket ArchivedTicket) error {
+	return putArchivedTicket(b.tx.Bucket(vspBktK).Bucket(archiveBktK), ticket)
+}
+
+func (b boltImporter) putVoteChange(ticketHash string, index uint32, record VoteChangeRecord) error {
+	bkt, err := b.tx.Bucket(vspBktK).Bucket(voteChangeBktK).
+		CreateBucketIfNotExists([]byte(ticketHash))
+	if err != nil {
+		return err
+	}
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(uint32ToBytes(index), recordBytes)
+}
+
+func (b boltImporter) putAltSignAddr(ticketHash string, data *AltSignAddrData) error {
+	bkt, err := b.tx.Bucket(vspBktK).Bucket(altSignAddrBktK).CreateBucket([]byte(ticketHash))
+	if err != nil {
+		return err
+	}
+	return putAltSignAddrInBucket(bkt, data)
+}
+
+func (b boltImporter) appendTicketEvent(ticketHash string, event TicketEvent) error {
+	bkt, err := b.tx.Bucket(vspBktK).Bucket(ticketEventBktK).
+		CreateBucketIfNotExists([]byte(ticketHash))
+	if err != nil {
+		return err
+	}
+	id, err := bkt.NextSequence()
+	if err != nil {
+		return err
+	}
+	eventBytes, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return bkt.Put(uint64ToBytes(id), eventBytes)
+}
+
+func (b boltImporter) appendVspStatusChange(change VspStatusChange) error {
+	return putVspStatusChange(b.tx.Bucket(vspBktK).Bucket(vspStatusBktK), change)
+}
+
+func (b boltImporter) putMaintenanceWindow(window MaintenanceWindow) error {
+	_, err := putMaintenanceWindow(b.tx.Bucket(vspBktK).Bucket(maintenanceBktK), window)
+	return err
+}
@@ -0,0 +1,301 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/decred/slog"
+)
+
+const importDb = "import.db"
+
+// importExport writes an export of the test database and imports it into a new
+// database, which is returned open.
+func importExport(t *testing.T, includeSecrets bool) Database {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := db.Export(&buf, includeSecrets)
+	if err != nil {
+		t.Fatalf("error exporting database: %v", err)
+	}
+
+	err = backend.Import(importDb, &buf)
+	if err != nil {
+		t.Fatalf("error importing database: %v", err)
+	}
+
+	imported, err := backend.Open(importDb, slog.Disabled, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error opening imported database: %v", err)
+	}
+
+	t.Cleanup(func() {
+		imported.Close(false)
+	})
+
+	return imported
+}
+
+func testExportImport(t *testing.T) {
+	defer removeImportDb()
+
+	err := db.RetireXPub("newfeexpub")
+	if err != nil {
+		t.Fatalf("error retiring xpub: %v", err)
+	}
+
+	ticket := exampleTicket()
+	err = db.InsertNewTicket(ticket)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+
+	for range 2 {
+		err = db.SaveVoteChange(ticket.Hash, exampleRecord())
+		if err != nil {
+			t.Fatalf("error saving vote change: %v", err)
+		}
+	}
+
+	altSig := exampleAltSignAddrData()
+	err = db.InsertAltSignAddr(ticket.Hash, altSig)
+	if err != nil {
+		t.Fatalf("error inserting alt sign addr: %v", err)
+	}
+
+	for _, eventType := range []TicketEventType{EventFeeAddress, EventFeeReceived} {
+		err = db.AppendTicketEvent(ticket.Hash, TicketEvent{Timestamp: 1, Type: eventType})
+		if err != nil {
+			t.Fatalf("error appending ticket event: %v", err)
+		}
+	}
+
+	archived := exampleTicket()
+	archived.FeeTxStatus = FeeConfirmed
+	archived.Outcome = Voted
+	archived.PurchaseHeight = 1000
+	archived.OutcomeHeight = 2000
+	err = db.InsertNewTicket(archived)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+	_, err = db.ArchiveTickets(20000, 0)
+	if err != nil {
+		t.Fatalf("error archiving tickets: %v", err)
+	}
+
+	for _, closed := range []bool{true, false} {
+		err = db.SetVspStatus(VspStatusChange{Timestamp: 1, Closed: closed, ChangedBy: "test"})
+		if err != nil {
+			t.Fatalf("error setting VSP status: %v", err)
+		}
+	}
+
+	_, err = db.AddMaintenanceWindow(MaintenanceWindow{Start: 1000, End: 2000, Message: "test"})
+	if err != nil {
+		t.Fatalf("error adding maintenance window: %v", err)
+	}
+
+	imported := importExport(t, true)
+
+	// Ensure every type of data was copied to the new database.
+	wantXPubs, _ := db.AllXPubs()
+	gotXPubs, err := imported.AllXPubs()
+	if err != nil {
+		t.Fatalf("error getting xpubs: %v", err)
+	}
+	if !reflect.DeepEqual(wantXPubs, gotXPubs) {
+		t.Fatalf("expected xpubs %v, got %v", wantXPubs, gotXPubs)
+	}
+
+	gotTicket, found, err := imported.GetTicketByHash(ticket.Hash)
+	if err != nil || !found {
+		t.Fatalf("error retrieving ticket: found=%t, err=%v", found, err)
+	}
+	if !reflect.DeepEqual(ticket, gotTicket) {
+		t.Fatalf("expected ticket %v, got %v", ticket, gotTicket)
+	}
+
+	gotArchived, found, err := imported.GetArchivedTicket(archived.Hash)
+	if err != nil || !found {
+		t.Fatalf("error retrieving archived ticket: found=%t, err=%v", found, err)
+	}
+	if !reflect.DeepEqual(archivedTicket(archived), gotArchived) {
+		t.Fatalf("expected archived ticket %v, got %v", archivedTicket(archived), gotArchived)
+	}
+
+	wantStats, _ := db.TicketStats(20000)
+	gotStats, err := imported.TicketStats(20000)
+	if err != nil {
+		t.Fatalf("error getting ticket stats: %v", err)
+	}
+	if wantStats != gotStats {
+		t.Fatalf("expected ticket stats %+v, got %+v", wantStats, gotStats)
+	}
+
+	wantChanges, _ := db.GetVoteChanges(ticket.Hash)
+	gotChanges, err := imported.GetVoteChanges(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error getting vote changes: %v", err)
+	}
+	if !reflect.DeepEqual(wantChanges, gotChanges) {
+		t.Fatalf("expected vote changes %v, got %v", wantChanges, gotChanges)
+	}
+
+	gotAltSig, err := imported.AltSignAddrData(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error getting alt sign addr: %v", err)
+	}
+	if !reflect.DeepEqual(altSig, gotAltSig) {
+		t.Fatalf("expected alt sign addr %v, got %v", altSig, gotAltSig)
+	}
+
+	wantEvents, _ := db.TicketEvents(ticket.Hash)
+	gotEvents, err := imported.TicketEvents(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error getting ticket events: %v", err)
+	}
+	if !reflect.DeepEqual(wantEvents, gotEvents) {
+		t.Fatalf("expected ticket events %v, got %v", wantEvents, gotEvents)
+	}
+
+	wantStatus, _ := db.VspStatusHistory()
+	gotStatus, err := imported.VspStatusHistory()
+	if err != nil {
+		t.Fatalf("error getting VSP status history: %v", err)
+	}
+	if !reflect.DeepEqual(wantStatus, gotStatus) {
+		t.Fatalf("expected VSP status history %v, got %v", wantStatus, gotStatus)
+	}
+
+	wantWindows, _ := db.MaintenanceWindows()
+	gotWindows, err := imported.MaintenanceWindows()
+	if err != nil {
+		t.Fatalf("error getting maintenance windows: %v", err)
+	}
+	if !reflect.DeepEqual(wantWindows, gotWindows) {
+		t.Fatalf("expected maintenance windows %v, got %v", wantWindows, gotWindows)
+	}
+
+	// Secrets were included so they should also match.
+	wantKey, _, _ := db.KeyPair()
+	gotKey, _, err := imported.KeyPair()
+	if err != nil {
+		t.Fatalf("error getting keypair: %v", err)
+	}
+	if !wantKey.Equal(gotKey) {
+		t.Fatal("expected signing key to be imported")
+	}
+
+	wantSecret, _ := db.CookieSecret()
+	gotSecret, err := imported.CookieSecret()
+	if err != nil {
+		t.Fatalf("error getting cookie secret: %v", err)
+	}
+	if !bytes.Equal(wantSecret, gotSecret) {
+		t.Fatal("expected cookie secret to be imported")
+	}
+}
+
+func testExportImportNoSecrets(t *testing.T) {
+	defer removeImportDb()
+
+	ticket := exampleTicket()
+	err := db.InsertNewTicket(ticket)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+
+	imported := importExport(t, false)
+
+	// Voting WIFs are secrets, so they should not be exported.
+	gotTicket, found, err := imported.GetTicketByHash(ticket.Hash)
+	if err != nil || !found {
+		t.Fatalf("error retrieving ticket: found=%t, err=%v", found, err)
+	}
+	if gotTicket.VotingWIF != "" {
+		t.Fatalf("expected voting WIF to be redacted, got %q", gotTicket.VotingWIF)
+	}
+	ticket.VotingWIF = ""
+	if !reflect.DeepEqual(ticket, gotTicket) {
+		t.Fatalf("expected ticket %v, got %v", ticket, gotTicket)
+	}
+
+	// New secrets should be generated rather than copied.
+	wantKey, _, _ := db.KeyPair()
+	gotKey, _, err := imported.KeyPair()
+	if err != nil {
+		t.Fatalf("error getting keypair: %v", err)
+	}
+	if wantKey.Equal(gotKey) {
+		t.Fatal("expected a new signing key to be generated")
+	}
+}
+
+func testImportInvalid(t *testing.T) {
+	defer removeImportDb()
+
+	dbVersion, err := latestDatabaseVersion(backend)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// header returns a header record which is valid apart from the provided
+	// backend and database version.
+	header := func(backend Backend, dbVersion uint32) string {
+		return fmt.Sprintf(`{"type":"header","data":{"format":"vspd-export",`+
+			`"version":%d,"backend":%q,"dbversion":%d}}`, ExportVersion, backend, dbVersion)
+	}
+	xpub := `{"type":"feexpub","data":{"id":0,"key":"xpub"}}`
+
+	tests := map[string]string{
+		"empty":      "",
+		"no header":  xpub,
+		"version":    `{"type":"header","data":{"format":"vspd-export","version":999}}`,
+		"no backend": header("", dbVersion) + "\n" + xpub,
+		"backend":    header("unknown", dbVersion) + "\n" + xpub,
+		"old db":     header(backend, dbVersion-1) + "\n" + xpub,
+		"new db":     header(backend, dbVersion+1) + "\n" + xpub,
+		"no xpub":    header(backend, dbVersion),
+		"unknown":    header(backend, dbVersion) + "\n" + `{"type":"unknown","data":{}}`,
+	}
+
+	for name, input := range tests {
+		err := backend.Import(importDb, strings.NewReader(input))
+		if err == nil {
+			t.Fatalf("%s: expected import to fail", name)
+		}
+
+		// A failed import should not leave a database behind.
+		if fileExists(importDb) {
+			t.Fatalf("%s: expected failed import to remove database", name)
+		}
+	}
+
+	// Importing over an existing database should fail.
+	err = backend.Import(testDb, strings.NewReader(""))
+	if err == nil {
+		t.Fatal("expected import over existing database to fail")
+	}
+	if !fileExists(testDb) {
+		t.Fatal("expected existing database not to be removed")
+	}
+}
+
+func removeImportDb() {
+	removeDatabaseFiles(importDb)
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	bolt "go.etcd.io/bbolt"
+)
+
+// FeeAddressRecord is the fee address issued to a ticket, along with the xpub
+// and index it was derived from.
+type FeeAddressRecord struct {
+	TicketHash string
+	XPubID     uint32
+	Index      uint32
+	Address    string
+	Archived   bool
+}
+
+// FeeAddressGap is a range of fee address indexes of an xpub, up to its last
+// used index, which are not recorded against any ticket. Gaps are expected when
+// tickets which never paid a fee are deleted, but may also indicate that
+// tickets are missing from the database.
+type FeeAddressGap struct {
+	XPubID uint32
+	From   uint32
+	To     uint32
+}
+
+// FeeAddressReport is the result of CheckFeeAddresses.
+type FeeAddressReport struct {
+	// Checked is the number of fee addresses which were checked.
+	Checked int
+	// Problems describes every fee address which could not be re-derived from
+	// its xpub and index, every index beyond the last used index of its xpub,
+	// and every index used by more than one ticket.
+	Problems []string
+	// Gaps lists the unused indexes of every xpub.
+	Gaps []FeeAddressGap
+}
+
+// CheckFeeAddresses re-derives the fee address of every ticket in the
+// database, including archived tickets, from the xpub and index recorded with
+// the ticket. This ensures that every fee paid to the VSP can be accounted for
+// using only the fee xpubs.
+func CheckFeeAddresses(db Database, params *chaincfg.Params) (FeeAddressReport, error) {
+	xpubs, err := db.AllXPubs()
+	if err != nil {
+		return FeeAddressReport{}, fmt.Errorf("db.AllXPubs error: %w", err)
+	}
+
+	records, err := db.FeeAddresses()
+	if err != nil {
+		return FeeAddressReport{}, fmt.Errorf("db.FeeAddresses error: %w", err)
+	}
+
+	c := newFeeAddressChecker(params)
+	for _, id := range slices.Sorted(maps.Keys(xpubs)) {
+		c.addXPub(xpubs[id])
+	}
+	for _, record := range records {
+		c.check(record)
+	}
+
+	return FeeAddressReport{
+		Checked:  len(records),
+		Problems: c.problems,
+		Gaps:     c.gaps(),
+	}, nil
+}
+
+// feeAddressChecker re-derives fee addresses from the xpubs added to it, and
+// records any problems found.
+type feeAddressChecker struct {
+	params   *chaincfg.Params
+	xpubs    map[uint32]FeeXPub
+	external map[uint32]*hdkeychain.ExtendedKey
+	// used maps xpub ID and address index to the ticket the index was used by.
+	used     map[uint32]map[uint32]string
+	problems []string
+}
+
+func newFeeAddressChecker(params *chaincfg.Params) feeAddressChecker {
+	return feeAddressChecker{
+		params:   params,
+		xpubs:    make(map[uint32]FeeXPub),
+		external: make(map[uint32]*hdkeychain.ExtendedKey),
+		used:     make(map[uint32]map[uint32]string),
+	}
+}
+
+func (c *feeAddressChecker) problem(format string, a ...any) {
+	c.problems = append(c.problems, fmt.Sprintf(format, a...))
+}
+
+// addXPub adds an xpub which fee addresses may have been derived from.
+func (c *feeAddressChecker) addXPub(xpub FeeXPub) {
+	c.xpubs[xpub.ID] = xpub
+	c.used[xpub.ID] = make(map[uint32]string)
+
+	key, err := hdkeychain.NewKeyFromString(xpub.Key, c.params)
+	if err != nil {
+		c.problem("xpub %d: could not parse key: %v", xpub.ID, err)
+		return
+	}
+
+	if key.IsPrivate() {
+		c.problem("xpub %d: not a public key", xpub.ID)
+		return
+	}
+
+	external, err := key.Child(0)
+	if err != nil {
+		c.problem("xpub %d: could not derive external branch: %v", xpub.ID, err)
+		return
+	}
+
+	c.external[xpub.ID] = external
+}
+
+// check checks a single fee address. Every xpub must already have been added.
+func (c *feeAddressChecker) check(record FeeAddressRecord) {
+	desc := "ticket " + record.TicketHash
+	if record.Archived {
+		desc = "archived " + desc
+	}
+
+	xpub, ok := c.xpubs[record.XPubID]
+	if !ok {
+		c.problem("%s: fee address xpub %d not found", desc, record.XPubID)
+		return
+	}
+
+	if record.Index > xpub.LastUsedIdx {
+		c.problem("%s: fee address index %d is beyond last used index %d of xpub %d",
+			desc, record.Index, xpub.LastUsedIdx, record.XPubID)
+	}
+
+	if other, ok := c.used[record.XPubID][record.Index]; ok {
+		c.problem("%s: fee address index %d of xpub %d is also used by ticket %s",
+			desc, record.Index, record.XPubID, other)
+	} else {
+		c.used[record.XPubID][record.Index] = record.TicketHash
+	}
+
+	external, ok := c.external[record.XPubID]
+	if !ok {
+		// The xpub could not be parsed, which has already been reported.
+		return
+	}
+
+	addr, err := deriveFeeAddress(external, record.Index, c.params)
+	if err != nil {
+		c.problem("%s: could not derive fee address: %v", desc, err)
+		return
+	}
+
+	if addr != record.Address {
+		c.problem("%s: fee address %s does not match address %s derived from xpub %d index %d",
+			desc, record.Address, addr, record.XPubID, record.Index)
+	}
+}
+
+// gaps returns every range of indexes between 1 and the last used index of
+// each xpub which are not used by any ticket. Indexes which cannot be used to
+// derive an address, and so are skipped when generating fee addresses, are not
+// considered to be gaps.
+func (c *feeAddressChecker) gaps() []FeeAddressGap {
+	var gaps []FeeAddressGap
+	for _, id := range slices.Sorted(maps.Keys(c.xpubs)) {
+		used := c.used[id]
+		external := c.external[id]
+
+		var gap *FeeAddressGap
+		for idx := uint32(1); idx <= c.xpubs[id].LastUsedIdx; idx++ {
+			_, ok := used[idx]
+			if !ok && external != nil {
+				_, err := external.Child(idx)
+				ok = errors.Is(err, hdkeychain.ErrInvalidChild)
+			}
+
+			switch {
+			case ok && gap != nil:
+				gaps = append(gaps, *gap)
+				gap = nil
+			case !ok && gap == nil:
+				gap = &FeeAddressGap{XPubID: id, From: idx, To: idx}
+			case !ok:
+				gap.To = idx
+			}
+		}
+		if gap != nil {
+			gaps = append(gaps, *gap)
+		}
+	}
+
+	return gaps
+}
+
+// deriveFeeAddress returns the pay-to-pubkey-hash address at index of the
+// external branch of a fee xpub.
+func deriveFeeAddress(external *hdkeychain.ExtendedKey, index uint32, params *chaincfg.Params) (string, error) {
+	key, err := external.Child(index)
+	if err != nil {
+		return "", err
+	}
+
+	pkHash := stdaddr.Hash160(key.SerializedPubKey())
+	addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, params)
+	if err != nil {
+		return "", err
+	}
+
+	return addr.String(), nil
+}
+
+// feeAddressFromBkt reads the fee address of a ticket or archived ticket
+// stored in bkt.
+func feeAddressFromBkt(bkt *bolt.Bucket, archived bool) FeeAddressRecord {
+	return FeeAddressRecord{
+		TicketHash: string(bkt.Get(hashK)),
+		XPubID:     bytesToUint32(bkt.Get(feeAddressXPubIDK)),
+		Index:      bytesToUint32(bkt.Get(feeAddressIndexK)),
+		Address:    string(bkt.Get(feeAddressK)),
+		Archived:   archived,
+	}
+}
+
+// FeeAddresses returns the fee address of every ticket, followed by every
+// archived ticket, which has been issued one.
+func (vdb *VspDatabase) FeeAddresses() ([]FeeAddressRecord, error) {
+	var records []FeeAddressRecord
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+
+		for _, b := range []struct {
+			k        []byte
+			archived bool
+		}{{ticketBktK, false}, {archiveBktK, true}} {
+			bkt := vspBkt.Bucket(b.k)
+			err := bkt.ForEach(func(k, v []byte) error {
+				// The archive bucket also contains running totals, which are
+				// values rather than buckets.
+				if v != nil {
+					return nil
+				}
+
+				record := feeAddressFromBkt(bkt.Bucket(k), b.archived)
+				if record.Address != "" {
+					records = append(records, record)
+				}
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("error iterating over %s bucket: %w", string(b.k), err)
+			}
+		}
+
+		return nil
+	})
+
+	return records, err
+}
@@ -0,0 +1,167 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/slog"
+)
+
+func testCheckFeeAddresses(t *testing.T) {
+	// The test database is created with an invalid xpub, so a separate
+	// database is required.
+	const feeAddrDb = "feeaddr.db"
+	removeDatabaseFiles(feeAddrDb)
+	defer removeDatabaseFiles(feeAddrDb)
+
+	params := chaincfg.TestNet3Params()
+
+	err := backend.CreateNew(feeAddrDb, testnetXPub)
+	if err != nil {
+		t.Fatalf("error creating database: %v", err)
+	}
+
+	fdb, err := backend.Open(feeAddrDb, slog.Disabled, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	defer fdb.Close(false)
+
+	key, err := hdkeychain.NewKeyFromString(testnetXPub, params)
+	if err != nil {
+		t.Fatalf("error parsing xpub: %v", err)
+	}
+	retiredExternal, err := key.Child(0)
+	if err != nil {
+		t.Fatalf("error deriving external branch: %v", err)
+	}
+
+	// Use a child of the first xpub as the second xpub.
+	newKey, err := key.Child(1)
+	if err != nil {
+		t.Fatalf("error deriving new xpub: %v", err)
+	}
+	newExternal, err := newKey.Child(0)
+	if err != nil {
+		t.Fatalf("error deriving external branch: %v", err)
+	}
+
+	// ticketFor returns a ticket with a correctly derived fee address.
+	ticketFor := func(xpubID, idx uint32) Ticket {
+		t.Helper()
+		external := retiredExternal
+		if xpubID == 1 {
+			external = newExternal
+		}
+		addr, err := deriveFeeAddress(external, idx, params)
+		if err != nil {
+			t.Fatalf("error deriving fee address: %v", err)
+		}
+		ticket := exampleTicket()
+		ticket.FeeAddressXPubID = xpubID
+		ticket.FeeAddressIndex = idx
+		ticket.FeeAddress = addr
+		return ticket
+	}
+
+	// Indexes 1, 2 and 4 of the retired xpub are used. Index 1 is used by a
+	// ticket which is archived.
+	archived := ticketFor(0, 1)
+	archived.FeeTxStatus = FeeConfirmed
+	archived.Outcome = Voted
+	archived.PurchaseHeight = 1000
+	archived.OutcomeHeight = 2000
+	tickets := []Ticket{archived, ticketFor(0, 2), ticketFor(0, 4)}
+	for _, ticket := range tickets {
+		err = fdb.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+	err = fdb.SetLastAddressIndex(0, 5)
+	if err != nil {
+		t.Fatalf("error setting last address index: %v", err)
+	}
+	n, err := fdb.ArchiveTickets(100000, 10)
+	if err != nil {
+		t.Fatalf("error archiving tickets: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 ticket to be archived, got %d", n)
+	}
+
+	// Index 1 of the new xpub is used.
+	err = fdb.RetireXPub(newKey.String())
+	if err != nil {
+		t.Fatalf("error retiring xpub: %v", err)
+	}
+	err = fdb.InsertNewTicket(ticketFor(1, 1))
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+	err = fdb.SetLastAddressIndex(1, 1)
+	if err != nil {
+		t.Fatalf("error setting last address index: %v", err)
+	}
+
+	report, err := CheckFeeAddresses(fdb, params)
+	if err != nil {
+		t.Fatalf("error checking fee addresses: %v", err)
+	}
+	expected := FeeAddressReport{
+		Checked: 4,
+		Gaps: []FeeAddressGap{
+			{XPubID: 0, From: 3, To: 3},
+			{XPubID: 0, From: 5, To: 5},
+		},
+	}
+	if !reflect.DeepEqual(expected, report) {
+		t.Fatalf("expected report %+v, got %+v", expected, report)
+	}
+
+	// Add tickets which reuse an index, use an index beyond the last used
+	// index, and have a fee address which does not match the index.
+	reused := ticketFor(0, 2)
+	beyond := ticketFor(1, 3)
+	mismatch := ticketFor(0, 3)
+	mismatch.FeeAddress = tickets[1].FeeAddress
+	for _, ticket := range []Ticket{reused, beyond, mismatch} {
+		err = fdb.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	report, err = CheckFeeAddresses(fdb, params)
+	if err != nil {
+		t.Fatalf("error checking fee addresses: %v", err)
+	}
+	if report.Checked != 7 {
+		t.Fatalf("expected 7 fee addresses checked, got %d", report.Checked)
+	}
+	if len(report.Problems) != 3 {
+		t.Fatalf("expected 3 problems, got %q", report.Problems)
+	}
+	for _, s := range []string{"also used", "beyond last used index", "does not match"} {
+		found := false
+		for _, problem := range report.Problems {
+			found = found || strings.Contains(problem, s)
+		}
+		if !found {
+			t.Fatalf("expected a problem containing %q, got %q", s, report.Problems)
+		}
+	}
+
+	// Index 3 is now used, leaving only the gap at index 5.
+	expectedGaps := []FeeAddressGap{{XPubID: 0, From: 5, To: 5}}
+	if !reflect.DeepEqual(expectedGaps, report.Gaps) {
+		t.Fatalf("expected gaps %+v, got %+v", expectedGaps, report.Gaps)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// FeePayment is the fee which a VSP expects to have received for a confirmed
+// ticket whose fee tx has been broadcast.
+type FeePayment struct {
+	TicketHash  string
+	XPubID      uint32
+	FeeAddress  string
+	FeeAmount   int64
+	FeeTxHash   string
+	FeeTxStatus FeeStatus
+	Archived    bool
+}
+
+// feePaymentFromBkt reads the fee payment of the ticket or archived ticket
+// stored in the provided bucket.
+func feePaymentFromBkt(bkt *bolt.Bucket, status FeeStatus, archived bool) FeePayment {
+	return FeePayment{
+		TicketHash:  string(bkt.Get(hashK)),
+		XPubID:      bytesToUint32(bkt.Get(feeAddressXPubIDK)),
+		FeeAddress:  string(bkt.Get(feeAddressK)),
+		FeeAmount:   bytesToInt64(bkt.Get(feeAmountK)),
+		FeeTxHash:   string(bkt.Get(feeTxHashK)),
+		FeeTxStatus: status,
+		Archived:    archived,
+	}
+}
+
+// FeePayments returns the fee payment of every confirmed ticket with a fee tx
+// which has been broadcast, followed by every archived ticket. Every archived
+// ticket had a confirmed fee tx when it was archived.
+func (vdb *VspDatabase) FeePayments() ([]FeePayment, error) {
+	var payments []FeePayment
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+		err := ticketBkt.ForEach(func(k, _ []byte) error {
+			tBkt := ticketBkt.Bucket(k)
+			if !bytesToBool(tBkt.Get(confirmedK)) {
+				return nil
+			}
+
+			status := FeeStatus(tBkt.Get(feeTxStatusK))
+			if status == FeeBroadcast || status == FeeConfirmed {
+				payments = append(payments, feePaymentFromBkt(tBkt, status, false))
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over %s bucket: %w", string(ticketBktK), err)
+		}
+
+		archiveBkt := vspBkt.Bucket(archiveBktK)
+		err = archiveBkt.ForEach(func(k, v []byte) error {
+			// The archive bucket also contains running totals, which are
+			// values rather than buckets.
+			if v != nil {
+				return nil
+			}
+
+			payments = append(payments, feePaymentFromBkt(archiveBkt.Bucket(k), FeeConfirmed, true))
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over %s bucket: %w", string(archiveBktK), err)
+		}
+
+		return nil
+	})
+
+	return payments, err
+}
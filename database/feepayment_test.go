@@ -0,0 +1,79 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func testFeePayments(t *testing.T) {
+	// paymentOf returns the fee payment expected for the provided ticket.
+	paymentOf := func(ticket Ticket, archived bool) FeePayment {
+		return FeePayment{
+			TicketHash:  ticket.Hash,
+			XPubID:      ticket.FeeAddressXPubID,
+			FeeAddress:  ticket.FeeAddress,
+			FeeAmount:   ticket.FeeAmount,
+			FeeTxHash:   ticket.FeeTxHash,
+			FeeTxStatus: ticket.FeeTxStatus,
+			Archived:    archived,
+		}
+	}
+
+	// Tickets which are not confirmed, or have no fee tx on-chain, are not
+	// expected to have paid a fee.
+	unconfirmed := exampleTicket()
+	received := exampleTicket()
+	received.Confirmed = true
+	received.FeeTxStatus = FeeReceieved
+	feeError := exampleTicket()
+	feeError.Confirmed = true
+	feeError.FeeTxStatus = FeeError
+
+	broadcast := exampleTicket()
+	broadcast.Confirmed = true
+	confirmed := exampleTicket()
+	confirmed.Confirmed = true
+	confirmed.FeeTxStatus = FeeConfirmed
+	archived := exampleTicket()
+	archived.Confirmed = true
+	archived.FeeTxStatus = FeeConfirmed
+	archived.PurchaseHeight = 1000
+	archived.Outcome = Voted
+	archived.OutcomeHeight = 2000
+
+	for _, ticket := range []Ticket{unconfirmed, received, feeError, broadcast, confirmed, archived} {
+		err := db.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	n, err := db.ArchiveTickets(100000, 10)
+	if err != nil {
+		t.Fatalf("error archiving tickets: %v", err)
+	}
+	if n != 1 {
+		t.Fatalf("expected 1 ticket to be archived, got %d", n)
+	}
+
+	payments, err := db.FeePayments()
+	if err != nil {
+		t.Fatalf("error getting fee payments: %v", err)
+	}
+
+	// Tickets are sorted by hash, followed by archived tickets.
+	expected := []FeePayment{paymentOf(broadcast, false), paymentOf(confirmed, false)}
+	sort.Slice(expected, func(i, j int) bool {
+		return expected[i].TicketHash < expected[j].TicketHash
+	})
+	expected = append(expected, paymentOf(archived, true))
+
+	if !reflect.DeepEqual(expected, payments) {
+		t.Fatalf("expected fee payments %+v, got %+v", expected, payments)
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,6 +8,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"time"
 
 	bolt "go.etcd.io/bbolt"
@@ -19,7 +21,7 @@ type FeeXPub struct {
 	Key         string `json:"key"`
 	LastUsedIdx uint32 `json:"lastusedidx"`
 	// Retired is a unix timestamp representing the moment the key was retired,
-	// or zero for the currently active key.
+	// or zero for an active key.
 	Retired int64 `json:"retired"`
 }
 
@@ -46,112 +48,202 @@ func insertFeeXPub(tx *bolt.Tx, xpub FeeXPub) error {
 	return nil
 }
 
-// FeeXPub retrieves the currently active extended pubkey used for generating
-// fee addresses from the database.
-func (vdb *VspDatabase) FeeXPub() (FeeXPub, error) {
-	xpubs, err := vdb.AllXPubs()
+// activeXPubs returns the xpubs which have not been retired, sorted by ID.
+func activeXPubs(xpubs map[uint32]FeeXPub) []FeeXPub {
+	var active []FeeXPub
+	for _, id := range slices.Sorted(maps.Keys(xpubs)) {
+		if xpubs[id].Retired == 0 {
+			active = append(active, xpubs[id])
+		}
+	}
+	return active
+}
+
+// ActiveXPubs returns every fee xpub which has not been retired, sorted by ID.
+// New fee addresses are derived from the active xpubs.
+func ActiveXPubs(db Database) ([]FeeXPub, error) {
+	xpubs, err := db.AllXPubs()
 	if err != nil {
-		return FeeXPub{}, err
+		return nil, err
 	}
+	return activeXPubs(xpubs), nil
+}
 
-	// Find the active xpub - the one with the highest ID.
-	var highest uint32
-	for id := range xpubs {
-		if id > highest {
-			highest = id
+// newXPub returns the FeeXPub to insert for the provided pubkey, which is
+// given the next unused ID. Returns an error if the pubkey has been used
+// before.
+func newXPub(xpubs map[uint32]FeeXPub, key string) (FeeXPub, error) {
+	var nextID uint32
+	for _, x := range xpubs {
+		if x.Key == key {
+			return FeeXPub{}, errors.New("provided xpub has already been used")
 		}
+		nextID = max(nextID, x.ID+1)
 	}
 
-	return xpubs[highest], nil
+	return FeeXPub{
+		ID:          nextID,
+		Key:         key,
+		LastUsedIdx: 0,
+		Retired:     0,
+	}, nil
 }
 
-// RetireXPub will mark the currently active xpub key as retired and insert the
-// provided pubkey as the currently active one.
-func (vdb *VspDatabase) RetireXPub(xpub string) error {
-	// Ensure the new xpub has never been used before.
-	xpubs, err := vdb.AllXPubs()
-	if err != nil {
-		return err
+// retiredXPub returns the active xpub with the provided ID marked as retired.
+// Returns an error if there is no such xpub, or if it is the only active xpub.
+func retiredXPub(xpubs map[uint32]FeeXPub, id uint32) (FeeXPub, error) {
+	xpub, ok := xpubs[id]
+	if !ok {
+		return FeeXPub{}, fmt.Errorf("no xpub with ID %d", id)
 	}
-	for _, x := range xpubs {
-		if x.Key == xpub {
-			return errors.New("provided xpub has already been used")
+	if xpub.Retired != 0 {
+		return FeeXPub{}, fmt.Errorf("xpub %d is already retired", id)
+	}
+	if len(activeXPubs(xpubs)) == 1 {
+		return FeeXPub{}, fmt.Errorf("xpub %d is the only active xpub", id)
+	}
+
+	xpub.Retired = time.Now().Unix()
+	return xpub, nil
+}
+
+// allXPubs retrieves the current and any retired extended pubkeys.
+func allXPubs(tx *bolt.Tx) (map[uint32]FeeXPub, error) {
+	bkt := tx.Bucket(vspBktK).Bucket(xPubBktK)
+	if bkt == nil {
+		return nil, fmt.Errorf("%s bucket doesn't exist", string(xPubBktK))
+	}
+
+	xpubs := make(map[uint32]FeeXPub)
+	err := bkt.ForEach(func(k, v []byte) error {
+		var xpub FeeXPub
+		err := json.Unmarshal(v, &xpub)
+		if err != nil {
+			return fmt.Errorf("could not unmarshal xpub key: %w", err)
 		}
+
+		xpubs[bytesToUint32(k)] = xpub
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error iterating over %s bucket: %w", string(xPubBktK), err)
 	}
 
-	current, err := vdb.FeeXPub()
+	return xpubs, nil
+}
+
+// FeeXPub retrieves the most recently added active extended pubkey used for
+// generating fee addresses from the database.
+func (vdb *VspDatabase) FeeXPub() (FeeXPub, error) {
+	xpubs, err := vdb.AllXPubs()
 	if err != nil {
-		return err
+		return FeeXPub{}, err
+	}
+
+	active := activeXPubs(xpubs)
+	if len(active) == 0 {
+		return FeeXPub{}, errors.New("no active fee xpub found")
 	}
-	current.Retired = time.Now().Unix()
 
+	return active[len(active)-1], nil
+}
+
+// RetireXPub will mark every active xpub key as retired and insert the
+// provided pubkey as the only active one.
+func (vdb *VspDatabase) RetireXPub(xpub string) error {
 	return vdb.db.Update(func(tx *bolt.Tx) error {
-		// Store the retired xpub.
-		err := insertFeeXPub(tx, current)
+		xpubs, err := allXPubs(tx)
 		if err != nil {
 			return err
 		}
 
-		// Insert new xpub.
-		newKey := FeeXPub{
-			ID:          current.ID + 1,
-			Key:         xpub,
-			LastUsedIdx: 0,
-			Retired:     0,
-		}
-		err = insertFeeXPub(tx, newKey)
+		// Ensure the new xpub has never been used before.
+		newKey, err := newXPub(xpubs, xpub)
 		if err != nil {
 			return err
 		}
 
-		return nil
+		// Store the retired xpubs.
+		now := time.Now().Unix()
+		for _, current := range activeXPubs(xpubs) {
+			current.Retired = now
+			err = insertFeeXPub(tx, current)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Insert new xpub.
+		return insertFeeXPub(tx, newKey)
 	})
 }
 
-// AllXPubs retrieves the current and any retired extended pubkeys from the
-// database.
-func (vdb *VspDatabase) AllXPubs() (map[uint32]FeeXPub, error) {
-	xpubs := make(map[uint32]FeeXPub)
-
-	err := vdb.db.View(func(tx *bolt.Tx) error {
-		bkt := tx.Bucket(vspBktK).Bucket(xPubBktK)
+// AddXPub inserts the provided pubkey as an active xpub alongside any existing
+// active xpubs.
+func (vdb *VspDatabase) AddXPub(xpub string) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		xpubs, err := allXPubs(tx)
+		if err != nil {
+			return err
+		}
 
-		if bkt == nil {
-			return fmt.Errorf("%s bucket doesn't exist", string(xPubBktK))
+		// Ensure the new xpub has never been used before.
+		newKey, err := newXPub(xpubs, xpub)
+		if err != nil {
+			return err
 		}
 
-		err := bkt.ForEach(func(k, v []byte) error {
-			var xpub FeeXPub
-			err := json.Unmarshal(v, &xpub)
-			if err != nil {
-				return fmt.Errorf("could not unmarshal xpub key: %w", err)
-			}
+		return insertFeeXPub(tx, newKey)
+	})
+}
 
-			xpubs[bytesToUint32(k)] = xpub
+// RetireXPubID marks the active xpub with the provided ID as retired. At least
+// one xpub must remain active.
+func (vdb *VspDatabase) RetireXPubID(id uint32) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		xpubs, err := allXPubs(tx)
+		if err != nil {
+			return err
+		}
 
-			return nil
-		})
+		retired, err := retiredXPub(xpubs, id)
 		if err != nil {
-			return fmt.Errorf("error iterating over %s bucket: %w", string(xPubBktK), err)
+			return err
 		}
 
-		return nil
+		return insertFeeXPub(tx, retired)
+	})
+}
+
+// AllXPubs retrieves the current and any retired extended pubkeys from the
+// database.
+func (vdb *VspDatabase) AllXPubs() (map[uint32]FeeXPub, error) {
+	var xpubs map[uint32]FeeXPub
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		var err error
+		xpubs, err = allXPubs(tx)
+		return err
 	})
 
 	return xpubs, err
 }
 
 // SetLastAddressIndex updates the last index used to derive a new fee address
-// from the fee xpub key.
-func (vdb *VspDatabase) SetLastAddressIndex(idx uint32) error {
-	current, err := vdb.FeeXPub()
-	if err != nil {
-		return err
-	}
+// from the fee xpub key with the provided ID.
+func (vdb *VspDatabase) SetLastAddressIndex(xpubID, idx uint32) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		xpubs, err := allXPubs(tx)
+		if err != nil {
+			return err
+		}
 
-	current.LastUsedIdx = idx
+		xpub, ok := xpubs[xpubID]
+		if !ok {
+			return fmt.Errorf("no xpub with ID %d", xpubID)
+		}
+		xpub.LastUsedIdx = idx
 
-	return vdb.db.Update(func(tx *bolt.Tx) error {
-		return insertFeeXPub(tx, current)
+		return insertFeeXPub(tx, xpub)
 	})
 }
@@ -1,10 +1,11 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package database
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -32,7 +33,7 @@ func testFeeXPub(t *testing.T) {
 
 	// Update address index.
 	idx := uint32(99)
-	err = db.SetLastAddressIndex(idx)
+	err = db.SetLastAddressIndex(0, idx)
 	if err != nil {
 		t.Fatalf("error setting address index: %v", err)
 	}
@@ -61,7 +62,7 @@ func testFeeXPub(t *testing.T) {
 func testRetireFeeXPub(t *testing.T) {
 	// Increment the last used index to simulate some usage.
 	idx := uint32(99)
-	err := db.SetLastAddressIndex(idx)
+	err := db.SetLastAddressIndex(0, idx)
 	if err != nil {
 		t.Fatalf("error setting address index: %v", err)
 	}
@@ -115,3 +116,95 @@ func testRetireFeeXPub(t *testing.T) {
 		t.Fatalf("old xpub retired field not set")
 	}
 }
+
+func testMultipleActiveXPubs(t *testing.T) {
+	// activeIDs returns the IDs of the active xpubs.
+	activeIDs := func() []uint32 {
+		t.Helper()
+		active, err := ActiveXPubs(db)
+		if err != nil {
+			t.Fatalf("error getting active xpubs: %v", err)
+		}
+		ids := make([]uint32, 0, len(active))
+		for _, xpub := range active {
+			ids = append(ids, xpub.ID)
+		}
+		return ids
+	}
+
+	// Ensure a previously used xpub is rejected.
+	err := db.AddXPub(feeXPub)
+	if err == nil {
+		t.Fatalf("previous xpub was not rejected")
+	}
+
+	for _, xpub := range []string{"feexpub2", "feexpub3"} {
+		err = db.AddXPub(xpub)
+		if err != nil {
+			t.Fatalf("adding xpub failed: %v", err)
+		}
+	}
+	if ids := activeIDs(); !reflect.DeepEqual(ids, []uint32{0, 1, 2}) {
+		t.Fatalf("expected active xpubs [0 1 2], got %v", ids)
+	}
+
+	// The last used index of each xpub is updated independently.
+	err = db.SetLastAddressIndex(1, 5)
+	if err != nil {
+		t.Fatalf("error setting address index: %v", err)
+	}
+	err = db.SetLastAddressIndex(3, 5)
+	if err == nil {
+		t.Fatalf("setting address index of unknown xpub was not rejected")
+	}
+	xpubs, err := db.AllXPubs()
+	if err != nil {
+		t.Fatalf("error getting all fee xpubs: %v", err)
+	}
+	if xpubs[0].LastUsedIdx != 0 || xpubs[1].LastUsedIdx != 5 || xpubs[2].LastUsedIdx != 0 {
+		t.Fatalf("unexpected last used indexes %+v", xpubs)
+	}
+
+	// Retiring the most recent xpub leaves the others active.
+	err = db.RetireXPubID(2)
+	if err != nil {
+		t.Fatalf("retiring xpub failed: %v", err)
+	}
+	err = db.RetireXPubID(2)
+	if err == nil {
+		t.Fatalf("retiring a retired xpub was not rejected")
+	}
+	if ids := activeIDs(); !reflect.DeepEqual(ids, []uint32{0, 1}) {
+		t.Fatalf("expected active xpubs [0 1], got %v", ids)
+	}
+	current, err := db.FeeXPub()
+	if err != nil {
+		t.Fatalf("error getting fee xpub: %v", err)
+	}
+	if current.ID != 1 {
+		t.Fatalf("expected most recent active xpub 1, got %d", current.ID)
+	}
+
+	// The only active xpub can not be retired.
+	err = db.RetireXPubID(0)
+	if err != nil {
+		t.Fatalf("retiring xpub failed: %v", err)
+	}
+	err = db.RetireXPubID(1)
+	if err == nil {
+		t.Fatalf("retiring the only active xpub was not rejected")
+	}
+
+	// RetireXPub retires every active xpub.
+	err = db.AddXPub("feexpub4")
+	if err != nil {
+		t.Fatalf("adding xpub failed: %v", err)
+	}
+	err = db.RetireXPub("feexpub5")
+	if err != nil {
+		t.Fatalf("retiring xpub failed: %v", err)
+	}
+	if ids := activeIDs(); !reflect.DeepEqual(ids, []uint32{4}) {
+		t.Fatalf("expected active xpubs [4], got %v", ids)
+	}
+}
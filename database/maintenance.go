@@ -0,0 +1,104 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// MaintenanceWindow is serialized to json and stored in bbolt db. It describes
+// a period scheduled by an admin during which vspd does not accept new tickets.
+type MaintenanceWindow struct {
+	// Start and End are unix timestamps. The window includes Start but not
+	// End.
+	Start   int64  `json:"start"`
+	End     int64  `json:"end"`
+	Message string `json:"message"`
+}
+
+// createMaintenanceBucket creates the empty maintenance window bucket within
+// the provided vsp bucket.
+func createMaintenanceBucket(vspBkt *bolt.Bucket) error {
+	_, err := vspBkt.CreateBucket(maintenanceBktK)
+	if err != nil {
+		return fmt.Errorf("failed to create %s bucket: %w", maintenanceBktK, err)
+	}
+	return nil
+}
+
+// putMaintenanceWindow stores the provided window in bkt using the next
+// available ID, and returns the ID.
+func putMaintenanceWindow(bkt *bolt.Bucket, window MaintenanceWindow) (uint64, error) {
+	id, err := bkt.NextSequence()
+	if err != nil {
+		return 0, fmt.Errorf("could not get next maintenance window ID: %w", err)
+	}
+
+	windowBytes, err := json.Marshal(window)
+	if err != nil {
+		return 0, fmt.Errorf("could not marshal maintenance window: %w", err)
+	}
+
+	err = bkt.Put(uint64ToBytes(id), windowBytes)
+	if err != nil {
+		return 0, fmt.Errorf("could not store maintenance window: %w", err)
+	}
+
+	return id, nil
+}
+
+// AddMaintenanceWindow stores the provided maintenance window and returns its
+// ID.
+func (vdb *VspDatabase) AddMaintenanceWindow(window MaintenanceWindow) (uint64, error) {
+	var id uint64
+	err := vdb.db.Update(func(tx *bolt.Tx) error {
+		var err error
+		id, err = putMaintenanceWindow(tx.Bucket(vspBktK).Bucket(maintenanceBktK), window)
+		return err
+	})
+	return id, err
+}
+
+// DeleteMaintenanceWindow removes the maintenance window with the provided ID.
+// No error is returned if the window does not exist.
+func (vdb *VspDatabase) DeleteMaintenanceWindow(id uint64) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(vspBktK).Bucket(maintenanceBktK).Delete(uint64ToBytes(id))
+		if err != nil {
+			return fmt.Errorf("could not delete maintenance window: %w", err)
+		}
+		return nil
+	})
+}
+
+// MaintenanceWindows retrieves every stored maintenance window, keyed by ID.
+func (vdb *VspDatabase) MaintenanceWindows() (map[uint64]MaintenanceWindow, error) {
+	windows := make(map[uint64]MaintenanceWindow)
+
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(vspBktK).Bucket(maintenanceBktK)
+		err := bkt.ForEach(func(k, v []byte) error {
+			var window MaintenanceWindow
+			err := json.Unmarshal(v, &window)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal maintenance window: %w", err)
+			}
+
+			windows[bytesToUint64(k)] = window
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over %s bucket: %w", maintenanceBktK, err)
+		}
+
+		return nil
+	})
+
+	return windows, err
+}
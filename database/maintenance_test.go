@@ -0,0 +1,66 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testMaintenanceWindows(t *testing.T) {
+	// A new database should have no maintenance windows.
+	windows, err := db.MaintenanceWindows()
+	if err != nil {
+		t.Fatalf("error retrieving maintenance windows: %v", err)
+	}
+	if len(windows) != 0 {
+		t.Fatalf("expected no maintenance windows, got %d", len(windows))
+	}
+
+	// Add two windows, IDs should be increasing.
+	first := MaintenanceWindow{Start: 1000, End: 2000, Message: "Upgrading dcrd"}
+	firstID, err := db.AddMaintenanceWindow(first)
+	if err != nil {
+		t.Fatalf("error adding maintenance window: %v", err)
+	}
+	second := MaintenanceWindow{Start: 3000, End: 4000}
+	secondID, err := db.AddMaintenanceWindow(second)
+	if err != nil {
+		t.Fatalf("error adding maintenance window: %v", err)
+	}
+	if secondID <= firstID {
+		t.Fatalf("expected second ID %d to be greater than first ID %d",
+			secondID, firstID)
+	}
+
+	windows, err = db.MaintenanceWindows()
+	if err != nil {
+		t.Fatalf("error retrieving maintenance windows: %v", err)
+	}
+	expected := map[uint64]MaintenanceWindow{firstID: first, secondID: second}
+	if !reflect.DeepEqual(windows, expected) {
+		t.Fatalf("expected maintenance windows %+v, got %+v", expected, windows)
+	}
+
+	// Delete the first window.
+	err = db.DeleteMaintenanceWindow(firstID)
+	if err != nil {
+		t.Fatalf("error deleting maintenance window: %v", err)
+	}
+	windows, err = db.MaintenanceWindows()
+	if err != nil {
+		t.Fatalf("error retrieving maintenance windows: %v", err)
+	}
+	expected = map[uint64]MaintenanceWindow{secondID: second}
+	if !reflect.DeepEqual(windows, expected) {
+		t.Fatalf("expected maintenance windows %+v, got %+v", expected, windows)
+	}
+
+	// Deleting a window which does not exist should not error.
+	err = db.DeleteMaintenanceWindow(firstID)
+	if err != nil {
+		t.Fatalf("error deleting missing maintenance window: %v", err)
+	}
+}
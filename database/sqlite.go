@@ -0,0 +1,635 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/slog"
+
+	// Register the pure Go SQLite driver with database/sql.
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteDatabase wraps an instance of sql.DB backed by a SQLite file, and
+// implements Database using the same semantics as VspDatabase.
+type SQLiteDatabase struct {
+	db                   *sql.DB
+	path                 string
+	maxVoteChangeRecords int
+	log                  slog.Logger
+	wifs                 wifEncryption
+}
+
+// The versions of the SQLite schema, which is stored in the user_version pragma
+// of the database.
+const (
+	// sqliteInitialVersion is the version of a freshly created database which
+	// has had no upgrades applied.
+	sqliteInitialVersion = 1
+
+	// sqliteFeePolicyVersion adds a fee_policy column to the tickets and
+	// archived_tickets tables, recording the name of the fee schedule policy
+	// which priced each ticket.
+	sqliteFeePolicyVersion = 2
+
+	// sqliteVspStatusVersion adds a vsp_status_changes table to store the
+	// history of changes to the open or closed status of the VSP made while
+	// vspd is running.
+	sqliteVspStatusVersion = 3
+
+	// sqliteMaintenanceVersion adds a maintenance_windows table to store
+	// maintenance windows scheduled by an admin.
+	sqliteMaintenanceVersion = 4
+
+	// sqliteLatestVersion is the latest version of the schema that is
+	// understood by vspd.
+	sqliteLatestVersion = sqliteMaintenanceVersion
+)
+
+// sqliteUpgrades maps between old schema versions and the statements which
+// upgrade the schema to the next version.
+var sqliteUpgrades = []string{
+	sqliteInitialVersion: `
+		ALTER TABLE tickets ADD COLUMN fee_policy TEXT NOT NULL DEFAULT '';
+		ALTER TABLE archived_tickets ADD COLUMN fee_policy TEXT NOT NULL DEFAULT '';`,
+	sqliteFeePolicyVersion: sqliteVspStatusTable,
+	sqliteVspStatusVersion: sqliteMaintenanceTable,
+}
+
+// sqliteVspStatusTable is part of sqliteSchema, and is also created by the
+// upgrade to sqliteVspStatusVersion.
+const sqliteVspStatusTable = `
+CREATE TABLE vsp_status_changes (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	timestamp  INTEGER NOT NULL,
+	closed     INTEGER NOT NULL,
+	closed_msg TEXT NOT NULL,
+	changed_by TEXT NOT NULL
+);`
+
+// sqliteMaintenanceTable is part of sqliteSchema, and is also created by the
+// upgrade to sqliteMaintenanceVersion.
+const sqliteMaintenanceTable = `
+CREATE TABLE maintenance_windows (
+	id         INTEGER PRIMARY KEY AUTOINCREMENT,
+	start_time INTEGER NOT NULL,
+	end_time   INTEGER NOT NULL,
+	message    TEXT NOT NULL
+);`
+
+// The keys used in the meta table.
+const (
+	sqlitePrivateKeyK   = "privatekey"
+	sqliteCookieSecretK = "cookiesecret"
+	sqliteWIFKeyK       = "wifkey"
+)
+
+// sqliteSchema creates every table and index required by vspd. Column names
+// match the fields of the equivalent Go types so that the tables are easy to
+// query directly for reporting. Ticket hashes must not be empty, matching the
+// behavior of bbolt which does not allow empty bucket names.
+const sqliteSchema = `
+CREATE TABLE meta (
+	key   TEXT PRIMARY KEY,
+	value BLOB NOT NULL
+);
+
+CREATE TABLE fee_xpubs (
+	id            INTEGER PRIMARY KEY,
+	key           TEXT NOT NULL UNIQUE,
+	last_used_idx INTEGER NOT NULL,
+	retired       INTEGER NOT NULL
+);
+
+CREATE TABLE tickets (
+	hash                TEXT PRIMARY KEY CHECK (hash <> ''),
+	purchase_height     INTEGER NOT NULL,
+	commitment_address  TEXT NOT NULL,
+	fee_address_xpub_id INTEGER NOT NULL,
+	fee_address_index   INTEGER NOT NULL,
+	fee_address         TEXT NOT NULL,
+	fee_amount          INTEGER NOT NULL,
+	fee_expiration      INTEGER NOT NULL,
+	confirmed           INTEGER NOT NULL,
+	voting_wif          TEXT NOT NULL,
+	vote_choices        TEXT NOT NULL,
+	tspend_policy       TEXT NOT NULL,
+	treasury_policy     TEXT NOT NULL,
+	fee_tx_hex          TEXT NOT NULL,
+	fee_tx_hash         TEXT NOT NULL,
+	fee_tx_status       TEXT NOT NULL,
+	fee_error_reason    TEXT NOT NULL,
+	fee_error_permanent INTEGER NOT NULL,
+	outcome             TEXT NOT NULL,
+	outcome_height      INTEGER NOT NULL,
+	fee_policy          TEXT NOT NULL
+);
+CREATE INDEX tickets_fee_tx_status ON tickets (fee_tx_status);
+CREATE INDEX tickets_outcome ON tickets (outcome);
+CREATE INDEX tickets_confirmed ON tickets (confirmed);
+CREATE INDEX tickets_purchase_height ON tickets (purchase_height);
+
+CREATE TABLE archived_tickets (
+	hash                TEXT PRIMARY KEY CHECK (hash <> ''),
+	purchase_height     INTEGER NOT NULL,
+	commitment_address  TEXT NOT NULL,
+	fee_address_xpub_id INTEGER NOT NULL,
+	fee_address_index   INTEGER NOT NULL,
+	fee_address         TEXT NOT NULL,
+	fee_amount          INTEGER NOT NULL,
+	fee_tx_hash         TEXT NOT NULL,
+	vote_choices        TEXT NOT NULL,
+	tspend_policy       TEXT NOT NULL,
+	treasury_policy     TEXT NOT NULL,
+	outcome             TEXT NOT NULL,
+	outcome_height      INTEGER NOT NULL,
+	fee_policy          TEXT NOT NULL
+);
+
+CREATE TABLE vote_changes (
+	ticket_hash        TEXT NOT NULL CHECK (ticket_hash <> ''),
+	idx                INTEGER NOT NULL,
+	request            BLOB NOT NULL,
+	request_signature  TEXT NOT NULL,
+	response           BLOB NOT NULL,
+	response_signature TEXT NOT NULL,
+	PRIMARY KEY (ticket_hash, idx)
+);
+
+CREATE TABLE alt_sign_addrs (
+	ticket_hash   TEXT PRIMARY KEY CHECK (ticket_hash <> ''),
+	alt_sign_addr TEXT NOT NULL,
+	req           BLOB NOT NULL,
+	req_sig       TEXT NOT NULL,
+	resp          BLOB NOT NULL,
+	resp_sig      TEXT NOT NULL
+);
+
+CREATE TABLE ticket_events (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	ticket_hash TEXT NOT NULL CHECK (ticket_hash <> ''),
+	timestamp   INTEGER NOT NULL,
+	type        TEXT NOT NULL,
+	detail      TEXT NOT NULL
+);
+CREATE INDEX ticket_events_ticket_hash ON ticket_events (ticket_hash);
+
+CREATE TABLE webhooks (
+	id           INTEGER PRIMARY KEY AUTOINCREMENT,
+	url          TEXT NOT NULL,
+	payload      TEXT NOT NULL,
+	signature    TEXT NOT NULL,
+	attempts     INTEGER NOT NULL,
+	next_attempt INTEGER NOT NULL
+);
+` + sqliteVspStatusTable + sqliteMaintenanceTable
+
+// openSQLite opens a connection to the SQLite file at dbFile, creating it if
+// it does not exist. The database uses write-ahead logging so that it can be
+// read by other processes (eg. for reporting) while vspd is running.
+func openSQLite(dbFile string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dbFile+
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)&_pragma=synchronous(FULL)")
+	if err != nil {
+		return nil, fmt.Errorf("unable to open db file: %w", err)
+	}
+
+	// A single connection serializes access to the database in the same way
+	// as bbolt, which avoids any possibility of busy errors within vspd.
+	db.SetMaxOpenConns(1)
+
+	err = db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to open db file: %w", err)
+	}
+
+	return db, nil
+}
+
+// sqliteTx runs f inside a transaction on db, committing it if f returns nil
+// and rolling it back otherwise.
+func sqliteTx(db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+
+	err = f(tx)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// sqliteView runs f inside a read-only transaction on db, providing a
+// consistent view of the database.
+func sqliteView(db *sql.DB, f func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return fmt.Errorf("could not begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	return f(tx)
+}
+
+// CreateNewSQLite initializes a new SQLite database with all of the tables
+// required by vspd, and inserts the same initial values as CreateNew.
+func CreateNewSQLite(dbFile, feeXPub string) error {
+	// Return an error rather than modifying an existing file.
+	if _, err := os.Stat(dbFile); err == nil {
+		return fmt.Errorf("database file %s already exists", dbFile)
+	}
+
+	db, err := openSQLite(dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return sqliteTx(db, func(tx *sql.Tx) error {
+		_, err := tx.Exec(sqliteSchema)
+		if err != nil {
+			return fmt.Errorf("failed to create tables: %w", err)
+		}
+
+		_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", sqliteLatestVersion))
+		if err != nil {
+			return err
+		}
+
+		// Generate ed25519 key.
+		_, signKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return fmt.Errorf("failed to generate signing key: %w", err)
+		}
+		err = sqlitePutMeta(tx, sqlitePrivateKeyK, signKey.Seed())
+		if err != nil {
+			return err
+		}
+
+		// Generate a secret key for initializing the cookie store.
+		// Since go 1.24, crypto/rand.Read will never return an error.
+		secret := make([]byte, 32)
+		_, _ = rand.Read(secret)
+
+		err = sqlitePutMeta(tx, sqliteCookieSecretK, secret)
+		if err != nil {
+			return err
+		}
+
+		// Insert the initial fee xpub with ID 0.
+		return sqliteInsertFeeXPub(tx, FeeXPub{
+			ID:          0,
+			Key:         feeXPub,
+			LastUsedIdx: 0,
+			Retired:     0,
+		})
+	})
+}
+
+// OpenSQLite initializes and returns an open SQLite database. An error is
+// returned if no database file is found at the provided path.
+func OpenSQLite(dbFile string, log slog.Logger, maxVoteChangeRecords int) (*SQLiteDatabase, error) {
+	// Error if db file does not exist. The SQLite driver would otherwise
+	// silently create a new empty database.
+	_, err := os.Stat(dbFile)
+	if os.IsNotExist(err) {
+		return nil, err
+	}
+
+	db, err := openSQLite(dbFile)
+	if err != nil {
+		return nil, err
+	}
+
+	sdb := &SQLiteDatabase{
+		db:                   db,
+		path:                 dbFile,
+		log:                  log,
+		maxVoteChangeRecords: maxVoteChangeRecords,
+	}
+
+	dbVersion, err := sdb.Version()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to get db version: %w", err)
+	}
+
+	if dbVersion == 0 {
+		db.Close()
+		return nil, fmt.Errorf("%s is not a vspd database", dbFile)
+	}
+
+	if dbVersion > sqliteLatestVersion {
+		db.Close()
+		return nil, fmt.Errorf("expected database version <= %d, got %d",
+			sqliteLatestVersion, dbVersion)
+	}
+
+	err = sdb.loadWIFKey()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("unable to load voting WIF key: %w", err)
+	}
+
+	log.Infof("Opened SQLite database (version=%d, file=%s)", dbVersion, dbFile)
+
+	if dbVersion < sqliteLatestVersion {
+		err = sdb.writePreUpgradeBackup(dbVersion)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to write pre-upgrade backup: %w", err)
+		}
+
+		err = sdb.applyUpgrades(dbVersion)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("upgrade failed: %w", err)
+		}
+	}
+
+	return sdb, nil
+}
+
+// Close will close the database and, if requested, make a copy of the database
+// to the backup location.
+func (sdb *SQLiteDatabase) Close(writeBackup bool) {
+	if writeBackup {
+		err := sdb.WriteHotBackupFile()
+		if err != nil {
+			sdb.log.Errorf("Failed to write a database backup: %v", err)
+		}
+	}
+
+	err := sdb.db.Close()
+	if err != nil {
+		sdb.log.Errorf("Error closing database: %v", err)
+		return
+	}
+
+	sdb.log.Debug("Database closed")
+}
+
+// vacuumInto writes a compacted copy of the database to path, which must not
+// exist or be an empty file. Unlike copying the database file, this is safe
+// while the database is in use.
+func (sdb *SQLiteDatabase) vacuumInto(path string) error {
+	_, err := sdb.db.Exec("VACUUM INTO ?", path)
+	return err
+}
+
+// WriteHotBackupFile writes a backup of the database file while the database
+// is still open.
+func (sdb *SQLiteDatabase) WriteHotBackupFile() error {
+	backupMtx.Lock()
+	defer backupMtx.Unlock()
+
+	backupPath := HotBackupFile(sdb.path)
+	tempPath := backupPath + "~"
+
+	// Remove any temporary file left behind by a previous failure.
+	err := os.Remove(tempPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("os.Remove: %w", err)
+	}
+
+	// Write backup to temporary file.
+	err = sdb.vacuumInto(tempPath)
+	if err != nil {
+		return fmt.Errorf("VACUUM INTO: %w", err)
+	}
+
+	err = os.Chmod(tempPath, backupFileMode)
+	if err != nil {
+		return fmt.Errorf("os.Chmod: %w", err)
+	}
+
+	// Rename temporary file to actual backup file.
+	err = os.Rename(tempPath, backupPath)
+	if err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	sdb.log.Tracef("Database backup written to %s", backupPath)
+
+	return nil
+}
+
+// BackupDB streams a backup of the database over an http response writer.
+func (sdb *SQLiteDatabase) BackupDB(w http.ResponseWriter) error {
+	f, err := os.CreateTemp(filepath.Dir(sdb.path), "vspd-download-*.sqlite")
+	if err != nil {
+		return fmt.Errorf("os.CreateTemp: %w", err)
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	err = sdb.vacuumInto(f.Name())
+	if err != nil {
+		return fmt.Errorf("VACUUM INTO: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", `attachment; filename="vspd.sqlite"`)
+	w.Header().Set("Content-Length", strconv.FormatInt(info.Size(), 10))
+
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Version returns the current database version.
+func (sdb *SQLiteDatabase) Version() (uint32, error) {
+	var version uint32
+	err := sdb.db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		return 0, err
+	}
+
+	return version, nil
+}
+
+// Size returns the current size of the database in bytes. This does not
+// include the write-ahead log.
+func (sdb *SQLiteDatabase) Size() (uint64, error) {
+	var size uint64
+	err := sdb.db.QueryRow(`SELECT page_count * page_size
+		FROM pragma_page_count(), pragma_page_size()`).Scan(&size)
+	return size, err
+}
+
+func sqlitePutMeta(tx *sql.Tx, key string, value []byte) error {
+	_, err := tx.Exec(`INSERT INTO meta (key, value) VALUES (?, ?)
+		ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	if err != nil {
+		return fmt.Errorf("could not store %s: %w", key, err)
+	}
+	return nil
+}
+
+func (sdb *SQLiteDatabase) getMeta(key string) ([]byte, error) {
+	var value []byte
+	err := sdb.db.QueryRow("SELECT value FROM meta WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return value, err
+}
+
+// KeyPair retrieves the keypair used to sign API responses from the database.
+func (sdb *SQLiteDatabase) KeyPair() (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	seed, err := sdb.getMeta(sqlitePrivateKeyK)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if seed == nil {
+		// should not happen
+		return nil, nil, errors.New("no private key found")
+	}
+
+	return keyPairFromSeed(seed)
+}
+
+// CookieSecret retrieves the generated cookie store secret key from the
+// database.
+func (sdb *SQLiteDatabase) CookieSecret() ([]byte, error) {
+	return sdb.getMeta(sqliteCookieSecretK)
+}
+
+// writePreUpgradeBackup writes a copy of the database to PreUpgradeBackupFile.
+// Any existing file is replaced, because it cannot be newer than the database.
+func (sdb *SQLiteDatabase) writePreUpgradeBackup(currentVersion uint32) error {
+	backupPath := PreUpgradeBackupFile(sdb.path, currentVersion)
+	tempPath := backupPath + "~"
+
+	// Remove any temporary file left behind by a previous failure.
+	err := os.Remove(tempPath)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("os.Remove: %w", err)
+	}
+
+	err = sdb.vacuumInto(tempPath)
+	if err != nil {
+		return fmt.Errorf("VACUUM INTO: %w", err)
+	}
+
+	err = os.Chmod(tempPath, backupFileMode)
+	if err != nil {
+		return fmt.Errorf("os.Chmod: %w", err)
+	}
+
+	err = os.Rename(tempPath, backupPath)
+	if err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	sdb.log.Infof("Database version %d backed up to %s before upgrade", currentVersion, backupPath)
+
+	return nil
+}
+
+// applyUpgrades executes all upgrades required to update the database from
+// currentVersion to the latest version. Each upgrade is applied in its own
+// transaction along with the change of version.
+func (sdb *SQLiteDatabase) applyUpgrades(currentVersion uint32) error {
+	for version := currentVersion; version < sqliteLatestVersion; version++ {
+		err := sqliteTx(sdb.db, func(tx *sql.Tx) error {
+			_, err := tx.Exec(sqliteUpgrades[version])
+			if err != nil {
+				return err
+			}
+
+			_, err = tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", version+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("upgrade to version %d failed: %w", version+1, err)
+		}
+
+		sdb.log.Infof("Upgraded SQLite database to version %d", version+1)
+	}
+
+	return nil
+}
+
+func upgradeSQLite(dbFile string, log slog.Logger) (UpgradeReport, error) {
+	db, version, err := openSQLiteReadOnly(dbFile)
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	db.Close()
+
+	sdb, err := OpenSQLite(dbFile, log, 0)
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	sdb.Close(false)
+
+	report := UpgradeReport{FromVersion: version, ToVersion: sqliteLatestVersion}
+	if version < sqliteLatestVersion {
+		report.Backup = PreUpgradeBackupFile(dbFile, version)
+	}
+
+	return report, nil
+}
+
+func dryRunUpgradeSQLite(dbFile string, params *chaincfg.Params, log slog.Logger) (UpgradeReport, error) {
+	db, version, err := openSQLiteReadOnly(dbFile)
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	defer db.Close()
+
+	f, err := os.CreateTemp("", "vspd-upgrade-*.sqlite")
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	_, err = db.Exec("VACUUM INTO ?", f.Name())
+	if err != nil {
+		return UpgradeReport{}, fmt.Errorf("VACUUM INTO: %w", err)
+	}
+
+	copyDB, err := openSQLite(f.Name())
+	if err != nil {
+		return UpgradeReport{}, fmt.Errorf("unable to open copy of db file: %w", err)
+	}
+	defer copyDB.Close()
+
+	sdb := &SQLiteDatabase{db: copyDB, path: f.Name(), log: log}
+	err = sdb.applyUpgrades(version)
+	if err != nil {
+		return UpgradeReport{}, fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	report := UpgradeReport{FromVersion: version, ToVersion: sqliteLatestVersion}
+	report.Verify, err = sdb.verify(params, sqliteLatestVersion)
+	return report, err
+}
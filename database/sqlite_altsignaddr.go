@@ -0,0 +1,68 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+func sqliteInsertAltSignAddr(tx *sql.Tx, ticketHash string, data *AltSignAddrData) error {
+	_, err := tx.Exec(`INSERT INTO alt_sign_addrs (ticket_hash, alt_sign_addr, req,
+		req_sig, resp, resp_sig) VALUES (?, ?, ?, ?, ?, ?)`,
+		ticketHash, data.AltSignAddr, []byte(data.Req), data.ReqSig,
+		[]byte(data.Resp), data.RespSig)
+	if err != nil {
+		return fmt.Errorf("could not insert alt sign addr: %w", err)
+	}
+	return nil
+}
+
+// InsertAltSignAddr will insert the provided alternate signing address into the
+// database. Returns an error if data for the ticket hash already exist.
+//
+// Passed data must have no empty fields.
+func (sdb *SQLiteDatabase) InsertAltSignAddr(ticketHash string, data *AltSignAddrData) error {
+	err := checkAltSignAddrData(data)
+	if err != nil {
+		return err
+	}
+
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		return sqliteInsertAltSignAddr(tx, ticketHash, data)
+	})
+}
+
+// DeleteAltSignAddr deletes an alternate signing address from the database.
+// Does not error if there is no record in the database to delete.
+func (sdb *SQLiteDatabase) DeleteAltSignAddr(ticketHash string) error {
+	_, err := sdb.db.Exec("DELETE FROM alt_sign_addrs WHERE ticket_hash = ?", ticketHash)
+	if err != nil {
+		return fmt.Errorf("could not delete altsignaddr: %w", err)
+	}
+	return nil
+}
+
+// AltSignAddrData retrieves a ticket's alternate signing data. Existence of an
+// alternate signing address can be inferred by no error and nil data return.
+func (sdb *SQLiteDatabase) AltSignAddrData(ticketHash string) (*AltSignAddrData, error) {
+	var data AltSignAddrData
+	var req, resp []byte
+	err := sdb.db.QueryRow(`SELECT alt_sign_addr, req, req_sig, resp, resp_sig
+		FROM alt_sign_addrs WHERE ticket_hash = ?`, ticketHash).
+		Scan(&data.AltSignAddr, &req, &data.ReqSig, &resp, &data.RespSig)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data.Req = string(req)
+	data.Resp = string(resp)
+
+	return &data, nil
+}
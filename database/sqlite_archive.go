@@ -0,0 +1,158 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// sqliteArchivedTicketColumns lists the columns of the archived_tickets table
+// in the order expected by scanArchivedTicket and sqliteInsertArchivedTicket.
+const sqliteArchivedTicketColumns = `hash, purchase_height, commitment_address,
+	fee_address_xpub_id, fee_address_index, fee_address, fee_amount, fee_tx_hash,
+	vote_choices, tspend_policy, treasury_policy, outcome, outcome_height,
+	fee_policy`
+
+func sqliteInsertArchivedTicket(tx *sql.Tx, ticket ArchivedTicket) error {
+	switch ticket.Outcome {
+	case Voted, Expired, Missed, Revoked:
+	default:
+		return fmt.Errorf("cannot archive ticket with outcome %q", ticket.Outcome)
+	}
+
+	_, err := tx.Exec(`INSERT INTO archived_tickets (`+sqliteArchivedTicketColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ticket.Hash,
+		ticket.PurchaseHeight,
+		ticket.CommitmentAddress,
+		ticket.FeeAddressXPubID,
+		ticket.FeeAddressIndex,
+		ticket.FeeAddress,
+		ticket.FeeAmount,
+		ticket.FeeTxHash,
+		string(stringMapToBytes(ticket.VoteChoices)),
+		string(stringMapToBytes(ticket.TSpendPolicy)),
+		string(stringMapToBytes(ticket.TreasuryPolicy)),
+		string(ticket.Outcome),
+		ticket.OutcomeHeight,
+		ticket.FeePolicy,
+	)
+	if err != nil {
+		return fmt.Errorf("could not insert archived ticket: %w", err)
+	}
+	return nil
+}
+
+// scanArchivedTicket reads an archived ticket from a row containing
+// sqliteArchivedTicketColumns.
+func scanArchivedTicket(row interface{ Scan(...any) error }) (ArchivedTicket, error) {
+	var ticket ArchivedTicket
+	var voteChoices, tSpendPolicy, treasuryPolicy []byte
+
+	err := row.Scan(
+		&ticket.Hash,
+		&ticket.PurchaseHeight,
+		&ticket.CommitmentAddress,
+		&ticket.FeeAddressXPubID,
+		&ticket.FeeAddressIndex,
+		&ticket.FeeAddress,
+		&ticket.FeeAmount,
+		&ticket.FeeTxHash,
+		&voteChoices,
+		&tSpendPolicy,
+		&treasuryPolicy,
+		&ticket.Outcome,
+		&ticket.OutcomeHeight,
+		&ticket.FeePolicy,
+	)
+	if err != nil {
+		return ticket, err
+	}
+
+	ticket.VoteChoices, err = bytesToStringMap(voteChoices)
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal VoteChoices err: %w", err)
+	}
+
+	ticket.TSpendPolicy, err = bytesToStringMap(tSpendPolicy)
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal TSpendPolicy err: %w", err)
+	}
+
+	ticket.TreasuryPolicy, err = bytesToStringMap(treasuryPolicy)
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal TreasuryPolicy err: %w", err)
+	}
+
+	return ticket, nil
+}
+
+// ArchiveTickets moves every ticket which had its outcome set at least
+// retention blocks before the provided block height into the archive,
+// discarding its voting key and raw fee tx. Tickets purchased in the last 28
+// days are not archived. Returns the number of tickets archived.
+func (sdb *SQLiteDatabase) ArchiveTickets(blockHeight, retention int64) (int, error) {
+	var archived int
+	err := sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT "+sqliteTicketColumns+" FROM tickets "+
+			"WHERE fee_tx_status = ? AND outcome <> '' ORDER BY hash", FeeConfirmed)
+		if err != nil {
+			return err
+		}
+
+		// Read every candidate before modifying the table.
+		var tickets TicketList
+		for rows.Next() {
+			ticket, err := scanTicket(rows)
+			if err != nil {
+				rows.Close()
+				return fmt.Errorf("could not get ticket: %w", err)
+			}
+			if ticket.archivable(blockHeight, retention) {
+				tickets = append(tickets, ticket)
+			}
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		for _, ticket := range tickets {
+			_, err = tx.Exec("DELETE FROM tickets WHERE hash = ?", ticket.Hash)
+			if err != nil {
+				return fmt.Errorf("could not delete ticket: %w", err)
+			}
+
+			err = sqliteInsertArchivedTicket(tx, archivedTicket(ticket))
+			if err != nil {
+				return fmt.Errorf("could not archive ticket %s: %w", ticket.Hash, err)
+			}
+		}
+
+		archived = len(tickets)
+		return nil
+	})
+
+	return archived, err
+}
+
+// GetArchivedTicket returns the archive record of the ticket with the provided
+// hash. The returned bool is false if the ticket has not been archived.
+func (sdb *SQLiteDatabase) GetArchivedTicket(ticketHash string) (ArchivedTicket, bool, error) {
+	row := sdb.db.QueryRow("SELECT "+sqliteArchivedTicketColumns+
+		" FROM archived_tickets WHERE hash = ?", ticketHash)
+
+	ticket, err := scanArchivedTicket(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ArchivedTicket{}, false, nil
+	}
+	if err != nil {
+		return ArchivedTicket{}, false, fmt.Errorf("could not get archived ticket: %w", err)
+	}
+
+	return ticket, true, nil
+}
@@ -0,0 +1,286 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Export writes the contents of the database to w in a portable JSON lines
+// format. The signing key, cookie secret and the voting WIFs of tickets are
+// only included if includeSecrets is true. Pending webhook deliveries are not
+// exported.
+func (sdb *SQLiteDatabase) Export(w io.Writer, includeSecrets bool) error {
+	e := &exportEncoder{enc: json.NewEncoder(w)}
+
+	return sqliteView(sdb.db, func(tx *sql.Tx) error {
+		var version uint32
+		err := tx.QueryRow("PRAGMA user_version").Scan(&version)
+		if err != nil {
+			return err
+		}
+
+		err = e.write(RecordHeader, ExportHeader{
+			Format:          exportFormat,
+			Version:         ExportVersion,
+			Backend:         SQLiteBackend,
+			DatabaseVersion: version,
+			Timestamp:       time.Now().Unix(),
+			IncludesSecrets: includeSecrets,
+		})
+		if err != nil {
+			return err
+		}
+
+		if includeSecrets {
+			for _, secret := range []struct {
+				key        string
+				recordType ExportRecordType
+			}{
+				{sqlitePrivateKeyK, RecordSigningKey},
+				{sqliteCookieSecretK, RecordCookieSecret},
+			} {
+				var value []byte
+				err = tx.QueryRow("SELECT value FROM meta WHERE key = ?", secret.key).Scan(&value)
+				if err != nil {
+					return fmt.Errorf("could not get %s: %w", secret.key, err)
+				}
+				err = e.write(secret.recordType, hex.EncodeToString(value))
+				if err != nil {
+					return err
+				}
+			}
+		}
+
+		var wifKey []byte
+		err = tx.QueryRow("SELECT value FROM meta WHERE key = ?", sqliteWIFKeyK).Scan(&wifKey)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("could not get %s: %w", sqliteWIFKeyK, err)
+		}
+		params, err := bytesToWIFKeyParams(wifKey)
+		if err != nil {
+			return err
+		}
+		if params != nil {
+			err = e.write(RecordWIFKey, ExportWIFKey(*params))
+			if err != nil {
+				return err
+			}
+		}
+
+		err = sqliteForEach(tx, "SELECT id, key, last_used_idx, retired FROM fee_xpubs ORDER BY id",
+			func(rows *sql.Rows) error {
+				var xpub FeeXPub
+				err := rows.Scan(&xpub.ID, &xpub.Key, &xpub.LastUsedIdx, &xpub.Retired)
+				if err != nil {
+					return err
+				}
+				return e.write(RecordFeeXPub, xpub)
+			})
+		if err != nil {
+			return err
+		}
+
+		err = sqliteForEach(tx, "SELECT "+sqliteTicketColumns+" FROM tickets ORDER BY hash",
+			func(rows *sql.Rows) error {
+				ticket, err := scanTicket(rows)
+				if err != nil {
+					return fmt.Errorf("could not get ticket: %w", err)
+				}
+				if !includeSecrets {
+					ticket.VotingWIF = ""
+				}
+				return e.write(RecordTicket, ticket)
+			})
+		if err != nil {
+			return err
+		}
+
+		err = sqliteForEach(tx, "SELECT "+sqliteArchivedTicketColumns+" FROM archived_tickets ORDER BY hash",
+			func(rows *sql.Rows) error {
+				ticket, err := scanArchivedTicket(rows)
+				if err != nil {
+					return fmt.Errorf("could not get archived ticket: %w", err)
+				}
+				return e.write(RecordArchivedTicket, ticket)
+			})
+		if err != nil {
+			return err
+		}
+
+		err = sqliteForEach(tx, `SELECT ticket_hash, idx, request, request_signature,
+			response, response_signature FROM vote_changes ORDER BY ticket_hash, idx`,
+			func(rows *sql.Rows) error {
+				var change ExportVoteChange
+				var request, response []byte
+				err := rows.Scan(&change.TicketHash, &change.Index, &request,
+					&change.Record.RequestSignature, &response,
+					&change.Record.ResponseSignature)
+				if err != nil {
+					return err
+				}
+				change.Record.Request = string(request)
+				change.Record.Response = string(response)
+				return e.write(RecordVoteChange, change)
+			})
+		if err != nil {
+			return err
+		}
+
+		err = sqliteForEach(tx, `SELECT ticket_hash, alt_sign_addr, req, req_sig, resp,
+			resp_sig FROM alt_sign_addrs ORDER BY ticket_hash`,
+			func(rows *sql.Rows) error {
+				var altSig ExportAltSignAddr
+				err := rows.Scan(&altSig.TicketHash, &altSig.AltSignAddr, &altSig.Req,
+					&altSig.ReqSig, &altSig.Resp, &altSig.RespSig)
+				if err != nil {
+					return err
+				}
+				return e.write(RecordAltSignAddr, altSig)
+			})
+		if err != nil {
+			return err
+		}
+
+		err = sqliteForEach(tx, `SELECT ticket_hash, timestamp, type, detail
+			FROM ticket_events ORDER BY ticket_hash, id`,
+			func(rows *sql.Rows) error {
+				var event ExportTicketEvent
+				err := rows.Scan(&event.TicketHash, &event.Event.Timestamp,
+					&event.Event.Type, &event.Event.Detail)
+				if err != nil {
+					return err
+				}
+				return e.write(RecordTicketEvent, event)
+			})
+		if err != nil {
+			return err
+		}
+
+		err = sqliteForEach(tx, `SELECT `+sqliteVspStatusColumns+`
+			FROM vsp_status_changes ORDER BY id`,
+			func(rows *sql.Rows) error {
+				var change VspStatusChange
+				err := rows.Scan(&change.Timestamp, &change.Closed, &change.ClosedMsg,
+					&change.ChangedBy)
+				if err != nil {
+					return err
+				}
+				return e.write(RecordVspStatusChange, change)
+			})
+		if err != nil {
+			return err
+		}
+
+		return sqliteForEach(tx, `SELECT start_time, end_time, message
+			FROM maintenance_windows ORDER BY id`,
+			func(rows *sql.Rows) error {
+				var window MaintenanceWindow
+				err := rows.Scan(&window.Start, &window.End, &window.Message)
+				if err != nil {
+					return err
+				}
+				return e.write(RecordMaintenanceWindow, window)
+			})
+	})
+}
+
+// sqliteForEach runs query in tx and calls f for each row of the result.
+func sqliteForEach(tx *sql.Tx, query string, f func(rows *sql.Rows) error) error {
+	rows, err := tx.Query(query)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		err = f(rows)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// ImportSQLite creates a new SQLite database at dbFile containing the data read
+// from r. See Import for details.
+func ImportSQLite(dbFile string, r io.Reader) error {
+	return importNew(dbFile, r, CreateNewSQLite, importSQLite)
+}
+
+func importSQLite(dbFile string, r io.Reader) error {
+	db, err := openSQLite(dbFile)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	return sqliteTx(db, func(tx *sql.Tx) error {
+		// Remove the placeholder xpub inserted by CreateNewSQLite.
+		_, err := tx.Exec("DELETE FROM fee_xpubs")
+		if err != nil {
+			return fmt.Errorf("failed to delete fee xpubs: %w", err)
+		}
+
+		return importRecords(r, sqliteImporter{tx})
+	})
+}
+
+// sqliteImporter stores imported records in a SQLite database.
+type sqliteImporter struct {
+	tx *sql.Tx
+}
+
+func (s sqliteImporter) putSigningKey(seed []byte) error {
+	return sqlitePutMeta(s.tx, sqlitePrivateKeyK, seed)
+}
+
+func (s sqliteImporter) putCookieSecret(secret []byte) error {
+	return sqlitePutMeta(s.tx, sqliteCookieSecretK, secret)
+}
+
+func (s sqliteImporter) putWIFKey(params *wifKeyParams) error {
+	return sqlitePutMeta(s.tx, sqliteWIFKeyK, params.bytes())
+}
+
+func (s sqliteImporter) putFeeXPub(xpub FeeXPub) error {
+	return sqliteInsertFeeXPub(s.tx, xpub)
+}
+
+func (s sqliteImporter) putTicket(ticket Ticket) error {
+	return sqliteInsertTicket(s.tx, ticket)
+}
+
+func (s sqliteImporter) putArchivedTicket(ticket ArchivedTicket) error {
+	return sqliteInsertArchivedTicket(s.tx, ticket)
+}
+
+func (s sqliteImporter) putVoteChange(ticketHash string, index uint32, record VoteChangeRecord) error {
+	return sqliteInsertVoteChange(s.tx, ticketHash, index, record)
+}
+
+func (s sqliteImporter) putAltSignAddr(ticketHash string, data *AltSignAddrData) error {
+	return sqliteInsertAltSignAddr(s.tx, ticketHash, data)
+}
+
+func (s sqliteImporter) appendTicketEvent(ticketHash string, event TicketEvent) error {
+	return sqliteAppendTicketEvent(s.tx, ticketHash, event)
+}
+
+func (s sqliteImporter) appendVspStatusChange(change VspStatusChange) error {
+	return sqliteSetVspStatus(s.tx, change)
+}
+
+func (s sqliteImporter) putMaintenanceWindow(window MaintenanceWindow) error {
+	_, err := sqliteAddMaintenanceWindow(s.tx, window)
+	return err
+}
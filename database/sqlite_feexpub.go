@@ -0,0 +1,179 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// sqliteInsertFeeXPub stores the provided pubkey in the database, regardless of
+// whether a value pre-exists.
+func sqliteInsertFeeXPub(tx *sql.Tx, xpub FeeXPub) error {
+	_, err := tx.Exec(`INSERT INTO fee_xpubs (id, key, last_used_idx, retired)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET key = excluded.key,
+		last_used_idx = excluded.last_used_idx, retired = excluded.retired`,
+		xpub.ID, xpub.Key, xpub.LastUsedIdx, xpub.Retired)
+	if err != nil {
+		return fmt.Errorf("could not store xpub: %w", err)
+	}
+	return nil
+}
+
+// sqliteAllXPubs retrieves the current and any retired extended pubkeys.
+func sqliteAllXPubs(q interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}) (map[uint32]FeeXPub, error) {
+	rows, err := q.Query("SELECT id, key, last_used_idx, retired FROM fee_xpubs")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	xpubs := make(map[uint32]FeeXPub)
+	for rows.Next() {
+		var xpub FeeXPub
+		err := rows.Scan(&xpub.ID, &xpub.Key, &xpub.LastUsedIdx, &xpub.Retired)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan xpub: %w", err)
+		}
+		xpubs[xpub.ID] = xpub
+	}
+
+	return xpubs, rows.Err()
+}
+
+// FeeXPub retrieves the most recently added active extended pubkey used for
+// generating fee addresses from the database.
+func (sdb *SQLiteDatabase) FeeXPub() (FeeXPub, error) {
+	var xpub FeeXPub
+	err := sdb.db.QueryRow(`SELECT id, key, last_used_idx, retired FROM fee_xpubs
+		WHERE retired = 0 ORDER BY id DESC LIMIT 1`).
+		Scan(&xpub.ID, &xpub.Key, &xpub.LastUsedIdx, &xpub.Retired)
+	if errors.Is(err, sql.ErrNoRows) {
+		return xpub, errors.New("no active fee xpub found")
+	}
+	return xpub, err
+}
+
+// RetireXPub will mark every active xpub key as retired and insert the
+// provided pubkey as the only active one.
+func (sdb *SQLiteDatabase) RetireXPub(xpub string) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		xpubs, err := sqliteAllXPubs(tx)
+		if err != nil {
+			return err
+		}
+
+		// Ensure the new xpub has never been used before.
+		newKey, err := newXPub(xpubs, xpub)
+		if err != nil {
+			return err
+		}
+
+		// Store the retired xpubs.
+		_, err = tx.Exec("UPDATE fee_xpubs SET retired = ? WHERE retired = 0", time.Now().Unix())
+		if err != nil {
+			return err
+		}
+
+		// Insert new xpub.
+		return sqliteInsertFeeXPub(tx, newKey)
+	})
+}
+
+// AddXPub inserts the provided pubkey as an active xpub alongside any existing
+// active xpubs.
+func (sdb *SQLiteDatabase) AddXPub(xpub string) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		xpubs, err := sqliteAllXPubs(tx)
+		if err != nil {
+			return err
+		}
+
+		// Ensure the new xpub has never been used before.
+		newKey, err := newXPub(xpubs, xpub)
+		if err != nil {
+			return err
+		}
+
+		return sqliteInsertFeeXPub(tx, newKey)
+	})
+}
+
+// RetireXPubID marks the active xpub with the provided ID as retired. At least
+// one xpub must remain active.
+func (sdb *SQLiteDatabase) RetireXPubID(id uint32) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		xpubs, err := sqliteAllXPubs(tx)
+		if err != nil {
+			return err
+		}
+
+		retired, err := retiredXPub(xpubs, id)
+		if err != nil {
+			return err
+		}
+
+		return sqliteInsertFeeXPub(tx, retired)
+	})
+}
+
+// AllXPubs retrieves the current and any retired extended pubkeys from the
+// database.
+func (sdb *SQLiteDatabase) AllXPubs() (map[uint32]FeeXPub, error) {
+	return sqliteAllXPubs(sdb.db)
+}
+
+// SetLastAddressIndex updates the last index used to derive a new fee address
+// from the fee xpub key with the provided ID.
+func (sdb *SQLiteDatabase) SetLastAddressIndex(xpubID, idx uint32) error {
+	res, err := sdb.db.Exec("UPDATE fee_xpubs SET last_used_idx = ? WHERE id = ?", idx, xpubID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("no xpub with ID %d", xpubID)
+	}
+
+	return nil
+}
+
+// FeeAddresses returns the fee address of every ticket, followed by every
+// archived ticket, which has been issued one.
+func (sdb *SQLiteDatabase) FeeAddresses() ([]FeeAddressRecord, error) {
+	rows, err := sdb.db.Query(`
+		SELECT hash, fee_address_xpub_id, fee_address_index, fee_address, 0 AS archived
+			FROM tickets WHERE fee_address <> ''
+		UNION ALL
+		SELECT hash, fee_address_xpub_id, fee_address_index, fee_address, 1 AS archived
+			FROM archived_tickets WHERE fee_address <> ''
+		ORDER BY archived, hash`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []FeeAddressRecord
+	for rows.Next() {
+		var record FeeAddressRecord
+		err = rows.Scan(&record.TicketHash, &record.XPubID, &record.Index,
+			&record.Address, &record.Archived)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+
+	return records, rows.Err()
+}
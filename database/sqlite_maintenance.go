@@ -0,0 +1,70 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func sqliteAddMaintenanceWindow(tx *sql.Tx, window MaintenanceWindow) (uint64, error) {
+	res, err := tx.Exec(`INSERT INTO maintenance_windows (start_time, end_time, message)
+		VALUES (?, ?, ?)`, window.Start, window.End, window.Message)
+	if err != nil {
+		return 0, fmt.Errorf("could not store maintenance window: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("could not get maintenance window ID: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+// AddMaintenanceWindow stores the provided maintenance window and returns its
+// ID.
+func (sdb *SQLiteDatabase) AddMaintenanceWindow(window MaintenanceWindow) (uint64, error) {
+	var id uint64
+	err := sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		var err error
+		id, err = sqliteAddMaintenanceWindow(tx, window)
+		return err
+	})
+	return id, err
+}
+
+// DeleteMaintenanceWindow removes the maintenance window with the provided ID.
+// No error is returned if the window does not exist.
+func (sdb *SQLiteDatabase) DeleteMaintenanceWindow(id uint64) error {
+	_, err := sdb.db.Exec("DELETE FROM maintenance_windows WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("could not delete maintenance window: %w", err)
+	}
+	return nil
+}
+
+// MaintenanceWindows retrieves every stored maintenance window, keyed by ID.
+func (sdb *SQLiteDatabase) MaintenanceWindows() (map[uint64]MaintenanceWindow, error) {
+	rows, err := sdb.db.Query(`SELECT id, start_time, end_time, message
+		FROM maintenance_windows`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	windows := make(map[uint64]MaintenanceWindow)
+	for rows.Next() {
+		var id uint64
+		var window MaintenanceWindow
+		err := rows.Scan(&id, &window.Start, &window.End, &window.Message)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan maintenance window: %w", err)
+		}
+		windows[id] = window
+	}
+
+	return windows, rows.Err()
+}
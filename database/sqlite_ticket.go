@@ -0,0 +1,418 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// sqliteTicketColumns lists the columns of the tickets table in the order
+// expected by scanTicket and ticketArgs.
+const sqliteTicketColumns = `hash, purchase_height, commitment_address,
+	fee_address_xpub_id, fee_address_index, fee_address, fee_amount,
+	fee_expiration, confirmed, voting_wif, vote_choices, tspend_policy,
+	treasury_policy, fee_tx_hex, fee_tx_hash, fee_tx_status, fee_error_reason,
+	fee_error_permanent, outcome, outcome_height, fee_policy`
+
+// ticketArgs returns the fields of ticket in the order of sqliteTicketColumns.
+func ticketArgs(ticket Ticket) []any {
+	return []any{
+		ticket.Hash,
+		ticket.PurchaseHeight,
+		ticket.CommitmentAddress,
+		ticket.FeeAddressXPubID,
+		ticket.FeeAddressIndex,
+		ticket.FeeAddress,
+		ticket.FeeAmount,
+		ticket.FeeExpiration,
+		ticket.Confirmed,
+		ticket.VotingWIF,
+		string(stringMapToBytes(ticket.VoteChoices)),
+		string(stringMapToBytes(ticket.TSpendPolicy)),
+		string(stringMapToBytes(ticket.TreasuryPolicy)),
+		ticket.FeeTxHex,
+		ticket.FeeTxHash,
+		string(ticket.FeeTxStatus),
+		ticket.FeeErrorReason,
+		ticket.FeeErrorPermanent,
+		string(ticket.Outcome),
+		ticket.OutcomeHeight,
+		ticket.FeePolicy,
+	}
+}
+
+// scanTicket reads a ticket from a row containing sqliteTicketColumns.
+func scanTicket(row interface{ Scan(...any) error }) (Ticket, error) {
+	var ticket Ticket
+	var voteChoices, tSpendPolicy, treasuryPolicy []byte
+
+	err := row.Scan(
+		&ticket.Hash,
+		&ticket.PurchaseHeight,
+		&ticket.CommitmentAddress,
+		&ticket.FeeAddressXPubID,
+		&ticket.FeeAddressIndex,
+		&ticket.FeeAddress,
+		&ticket.FeeAmount,
+		&ticket.FeeExpiration,
+		&ticket.Confirmed,
+		&ticket.VotingWIF,
+		&voteChoices,
+		&tSpendPolicy,
+		&treasuryPolicy,
+		&ticket.FeeTxHex,
+		&ticket.FeeTxHash,
+		&ticket.FeeTxStatus,
+		&ticket.FeeErrorReason,
+		&ticket.FeeErrorPermanent,
+		&ticket.Outcome,
+		&ticket.OutcomeHeight,
+		&ticket.FeePolicy,
+	)
+	if err != nil {
+		return ticket, err
+	}
+
+	ticket.VoteChoices, err = bytesToStringMap(voteChoices)
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal VoteChoices err: %w", err)
+	}
+
+	ticket.TSpendPolicy, err = bytesToStringMap(tSpendPolicy)
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal TSpendPolicy err: %w", err)
+	}
+
+	ticket.TreasuryPolicy, err = bytesToStringMap(treasuryPolicy)
+	if err != nil {
+		return ticket, fmt.Errorf("unmarshal TreasuryPolicy err: %w", err)
+	}
+
+	return ticket, nil
+}
+
+func sqliteInsertTicket(tx *sql.Tx, ticket Ticket) error {
+	_, err := tx.Exec(`INSERT INTO tickets (`+sqliteTicketColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		ticketArgs(ticket)...)
+	if err != nil {
+		return fmt.Errorf("could not insert ticket: %w", err)
+	}
+	return nil
+}
+
+// InsertNewTicket will insert the provided ticket into the database. Returns an
+// error if the ticket hash already exists.
+func (sdb *SQLiteDatabase) InsertNewTicket(ticket Ticket) error {
+	err := sdb.wifs.encryptTicket(&ticket)
+	if err != nil {
+		return err
+	}
+
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		return sqliteInsertTicket(tx, ticket)
+	})
+}
+
+func (sdb *SQLiteDatabase) DeleteTicket(ticket Ticket) error {
+	res, err := sdb.db.Exec("DELETE FROM tickets WHERE hash = ?", ticket.Hash)
+	if err != nil {
+		return fmt.Errorf("could not delete ticket: %w", err)
+	}
+
+	return requireRowAffected(res, ticket.Hash)
+}
+
+// PurgeTicket deletes a ticket along with its vote changes, alternate signing
+// address and event history in a single transaction, so either all of them are
+// deleted or none are.
+func (sdb *SQLiteDatabase) PurgeTicket(ticket Ticket) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		res, err := tx.Exec("DELETE FROM tickets WHERE hash = ?", ticket.Hash)
+		if err != nil {
+			return fmt.Errorf("could not delete ticket: %w", err)
+		}
+		err = requireRowAffected(res, ticket.Hash)
+		if err != nil {
+			return err
+		}
+
+		for _, table := range []string{"vote_changes", "alt_sign_addrs", "ticket_events"} {
+			_, err = tx.Exec("DELETE FROM "+table+" WHERE ticket_hash = ?", ticket.Hash)
+			if err != nil {
+				return fmt.Errorf("could not delete %s of ticket: %w", table, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+func (sdb *SQLiteDatabase) UpdateTicket(ticket Ticket) error {
+	err := sdb.wifs.encryptTicket(&ticket)
+	if err != nil {
+		return err
+	}
+
+	return sqliteUpdateTicket(sdb.db, ticket)
+}
+
+// sqliteUpdateTicket updates every column of ticket apart from the hash.
+func sqliteUpdateTicket(e interface {
+	Exec(query string, args ...any) (sql.Result, error)
+}, ticket Ticket) error {
+	columns := strings.Split(sqliteTicketColumns, ",")[1:]
+	for i, column := range columns {
+		columns[i] = strings.TrimSpace(column) + " = ?"
+	}
+
+	args := append(ticketArgs(ticket)[1:], ticket.Hash)
+
+	res, err := e.Exec("UPDATE tickets SET "+strings.Join(columns, ", ")+
+		" WHERE hash = ?", args...)
+	if err != nil {
+		return fmt.Errorf("could not update ticket: %w", err)
+	}
+
+	return requireRowAffected(res, ticket.Hash)
+}
+
+// requireRowAffected returns an error if the statement which produced res did
+// not modify any rows, indicating that the ticket does not exist.
+func requireRowAffected(res sql.Result, ticketHash string) error {
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("ticket does not exist with hash %s", ticketHash)
+	}
+	return nil
+}
+
+func (sdb *SQLiteDatabase) GetTicketByHash(ticketHash string) (Ticket, bool, error) {
+	row := sdb.db.QueryRow("SELECT "+sqliteTicketColumns+" FROM tickets WHERE hash = ?",
+		ticketHash)
+
+	ticket, err := scanTicket(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Ticket{}, false, nil
+	}
+	if err != nil {
+		return Ticket{}, false, fmt.Errorf("could not get ticket: %w", err)
+	}
+
+	err = sdb.wifs.decryptTicket(&ticket)
+	if err != nil {
+		return Ticket{}, false, err
+	}
+
+	return ticket, true, nil
+}
+
+// TicketStats returns the total number of voted, expired, missed, and
+// currently voting tickets, as well as the fee revenue earned from those
+// tickets. The provided block height is used to determine which tickets
+// were purchased in the previous 28d and 24h window.
+func (sdb *SQLiteDatabase) TicketStats(blockHeight int64) (TicketStats, error) {
+	height28DaysAgo := max(blockHeight-blocksIn28Days, 1)
+	height24HoursAgo := max(blockHeight-blocksIn24Hours, 1)
+
+	// Purchase height is only set when tickets have 6 confs. Zero indicates
+	// they have only just been purchased, so they must count towards 28d/24h
+	// periods. Revoked tickets are counted as expired.
+	var stats TicketStats
+	err := sdb.db.QueryRow(`SELECT
+		COUNT(CASE WHEN outcome NOT IN (?, ?, ?, ?) THEN 1 END),
+		COUNT(CASE WHEN outcome = ? THEN 1 END),
+		COUNT(CASE WHEN outcome IN (?, ?) THEN 1 END),
+		COUNT(CASE WHEN outcome = ? THEN 1 END),
+		COALESCE(SUM(fee_amount), 0),
+		COALESCE(SUM(CASE WHEN purchase_height >= ? OR purchase_height = 0 THEN fee_amount END), 0),
+		COALESCE(SUM(CASE WHEN purchase_height >= ? OR purchase_height = 0 THEN fee_amount END), 0)
+		FROM tickets WHERE fee_tx_status = ?`,
+		Voted, Expired, Missed, Revoked,
+		Voted,
+		Expired, Revoked,
+		Missed,
+		height28DaysAgo,
+		height24HoursAgo,
+		FeeConfirmed,
+	).Scan(
+		&stats.Voting,
+		&stats.Voted,
+		&stats.Expired,
+		&stats.Missed,
+		&stats.RevenueLifetime,
+		&stats.Revenue28Days,
+		&stats.Revenue24Hours,
+	)
+	if err != nil {
+		return stats, err
+	}
+
+	// Archived tickets were all purchased more than 28 days ago, so they only
+	// count towards lifetime revenue.
+	var voted, expired, missed, revenue int64
+	err = sdb.db.QueryRow(`SELECT
+		COUNT(CASE WHEN outcome = ? THEN 1 END),
+		COUNT(CASE WHEN outcome IN (?, ?) THEN 1 END),
+		COUNT(CASE WHEN outcome = ? THEN 1 END),
+		COALESCE(SUM(fee_amount), 0)
+		FROM archived_tickets`,
+		Voted,
+		Expired, Revoked,
+		Missed,
+	).Scan(&voted, &expired, &missed, &revenue)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Voted += voted
+	stats.Expired += expired
+	stats.Missed += missed
+	stats.RevenueLifetime += revenue
+
+	return stats, nil
+}
+
+// FeePayments returns the fee payment of every confirmed ticket with a fee tx
+// which has been broadcast, followed by every archived ticket. Every archived
+// ticket had a confirmed fee tx when it was archived.
+func (sdb *SQLiteDatabase) FeePayments() ([]FeePayment, error) {
+	rows, err := sdb.db.Query(`
+		SELECT hash, fee_address_xpub_id, fee_address, fee_amount, fee_tx_hash,
+			fee_tx_status, 0 AS archived
+			FROM tickets WHERE confirmed AND fee_tx_status IN (?, ?)
+		UNION ALL
+		SELECT hash, fee_address_xpub_id, fee_address, fee_amount, fee_tx_hash,
+			?, 1 AS archived
+			FROM archived_tickets
+		ORDER BY archived, hash`,
+		FeeBroadcast, FeeConfirmed, FeeConfirmed)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var payments []FeePayment
+	for rows.Next() {
+		var payment FeePayment
+		err = rows.Scan(&payment.TicketHash, &payment.XPubID, &payment.FeeAddress,
+			&payment.FeeAmount, &payment.FeeTxHash, &payment.FeeTxStatus, &payment.Archived)
+		if err != nil {
+			return nil, err
+		}
+		payments = append(payments, payment)
+	}
+
+	return payments, rows.Err()
+}
+
+// GetUnconfirmedTickets returns tickets which are not yet confirmed.
+func (sdb *SQLiteDatabase) GetUnconfirmedTickets() (TicketList, error) {
+	return sdb.queryTickets("NOT confirmed")
+}
+
+// GetPendingFees returns tickets which are confirmed and have a fee tx which is
+// not yet broadcast.
+func (sdb *SQLiteDatabase) GetPendingFees() (TicketList, error) {
+	return sdb.queryTickets("confirmed AND fee_tx_status = ?", FeeReceieved)
+}
+
+// GetUnconfirmedFees returns tickets with a fee tx that is broadcast but not
+// confirmed yet.
+func (sdb *SQLiteDatabase) GetUnconfirmedFees() (TicketList, error) {
+	return sdb.queryTickets("fee_tx_status = ?", FeeBroadcast)
+}
+
+// GetRecoverableFees returns tickets with a fee tx which could not be
+// broadcast, excluding any which have been marked as permanently failed.
+func (sdb *SQLiteDatabase) GetRecoverableFees() (TicketList, error) {
+	return sdb.queryTickets("fee_tx_status = ? AND NOT fee_error_permanent", FeeError)
+}
+
+// GetVotableTickets returns tickets with a confirmed fee tx and no outcome (ie.
+// not expired/voted/missed).
+func (sdb *SQLiteDatabase) GetVotableTickets() (TicketList, error) {
+	return sdb.queryTickets("fee_tx_status = ? AND outcome = ''", FeeConfirmed)
+}
+
+// GetVotedTickets returns tickets with a confirmed fee tx and outcome == voted.
+func (sdb *SQLiteDatabase) GetVotedTickets() (TicketList, error) {
+	return sdb.queryTickets("fee_tx_status = ? AND outcome = ?", FeeConfirmed, Voted)
+}
+
+// GetRevokedTickets returns all tickets which have outcome == revoked.
+func (sdb *SQLiteDatabase) GetRevokedTickets() (TicketList, error) {
+	return sdb.queryTickets("outcome = ?", Revoked)
+}
+
+// GetMissingPurchaseHeight returns tickets which are confirmed but do not have
+// a purchase height.
+func (sdb *SQLiteDatabase) GetMissingPurchaseHeight() (TicketList, error) {
+	return sdb.queryTickets("confirmed AND purchase_height = 0")
+}
+
+// GetMissedTickets returns all tickets which have outcome == missed.
+func (sdb *SQLiteDatabase) GetMissedTickets() (TicketList, error) {
+	return sdb.queryTickets("outcome = ?", Missed)
+}
+
+// ListTickets returns all tickets which match every criteria in the provided
+// filter.
+func (sdb *SQLiteDatabase) ListTickets(filter TicketFilter) (TicketList, error) {
+	conditions := []string{"TRUE"}
+	var args []any
+
+	if filter.FeeStatus != "" {
+		conditions = append(conditions, "fee_tx_status = ?")
+		args = append(args, filter.FeeStatus)
+	}
+	if filter.Outcome != "" {
+		conditions = append(conditions, "outcome = ?")
+		args = append(args, filter.Outcome)
+	}
+	if filter.MinHeight != 0 {
+		conditions = append(conditions, "purchase_height >= ?")
+		args = append(args, filter.MinHeight)
+	}
+	if filter.MaxHeight != 0 {
+		conditions = append(conditions, "purchase_height <= ?")
+		args = append(args, filter.MaxHeight)
+	}
+
+	return sdb.queryTickets(strings.Join(conditions, " AND "), args...)
+}
+
+// queryTickets returns all tickets from the database which match the provided
+// SQL condition. Tickets are ordered by hash, matching the order in which they
+// are returned by VspDatabase.
+func (sdb *SQLiteDatabase) queryTickets(condition string, args ...any) (TicketList, error) {
+	rows, err := sdb.db.Query("SELECT "+sqliteTicketColumns+" FROM tickets WHERE "+
+		condition+" ORDER BY hash", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tickets TicketList
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, fmt.Errorf("could not get ticket: %w", err)
+		}
+		err = sdb.wifs.decryptTicket(&ticket)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+
+	return tickets, rows.Err()
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func sqliteAppendTicketEvent(tx *sql.Tx, ticketHash string, event TicketEvent) error {
+	_, err := tx.Exec(`INSERT INTO ticket_events (ticket_hash, timestamp, type, detail)
+		VALUES (?, ?, ?, ?)`, ticketHash, event.Timestamp, event.Type, event.Detail)
+	if err != nil {
+		return fmt.Errorf("could not store ticket event: %w", err)
+	}
+	return nil
+}
+
+// AppendTicketEvent adds the provided event to the end of the event history of
+// the ticket with the provided hash. Events are never modified once stored.
+func (sdb *SQLiteDatabase) AppendTicketEvent(ticketHash string, event TicketEvent) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		return sqliteAppendTicketEvent(tx, ticketHash, event)
+	})
+}
+
+// TicketEvents retrieves the event history of the ticket with the provided
+// hash, oldest first.
+func (sdb *SQLiteDatabase) TicketEvents(ticketHash string) ([]TicketEvent, error) {
+	rows, err := sdb.db.Query(`SELECT timestamp, type, detail FROM ticket_events
+		WHERE ticket_hash = ? ORDER BY id`, ticketHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []TicketEvent
+	for rows.Next() {
+		var event TicketEvent
+		err := rows.Scan(&event.Timestamp, &event.Type, &event.Detail)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan ticket event: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, rows.Err()
+}
+
+// DeleteTicketEvents deletes the event history of the ticket with the provided
+// hash. Does not error if there is no history for the ticket.
+func (sdb *SQLiteDatabase) DeleteTicketEvents(ticketHash string) error {
+	_, err := sdb.db.Exec("DELETE FROM ticket_events WHERE ticket_hash = ?", ticketHash)
+	if err != nil {
+		return fmt.Errorf("could not delete ticket events: %w", err)
+	}
+	return nil
+}
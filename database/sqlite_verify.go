@@ -0,0 +1,181 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/slog"
+)
+
+// sqliteURIReplacer escapes the characters which have a special meaning in the
+// path of a SQLite URI filename.
+var sqliteURIReplacer = strings.NewReplacer("%", "%25", "?", "%3f", "#", "%23")
+
+// openSQLiteReadOnly opens an existing SQLite database file read-only, and
+// returns its version.
+func openSQLiteReadOnly(dbFile string) (*sql.DB, uint32, error) {
+	_, err := os.Stat(dbFile)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db, err := sql.Open("sqlite", "file:"+sqliteURIReplacer.Replace(dbFile)+
+		"?mode=ro&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to open db file: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	var version uint32
+	err = db.QueryRow("PRAGMA user_version").Scan(&version)
+	if err != nil {
+		db.Close()
+		return nil, 0, fmt.Errorf("unable to get db version: %w", err)
+	}
+
+	if version == 0 {
+		db.Close()
+		return nil, 0, fmt.Errorf("%s is not a vspd database", dbFile)
+	}
+
+	if version > sqliteLatestVersion {
+		db.Close()
+		return nil, 0, fmt.Errorf("expected database version <= %d, got %d",
+			sqliteLatestVersion, version)
+	}
+
+	return db, version, nil
+}
+
+// verifySQLite verifies a SQLite database file, which is opened read-only.
+// Databases of an older version are verified by upgrading a temporary copy,
+// because the checks only understand the latest version.
+func verifySQLite(dbFile string, params *chaincfg.Params) (VerifyReport, error) {
+	db, version, err := openSQLiteReadOnly(dbFile)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	if version < sqliteLatestVersion {
+		db.Close()
+		report, err := dryRunUpgradeSQLite(dbFile, params, slog.Disabled)
+		report.Verify.Version = version
+		return report.Verify, err
+	}
+	defer db.Close()
+
+	sdb := &SQLiteDatabase{db: db, path: dbFile, log: slog.Disabled}
+	return sdb.verify(params, version)
+}
+
+// verify checks the contents of the database, which must be the latest
+// version.
+func (sdb *SQLiteDatabase) verify(params *chaincfg.Params, version uint32) (VerifyReport, error) {
+	c := newIntegrityCheck(params, version)
+
+	// Check the structure of the file itself.
+	rows, err := sdb.db.Query("PRAGMA integrity_check")
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	for rows.Next() {
+		var result string
+		err = rows.Scan(&result)
+		if err != nil {
+			rows.Close()
+			return VerifyReport{}, err
+		}
+		if result != "ok" {
+			c.problem("%s", result)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return VerifyReport{}, err
+	}
+
+	seed, err := sdb.getMeta(sqlitePrivateKeyK)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	cookieSecret, err := sdb.getMeta(sqliteCookieSecretK)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	c.checkKeys(seed, cookieSecret)
+
+	wifKey, err := sdb.getMeta(sqliteWIFKeyK)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	c.checkWIFKey(wifKey)
+
+	xpubs, err := sdb.AllXPubs()
+	if err != nil {
+		return VerifyReport{}, err
+	}
+	for _, id := range slices.Sorted(maps.Keys(xpubs)) {
+		c.checkXPub(xpubs[id])
+	}
+
+	err = sdb.verifyTickets(c)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	err = sdb.verifyArchivedTickets(c)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	return c.result()
+}
+
+func (sdb *SQLiteDatabase) verifyTickets(c *integrityCheck) error {
+	rows, err := sdb.db.Query("SELECT " + sqliteTicketColumns + " FROM tickets ORDER BY hash")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			c.report.Tickets++
+			c.problem("ticket %s: could not decode: %v", ticket.Hash, err)
+			continue
+		}
+		c.checkTicket(ticket)
+	}
+
+	return rows.Err()
+}
+
+func (sdb *SQLiteDatabase) verifyArchivedTickets(c *integrityCheck) error {
+	rows, err := sdb.db.Query("SELECT " + sqliteArchivedTicketColumns + " FROM archived_tickets ORDER BY hash")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		ticket, err := scanArchivedTicket(rows)
+		if err != nil {
+			c.report.ArchivedTickets++
+			c.problem("archived ticket %s: could not decode: %v", ticket.Hash, err)
+			continue
+		}
+		c.checkArchivedTicket(ticket)
+	}
+
+	return rows.Err()
+}
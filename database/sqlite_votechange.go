@@ -0,0 +1,133 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+)
+
+func sqliteInsertVoteChange(tx *sql.Tx, ticketHash string, idx uint32, record VoteChangeRecord) error {
+	_, err := tx.Exec(`INSERT INTO vote_changes (ticket_hash, idx, request,
+		request_signature, response, response_signature) VALUES (?, ?, ?, ?, ?, ?)`,
+		ticketHash, idx, []byte(record.Request), record.RequestSignature,
+		[]byte(record.Response), record.ResponseSignature)
+	if err != nil {
+		return fmt.Errorf("could not store vote change record: %w", err)
+	}
+	return nil
+}
+
+// SaveVoteChange will insert the provided vote change record into the database,
+// and if this breaches the maximum amount of allowed records, delete the oldest
+// one which is currently stored. Records are stored using a serially increasing
+// integer as the index.
+func (sdb *SQLiteDatabase) SaveVoteChange(ticketHash string, record VoteChangeRecord) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		return sqliteSaveVoteChange(tx, ticketHash, record, sdb.maxVoteChangeRecords)
+	})
+}
+
+// sqliteSaveVoteChange inserts the provided vote change record for a ticket,
+// deleting the oldest record of the ticket if there are already maxRecords.
+func sqliteSaveVoteChange(tx *sql.Tx, ticketHash string, record VoteChangeRecord, maxRecords int) error {
+	// Count the records, as well as finding the most recent and the oldest
+	// record.
+	var count int
+	var newest, oldest sql.NullInt64
+	err := tx.QueryRow(`SELECT COUNT(*), MAX(idx), MIN(idx) FROM vote_changes
+		WHERE ticket_hash = ?`, ticketHash).Scan(&count, &newest, &oldest)
+	if err != nil {
+		return fmt.Errorf("error counting vote change records: %w", err)
+	}
+
+	// If at (or over) the limit of max allowed records, remove the oldest
+	// one.
+	if count >= maxRecords {
+		_, err = tx.Exec("DELETE FROM vote_changes WHERE ticket_hash = ? AND idx = ?",
+			ticketHash, oldest.Int64)
+		if err != nil {
+			return fmt.Errorf("failed to delete old vote change record: %w", err)
+		}
+	}
+
+	// Insert record with index 0 if there are currently no records,
+	// otherwise use most recent + 1.
+	var newIdx uint32
+	if count > 0 {
+		newIdx = uint32(newest.Int64) + 1
+	}
+
+	return sqliteInsertVoteChange(tx, ticketHash, newIdx, record)
+}
+
+// UpdateVoteChoices stores the updated vote choices of every ticket along with
+// the record of the request which changed them, in a single transaction. Either
+// every update is stored, or none of them are.
+func (sdb *SQLiteDatabase) UpdateVoteChoices(updates []VoteChoicesUpdate) error {
+	// Encrypt copies of the tickets so the caller's tickets are unchanged.
+	updates = slices.Clone(updates)
+	for i := range updates {
+		err := sdb.wifs.encryptTicket(&updates[i].Ticket)
+		if err != nil {
+			return err
+		}
+	}
+
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		for _, update := range updates {
+			err := sqliteUpdateTicket(tx, update.Ticket)
+			if err != nil {
+				return err
+			}
+
+			err = sqliteSaveVoteChange(tx, update.Ticket.Hash, update.Record, sdb.maxVoteChangeRecords)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// GetVoteChanges retrieves all of the stored vote change records for the
+// provided ticket hash.
+func (sdb *SQLiteDatabase) GetVoteChanges(ticketHash string) (map[uint32]VoteChangeRecord, error) {
+	rows, err := sdb.db.Query(`SELECT idx, request, request_signature, response,
+		response_signature FROM vote_changes WHERE ticket_hash = ?`, ticketHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	records := make(map[uint32]VoteChangeRecord)
+	for rows.Next() {
+		var idx uint32
+		var record VoteChangeRecord
+		var request, response []byte
+		err := rows.Scan(&idx, &request, &record.RequestSignature, &response,
+			&record.ResponseSignature)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan vote change record: %w", err)
+		}
+		record.Request = string(request)
+		record.Response = string(response)
+		records[idx] = record
+	}
+
+	return records, rows.Err()
+}
+
+// DeleteVoteChanges deletes all of the stored vote change records for the
+// provided ticket hash.
+func (sdb *SQLiteDatabase) DeleteVoteChanges(ticketHash string) error {
+	_, err := sdb.db.Exec("DELETE FROM vote_changes WHERE ticket_hash = ?", ticketHash)
+	if err != nil {
+		return fmt.Errorf("could not delete vote changes: %w", err)
+	}
+	return nil
+}
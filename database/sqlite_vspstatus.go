@@ -0,0 +1,74 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// sqliteVspStatusColumns lists the columns of the vsp_status_changes table in
+// the order they are scanned into a VspStatusChange.
+const sqliteVspStatusColumns = "timestamp, closed, closed_msg, changed_by"
+
+func sqliteSetVspStatus(tx *sql.Tx, change VspStatusChange) error {
+	_, err := tx.Exec(`INSERT INTO vsp_status_changes (`+sqliteVspStatusColumns+`)
+		VALUES (?, ?, ?, ?)`, change.Timestamp, change.Closed, change.ClosedMsg,
+		change.ChangedBy)
+	if err != nil {
+		return fmt.Errorf("could not store VSP status change: %w", err)
+	}
+	return nil
+}
+
+// SetVspStatus records the provided change as the current status of the VSP.
+// Changes are never modified once stored, so every previous status remains
+// available from VspStatusHistory.
+func (sdb *SQLiteDatabase) SetVspStatus(change VspStatusChange) error {
+	return sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		return sqliteSetVspStatus(tx, change)
+	})
+}
+
+// VspStatus retrieves the most recent change to the status of the VSP. A
+// boolean indicates whether the status has ever been changed.
+func (sdb *SQLiteDatabase) VspStatus() (VspStatusChange, bool, error) {
+	var change VspStatusChange
+	err := sdb.db.QueryRow(`SELECT `+sqliteVspStatusColumns+` FROM vsp_status_changes
+		ORDER BY id DESC LIMIT 1`).
+		Scan(&change.Timestamp, &change.Closed, &change.ClosedMsg, &change.ChangedBy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return VspStatusChange{}, false, nil
+	}
+	if err != nil {
+		return VspStatusChange{}, false, fmt.Errorf("could not get VSP status: %w", err)
+	}
+
+	return change, true, nil
+}
+
+// VspStatusHistory retrieves every change to the status of the VSP, oldest
+// first.
+func (sdb *SQLiteDatabase) VspStatusHistory() ([]VspStatusChange, error) {
+	rows, err := sdb.db.Query(`SELECT ` + sqliteVspStatusColumns + ` FROM vsp_status_changes
+		ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []VspStatusChange
+	for rows.Next() {
+		var change VspStatusChange
+		err := rows.Scan(&change.Timestamp, &change.Closed, &change.ClosedMsg, &change.ChangedBy)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan VSP status change: %w", err)
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
@@ -0,0 +1,81 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"fmt"
+)
+
+// QueueWebhook inserts the provided delivery at the end of the webhook queue
+// and returns its ID.
+func (sdb *SQLiteDatabase) QueueWebhook(delivery WebhookDelivery) (uint64, error) {
+	res, err := sdb.db.Exec(`INSERT INTO webhooks (url, payload, signature, attempts,
+		next_attempt) VALUES (?, ?, ?, ?, ?)`, delivery.URL, delivery.Payload,
+		delivery.Signature, delivery.Attempts, delivery.NextAttempt)
+	if err != nil {
+		return 0, fmt.Errorf("could not store webhook delivery: %w", err)
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("could not get webhook ID: %w", err)
+	}
+
+	return uint64(id), nil
+}
+
+// UpdateWebhook overwrites the stored delivery with the provided ID.
+func (sdb *SQLiteDatabase) UpdateWebhook(id uint64, delivery WebhookDelivery) error {
+	res, err := sdb.db.Exec(`UPDATE webhooks SET url = ?, payload = ?, signature = ?,
+		attempts = ?, next_attempt = ? WHERE id = ?`, delivery.URL, delivery.Payload,
+		delivery.Signature, delivery.Attempts, delivery.NextAttempt, id)
+	if err != nil {
+		return fmt.Errorf("could not store webhook delivery: %w", err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("webhook delivery does not exist with ID %d", id)
+	}
+
+	return nil
+}
+
+// DeleteWebhook removes the delivery with the provided ID from the webhook
+// queue. Does not error if the delivery does not exist.
+func (sdb *SQLiteDatabase) DeleteWebhook(id uint64) error {
+	_, err := sdb.db.Exec("DELETE FROM webhooks WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("could not delete webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// PendingWebhooks retrieves every delivery in the webhook queue, keyed by ID.
+func (sdb *SQLiteDatabase) PendingWebhooks() (map[uint64]WebhookDelivery, error) {
+	rows, err := sdb.db.Query(`SELECT id, url, payload, signature, attempts,
+		next_attempt FROM webhooks`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := make(map[uint64]WebhookDelivery)
+	for rows.Next() {
+		var id uint64
+		var delivery WebhookDelivery
+		err := rows.Scan(&id, &delivery.URL, &delivery.Payload, &delivery.Signature,
+			&delivery.Attempts, &delivery.NextAttempt)
+		if err != nil {
+			return nil, fmt.Errorf("could not scan webhook delivery: %w", err)
+		}
+		deliveries[id] = delivery
+	}
+
+	return deliveries, rows.Err()
+}
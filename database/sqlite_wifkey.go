@@ -0,0 +1,137 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+// WIFsEncrypted returns true if voting WIFs are encrypted in the database.
+func (sdb *SQLiteDatabase) WIFsEncrypted() bool {
+	return sdb.wifs.encrypted()
+}
+
+// UnlockWIFs derives the voting WIF encryption key from the provided
+// passphrase. Until it is called, tickets read from a database with encrypted
+// WIFs contain the encrypted value, and new WIFs cannot be stored.
+func (sdb *SQLiteDatabase) UnlockWIFs(passphrase []byte) error {
+	return sdb.wifs.unlock(passphrase)
+}
+
+// EncryptWIFs encrypts the voting WIF of every ticket with a key derived from
+// the provided passphrase, and leaves the database unlocked.
+func (sdb *SQLiteDatabase) EncryptWIFs(passphrase []byte) error {
+	if sdb.wifs.encrypted() {
+		return ErrWIFsEncrypted
+	}
+
+	params, c, err := newWIFKey(passphrase)
+	if err != nil {
+		return err
+	}
+
+	err = sdb.reencryptWIFs(params, nil, c)
+	if err != nil {
+		return err
+	}
+
+	sdb.wifs.set(params, c)
+	return nil
+}
+
+// RotateWIFKey re-encrypts the voting WIF of every ticket with a key derived
+// from newPassphrase, and leaves the database unlocked with the new key.
+func (sdb *SQLiteDatabase) RotateWIFKey(oldPassphrase, newPassphrase []byte) error {
+	oldCipher, err := sdb.wifs.verify(oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	params, newCipher, err := newWIFKey(newPassphrase)
+	if err != nil {
+		return err
+	}
+
+	err = sdb.reencryptWIFs(params, oldCipher, newCipher)
+	if err != nil {
+		return err
+	}
+
+	sdb.wifs.set(params, newCipher)
+	return nil
+}
+
+// reencryptWIFs re-encrypts the voting WIF of every ticket and stores the new
+// key parameters in a single transaction. The database is then vacuumed so that
+// the previous values do not remain in free pages of the database file.
+func (sdb *SQLiteDatabase) reencryptWIFs(params *wifKeyParams, from, to *wifCipher) error {
+	err := sqliteTx(sdb.db, func(tx *sql.Tx) error {
+		rows, err := tx.Query("SELECT hash, voting_wif FROM tickets WHERE voting_wif <> '' ORDER BY hash")
+		if err != nil {
+			return err
+		}
+
+		// Read every WIF before modifying the table.
+		wifs := make(map[string]string)
+		for rows.Next() {
+			var hash, value string
+			err = rows.Scan(&hash, &value)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			wifs[hash] = value
+		}
+		rows.Close()
+		if err = rows.Err(); err != nil {
+			return err
+		}
+
+		for hash, value := range wifs {
+			value, err = reencryptWIF(hash, value, from, to)
+			if err != nil {
+				return err
+			}
+			_, err = tx.Exec("UPDATE tickets SET voting_wif = ? WHERE hash = ?", value, hash)
+			if err != nil {
+				return fmt.Errorf("could not update ticket: %w", err)
+			}
+		}
+
+		return sqlitePutMeta(tx, sqliteWIFKeyK, params.bytes())
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = sdb.db.Exec("VACUUM")
+	if err != nil {
+		return fmt.Errorf("VACUUM: %w", err)
+	}
+
+	_, err = sdb.db.Exec("PRAGMA wal_checkpoint(TRUNCATE)")
+	if err != nil {
+		return fmt.Errorf("wal_checkpoint: %w", err)
+	}
+
+	return nil
+}
+
+// loadWIFKey reads the voting WIF key parameters from the database.
+func (sdb *SQLiteDatabase) loadWIFKey() error {
+	value, err := sdb.getMeta(sqliteWIFKeyK)
+	if err != nil {
+		return err
+	}
+
+	params, err := bytesToWIFKeyParams(value)
+	if err != nil {
+		return err
+	}
+
+	sdb.wifs.set(params, nil)
+	return nil
+}
@@ -62,6 +62,7 @@ var (
 	feeAddressK        = []byte("FeeAddress")
 	feeAmountK         = []byte("FeeAmount")
 	feeExpirationK     = []byte("FeeExpiration")
+	feePolicyK         = []byte("FeePolicy")
 	confirmedK         = []byte("Confirmed")
 	votingWIFK         = []byte("VotingWIF")
 	voteChoicesK       = []byte("VoteChoices")
@@ -70,7 +71,10 @@ var (
 	feeTxHexK          = []byte("FeeTxHex")
 	feeTxHashK         = []byte("FeeTxHash")
 	feeTxStatusK       = []byte("FeeTxStatus")
+	feeErrorReasonK    = []byte("FeeErrorReason")
+	feeErrorPermK      = []byte("FeeErrorPermanent")
 	outcomeK           = []byte("Outcome")
+	outcomeHeightK     = []byte("OutcomeHeight")
 )
 
 type Ticket struct {
@@ -83,6 +87,9 @@ type Ticket struct {
 	FeeAmount         int64
 	FeeExpiration     int64
 
+	// FeePolicy is the name of the fee schedule policy which set FeeAmount.
+	FeePolicy string
+
 	// Confirmed will be set when the ticket has 6+ confirmations.
 	Confirmed bool
 
@@ -102,9 +109,20 @@ type Ticket struct {
 	// FeeTxStatus indicates the current state of the fee transaction.
 	FeeTxStatus FeeStatus
 
+	// FeeErrorReason describes why the fee tx most recently failed. It is only
+	// relevant when FeeTxStatus is FeeError.
+	FeeErrorReason string
+	// FeeErrorPermanent is set when vspd has determined the fee tx can never
+	// be broadcast (eg. its inputs are double spent), meaning no further
+	// attempts will be made and the client must provide a new fee tx.
+	FeeErrorPermanent bool
+
 	// Outcome is set once a ticket is either voted or revoked. An empty outcome
 	// indicates that a ticket is still votable.
 	Outcome TicketOutcome
+	// OutcomeHeight is the height of the block which spent the ticket. It is
+	// zero for tickets whose outcome was set before it was recorded.
+	OutcomeHeight int64
 }
 
 type TicketList []Ticket
@@ -140,6 +158,11 @@ func (t *Ticket) FeeExpired() bool {
 // InsertNewTicket will insert the provided ticket into the database. Returns an
 // error if either the ticket hash or fee address already exist.
 func (vdb *VspDatabase) InsertNewTicket(ticket Ticket) error {
+	err := vdb.wifs.encryptTicket(&ticket)
+	if err != nil {
+		return err
+	}
+
 	return vdb.db.Update(func(tx *bolt.Tx) error {
 		ticketBkt := tx.Bucket(vspBktK).Bucket(ticketBktK)
 
@@ -160,9 +183,14 @@ func (vdb *VspDatabase) InsertNewTicket(ticket Ticket) error {
 }
 
 // putTicketInBucket encodes each of the fields of the provided ticket as a byte
-// array, and stores them as values within the provided db bucket.
+// array, and stores them as values within the provided db bucket. The ticket
+// indexes are updated to reflect the new values.
 func putTicketInBucket(bkt *bolt.Bucket, ticket Ticket) error {
-	var err error
+	err := unindexTicket(bkt)
+	if err != nil {
+		return err
+	}
+
 	if err = bkt.Put(hashK, []byte(ticket.Hash)); err != nil {
 		return err
 	}
@@ -187,6 +215,15 @@ func putTicketInBucket(bkt *bolt.Bucket, ticket Ticket) error {
 	if err = bkt.Put(outcomeK, []byte(ticket.Outcome)); err != nil {
 		return err
 	}
+	if err = bkt.Put(outcomeHeightK, int64ToBytes(ticket.OutcomeHeight)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeErrorReasonK, []byte(ticket.FeeErrorReason)); err != nil {
+		return err
+	}
+	if err = bkt.Put(feeErrorPermK, boolToBytes(ticket.FeeErrorPermanent)); err != nil {
+		return err
+	}
 	if err = bkt.Put(purchaseHeightK, int64ToBytes(ticket.PurchaseHeight)); err != nil {
 		return err
 	}
@@ -202,6 +239,9 @@ func putTicketInBucket(bkt *bolt.Bucket, ticket Ticket) error {
 	if err = bkt.Put(feeExpirationK, int64ToBytes(ticket.FeeExpiration)); err != nil {
 		return err
 	}
+	if err = bkt.Put(feePolicyK, []byte(ticket.FeePolicy)); err != nil {
+		return err
+	}
 	if err = bkt.Put(confirmedK, boolToBytes(ticket.Confirmed)); err != nil {
 		return err
 	}
@@ -212,7 +252,11 @@ func putTicketInBucket(bkt *bolt.Bucket, ticket Ticket) error {
 		return err
 	}
 
-	return bkt.Put(voteChoicesK, stringMapToBytes(ticket.VoteChoices))
+	if err = bkt.Put(voteChoicesK, stringMapToBytes(ticket.VoteChoices)); err != nil {
+		return err
+	}
+
+	return indexTicket(bkt)
 }
 
 func getTicketFromBkt(bkt *bolt.Bucket) (Ticket, error) {
@@ -226,6 +270,8 @@ func getTicketFromBkt(bkt *bolt.Bucket) (Ticket, error) {
 	ticket.FeeTxHash = string(bkt.Get(feeTxHashK))
 	ticket.FeeTxStatus = FeeStatus(bkt.Get(feeTxStatusK))
 	ticket.Outcome = TicketOutcome(bkt.Get(outcomeK))
+	ticket.FeeErrorReason = string(bkt.Get(feeErrorReasonK))
+	ticket.FeePolicy = string(bkt.Get(feePolicyK))
 
 	ticket.PurchaseHeight = bytesToInt64(bkt.Get(purchaseHeightK))
 	ticket.FeeAddressXPubID = bytesToUint32(bkt.Get(feeAddressXPubIDK))
@@ -235,6 +281,17 @@ func getTicketFromBkt(bkt *bolt.Bucket) (Ticket, error) {
 
 	ticket.Confirmed = bytesToBool(bkt.Get(confirmedK))
 
+	// FeeErrorPermanent is not present in tickets which were stored before it
+	// was introduced.
+	if v := bkt.Get(feeErrorPermK); v != nil {
+		ticket.FeeErrorPermanent = bytesToBool(v)
+	}
+
+	// Likewise for OutcomeHeight.
+	if v := bkt.Get(outcomeHeightK); v != nil {
+		ticket.OutcomeHeight = bytesToInt64(v)
+	}
+
 	var err error
 	ticket.VoteChoices, err = bytesToStringMap(bkt.Get(voteChoicesK))
 	if err != nil {
@@ -254,10 +311,33 @@ func getTicketFromBkt(bkt *bolt.Bucket) (Ticket, error) {
 	return ticket, nil
 }
 
+// readTicket reads the ticket stored in the provided bucket, decrypting its
+// voting WIF if the database is unlocked.
+func (vdb *VspDatabase) readTicket(bkt *bolt.Bucket) (Ticket, error) {
+	ticket, err := getTicketFromBkt(bkt)
+	if err != nil {
+		return ticket, fmt.Errorf("could not get ticket: %w", err)
+	}
+
+	err = vdb.wifs.decryptTicket(&ticket)
+	if err != nil {
+		return ticket, err
+	}
+
+	return ticket, nil
+}
+
 func (vdb *VspDatabase) DeleteTicket(ticket Ticket) error {
 	return vdb.db.Update(func(tx *bolt.Tx) error {
 		ticketBkt := tx.Bucket(vspBktK).Bucket(ticketBktK)
 
+		if bkt := ticketBkt.Bucket([]byte(ticket.Hash)); bkt != nil {
+			err := unindexTicket(bkt)
+			if err != nil {
+				return err
+			}
+		}
+
 		err := ticketBkt.DeleteBucket([]byte(ticket.Hash))
 		if err != nil {
 			return fmt.Errorf("could not delete ticket: %w", err)
@@ -267,7 +347,49 @@ func (vdb *VspDatabase) DeleteTicket(ticket Ticket) error {
 	})
 }
 
+// PurgeTicket deletes a ticket along with its vote changes, alternate signing
+// address and event history in a single transaction, so either all of them are
+// deleted or none are.
+func (vdb *VspDatabase) PurgeTicket(ticket Ticket) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+
+		bkt := ticketBkt.Bucket([]byte(ticket.Hash))
+		if bkt == nil {
+			return fmt.Errorf("no ticket found with hash %s", ticket.Hash)
+		}
+		err := unindexTicket(bkt)
+		if err != nil {
+			return err
+		}
+		err = ticketBkt.DeleteBucket([]byte(ticket.Hash))
+		if err != nil {
+			return fmt.Errorf("could not delete ticket: %w", err)
+		}
+
+		// The ticket may not have any data in the other buckets.
+		for _, key := range [][]byte{voteChangeBktK, altSignAddrBktK, ticketEventBktK} {
+			parent := vspBkt.Bucket(key)
+			if parent.Bucket([]byte(ticket.Hash)) == nil {
+				continue
+			}
+			err = parent.DeleteBucket([]byte(ticket.Hash))
+			if err != nil {
+				return fmt.Errorf("could not delete %s of ticket: %w", key, err)
+			}
+		}
+
+		return nil
+	})
+}
+
 func (vdb *VspDatabase) UpdateTicket(ticket Ticket) error {
+	err := vdb.wifs.encryptTicket(&ticket)
+	if err != nil {
+		return err
+	}
+
 	return vdb.db.Update(func(tx *bolt.Tx) error {
 		ticketBkt := tx.Bucket(vspBktK).Bucket(ticketBktK)
 
@@ -292,9 +414,9 @@ func (vdb *VspDatabase) GetTicketByHash(ticketHash string) (Ticket, bool, error)
 		}
 
 		var err error
-		ticket, err = getTicketFromBkt(ticketBkt)
+		ticket, err = vdb.readTicket(ticketBkt)
 		if err != nil {
-			return fmt.Errorf("could not get ticket: %w", err)
+			return err
 		}
 
 		found = true
@@ -333,53 +455,55 @@ type TicketStats struct {
 // tickets. The provided block height is used to determine which tickets
 // were purchased in the previous 28d and 24h window.
 //
-// This func iterates over every ticket so should be used sparingly.
+// Archived tickets are included using running totals. This func iterates over
+// every other ticket with a confirmed fee so should be used sparingly.
 func (vdb *VspDatabase) TicketStats(blockHeight int64) (TicketStats, error) {
 	height28DaysAgo := max(blockHeight-blocksIn28Days, 1)
 	height24HoursAgo := max(blockHeight-blocksIn24Hours, 1)
 
 	var stats TicketStats
 	err := vdb.db.View(func(tx *bolt.Tx) error {
-		ticketBkt := tx.Bucket(vspBktK).Bucket(ticketBktK)
+		err := forEachIndexedTicket(tx, feeStatusIdxBktK, []byte(FeeConfirmed), func(tBkt *bolt.Bucket) error {
+			feeAmount := bytesToInt64(tBkt.Get(feeAmountK))
+			purchaseHeight := bytesToInt64(tBkt.Get(purchaseHeightK))
 
-		return ticketBkt.ForEachBucket(func(k []byte) error {
-			tBkt := ticketBkt.Bucket(k)
-
-			if FeeStatus(tBkt.Get(feeTxStatusK)) == FeeConfirmed {
-				feeAmount := bytesToInt64(tBkt.Get(feeAmountK))
-				purchaseHeight := bytesToInt64(tBkt.Get(purchaseHeightK))
-
-				stats.RevenueLifetime += feeAmount
+			stats.RevenueLifetime += feeAmount
 
-				// Purchase height is only set when tickets have 6 confs. Zero
-				// indicates they have only just been purchased, so they must
-				// count towards 28d/24h periods.
-				if purchaseHeight >= height28DaysAgo || purchaseHeight == 0 {
-					stats.Revenue28Days += feeAmount
-				}
-				if purchaseHeight >= height24HoursAgo || purchaseHeight == 0 {
-					stats.Revenue24Hours += feeAmount
-				}
+			// Purchase height is only set when tickets have 6 confs. Zero
+			// indicates they have only just been purchased, so they must
+			// count towards 28d/24h periods.
+			if purchaseHeight >= height28DaysAgo || purchaseHeight == 0 {
+				stats.Revenue28Days += feeAmount
+			}
+			if purchaseHeight >= height24HoursAgo || purchaseHeight == 0 {
+				stats.Revenue24Hours += feeAmount
+			}
 
-				switch TicketOutcome(tBkt.Get(outcomeK)) {
-				case Voted:
-					stats.Voted++
-				case Expired:
-					stats.Expired++
-				case Missed:
-					stats.Missed++
-				case Revoked:
-					// There shouldn't be any revoked tickets in the db, they
-					// should have been updated to expired/missed. Give benefit
-					// of doubt to VSP admin and count these as expired.
-					stats.Expired++
-				default:
-					stats.Voting++
-				}
+			switch TicketOutcome(tBkt.Get(outcomeK)) {
+			case Voted:
+				stats.Voted++
+			case Expired:
+				stats.Expired++
+			case Missed:
+				stats.Missed++
+			case Revoked:
+				// There shouldn't be any revoked tickets in the db, they
+				// should have been updated to expired/missed. Give benefit
+				// of doubt to VSP admin and count these as expired.
+				stats.Expired++
+			default:
+				stats.Voting++
 			}
 
 			return nil
 		})
+		if err != nil {
+			return err
+		}
+
+		addArchiveStats(tx.Bucket(vspBktK).Bucket(archiveBktK), &stats)
+
+		return nil
 	})
 
 	return stats, err
@@ -387,47 +511,50 @@ func (vdb *VspDatabase) TicketStats(blockHeight int64) (TicketStats, error) {
 
 // GetUnconfirmedTickets returns tickets which are not yet confirmed.
 func (vdb *VspDatabase) GetUnconfirmedTickets() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return !bytesToBool(t.Get(confirmedK))
-	})
+	return vdb.filterIndexedTickets(confirmedIdxBktK, boolToBytes(false), nil)
 }
 
 // GetPendingFees returns tickets which are confirmed and have a fee tx which is
 // not yet broadcast.
 func (vdb *VspDatabase) GetPendingFees() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return bytesToBool(t.Get(confirmedK)) && FeeStatus(t.Get(feeTxStatusK)) == FeeReceieved
+	return vdb.filterIndexedTickets(feeStatusIdxBktK, []byte(FeeReceieved), func(t *bolt.Bucket) bool {
+		return bytesToBool(t.Get(confirmedK))
 	})
 }
 
 // GetUnconfirmedFees returns tickets with a fee tx that is broadcast but not
 // confirmed yet.
 func (vdb *VspDatabase) GetUnconfirmedFees() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return FeeStatus(t.Get(feeTxStatusK)) == FeeBroadcast
+	return vdb.filterIndexedTickets(feeStatusIdxBktK, []byte(FeeBroadcast), nil)
+}
+
+// GetRecoverableFees returns tickets with a fee tx which could not be
+// broadcast, excluding any which have been marked as permanently failed.
+func (vdb *VspDatabase) GetRecoverableFees() (TicketList, error) {
+	return vdb.filterIndexedTickets(feeStatusIdxBktK, []byte(FeeError), func(t *bolt.Bucket) bool {
+		perm := t.Get(feeErrorPermK)
+		return perm == nil || !bytesToBool(perm)
 	})
 }
 
 // GetVotableTickets returns tickets with a confirmed fee tx and no outcome (ie.
 // not expired/voted/missed).
 func (vdb *VspDatabase) GetVotableTickets() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return FeeStatus(t.Get(feeTxStatusK)) == FeeConfirmed && TicketOutcome(t.Get(outcomeK)) == ""
+	return vdb.filterIndexedTickets(outcomeIdxBktK, nil, func(t *bolt.Bucket) bool {
+		return FeeStatus(t.Get(feeTxStatusK)) == FeeConfirmed
 	})
 }
 
 // GetVotedTickets returns tickets with a confirmed fee tx and outcome == voted.
 func (vdb *VspDatabase) GetVotedTickets() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return FeeStatus(t.Get(feeTxStatusK)) == FeeConfirmed && TicketOutcome(t.Get(outcomeK)) == Voted
+	return vdb.filterIndexedTickets(outcomeIdxBktK, []byte(Voted), func(t *bolt.Bucket) bool {
+		return FeeStatus(t.Get(feeTxStatusK)) == FeeConfirmed
 	})
 }
 
 // GetRevokedTickets returns all tickets which have outcome == revoked.
 func (vdb *VspDatabase) GetRevokedTickets() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return TicketOutcome(t.Get(outcomeK)) == Revoked
-	})
+	return vdb.filterIndexedTickets(outcomeIdxBktK, []byte(Revoked), nil)
 }
 
 // GetMissingPurchaseHeight returns tickets which are confirmed but do not have
@@ -440,9 +567,49 @@ func (vdb *VspDatabase) GetMissingPurchaseHeight() (TicketList, error) {
 
 // GetMissedTickets returns all tickets which have outcome == missed.
 func (vdb *VspDatabase) GetMissedTickets() (TicketList, error) {
-	return vdb.filterTickets(func(t *bolt.Bucket) bool {
-		return TicketOutcome(t.Get(outcomeK)) == Missed
-	})
+	return vdb.filterIndexedTickets(outcomeIdxBktK, []byte(Missed), nil)
+}
+
+// TicketFilter describes which tickets should be returned by ListTickets.
+// Fields which are left empty match every ticket.
+type TicketFilter struct {
+	FeeStatus FeeStatus
+	Outcome   TicketOutcome
+	// MinHeight and MaxHeight are an inclusive range of purchase heights.
+	// Unconfirmed tickets have a purchase height of zero.
+	MinHeight int64
+	MaxHeight int64
+}
+
+// ListTickets returns all tickets which match every criteria in the provided
+// filter.
+func (vdb *VspDatabase) ListTickets(filter TicketFilter) (TicketList, error) {
+	match := func(t *bolt.Bucket) bool {
+		if filter.FeeStatus != "" && FeeStatus(t.Get(feeTxStatusK)) != filter.FeeStatus {
+			return false
+		}
+		if filter.Outcome != "" && TicketOutcome(t.Get(outcomeK)) != filter.Outcome {
+			return false
+		}
+		height := bytesToInt64(t.Get(purchaseHeightK))
+		if filter.MinHeight != 0 && height < filter.MinHeight {
+			return false
+		}
+		if filter.MaxHeight != 0 && height > filter.MaxHeight {
+			return false
+		}
+		return true
+	}
+
+	// Use an index to avoid visiting every ticket when possible.
+	switch {
+	case filter.FeeStatus != "":
+		return vdb.filterIndexedTickets(feeStatusIdxBktK, []byte(filter.FeeStatus), match)
+	case filter.Outcome != "":
+		return vdb.filterIndexedTickets(outcomeIdxBktK, []byte(filter.Outcome), match)
+	default:
+		return vdb.filterTickets(match)
+	}
 }
 
 // filterTickets accepts a filter function and returns all tickets from the
@@ -456,9 +623,9 @@ func (vdb *VspDatabase) filterTickets(filter func(*bolt.Bucket) bool) (TicketLis
 			ticketBkt := ticketBkt.Bucket(k)
 
 			if filter(ticketBkt) {
-				ticket, err := getTicketFromBkt(ticketBkt)
+				ticket, err := vdb.readTicket(ticketBkt)
 				if err != nil {
-					return fmt.Errorf("could not get ticket: %w", err)
+					return err
 				}
 				tickets = append(tickets, ticket)
 			}
@@ -21,6 +21,7 @@ func exampleTicket() Ticket {
 		FeeAddress:        randString(35, addrCharset),
 		FeeAmount:         1e7,
 		FeeExpiration:     4,
+		FeePolicy:         "default",
 		Confirmed:         false,
 		VoteChoices:       map[string]string{"AgendaID": "yes"},
 		TSpendPolicy:      map[string]string{randString(64, hexCharset): "no"},
@@ -29,6 +30,8 @@ func exampleTicket() Ticket {
 		FeeTxHex:          randString(504, hexCharset),
 		FeeTxHash:         randString(64, hexCharset),
 		FeeTxStatus:       FeeBroadcast,
+		FeeErrorReason:    randString(20, addrCharset),
+		FeeErrorPermanent: true,
 	}
 }
 
@@ -86,6 +89,75 @@ func testDeleteTicket(t *testing.T) {
 	}
 }
 
+func testPurgeTicket(t *testing.T) {
+	// Insert a ticket along with data in every table which references it.
+	ticket := exampleTicket()
+	err := db.InsertNewTicket(ticket)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+	err = db.SaveVoteChange(ticket.Hash, exampleRecord())
+	if err != nil {
+		t.Fatalf("error storing vote change record: %v", err)
+	}
+	err = db.InsertAltSignAddr(ticket.Hash, exampleAltSignAddrData())
+	if err != nil {
+		t.Fatalf("error storing alt sign addr: %v", err)
+	}
+	err = db.AppendTicketEvent(ticket.Hash, TicketEvent{Timestamp: 1, Type: EventFeeAddress})
+	if err != nil {
+		t.Fatalf("error storing ticket event: %v", err)
+	}
+
+	err = db.PurgeTicket(ticket)
+	if err != nil {
+		t.Fatalf("error purging ticket: %v", err)
+	}
+
+	// Nothing should be in the db.
+	_, found, err := db.GetTicketByHash(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket by ticket hash: %v", err)
+	}
+	if found {
+		t.Fatal("expected found==false")
+	}
+	voteChanges, err := db.GetVoteChanges(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving vote changes: %v", err)
+	}
+	altSignAddr, err := db.AltSignAddrData(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving alt sign addr: %v", err)
+	}
+	events, err := db.TicketEvents(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket events: %v", err)
+	}
+	if len(voteChanges) != 0 || altSignAddr != nil || len(events) != 0 {
+		t.Fatalf("expected no ticket data, got %d vote changes, alt sign addr %v and %d events",
+			len(voteChanges), altSignAddr, len(events))
+	}
+
+	// Purging a ticket which does not exist should fail, and must not delete
+	// data stored for it.
+	err = db.SaveVoteChange(ticket.Hash, exampleRecord())
+	if err != nil {
+		t.Fatalf("error storing vote change record: %v", err)
+	}
+	err = db.PurgeTicket(ticket)
+	if err == nil {
+		t.Fatal("expected an error purging unknown ticket")
+	}
+	voteChanges, err = db.GetVoteChanges(ticket.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving vote changes: %v", err)
+	}
+	if len(voteChanges) != 1 {
+		t.Fatalf("expected 1 vote change after failed purge, got %d", len(voteChanges))
+	}
+}
+
 func testGetTicketByHash(t *testing.T) {
 	// Insert a ticket into the database.
 	ticket := exampleTicket()
@@ -177,6 +249,8 @@ func testTicketFeeExpired(t *testing.T) {
 }
 
 func testFilterTickets(t *testing.T) {
+	db := db.(*VspDatabase)
+
 	// Insert a ticket.
 	ticket := exampleTicket()
 	err := db.InsertNewTicket(ticket)
@@ -229,6 +303,89 @@ func testFilterTickets(t *testing.T) {
 	}
 }
 
+func testListTickets(t *testing.T) {
+	// Insert some tickets with varying heights, fee statuses and outcomes.
+	tickets := make([]Ticket, 4)
+	for i := range tickets {
+		tickets[i] = exampleTicket()
+		tickets[i].PurchaseHeight = int64(100 * (i + 1))
+	}
+	tickets[0].FeeTxStatus = FeeError
+	tickets[1].FeeTxStatus = FeeConfirmed
+	tickets[1].Outcome = Voted
+	tickets[2].FeeTxStatus = FeeConfirmed
+	tickets[2].Outcome = Missed
+	tickets[3].FeeTxStatus = FeeConfirmed
+
+	for _, ticket := range tickets {
+		err := db.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	tests := map[string]struct {
+		filter   TicketFilter
+		expected int
+	}{
+		"no filter":              {TicketFilter{}, 4},
+		"fee status":             {TicketFilter{FeeStatus: FeeConfirmed}, 3},
+		"outcome":                {TicketFilter{Outcome: Voted}, 1},
+		"min height":             {TicketFilter{MinHeight: 200}, 3},
+		"max height":             {TicketFilter{MaxHeight: 200}, 2},
+		"height range":           {TicketFilter{MinHeight: 200, MaxHeight: 300}, 2},
+		"fee status and outcome": {TicketFilter{FeeStatus: FeeConfirmed, Outcome: Missed}, 1},
+		"fee status and height":  {TicketFilter{FeeStatus: FeeError, MinHeight: 200}, 0},
+		"unmatched fee status":   {TicketFilter{FeeStatus: FeeReceieved}, 0},
+		"unmatched height range": {TicketFilter{MinHeight: 1000}, 0},
+	}
+
+	for testName, test := range tests {
+		retrieved, err := db.ListTickets(test.filter)
+		if err != nil {
+			t.Fatalf("%s: error listing tickets: %v", testName, err)
+		}
+		if len(retrieved) != test.expected {
+			t.Fatalf("%s: expected to find %d tickets, found %d",
+				testName, test.expected, len(retrieved))
+		}
+	}
+}
+
+func testGetRecoverableFees(t *testing.T) {
+	// Insert a ticket with a recoverable fee error, one with a permanent fee
+	// error, and one without any fee error.
+	recoverable := exampleTicket()
+	recoverable.FeeTxStatus = FeeError
+	recoverable.FeeErrorPermanent = false
+
+	permanent := exampleTicket()
+	permanent.FeeTxStatus = FeeError
+	permanent.FeeErrorPermanent = true
+
+	broadcast := exampleTicket()
+	broadcast.FeeTxStatus = FeeBroadcast
+	broadcast.FeeErrorPermanent = false
+
+	for _, ticket := range []Ticket{recoverable, permanent, broadcast} {
+		err := db.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	retrieved, err := db.GetRecoverableFees()
+	if err != nil {
+		t.Fatalf("error getting recoverable fees: %v", err)
+	}
+	if len(retrieved) != 1 {
+		t.Fatalf("expected to find 1 ticket, found %d", len(retrieved))
+	}
+	if retrieved[0].Hash != recoverable.Hash {
+		t.Fatal("expected to find the recoverable ticket")
+	}
+}
+
 func testTicketStatsCounts(t *testing.T) {
 	count := func(test string, expectedVoting, expectedVoted, expectedExpired, expectedMissed int64) {
 		t.Helper()
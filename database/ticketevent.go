@@ -0,0 +1,161 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/decred/slog"
+	bolt "go.etcd.io/bbolt"
+)
+
+// TicketEventType describes a change in the state of a ticket.
+type TicketEventType string
+
+const (
+	// EventFeeAddress indicates a fee address and amount were issued for a
+	// ticket which was previously unknown to the VSP.
+	EventFeeAddress TicketEventType = "feeaddress"
+	// EventFeeRepriced indicates a new fee amount was issued because the
+	// previous fee had expired.
+	EventFeeRepriced TicketEventType = "feerepriced"
+	// EventFeeReceived indicates a fee transaction was received from the
+	// client.
+	EventFeeReceived TicketEventType = "feereceived"
+	// EventFeeBroadcast indicates the fee transaction was broadcast.
+	EventFeeBroadcast TicketEventType = "feebroadcast"
+	// EventFeeError indicates the fee transaction could not be broadcast or
+	// could not be found once broadcast.
+	EventFeeError TicketEventType = "feeerror"
+	// EventTicketConfirmed indicates the ticket purchase transaction reached
+	// the required number of confirmations.
+	EventTicketConfirmed TicketEventType = "ticketconfirmed"
+	// EventFeeConfirmed indicates the fee transaction reached the required
+	// number of confirmations.
+	EventFeeConfirmed TicketEventType = "feeconfirmed"
+	// EventAddedToWallet indicates the ticket was added to a voting wallet.
+	EventAddedToWallet TicketEventType = "addedtowallet"
+	// EventOutcome indicates the ticket was spent and its outcome recorded.
+	EventOutcome TicketEventType = "outcome"
+	// EventAdminChange indicates the ticket was manually modified by the VSP
+	// operator.
+	EventAdminChange TicketEventType = "adminchange"
+)
+
+// TicketEvent is serialized to json and stored in bbolt db. The json keys are
+// deliberately kept short because they are duplicated many times in the db.
+type TicketEvent struct {
+	// Timestamp is the unix time at which the event occurred.
+	Timestamp int64           `json:"t"`
+	Type      TicketEventType `json:"typ"`
+	// Detail is a human readable description of the event, eg. the fee amount
+	// which was issued or the wallet the ticket was added to.
+	Detail string `json:"d,omitempty"`
+}
+
+// AddTicketEvent adds an event which occurred now to the history of a
+// ticket. Errors are logged rather than returned because failing to record
+// history should not prevent the ticket from being processed.
+func AddTicketEvent(db Database, log slog.Logger, ticketHash string,
+	eventType TicketEventType, detail string) {
+
+	err := db.AppendTicketEvent(ticketHash, TicketEvent{
+		Timestamp: time.Now().Unix(),
+		Type:      eventType,
+		Detail:    detail,
+	})
+	if err != nil {
+		log.Errorf("db.AppendTicketEvent error (ticketHash=%s): %v", ticketHash, err)
+	}
+}
+
+// AppendTicketEvent adds the provided event to the end of the event history of
+// the ticket with the provided hash. Events are never modified once stored.
+func (vdb *VspDatabase) AppendTicketEvent(ticketHash string, event TicketEvent) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		// Create or get a bucket for this ticket.
+		bkt, err := tx.Bucket(vspBktK).Bucket(ticketEventBktK).
+			CreateBucketIfNotExists([]byte(ticketHash))
+		if err != nil {
+			return fmt.Errorf("failed to create ticket event bucket (ticketHash=%s): %w",
+				ticketHash, err)
+		}
+
+		// Events are stored using a serially increasing integer as the key, so
+		// iterating over the bucket returns them in the order they were added.
+		id, err := bkt.NextSequence()
+		if err != nil {
+			return fmt.Errorf("could not get next ticket event ID: %w", err)
+		}
+
+		eventBytes, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("could not marshal ticket event: %w", err)
+		}
+
+		err = bkt.Put(uint64ToBytes(id), eventBytes)
+		if err != nil {
+			return fmt.Errorf("could not store ticket event: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// TicketEvents retrieves the event history of the ticket with the provided
+// hash, oldest first.
+func (vdb *VspDatabase) TicketEvents(ticketHash string) ([]TicketEvent, error) {
+	var events []TicketEvent
+
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(vspBktK).Bucket(ticketEventBktK).
+			Bucket([]byte(ticketHash))
+
+		if bkt == nil {
+			return nil
+		}
+
+		err := bkt.ForEach(func(_, v []byte) error {
+			var event TicketEvent
+			err := json.Unmarshal(v, &event)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal ticket event: %w", err)
+			}
+
+			events = append(events, event)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over ticket event bucket: %w", err)
+		}
+
+		return nil
+	})
+
+	return events, err
+}
+
+// DeleteTicketEvents deletes the event history of the ticket with the provided
+// hash. Does not error if there is no history for the ticket.
+func (vdb *VspDatabase) DeleteTicketEvents(ticketHash string) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		eventBkt := tx.Bucket(vspBktK).Bucket(ticketEventBktK)
+
+		// Don't attempt delete if doesn't exist.
+		if eventBkt.Bucket([]byte(ticketHash)) == nil {
+			return nil
+		}
+
+		err := eventBkt.DeleteBucket([]byte(ticketHash))
+		if err != nil {
+			return fmt.Errorf("could not delete ticket events: %w", err)
+		}
+
+		return nil
+	})
+}
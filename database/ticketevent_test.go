@@ -0,0 +1,67 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testTicketEvents(t *testing.T) {
+	const hash = "MyHash"
+
+	// A ticket with no history should return no events.
+	retrieved, err := db.TicketEvents(hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket events: %v", err)
+	}
+	if len(retrieved) != 0 {
+		t.Fatalf("expected no ticket events, got %d", len(retrieved))
+	}
+
+	// More events than there are vote change records to ensure there is no
+	// limit on the number of events stored.
+	var expected []TicketEvent
+	for i := range maxVoteChangeRecords * 100 {
+		event := TicketEvent{
+			Timestamp: int64(1000 + i),
+			Type:      EventAddedToWallet,
+			Detail:    randString(20, addrCharset),
+		}
+		err = db.AppendTicketEvent(hash, event)
+		if err != nil {
+			t.Fatalf("error storing ticket event: %v", err)
+		}
+		expected = append(expected, event)
+	}
+
+	// Events should be returned in the order they were added.
+	retrieved, err = db.TicketEvents(hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket events: %v", err)
+	}
+	if !reflect.DeepEqual(retrieved, expected) {
+		t.Fatal("retrieved ticket events didnt match expected")
+	}
+
+	// Delete events and ensure they are gone.
+	err = db.DeleteTicketEvents(hash)
+	if err != nil {
+		t.Fatalf("error deleting ticket events: %v", err)
+	}
+	retrieved, err = db.TicketEvents(hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket events: %v", err)
+	}
+	if len(retrieved) != 0 {
+		t.Fatalf("expected no ticket events, got %d", len(retrieved))
+	}
+
+	// Deleting events for a ticket with no history should not error.
+	err = db.DeleteTicketEvents(hash)
+	if err != nil {
+		t.Fatalf("error deleting ticket events: %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"bytes"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// Tickets are indexed by the values of a few of their fields so that the
+// queries run on every block only need to visit the tickets they return, rather
+// than every ticket ever stored. Each index is a bucket nested in ticketidxbkt,
+// containing one bucket per distinct field value. Those buckets contain the
+// hashes of the tickets with that value as keys, with empty values.
+var (
+	// feeStatusIdxBktK indexes tickets by fee tx status.
+	feeStatusIdxBktK = []byte("feestatus")
+	// outcomeIdxBktK indexes tickets by outcome.
+	outcomeIdxBktK = []byte("outcome")
+	// confirmedIdxBktK indexes tickets by confirmed flag.
+	confirmedIdxBktK = []byte("confirmed")
+
+	// emptyIdxValueK is used in place of empty field values, eg. the outcome of
+	// a votable ticket, because bbolt does not allow empty bucket names.
+	emptyIdxValueK = []byte("<empty>")
+)
+
+// ticketIndex describes an index of tickets by the value of a single field.
+type ticketIndex struct {
+	bktK   []byte
+	fieldK []byte
+}
+
+var ticketIndexes = []ticketIndex{
+	{feeStatusIdxBktK, feeTxStatusK},
+	{outcomeIdxBktK, outcomeK},
+	{confirmedIdxBktK, confirmedK},
+}
+
+func idxValueKey(value []byte) []byte {
+	if len(value) == 0 {
+		return emptyIdxValueK
+	}
+	return value
+}
+
+// createTicketIndexBuckets creates the empty ticket index buckets within the
+// provided vsp bucket.
+func createTicketIndexBuckets(vspBkt *bolt.Bucket) error {
+	idxBkt, err := vspBkt.CreateBucket(ticketIdxBktK)
+	if err != nil {
+		return fmt.Errorf("failed to create %s bucket: %w", ticketIdxBktK, err)
+	}
+
+	for _, idx := range ticketIndexes {
+		_, err = idxBkt.CreateBucket(idx.bktK)
+		if err != nil {
+			return fmt.Errorf("failed to create %s bucket: %w", idx.bktK, err)
+		}
+	}
+
+	return nil
+}
+
+// ticketIdxBkt returns the bucket containing the ticket indexes, or nil if the
+// database has not yet been upgraded to ticketIndexVersion.
+func ticketIdxBkt(tx *bolt.Tx) *bolt.Bucket {
+	return tx.Bucket(vspBktK).Bucket(ticketIdxBktK)
+}
+
+// indexTicket adds the ticket stored in the provided bucket to every ticket
+// index.
+func indexTicket(bkt *bolt.Bucket) error {
+	idxBkt := ticketIdxBkt(bkt.Tx())
+	if idxBkt == nil {
+		// Earlier database upgrades write tickets before the index buckets
+		// exist. They are indexed by ticketIndexUpgrade instead.
+		return nil
+	}
+
+	hash := bytes.Clone(bkt.Get(hashK))
+
+	for _, idx := range ticketIndexes {
+		valueBkt, err := idxBkt.Bucket(idx.bktK).CreateBucketIfNotExists(
+			idxValueKey(bkt.Get(idx.fieldK)))
+		if err != nil {
+			return fmt.Errorf("failed to create %s index bucket: %w", idx.bktK, err)
+		}
+
+		err = valueBkt.Put(hash, []byte{})
+		if err != nil {
+			return fmt.Errorf("failed to add ticket to %s index: %w", idx.bktK, err)
+		}
+	}
+
+	return nil
+}
+
+// unindexTicket removes the ticket stored in the provided bucket from every
+// ticket index. It does nothing if the bucket does not yet contain a ticket.
+func unindexTicket(bkt *bolt.Bucket) error {
+	idxBkt := ticketIdxBkt(bkt.Tx())
+	if idxBkt == nil {
+		return nil
+	}
+
+	hash := bkt.Get(hashK)
+	if hash == nil {
+		return nil
+	}
+
+	for _, idx := range ticketIndexes {
+		valueBkt := idxBkt.Bucket(idx.bktK).Bucket(idxValueKey(bkt.Get(idx.fieldK)))
+		if valueBkt == nil {
+			continue
+		}
+
+		err := valueBkt.Delete(hash)
+		if err != nil {
+			return fmt.Errorf("failed to remove ticket from %s index: %w", idx.bktK, err)
+		}
+	}
+
+	return nil
+}
+
+// forEachIndexedTicket calls f with the bucket of every ticket which has the
+// provided value in the provided index.
+func forEachIndexedTicket(tx *bolt.Tx, idxK, value []byte, f func(*bolt.Bucket) error) error {
+	ticketBkt := tx.Bucket(vspBktK).Bucket(ticketBktK)
+
+	valueBkt := ticketIdxBkt(tx).Bucket(idxK).Bucket(idxValueKey(value))
+	if valueBkt == nil {
+		// No ticket has ever had this value.
+		return nil
+	}
+
+	return valueBkt.ForEach(func(k, _ []byte) error {
+		tBkt := ticketBkt.Bucket(k)
+		if tBkt == nil {
+			return fmt.Errorf("%s index contains missing ticket %s", idxK, k)
+		}
+		return f(tBkt)
+	})
+}
+
+// filterIndexedTickets returns all tickets which have the provided value in the
+// provided index and also match the filter function. A nil filter function
+// matches every ticket.
+func (vdb *VspDatabase) filterIndexedTickets(idxK, value []byte, filter func(*bolt.Bucket) bool) (TicketList, error) {
+	var tickets TicketList
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		return forEachIndexedTicket(tx, idxK, value, func(tBkt *bolt.Bucket) error {
+			if filter != nil && !filter(tBkt) {
+				return nil
+			}
+
+			ticket, err := vdb.readTicket(tBkt)
+			if err != nil {
+				return err
+			}
+			tickets = append(tickets, ticket)
+
+			return nil
+		})
+	})
+
+	return tickets, err
+}
@@ -0,0 +1,179 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"testing"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// indexContents returns the hashes of the tickets contained in each value
+// bucket of each ticket index, keyed by index name then value.
+func indexContents(t *testing.T, db *VspDatabase) map[string]map[string][]string {
+	t.Helper()
+
+	contents := make(map[string]map[string][]string)
+	err := db.db.View(func(tx *bolt.Tx) error {
+		for _, idx := range ticketIndexes {
+			idxBkt := ticketIdxBkt(tx).Bucket(idx.bktK)
+			values := make(map[string][]string)
+			err := idxBkt.ForEachBucket(func(value []byte) error {
+				return idxBkt.Bucket(value).ForEach(func(hash, _ []byte) error {
+					values[string(value)] = append(values[string(value)], string(hash))
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+			contents[string(idx.bktK)] = values
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("error reading ticket indexes: %v", err)
+	}
+
+	return contents
+}
+
+// expectedIndexContents returns the expected contents of the ticket indexes,
+// found by iterating over every ticket in the database.
+func expectedIndexContents(t *testing.T, db *VspDatabase) map[string]map[string][]string {
+	t.Helper()
+
+	contents := make(map[string]map[string][]string)
+	for _, idx := range ticketIndexes {
+		contents[string(idx.bktK)] = make(map[string][]string)
+	}
+
+	_, err := db.filterTickets(func(bkt *bolt.Bucket) bool {
+		for _, idx := range ticketIndexes {
+			value := string(idxValueKey(bkt.Get(idx.fieldK)))
+			values := contents[string(idx.bktK)]
+			values[value] = append(values[value], string(bkt.Get(hashK)))
+		}
+		return false
+	})
+	if err != nil {
+		t.Fatalf("error filtering tickets: %v", err)
+	}
+
+	return contents
+}
+
+func checkTicketIndexes(t *testing.T, db *VspDatabase) {
+	t.Helper()
+
+	expected := expectedIndexContents(t, db)
+	actual := indexContents(t, db)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("ticket indexes do not match tickets, expected %v, got %v",
+			expected, actual)
+	}
+}
+
+func testTicketIndexes(t *testing.T) {
+	db := db.(*VspDatabase)
+
+	// Insert some tickets in varying states.
+	tickets := make([]Ticket, 5)
+	for i := range tickets {
+		tickets[i] = exampleTicket()
+	}
+	tickets[0].FeeTxStatus = NoFee
+	tickets[1].FeeTxStatus = FeeReceieved
+	tickets[1].Confirmed = true
+	tickets[2].FeeTxStatus = FeeConfirmed
+	tickets[2].Confirmed = true
+	tickets[3].FeeTxStatus = FeeConfirmed
+	tickets[3].Confirmed = true
+	tickets[3].Outcome = Voted
+	tickets[4].FeeTxStatus = FeeError
+
+	for _, ticket := range tickets {
+		err := db.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	checkTicketIndexes(t, db)
+
+	// Update every indexed field of some tickets.
+	tickets[0].FeeTxStatus = FeeReceieved
+	tickets[0].Confirmed = true
+	tickets[2].Outcome = Missed
+	tickets[4].FeeTxStatus = FeeBroadcast
+
+	for _, i := range []int{0, 2, 4} {
+		err := db.UpdateTicket(tickets[i])
+		if err != nil {
+			t.Fatalf("error updating ticket: %v", err)
+		}
+	}
+
+	checkTicketIndexes(t, db)
+
+	// Delete a ticket.
+	err := db.DeleteTicket(tickets[1])
+	if err != nil {
+		t.Fatalf("error deleting ticket: %v", err)
+	}
+
+	checkTicketIndexes(t, db)
+
+	// Queries should only return tickets from the relevant index.
+	pending, err := db.GetPendingFees()
+	if err != nil {
+		t.Fatalf("error getting pending fees: %v", err)
+	}
+	if len(pending) != 1 || pending[0].Hash != tickets[0].Hash {
+		t.Fatalf("expected pending fee for ticket %s, got %v", tickets[0].Hash, pending)
+	}
+
+	unconfirmed, err := db.GetUnconfirmedTickets()
+	if err != nil {
+		t.Fatalf("error getting unconfirmed tickets: %v", err)
+	}
+	if len(unconfirmed) != 1 || unconfirmed[0].Hash != tickets[4].Hash {
+		t.Fatalf("expected unconfirmed ticket %s, got %v", tickets[4].Hash, unconfirmed)
+	}
+
+	// Remove the indexes and the buckets added by later upgrades, and rewind
+	// the database version to simulate a database created before the indexes
+	// were introduced, then ensure the upgrade rebuilds them correctly.
+	err = db.db.Update(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		for _, k := range [][]byte{ticketIdxBktK, archiveBktK, vspStatusBktK, maintenanceBktK} {
+			err := vspBkt.DeleteBucket(k)
+			if err != nil {
+				return err
+			}
+		}
+		return vspBkt.Put(versionK, uint32ToBytes(ticketEventVersion))
+	})
+	if err != nil {
+		t.Fatalf("error removing ticket indexes: %v", err)
+	}
+
+	defer removeDatabaseFiles(PreUpgradeBackupFile(testDb, ticketEventVersion))
+	err = db.Upgrade(ticketEventVersion)
+	if err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+
+	version, err := db.Version()
+	if err != nil {
+		t.Fatalf("error getting db version: %v", err)
+	}
+	if version != latestVersion {
+		t.Fatalf("expected db version %d, got %d", latestVersion, version)
+	}
+
+	checkTicketIndexes(t, db)
+}
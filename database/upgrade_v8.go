@@ -0,0 +1,59 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"fmt"
+
+	"github.com/decred/slog"
+	bolt "go.etcd.io/bbolt"
+)
+
+func ticketIndexUpgrade(db *bolt.DB, log slog.Logger) error {
+	log.Infof("Upgrading database to version %d", ticketIndexVersion)
+
+	// Run the upgrade in a single database transaction so it can be safely
+	// rolled back if an error is encountered.
+	err := db.Update(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+
+		// Create ticket index buckets.
+		err := createTicketIndexBuckets(vspBkt)
+		if err != nil {
+			return err
+		}
+
+		// Add every existing ticket to the indexes.
+		var count int
+		err = ticketBkt.ForEachBucket(func(k []byte) error {
+			err := indexTicket(ticketBkt.Bucket(k))
+			if err != nil {
+				return fmt.Errorf("failed to index ticket %s: %w", k, err)
+			}
+			count++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Infof("Indexed %d tickets", count)
+
+		// Update database version.
+		err = vspBkt.Put(versionK, uint32ToBytes(ticketIndexVersion))
+		if err != nil {
+			return fmt.Errorf("failed to update db version: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("Upgrade completed")
+	return nil
+}
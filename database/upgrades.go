@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -6,7 +6,10 @@ package database
 
 import (
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/slog"
 	bolt "go.etcd.io/bbolt"
 )
@@ -37,10 +40,35 @@ const (
 	// keys as well as the current key.
 	xPubBucketVersion = 5
 
+	// webhookVersion adds a bucket to store webhook deliveries which have not
+	// yet succeeded so they can be retried, even after a restart.
+	webhookVersion = 6
+
+	// ticketEventVersion adds a bucket to store a history of the state
+	// changes of each ticket.
+	ticketEventVersion = 7
+
+	// ticketIndexVersion adds buckets which index tickets by fee status,
+	// outcome and confirmed flag, so that finding tickets in a particular state
+	// does not require iterating over every ticket in the database.
+	ticketIndexVersion = 8
+
+	// archiveVersion adds a bucket to store compact records of voted and
+	// revoked tickets which have been archived.
+	archiveVersion = 9
+
+	// vspStatusVersion adds a bucket to store the history of changes to the
+	// open or closed status of the VSP made while vspd is running.
+	vspStatusVersion = 10
+
+	// maintenanceVersion adds a bucket to store maintenance windows scheduled
+	// by an admin.
+	maintenanceVersion = 11
+
 	// latestVersion is the latest version of the database that is understood by
 	// vspd. Databases with recorded versions higher than this will fail to open
 	// (meaning any upgrades prevent reverting to older software).
-	latestVersion = xPubBucketVersion
+	latestVersion = maintenanceVersion
 )
 
 // upgrades maps between old database versions and the upgrade function to
@@ -50,6 +78,12 @@ var upgrades = []func(tx *bolt.DB, log slog.Logger) error{
 	removeOldFeeTxVersion: ticketBucketUpgrade,
 	ticketBucketVersion:   altSignAddrUpgrade,
 	altSignAddrVersion:    xPubBucketUpgrade,
+	xPubBucketVersion:     webhookUpgrade,
+	webhookVersion:        ticketEventUpgrade,
+	ticketEventVersion:    ticketIndexUpgrade,
+	ticketIndexVersion:    archiveUpgrade,
+	archiveVersion:        vspStatusUpgrade,
+	vspStatusVersion:      maintenanceUpgrade,
 }
 
 // v1Ticket has the json tags required to unmarshal tickets stored in the
@@ -71,7 +105,16 @@ type v1Ticket struct {
 	Outcome           TicketOutcome     `json:"otcme"`
 }
 
-// Upgrade will update the database to the latest known version.
+// PreUpgradeBackupFile returns the path of the backup file which is written
+// for the database at dbFile before it is upgraded from the provided version.
+func PreUpgradeBackupFile(dbFile string, version uint32) string {
+	return fmt.Sprintf("%s-v%d-backup", dbFile, version)
+}
+
+// Upgrade will update the database to the latest known version. A copy of the
+// database is written to PreUpgradeBackupFile before any upgrades are applied,
+// so that it can be restored if the new version of vspd needs to be rolled
+// back.
 func (vdb *VspDatabase) Upgrade(currentVersion uint32) error {
 	if currentVersion == latestVersion {
 		// No upgrades required.
@@ -84,7 +127,40 @@ func (vdb *VspDatabase) Upgrade(currentVersion uint32) error {
 			latestVersion, currentVersion)
 	}
 
-	// Execute all necessary upgrades in order.
+	err := vdb.writePreUpgradeBackup(currentVersion)
+	if err != nil {
+		return fmt.Errorf("failed to write pre-upgrade backup: %w", err)
+	}
+
+	return vdb.applyUpgrades(currentVersion)
+}
+
+// writePreUpgradeBackup writes a copy of the database to PreUpgradeBackupFile.
+// Any existing file is replaced, because it cannot be newer than the database.
+func (vdb *VspDatabase) writePreUpgradeBackup(currentVersion uint32) error {
+	backupPath := PreUpgradeBackupFile(vdb.db.Path(), currentVersion)
+	tempPath := backupPath + "~"
+
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(tempPath, backupFileMode)
+	})
+	if err != nil {
+		return fmt.Errorf("tx.CopyFile: %w", err)
+	}
+
+	err = os.Rename(tempPath, backupPath)
+	if err != nil {
+		return fmt.Errorf("os.Rename: %w", err)
+	}
+
+	vdb.log.Infof("Database version %d backed up to %s before upgrade", currentVersion, backupPath)
+
+	return nil
+}
+
+// applyUpgrades executes all upgrades required to update the database from
+// currentVersion to the latest version.
+func (vdb *VspDatabase) applyUpgrades(currentVersion uint32) error {
 	for _, upgrade := range upgrades[currentVersion:] {
 		err := upgrade(vdb.db, vdb.log)
 		if err != nil {
@@ -94,3 +170,145 @@ func (vdb *VspDatabase) Upgrade(currentVersion uint32) error {
 
 	return nil
 }
+
+// UpgradeReport describes the upgrade of a database to the latest version.
+type UpgradeReport struct {
+	FromVersion uint32
+	ToVersion   uint32
+	// Backup is the path of the copy of the database written before it was
+	// upgraded. It is empty if no upgrades were required, or for a dry run.
+	Backup string
+	// Verify is the result of verifying the upgraded database. It is only set
+	// for a dry run.
+	Verify VerifyReport
+}
+
+// Upgrade opens the database file at dbFile using the storage backend, which
+// upgrades it to the latest version in exactly the same way as when it is
+// opened by vspd.
+func (b Backend) Upgrade(dbFile string, log slog.Logger) (UpgradeReport, error) {
+	switch b {
+	case BoltBackend:
+		return upgradeBolt(dbFile, log)
+	case SQLiteBackend:
+		return upgradeSQLite(dbFile, log)
+	default:
+		return UpgradeReport{}, fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// DryRunUpgrade upgrades a temporary copy of the database file at dbFile to the
+// latest version, writing the messages logged by each upgrade to log, and then
+// verifies the upgraded copy as VerifyBackup would. The database file is not
+// modified. If verification fails, the error describing the problems is
+// returned along with the report.
+func (b Backend) DryRunUpgrade(dbFile string, params *chaincfg.Params, log slog.Logger) (UpgradeReport, error) {
+	switch b {
+	case BoltBackend:
+		return dryRunUpgradeBolt(dbFile, params, log)
+	case SQLiteBackend:
+		return dryRunUpgradeSQLite(dbFile, params, log)
+	default:
+		return UpgradeReport{}, fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// openBoltReadOnly opens an existing bbolt database file read-only, and
+// returns its version.
+func openBoltReadOnly(dbFile string) (*bolt.DB, uint32, error) {
+	// bolt.Open would create the file if it does not exist.
+	_, err := os.Stat(dbFile)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	db, err := bolt.Open(dbFile, 0600, &bolt.Options{
+		Timeout:      1 * time.Second,
+		ReadOnly:     true,
+		NoStatistics: true,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("unable to open db file: %w", err)
+	}
+
+	var version uint32
+	err = db.View(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		if vspBkt == nil {
+			return fmt.Errorf("%s is not a vspd database", dbFile)
+		}
+		version = bytesToUint32(vspBkt.Get(versionK))
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, 0, err
+	}
+
+	if version > latestVersion {
+		db.Close()
+		return nil, 0, fmt.Errorf("expected database version <= %d, got %d",
+			latestVersion, version)
+	}
+
+	return db, version, nil
+}
+
+func upgradeBolt(dbFile string, log slog.Logger) (UpgradeReport, error) {
+	db, version, err := openBoltReadOnly(dbFile)
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	db.Close()
+
+	vdb, err := Open(dbFile, log, 0)
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	vdb.Close(false)
+
+	report := UpgradeReport{FromVersion: version, ToVersion: latestVersion}
+	if version < latestVersion {
+		report.Backup = PreUpgradeBackupFile(dbFile, version)
+	}
+
+	return report, nil
+}
+
+func dryRunUpgradeBolt(dbFile string, params *chaincfg.Params, log slog.Logger) (UpgradeReport, error) {
+	db, version, err := openBoltReadOnly(dbFile)
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	defer db.Close()
+
+	f, err := os.CreateTemp("", "vspd-upgrade-*.db")
+	if err != nil {
+		return UpgradeReport{}, err
+	}
+	f.Close()
+	defer os.Remove(f.Name())
+
+	err = db.View(func(tx *bolt.Tx) error {
+		return tx.CopyFile(f.Name(), 0600)
+	})
+	if err != nil {
+		return UpgradeReport{}, fmt.Errorf("tx.CopyFile: %w", err)
+	}
+
+	copyDB, err := bolt.Open(f.Name(), 0600, &bolt.Options{Timeout: 1 * time.Second})
+	if err != nil {
+		return UpgradeReport{}, fmt.Errorf("unable to open copy of db file: %w", err)
+	}
+	defer copyDB.Close()
+
+	vdb := &VspDatabase{db: copyDB, log: log}
+	err = vdb.applyUpgrades(version)
+	if err != nil {
+		return UpgradeReport{}, fmt.Errorf("upgrade failed: %w", err)
+	}
+
+	report := UpgradeReport{FromVersion: version, ToVersion: latestVersion}
+	report.Verify, err = vdb.verify(params)
+	return report, err
+}
@@ -0,0 +1,231 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/slog"
+	bolt "go.etcd.io/bbolt"
+)
+
+// boltFileVersion returns the version of the bbolt database file at dbFile.
+func boltFileVersion(t *testing.T, dbFile string) uint32 {
+	t.Helper()
+
+	db, version, err := openBoltReadOnly(dbFile)
+	if err != nil {
+		t.Fatalf("error opening %s: %v", dbFile, err)
+	}
+	db.Close()
+
+	return version
+}
+
+func testUpgrade(t *testing.T) {
+	const upgradeDb = "upgrade.db"
+	const fromVersion = ticketIndexVersion
+	preUpgradeBackup := PreUpgradeBackupFile(upgradeDb, fromVersion)
+	removeDatabaseFiles(upgradeDb)
+	removeDatabaseFiles(preUpgradeBackup)
+	defer removeDatabaseFiles(upgradeDb)
+	defer removeDatabaseFiles(preUpgradeBackup)
+
+	err := CreateNew(upgradeDb, testnetXPub)
+	if err != nil {
+		t.Fatalf("error creating database: %v", err)
+	}
+
+	// Revert the latest upgrades.
+	db, err := bolt.Open(upgradeDb, 0600, nil)
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		for _, k := range [][]byte{archiveBktK, vspStatusBktK, maintenanceBktK} {
+			err := vspBkt.DeleteBucket(k)
+			if err != nil {
+				return err
+			}
+		}
+		return vspBkt.Put(versionK, uint32ToBytes(fromVersion))
+	})
+	if err != nil {
+		t.Fatalf("error reverting upgrade: %v", err)
+	}
+	db.Close()
+
+	// A dry run should upgrade and verify a copy without modifying the
+	// database.
+	report, err := BoltBackend.DryRunUpgrade(upgradeDb, chaincfg.TestNet3Params(), slog.Disabled)
+	if err != nil {
+		t.Fatalf("error running dry run upgrade: %v", err)
+	}
+	expected := UpgradeReport{
+		FromVersion: fromVersion,
+		ToVersion:   latestVersion,
+		Verify:      VerifyReport{Version: latestVersion, XPubs: 1},
+	}
+	if report != expected {
+		t.Fatalf("expected dry run report %+v, got %+v", expected, report)
+	}
+	if version := boltFileVersion(t, upgradeDb); version != fromVersion {
+		t.Fatalf("dry run modified database version to %d", version)
+	}
+	if fileExists(preUpgradeBackup) {
+		t.Fatal("dry run wrote a pre-upgrade backup")
+	}
+
+	// A real upgrade should write a backup before upgrading.
+	report, err = BoltBackend.Upgrade(upgradeDb, slog.Disabled)
+	if err != nil {
+		t.Fatalf("error upgrading database: %v", err)
+	}
+	expected = UpgradeReport{
+		FromVersion: fromVersion,
+		ToVersion:   latestVersion,
+		Backup:      preUpgradeBackup,
+	}
+	if report != expected {
+		t.Fatalf("expected upgrade report %+v, got %+v", expected, report)
+	}
+	if version := boltFileVersion(t, upgradeDb); version != latestVersion {
+		t.Fatalf("expected upgraded database version %d, got %d", latestVersion, version)
+	}
+	if version := boltFileVersion(t, preUpgradeBackup); version != fromVersion {
+		t.Fatalf("expected pre-upgrade backup version %d, got %d", fromVersion, version)
+	}
+
+	// Upgrading a database which is already the latest version does nothing.
+	removeDatabaseFiles(preUpgradeBackup)
+	report, err = BoltBackend.Upgrade(upgradeDb, slog.Disabled)
+	if err != nil {
+		t.Fatalf("error upgrading database: %v", err)
+	}
+	expected = UpgradeReport{FromVersion: latestVersion, ToVersion: latestVersion}
+	if report != expected {
+		t.Fatalf("expected upgrade report %+v, got %+v", expected, report)
+	}
+	if fileExists(preUpgradeBackup) {
+		t.Fatal("pre-upgrade backup written when no upgrade was required")
+	}
+}
+
+// sqliteFileVersion returns the version of the SQLite database file at dbFile.
+func sqliteFileVersion(t *testing.T, dbFile string) uint32 {
+	t.Helper()
+
+	db, version, err := openSQLiteReadOnly(dbFile)
+	if err != nil {
+		t.Fatalf("error opening %s: %v", dbFile, err)
+	}
+	db.Close()
+
+	return version
+}
+
+func testSQLiteUpgrade(t *testing.T) {
+	const upgradeDb = "upgrade.sqlite"
+	const fromVersion = sqliteInitialVersion
+	preUpgradeBackup := PreUpgradeBackupFile(upgradeDb, fromVersion)
+	removeDatabaseFiles(upgradeDb)
+	removeDatabaseFiles(preUpgradeBackup)
+	defer removeDatabaseFiles(upgradeDb)
+	defer removeDatabaseFiles(preUpgradeBackup)
+
+	err := CreateNewSQLite(upgradeDb, testnetXPub)
+	if err != nil {
+		t.Fatalf("error creating database: %v", err)
+	}
+
+	// Revert the latest upgrades.
+	sqlDB, err := openSQLite(upgradeDb)
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	_, err = sqlDB.Exec(`ALTER TABLE tickets DROP COLUMN fee_policy;
+		ALTER TABLE archived_tickets DROP COLUMN fee_policy;
+		DROP TABLE vsp_status_changes;
+		DROP TABLE maintenance_windows;
+		PRAGMA user_version = 1;`)
+	if err != nil {
+		t.Fatalf("error reverting upgrade: %v", err)
+	}
+	sqlDB.Close()
+
+	// A dry run should upgrade and verify a copy without modifying the
+	// database.
+	report, err := SQLiteBackend.DryRunUpgrade(upgradeDb, chaincfg.TestNet3Params(), slog.Disabled)
+	if err != nil {
+		t.Fatalf("error running dry run upgrade: %v", err)
+	}
+	expected := UpgradeReport{
+		FromVersion: fromVersion,
+		ToVersion:   sqliteLatestVersion,
+		Verify:      VerifyReport{Version: sqliteLatestVersion, XPubs: 1},
+	}
+	if report != expected {
+		t.Fatalf("expected dry run report %+v, got %+v", expected, report)
+	}
+	if version := sqliteFileVersion(t, upgradeDb); version != fromVersion {
+		t.Fatalf("dry run modified database version to %d", version)
+	}
+	if fileExists(preUpgradeBackup) {
+		t.Fatal("dry run wrote a pre-upgrade backup")
+	}
+
+	// A real upgrade should write a backup before upgrading.
+	report, err = SQLiteBackend.Upgrade(upgradeDb, slog.Disabled)
+	if err != nil {
+		t.Fatalf("error upgrading database: %v", err)
+	}
+	expected = UpgradeReport{
+		FromVersion: fromVersion,
+		ToVersion:   sqliteLatestVersion,
+		Backup:      preUpgradeBackup,
+	}
+	if report != expected {
+		t.Fatalf("expected upgrade report %+v, got %+v", expected, report)
+	}
+	if version := sqliteFileVersion(t, upgradeDb); version != sqliteLatestVersion {
+		t.Fatalf("expected upgraded database version %d, got %d", sqliteLatestVersion, version)
+	}
+	if version := sqliteFileVersion(t, preUpgradeBackup); version != fromVersion {
+		t.Fatalf("expected pre-upgrade backup version %d, got %d", fromVersion, version)
+	}
+
+	// Tickets can be stored in the upgraded database.
+	sdb, err := OpenSQLite(upgradeDb, slog.Disabled, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error opening upgraded database: %v", err)
+	}
+	defer sdb.Close(false)
+	ticket := exampleTicket()
+	err = sdb.InsertNewTicket(ticket)
+	if err != nil {
+		t.Fatalf("error storing ticket in upgraded database: %v", err)
+	}
+	retrieved, found, err := sdb.GetTicketByHash(ticket.Hash)
+	if err != nil || !found {
+		t.Fatalf("error retrieving ticket from upgraded database: %v", err)
+	}
+	if retrieved.FeePolicy != ticket.FeePolicy {
+		t.Fatalf("expected fee policy %q, got %q", ticket.FeePolicy, retrieved.FeePolicy)
+	}
+
+	// The VSP status can be set in the upgraded database.
+	err = sdb.SetVspStatus(VspStatusChange{Timestamp: 1, Closed: true, ChangedBy: "test"})
+	if err != nil {
+		t.Fatalf("error setting VSP status in upgraded database: %v", err)
+	}
+
+	// Maintenance windows can be added to the upgraded database.
+	_, err = sdb.AddMaintenanceWindow(MaintenanceWindow{Start: 1000, End: 2000})
+	if err != nil {
+		t.Fatalf("error adding maintenance window to upgraded database: %v", err)
+	}
+}
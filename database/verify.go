@@ -0,0 +1,278 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/slog"
+	bolt "go.etcd.io/bbolt"
+)
+
+// maxReportedProblems is the maximum number of problems included in the error
+// returned by a failed verification. The total number is always reported.
+const maxReportedProblems = 20
+
+// VerifyReport summarizes a database which has been successfully verified.
+type VerifyReport struct {
+	Version         uint32
+	XPubs           int
+	Tickets         int
+	ArchivedTickets int
+}
+
+// VerifyBackup checks that the database file at dbFile, typically a backup,
+// could be opened and used by vspd on the provided network. The file is opened
+// read-only and is never modified. It checks that:
+//   - the database version is supported.
+//   - the signing keypair and cookie secret exist.
+//   - the voting WIF key parameters, if present, can be decoded.
+//   - every ticket and archived ticket can be decoded.
+//   - every fee address can be re-derived from the xpub and index recorded with
+//     the ticket, and no index is used twice or beyond the last used index of
+//     its xpub.
+//
+// An error describing every problem found is returned if any check fails.
+func (b Backend) VerifyBackup(dbFile string, params *chaincfg.Params) (VerifyReport, error) {
+	switch b {
+	case BoltBackend:
+		return verifyBolt(dbFile, params)
+	case SQLiteBackend:
+		return verifySQLite(dbFile, params)
+	default:
+		return VerifyReport{}, fmt.Errorf("unknown database backend %q", b)
+	}
+}
+
+// verifyBolt verifies a bbolt database file. Databases of an older version are
+// verified by upgrading a temporary copy, because the checks only understand
+// the latest version.
+func verifyBolt(dbFile string, params *chaincfg.Params) (VerifyReport, error) {
+	db, version, err := openBoltReadOnly(dbFile)
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	if version < latestVersion {
+		db.Close()
+		report, err := dryRunUpgradeBolt(dbFile, params, slog.Disabled)
+		report.Verify.Version = version
+		return report.Verify, err
+	}
+	defer db.Close()
+
+	vdb := &VspDatabase{db: db, log: slog.Disabled}
+	return vdb.verify(params)
+}
+
+// verify checks the contents of the database, which must be the latest
+// version.
+func (vdb *VspDatabase) verify(params *chaincfg.Params) (VerifyReport, error) {
+	var c *integrityCheck
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		c = newIntegrityCheck(params, bytesToUint32(vspBkt.Get(versionK)))
+
+		// Check the structure of the file itself. Every error must be read
+		// from the channel before it is closed.
+		for err := range tx.Check() {
+			c.problem("%v", err)
+		}
+
+		c.checkKeys(vspBkt.Get(privateKeyK), vspBkt.Get(cookieSecretK))
+		c.checkWIFKey(vspBkt.Get(wifKeyK))
+
+		xpubBkt := vspBkt.Bucket(xPubBktK)
+		if xpubBkt == nil {
+			c.problem("%s bucket doesn't exist", string(xPubBktK))
+		} else {
+			err := xpubBkt.ForEach(func(k, v []byte) error {
+				c.checkXPubJSON(bytesToUint32(k), v)
+				return nil
+			})
+			if err != nil {
+				return fmt.Errorf("error iterating over %s bucket: %w", string(xPubBktK), err)
+			}
+		}
+
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+		if ticketBkt == nil {
+			c.problem("%s bucket doesn't exist", string(ticketBktK))
+			return nil
+		}
+
+		err := ticketBkt.ForEach(func(k, _ []byte) error {
+			tbkt := ticketBkt.Bucket(k)
+			if tbkt == nil {
+				c.problem("ticket %s: not stored as a bucket", string(k))
+				return nil
+			}
+
+			ticket, err := getTicketFromBkt(tbkt)
+			if err != nil {
+				c.report.Tickets++
+				c.problem("ticket %s: could not decode: %v", string(k), err)
+				return nil
+			}
+
+			c.checkTicket(ticket)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over %s bucket: %w", string(ticketBktK), err)
+		}
+
+		archiveBkt := vspBkt.Bucket(archiveBktK)
+		if archiveBkt == nil {
+			c.problem("%s bucket doesn't exist", string(archiveBktK))
+			return nil
+		}
+
+		return archiveBkt.ForEach(func(k, v []byte) error {
+			// Running totals are stored as values alongside the buckets of
+			// archived tickets.
+			if v != nil {
+				return nil
+			}
+
+			ticket, err := getArchivedTicketFromBkt(archiveBkt.Bucket(k))
+			if err != nil {
+				c.report.ArchivedTickets++
+				c.problem("archived ticket %s: could not decode: %v", string(k), err)
+				return nil
+			}
+
+			c.checkArchivedTicket(ticket)
+			return nil
+		})
+	})
+	if err != nil {
+		return VerifyReport{}, err
+	}
+
+	return c.result()
+}
+
+// integrityCheck accumulates the results of checking the contents of a
+// database, independent of its storage backend.
+type integrityCheck struct {
+	feeAddressChecker
+	report VerifyReport
+	wifKey bool
+}
+
+func newIntegrityCheck(params *chaincfg.Params, version uint32) *integrityCheck {
+	return &integrityCheck{
+		feeAddressChecker: newFeeAddressChecker(params),
+		report:            VerifyReport{Version: version},
+	}
+}
+
+// checkKeys checks the stored signing key seed and cookie secret.
+func (c *integrityCheck) checkKeys(seed, cookieSecret []byte) {
+	switch len(seed) {
+	case 0:
+		c.problem("no private key found")
+	case ed25519.SeedSize:
+	default:
+		c.problem("private key seed has length %d, expected %d", len(seed), ed25519.SeedSize)
+	}
+
+	if len(cookieSecret) == 0 {
+		c.problem("no cookie secret found")
+	}
+}
+
+// checkWIFKey checks the stored voting WIF key parameters, which are nil if
+// voting WIFs are not encrypted.
+func (c *integrityCheck) checkWIFKey(value []byte) {
+	params, err := bytesToWIFKeyParams(value)
+	if err != nil {
+		c.problem("%v", err)
+		return
+	}
+	c.wifKey = params != nil
+}
+
+// checkXPub checks that an xpub can be used to derive fee addresses.
+func (c *integrityCheck) checkXPub(xpub FeeXPub) {
+	c.report.XPubs++
+	c.addXPub(xpub)
+}
+
+// checkXPubJSON checks an xpub stored as json.
+func (c *integrityCheck) checkXPubJSON(id uint32, value []byte) {
+	var xpub FeeXPub
+	err := json.Unmarshal(value, &xpub)
+	if err != nil {
+		c.report.XPubs++
+		c.problem("xpub %d: could not unmarshal: %v", id, err)
+		return
+	}
+	if xpub.ID != id {
+		c.problem("xpub %d: stored with ID %d", id, xpub.ID)
+	}
+	c.checkXPub(xpub)
+}
+
+// checkTicket checks a ticket which has been successfully decoded. Every xpub
+// must already have been checked.
+func (c *integrityCheck) checkTicket(ticket Ticket) {
+	c.report.Tickets++
+
+	if c.wifKey && ticket.VotingWIF != "" && !IsEncryptedWIF(ticket.VotingWIF) {
+		c.problem("ticket %s: voting WIF is not encrypted", ticket.Hash)
+	}
+
+	if ticket.FeeAddress != "" {
+		c.check(FeeAddressRecord{
+			TicketHash: ticket.Hash,
+			XPubID:     ticket.FeeAddressXPubID,
+			Index:      ticket.FeeAddressIndex,
+			Address:    ticket.FeeAddress,
+		})
+	}
+}
+
+// checkArchivedTicket checks an archived ticket which has been successfully
+// decoded. Every xpub must already have been checked.
+func (c *integrityCheck) checkArchivedTicket(ticket ArchivedTicket) {
+	c.report.ArchivedTickets++
+
+	if ticket.FeeAddress != "" {
+		c.check(FeeAddressRecord{
+			TicketHash: ticket.Hash,
+			XPubID:     ticket.FeeAddressXPubID,
+			Index:      ticket.FeeAddressIndex,
+			Address:    ticket.FeeAddress,
+			Archived:   true,
+		})
+	}
+}
+
+// result returns the report if no problems were found, or an error listing
+// them otherwise.
+func (c *integrityCheck) result() (VerifyReport, error) {
+	if len(c.problems) == 0 {
+		return c.report, nil
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d problem(s) found", len(c.problems))
+	for i, problem := range c.problems {
+		if i == maxReportedProblems {
+			fmt.Fprintf(&b, "\n  ... and %d more", len(c.problems)-i)
+			break
+		}
+		b.WriteString("\n  " + problem)
+	}
+
+	return c.report, errors.New(b.String())
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/hdkeychain/v3"
+	"github.com/decred/slog"
+)
+
+// testnetXPub is a valid testnet extended public key.
+const testnetXPub = "tpubVhnMyQmZAhoosTJRf8hRxGzMabXgJxf6st2Ch6WcBsf6XiqYX4QKp8n6fcaeVQCeoKqAoUSgbrGhGBiz9Tx1dYVSMZR9UnowKMrefxt8qVC"
+
+func testVerifyBackup(t *testing.T) {
+	// The test database is created with an invalid xpub, so a separate
+	// database is required.
+	const verifyDb = "verify.db"
+	removeDatabaseFiles(verifyDb)
+	defer removeDatabaseFiles(verifyDb)
+
+	params := chaincfg.TestNet3Params()
+
+	err := backend.CreateNew(verifyDb, testnetXPub)
+	if err != nil {
+		t.Fatalf("error creating database: %v", err)
+	}
+
+	vdb, err := backend.Open(verifyDb, slog.Disabled, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error opening database: %v", err)
+	}
+	defer vdb.Close(false)
+
+	key, err := hdkeychain.NewKeyFromString(testnetXPub, params)
+	if err != nil {
+		t.Fatalf("error parsing xpub: %v", err)
+	}
+	external, err := key.Child(0)
+	if err != nil {
+		t.Fatalf("error deriving external branch: %v", err)
+	}
+	feeAddress, err := deriveFeeAddress(external, 1, params)
+	if err != nil {
+		t.Fatalf("error deriving fee address: %v", err)
+	}
+
+	// A ticket without a fee address and a ticket with a correctly derived
+	// fee address.
+	noFee := exampleTicket()
+	noFee.FeeAddress = ""
+	valid := exampleTicket()
+	valid.FeeAddressXPubID = 0
+	valid.FeeAddressIndex = 1
+	valid.FeeAddress = feeAddress
+	for _, ticket := range []Ticket{noFee, valid} {
+		err = vdb.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+	err = vdb.SetLastAddressIndex(0, 2)
+	if err != nil {
+		t.Fatalf("error setting last address index: %v", err)
+	}
+
+	err = vdb.WriteHotBackupFile()
+	if err != nil {
+		t.Fatalf("error writing backup: %v", err)
+	}
+
+	report, err := backend.VerifyBackup(HotBackupFile(verifyDb), params)
+	if err != nil {
+		t.Fatalf("unexpected verification error: %v", err)
+	}
+	version, err := vdb.Version()
+	if err != nil {
+		t.Fatalf("error getting version: %v", err)
+	}
+	expected := VerifyReport{Version: version, XPubs: 1, Tickets: 2}
+	if report != expected {
+		t.Fatalf("expected report %+v, got %+v", expected, report)
+	}
+
+	// The xpub cannot be parsed for a different network.
+	_, err = backend.VerifyBackup(HotBackupFile(verifyDb), chaincfg.MainNetParams())
+	if err == nil || !strings.Contains(err.Error(), "xpub 0") {
+		t.Fatalf("expected xpub error verifying for wrong network, got %v", err)
+	}
+
+	// Tickets with a fee address which does not match the recorded index, or
+	// which was derived from an unknown xpub, should fail verification.
+	wrongIdx := valid
+	wrongIdx.Hash = exampleTicket().Hash
+	wrongIdx.FeeAddressIndex = 2
+	unknownXPub := valid
+	unknownXPub.Hash = exampleTicket().Hash
+	unknownXPub.FeeAddressXPubID = 5
+	for _, ticket := range []Ticket{wrongIdx, unknownXPub} {
+		err = vdb.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	err = vdb.WriteHotBackupFile()
+	if err != nil {
+		t.Fatalf("error writing backup: %v", err)
+	}
+
+	_, err = backend.VerifyBackup(HotBackupFile(verifyDb), params)
+	if err == nil {
+		t.Fatal("expected verification to fail")
+	}
+	for _, s := range []string{"2 problem(s)", wrongIdx.Hash, unknownXPub.Hash} {
+		if !strings.Contains(err.Error(), s) {
+			t.Fatalf("expected verification error to contain %q, got %v", s, err)
+		}
+	}
+
+	// Missing files are not created.
+	_, err = backend.VerifyBackup("missing.db", params)
+	if err == nil {
+		t.Fatal("expected error verifying missing file")
+	}
+	if fileExists("missing.db") {
+		t.Fatal("verifying a missing file should not create it")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"slices"
 
 	bolt "go.etcd.io/bbolt"
 )
@@ -21,65 +22,114 @@ type VoteChangeRecord struct {
 	ResponseSignature string `json:"rsps"`
 }
 
+// VoteChoicesUpdate is a ticket with updated vote choices, along with the record
+// of the request which updated them.
+type VoteChoicesUpdate struct {
+	Ticket Ticket
+	Record VoteChangeRecord
+}
+
 // SaveVoteChange will insert the provided vote change record into the database,
 // and if this breaches the maximum amount of allowed records, delete the oldest
 // one which is currently stored. Records are stored using a serially increasing
 // integer as the key.
 func (vdb *VspDatabase) SaveVoteChange(ticketHash string, record VoteChangeRecord) error {
 	return vdb.db.Update(func(tx *bolt.Tx) error {
+		return putVoteChange(tx, ticketHash, record, vdb.maxVoteChangeRecords)
+	})
+}
 
-		// Create or get a bucket for this ticket.
-		bkt, err := tx.Bucket(vspBktK).Bucket(voteChangeBktK).
-			CreateBucketIfNotExists([]byte(ticketHash))
+// putVoteChange inserts the provided vote change record for a ticket, deleting
+// the oldest record of the ticket if there are already maxRecords.
+func putVoteChange(tx *bolt.Tx, ticketHash string, record VoteChangeRecord, maxRecords int) error {
+	// Create or get a bucket for this ticket.
+	bkt, err := tx.Bucket(vspBktK).Bucket(voteChangeBktK).
+		CreateBucketIfNotExists([]byte(ticketHash))
+	if err != nil {
+		return fmt.Errorf("failed to create vote change bucket (ticketHash=%s): %w",
+			ticketHash, err)
+	}
+
+	// Loop through the bucket to count the records, as well as finding the
+	// most recent and the oldest record.
+	var count int
+	newest := uint32(0)
+	oldest := uint32(math.MaxUint32)
+	err = bkt.ForEach(func(k, _ []byte) error {
+		count++
+		key := bytesToUint32(k)
+		if key > newest {
+			newest = key
+		}
+		if key < oldest {
+			oldest = key
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error iterating over vote change bucket: %w", err)
+	}
+
+	// If bucket is at (or over) the limit of max allowed records, remove
+	// the oldest one.
+	if count >= maxRecords {
+		err = bkt.Delete(uint32ToBytes(oldest))
 		if err != nil {
-			return fmt.Errorf("failed to create vote change bucket (ticketHash=%s): %w",
-				ticketHash, err)
+			return fmt.Errorf("failed to delete old vote change record: %w", err)
 		}
+	}
+
+	// Insert record with index 0 if the bucket is currently empty,
+	// otherwise use most recent + 1.
+	var newKey uint32
+	if count > 0 {
+		newKey = newest + 1
+	}
+
+	// Insert record.
+	recordBytes, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("could not marshal vote change record: %w", err)
+	}
+	err = bkt.Put(uint32ToBytes(newKey), recordBytes)
+	if err != nil {
+		return fmt.Errorf("could not store vote change record: %w", err)
+	}
+
+	return nil
+}
 
-		// Loop through the bucket to count the records, as well as finding the
-		// most recent and the oldest record.
-		var count int
-		newest := uint32(0)
-		oldest := uint32(math.MaxUint32)
-		err = bkt.ForEach(func(k, _ []byte) error {
-			count++
-			key := bytesToUint32(k)
-			if key > newest {
-				newest = key
-			}
-			if key < oldest {
-				oldest = key
-			}
-			return nil
-		})
+// UpdateVoteChoices stores the updated vote choices of every ticket along with
+// the record of the request which changed them, in a single transaction. Either
+// every update is stored, or none of them are.
+func (vdb *VspDatabase) UpdateVoteChoices(updates []VoteChoicesUpdate) error {
+	// Encrypt copies of the tickets so the caller's tickets are unchanged.
+	updates = slices.Clone(updates)
+	for i := range updates {
+		err := vdb.wifs.encryptTicket(&updates[i].Ticket)
 		if err != nil {
-			return fmt.Errorf("error iterating over vote change bucket: %w", err)
+			return err
 		}
+	}
 
-		// If bucket is at (or over) the limit of max allowed records, remove
-		// the oldest one.
-		if count >= vdb.maxVoteChangeRecords {
-			err = bkt.Delete(uint32ToBytes(oldest))
-			if err != nil {
-				return fmt.Errorf("failed to delete old vote change record: %w", err)
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		ticketBkt := tx.Bucket(vspBktK).Bucket(ticketBktK)
+
+		for _, update := range updates {
+			bkt := ticketBkt.Bucket([]byte(update.Ticket.Hash))
+			if bkt == nil {
+				return fmt.Errorf("ticket does not exist with hash %s", update.Ticket.Hash)
 			}
-		}
 
-		// Insert record with index 0 if the bucket is currently empty,
-		// otherwise use most recent + 1.
-		var newKey uint32
-		if count > 0 {
-			newKey = newest + 1
-		}
+			err := putTicketInBucket(bkt, update.Ticket)
+			if err != nil {
+				return err
+			}
 
-		// Insert record.
-		recordBytes, err := json.Marshal(record)
-		if err != nil {
-			return fmt.Errorf("could not marshal vote change record: %w", err)
-		}
-		err = bkt.Put(uint32ToBytes(newKey), recordBytes)
-		if err != nil {
-			return fmt.Errorf("could not store vote change record: %w", err)
+			err = putVoteChange(tx, update.Ticket.Hash, update.Record, vdb.maxVoteChangeRecords)
+			if err != nil {
+				return err
+			}
 		}
 
 		return nil
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -103,3 +103,69 @@ func testDeleteVoteChanges(t *testing.T) {
 	}
 
 }
+
+func testUpdateVoteChoices(t *testing.T) {
+	// Insert two tickets.
+	first, second := exampleTicket(), exampleTicket()
+	for _, ticket := range []Ticket{first, second} {
+		err := db.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket in database: %v", err)
+		}
+	}
+
+	// Update the vote choices of both tickets.
+	first.VoteChoices = map[string]string{"AgendaID": "no"}
+	second.VoteChoices = map[string]string{"AgendaID": "abstain"}
+	record := exampleRecord()
+	err := db.UpdateVoteChoices([]VoteChoicesUpdate{
+		{Ticket: first, Record: record},
+		{Ticket: second, Record: record},
+	})
+	if err != nil {
+		t.Fatalf("error updating vote choices: %v", err)
+	}
+
+	for _, ticket := range []Ticket{first, second} {
+		retrieved, _, err := db.GetTicketByHash(ticket.Hash)
+		if err != nil {
+			t.Fatalf("error retrieving ticket: %v", err)
+		}
+		if !reflect.DeepEqual(retrieved, ticket) {
+			t.Fatalf("expected ticket %+v, got %+v", ticket, retrieved)
+		}
+
+		records, err := db.GetVoteChanges(ticket.Hash)
+		if err != nil {
+			t.Fatalf("error retrieving vote change records: %v", err)
+		}
+		if len(records) != 1 || !reflect.DeepEqual(records[0], record) {
+			t.Fatalf("expected one vote change record, got %v", records)
+		}
+	}
+
+	// If any ticket does not exist, nothing should be updated.
+	first.VoteChoices = map[string]string{"AgendaID": "yes"}
+	err = db.UpdateVoteChoices([]VoteChoicesUpdate{
+		{Ticket: first, Record: record},
+		{Ticket: exampleTicket(), Record: record},
+	})
+	if err == nil {
+		t.Fatal("expected error updating vote choices of unknown ticket")
+	}
+
+	retrieved, _, err := db.GetTicketByHash(first.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket: %v", err)
+	}
+	if retrieved.VoteChoices["AgendaID"] != "no" {
+		t.Fatalf("expected vote choices to be unchanged, got %v", retrieved.VoteChoices)
+	}
+	records, err := db.GetVoteChanges(first.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving vote change records: %v", err)
+	}
+	if len(records) != 1 {
+		t.Fatalf("expected one vote change record, got %d", len(records))
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// VspStatusChange records the VSP being opened or closed by an admin while vspd
+// is running. The most recent change is the current status of the VSP, which
+// takes precedence over the vspclosed and vspclosedmsg config options.
+type VspStatusChange struct {
+	// Timestamp is the unix time at which the change was made.
+	Timestamp int64  `json:"timestamp"`
+	Closed    bool   `json:"closed"`
+	ClosedMsg string `json:"closedmsg"`
+	// ChangedBy describes who made the change, eg. the admin page and the IP
+	// address it was used from.
+	ChangedBy string `json:"changedby"`
+}
+
+// createVspStatusBucket creates the empty VSP status bucket within the provided
+// vsp bucket.
+func createVspStatusBucket(vspBkt *bolt.Bucket) error {
+	_, err := vspBkt.CreateBucket(vspStatusBktK)
+	if err != nil {
+		return fmt.Errorf("failed to create %s bucket: %w", vspStatusBktK, err)
+	}
+	return nil
+}
+
+// putVspStatusChange adds the provided change to the end of the VSP status
+// history stored in bkt.
+func putVspStatusChange(bkt *bolt.Bucket, change VspStatusChange) error {
+	// Changes are stored using a serially increasing integer as the key, so
+	// the last key is always the current status.
+	id, err := bkt.NextSequence()
+	if err != nil {
+		return fmt.Errorf("could not get next VSP status change ID: %w", err)
+	}
+
+	changeBytes, err := json.Marshal(change)
+	if err != nil {
+		return fmt.Errorf("could not marshal VSP status change: %w", err)
+	}
+
+	err = bkt.Put(uint64ToBytes(id), changeBytes)
+	if err != nil {
+		return fmt.Errorf("could not store VSP status change: %w", err)
+	}
+
+	return nil
+}
+
+// SetVspStatus records the provided change as the current status of the VSP.
+// Changes are never modified once stored, so every previous status remains
+// available from VspStatusHistory.
+func (vdb *VspDatabase) SetVspStatus(change VspStatusChange) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		return putVspStatusChange(tx.Bucket(vspBktK).Bucket(vspStatusBktK), change)
+	})
+}
+
+// VspStatus retrieves the most recent change to the status of the VSP. A
+// boolean indicates whether the status has ever been changed.
+func (vdb *VspDatabase) VspStatus() (VspStatusChange, bool, error) {
+	var change VspStatusChange
+	var found bool
+
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		_, v := tx.Bucket(vspBktK).Bucket(vspStatusBktK).Cursor().Last()
+		if v == nil {
+			return nil
+		}
+
+		err := json.Unmarshal(v, &change)
+		if err != nil {
+			return fmt.Errorf("could not unmarshal VSP status change: %w", err)
+		}
+		found = true
+
+		return nil
+	})
+
+	return change, found, err
+}
+
+// VspStatusHistory retrieves every change to the status of the VSP, oldest
+// first.
+func (vdb *VspDatabase) VspStatusHistory() ([]VspStatusChange, error) {
+	var changes []VspStatusChange
+
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		err := tx.Bucket(vspBktK).Bucket(vspStatusBktK).ForEach(func(_, v []byte) error {
+			var change VspStatusChange
+			err := json.Unmarshal(v, &change)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal VSP status change: %w", err)
+			}
+
+			changes = append(changes, change)
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over %s bucket: %w", vspStatusBktK, err)
+		}
+
+		return nil
+	})
+
+	return changes, err
+}
@@ -0,0 +1,62 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func testVspStatus(t *testing.T) {
+	// A new database should have no status.
+	_, found, err := db.VspStatus()
+	if err != nil {
+		t.Fatalf("error retrieving VSP status: %v", err)
+	}
+	if found {
+		t.Fatal("expected no VSP status")
+	}
+	history, err := db.VspStatusHistory()
+	if err != nil {
+		t.Fatalf("error retrieving VSP status history: %v", err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("expected no VSP status history, got %d changes", len(history))
+	}
+
+	expected := []VspStatusChange{
+		{Timestamp: 1000, Closed: true, ClosedMsg: "Closed for maintenance", ChangedBy: "admin page (127.0.0.1)"},
+		{Timestamp: 2000, Closed: false, ChangedBy: "admin API (127.0.0.1)"},
+		{Timestamp: 3000, Closed: true, ChangedBy: "config reload"},
+	}
+
+	for _, change := range expected {
+		err = db.SetVspStatus(change)
+		if err != nil {
+			t.Fatalf("error setting VSP status: %v", err)
+		}
+
+		// The most recent change is the current status.
+		current, found, err := db.VspStatus()
+		if err != nil {
+			t.Fatalf("error retrieving VSP status: %v", err)
+		}
+		if !found {
+			t.Fatal("expected VSP status to be found")
+		}
+		if current != change {
+			t.Fatalf("expected VSP status %+v, got %+v", change, current)
+		}
+	}
+
+	// Changes should be returned in the order they were made.
+	history, err = db.VspStatusHistory()
+	if err != nil {
+		t.Fatalf("error retrieving VSP status history: %v", err)
+	}
+	if !reflect.DeepEqual(history, expected) {
+		t.Fatalf("expected VSP status history %+v, got %+v", expected, history)
+	}
+}
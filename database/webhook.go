@@ -0,0 +1,110 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+// WebhookDelivery is serialized to json and stored in bbolt db. It holds a
+// signed webhook payload which has not yet been successfully delivered.
+type WebhookDelivery struct {
+	URL       string `json:"url"`
+	Payload   string `json:"payload"`
+	Signature string `json:"sig"`
+	// Attempts is the number of failed attempts to deliver the payload.
+	Attempts uint32 `json:"attempts"`
+	// NextAttempt is a unix timestamp representing the earliest time at which
+	// delivery should be attempted again.
+	NextAttempt int64 `json:"next"`
+}
+
+// QueueWebhook inserts the provided delivery at the end of the webhook queue
+// and returns its ID.
+func (vdb *VspDatabase) QueueWebhook(delivery WebhookDelivery) (uint64, error) {
+	var id uint64
+	err := vdb.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(vspBktK).Bucket(webhookBktK)
+
+		var err error
+		id, err = bkt.NextSequence()
+		if err != nil {
+			return fmt.Errorf("could not get next webhook ID: %w", err)
+		}
+
+		return putWebhook(bkt, id, delivery)
+	})
+	return id, err
+}
+
+// UpdateWebhook overwrites the stored delivery with the provided ID.
+func (vdb *VspDatabase) UpdateWebhook(id uint64, delivery WebhookDelivery) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(vspBktK).Bucket(webhookBktK)
+
+		if bkt.Get(uint64ToBytes(id)) == nil {
+			return fmt.Errorf("webhook delivery does not exist with ID %d", id)
+		}
+
+		return putWebhook(bkt, id, delivery)
+	})
+}
+
+func putWebhook(bkt *bolt.Bucket, id uint64, delivery WebhookDelivery) error {
+	deliveryBytes, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("could not marshal webhook delivery: %w", err)
+	}
+
+	err = bkt.Put(uint64ToBytes(id), deliveryBytes)
+	if err != nil {
+		return fmt.Errorf("could not store webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteWebhook removes the delivery with the provided ID from the webhook
+// queue. Does not error if the delivery does not exist.
+func (vdb *VspDatabase) DeleteWebhook(id uint64) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		err := tx.Bucket(vspBktK).Bucket(webhookBktK).Delete(uint64ToBytes(id))
+		if err != nil {
+			return fmt.Errorf("could not delete webhook delivery: %w", err)
+		}
+		return nil
+	})
+}
+
+// PendingWebhooks retrieves every delivery in the webhook queue, keyed by ID.
+func (vdb *VspDatabase) PendingWebhooks() (map[uint64]WebhookDelivery, error) {
+	deliveries := make(map[uint64]WebhookDelivery)
+
+	err := vdb.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(vspBktK).Bucket(webhookBktK)
+
+		err := bkt.ForEach(func(k, v []byte) error {
+			var delivery WebhookDelivery
+			err := json.Unmarshal(v, &delivery)
+			if err != nil {
+				return fmt.Errorf("could not unmarshal webhook delivery: %w", err)
+			}
+
+			deliveries[bytesToUint64(k)] = delivery
+
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("error iterating over %s bucket: %w", string(webhookBktK), err)
+		}
+
+		return nil
+	})
+
+	return deliveries, err
+}
@@ -0,0 +1,94 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func exampleWebhookDelivery() WebhookDelivery {
+	return WebhookDelivery{
+		URL:         "https://" + randString(20, addrCharset) + ".example",
+		Payload:     randString(200, addrCharset),
+		Signature:   randString(88, sigCharset),
+		Attempts:    0,
+		NextAttempt: 1234,
+	}
+}
+
+func testWebhookQueue(t *testing.T) {
+	// A new database should have an empty queue.
+	pending, err := db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("error getting pending webhooks: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending webhooks, got %d", len(pending))
+	}
+
+	// Queue two deliveries, IDs should be increasing.
+	first := exampleWebhookDelivery()
+	firstID, err := db.QueueWebhook(first)
+	if err != nil {
+		t.Fatalf("error queueing webhook: %v", err)
+	}
+	second := exampleWebhookDelivery()
+	secondID, err := db.QueueWebhook(second)
+	if err != nil {
+		t.Fatalf("error queueing webhook: %v", err)
+	}
+	if secondID <= firstID {
+		t.Fatalf("expected second ID %d to be greater than first ID %d",
+			secondID, firstID)
+	}
+
+	pending, err = db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("error getting pending webhooks: %v", err)
+	}
+	if !reflect.DeepEqual(pending, map[uint64]WebhookDelivery{firstID: first, secondID: second}) {
+		t.Fatalf("unexpected pending webhooks: %v", pending)
+	}
+
+	// Update a delivery.
+	first.Attempts++
+	first.NextAttempt = 5678
+	err = db.UpdateWebhook(firstID, first)
+	if err != nil {
+		t.Fatalf("error updating webhook: %v", err)
+	}
+
+	pending, err = db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("error getting pending webhooks: %v", err)
+	}
+	if !reflect.DeepEqual(pending[firstID], first) {
+		t.Fatalf("expected updated delivery %v, got %v", first, pending[firstID])
+	}
+
+	// Updating a delivery which doesn't exist should fail.
+	err = db.UpdateWebhook(secondID+1, first)
+	if err == nil {
+		t.Fatal("expected an error updating a webhook which does not exist")
+	}
+
+	// Delete a delivery.
+	err = db.DeleteWebhook(firstID)
+	if err != nil {
+		t.Fatalf("error deleting webhook: %v", err)
+	}
+
+	pending, err = db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("error getting pending webhooks: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending webhook, got %d", len(pending))
+	}
+	if _, ok := pending[secondID]; !ok {
+		t.Fatal("expected second webhook to remain in queue")
+	}
+}
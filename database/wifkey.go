@@ -0,0 +1,396 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"bytes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	bolt "go.etcd.io/bbolt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// Voting WIFs can be encrypted at rest with a key derived from a passphrase
+// which is supplied to vspd at startup and never stored. Encrypted WIFs are
+// stored as encryptedWIFPrefix followed by the base64 encoded nonce and
+// ciphertext, with the ticket hash as additional data so that an encrypted WIF
+// cannot be moved to another ticket. Because WIFs are base58 encoded they can
+// never be mistaken for an encrypted value.
+const encryptedWIFPrefix = "enc1:"
+
+// wifKeyCheckAD is the additional data used when encrypting the key check
+// value, ensuring it can not be confused with an encrypted WIF.
+var wifKeyCheckAD = []byte("vspd wif key check")
+
+// Argon2id parameters used to derive the encryption key from the passphrase.
+const (
+	wifKeyTime    = 3
+	wifKeyMemory  = 64 * 1024
+	wifKeyThreads = 4
+	wifKeySaltLen = 16
+)
+
+var (
+	// ErrWIFsNotEncrypted is returned when attempting to unlock or rotate the
+	// key of a database in which voting WIFs are not encrypted.
+	ErrWIFsNotEncrypted = errors.New("voting WIFs are not encrypted")
+	// ErrWIFsEncrypted is returned when attempting to encrypt the voting WIFs
+	// of a database in which they are already encrypted.
+	ErrWIFsEncrypted = errors.New("voting WIFs are already encrypted")
+	// ErrWrongWIFPassphrase is returned when the provided passphrase does not
+	// match the one used to encrypt the voting WIFs.
+	ErrWrongWIFPassphrase = errors.New("incorrect voting WIF passphrase")
+	// ErrWIFsLocked is returned when attempting to store a voting WIF in a
+	// database in which WIFs are encrypted, but which has not been unlocked.
+	ErrWIFsLocked = errors.New("voting WIFs are encrypted and the database has not been unlocked")
+)
+
+// IsEncryptedWIF returns true if the provided voting WIF is encrypted. This is
+// the case for tickets read from a database which has not been unlocked.
+func IsEncryptedWIF(wif string) bool {
+	return strings.HasPrefix(wif, encryptedWIFPrefix)
+}
+
+// wifKeyParams is stored in the database when voting WIFs are encrypted. It
+// contains everything needed to derive the key from the passphrase and to
+// check that the passphrase is correct, but nothing secret.
+type wifKeyParams struct {
+	Salt  []byte `json:"salt"`
+	Check []byte `json:"check"`
+}
+
+// wifCipher encrypts and decrypts voting WIFs.
+type wifCipher struct {
+	aead cipher.AEAD
+}
+
+func deriveWIFCipher(passphrase, salt []byte) (*wifCipher, error) {
+	key := argon2.IDKey(passphrase, salt, wifKeyTime, wifKeyMemory, wifKeyThreads,
+		chacha20poly1305.KeySize)
+	aead, err := chacha20poly1305.NewX(key)
+	if err != nil {
+		return nil, err
+	}
+	return &wifCipher{aead: aead}, nil
+}
+
+// newWIFKey derives a new key from the provided passphrase with a random salt,
+// returning the parameters which should be stored in the database.
+func newWIFKey(passphrase []byte) (*wifKeyParams, *wifCipher, error) {
+	if len(passphrase) == 0 {
+		return nil, nil, errors.New("voting WIF passphrase is empty")
+	}
+
+	// Since go 1.24, crypto/rand.Read will never return an error.
+	salt := make([]byte, wifKeySaltLen)
+	_, _ = rand.Read(salt)
+
+	c, err := deriveWIFCipher(passphrase, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &wifKeyParams{Salt: salt, Check: c.seal(wifKeyCheckAD, nil)}, c, nil
+}
+
+// cipher derives the key from the provided passphrase, returning
+// ErrWrongWIFPassphrase if it is not the passphrase the parameters were
+// created with.
+func (p *wifKeyParams) cipher(passphrase []byte) (*wifCipher, error) {
+	c, err := deriveWIFCipher(passphrase, p.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = c.open(wifKeyCheckAD, p.Check); err != nil {
+		return nil, ErrWrongWIFPassphrase
+	}
+
+	return c, nil
+}
+
+func (p *wifKeyParams) bytes() []byte {
+	// Marshalling a struct of byte slices cannot fail.
+	b, _ := json.Marshal(p)
+	return b
+}
+
+func bytesToWIFKeyParams(b []byte) (*wifKeyParams, error) {
+	if b == nil {
+		return nil, nil
+	}
+
+	var p wifKeyParams
+	err := json.Unmarshal(b, &p)
+	if err != nil {
+		return nil, fmt.Errorf("could not unmarshal wif key: %w", err)
+	}
+	return &p, nil
+}
+
+// seal returns a random nonce followed by the encrypted plaintext.
+func (c *wifCipher) seal(ad, plaintext []byte) []byte {
+	nonce := make([]byte, c.aead.NonceSize(), c.aead.NonceSize()+len(plaintext)+c.aead.Overhead())
+	_, _ = rand.Read(nonce)
+	return c.aead.Seal(nonce, nonce, plaintext, ad)
+}
+
+func (c *wifCipher) open(ad, sealed []byte) ([]byte, error) {
+	if len(sealed) < c.aead.NonceSize() {
+		return nil, errors.New("ciphertext too short")
+	}
+	nonce, ciphertext := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	return c.aead.Open(nil, nonce, ciphertext, ad)
+}
+
+func (c *wifCipher) encrypt(ticketHash, wif string) string {
+	return encryptedWIFPrefix +
+		base64.StdEncoding.EncodeToString(c.seal([]byte(ticketHash), []byte(wif)))
+}
+
+func (c *wifCipher) decrypt(ticketHash, value string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedWIFPrefix))
+	if err != nil {
+		return "", fmt.Errorf("could not decode voting WIF of ticket %s: %w", ticketHash, err)
+	}
+
+	wif, err := c.open([]byte(ticketHash), sealed)
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt voting WIF of ticket %s: %w", ticketHash, err)
+	}
+
+	return string(wif), nil
+}
+
+// reencryptWIF returns the stored voting WIF value of a ticket re-encrypted
+// with to. Encrypted values are first decrypted with from, which may be nil if
+// values are expected to be plaintext. Empty values are left empty.
+func reencryptWIF(ticketHash, value string, from, to *wifCipher) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+
+	wif := value
+	if IsEncryptedWIF(value) {
+		if from == nil {
+			return "", fmt.Errorf("voting WIF of ticket %s is unexpectedly encrypted", ticketHash)
+		}
+		var err error
+		wif, err = from.decrypt(ticketHash, value)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return to.encrypt(ticketHash, wif), nil
+}
+
+// wifEncryption holds the voting WIF encryption state of an open database.
+type wifEncryption struct {
+	mtx    sync.RWMutex
+	params *wifKeyParams // nil if voting WIFs are not encrypted.
+	cipher *wifCipher    // nil until the database is unlocked.
+}
+
+func (w *wifEncryption) set(params *wifKeyParams, c *wifCipher) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.params = params
+	w.cipher = c
+}
+
+func (w *wifEncryption) encrypted() bool {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	return w.params != nil
+}
+
+// unlock derives the key from the provided passphrase so that voting WIFs can
+// be encrypted and decrypted.
+func (w *wifEncryption) unlock(passphrase []byte) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if w.params == nil {
+		return ErrWIFsNotEncrypted
+	}
+
+	c, err := w.params.cipher(passphrase)
+	if err != nil {
+		return err
+	}
+
+	w.cipher = c
+	return nil
+}
+
+// verify returns the cipher derived from the provided passphrase, checking
+// that it is the passphrase the voting WIFs are currently encrypted with.
+func (w *wifEncryption) verify(passphrase []byte) (*wifCipher, error) {
+	w.mtx.RLock()
+	params := w.params
+	w.mtx.RUnlock()
+
+	if params == nil {
+		return nil, ErrWIFsNotEncrypted
+	}
+
+	return params.cipher(passphrase)
+}
+
+// encryptTicket encrypts the voting WIF of a ticket which is about to be
+// stored. Tickets read from a database which has not been unlocked still
+// contain the encrypted WIF, which is stored unchanged.
+func (w *wifEncryption) encryptTicket(ticket *Ticket) error {
+	if ticket.VotingWIF == "" || IsEncryptedWIF(ticket.VotingWIF) {
+		return nil
+	}
+
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	if w.params == nil {
+		return nil
+	}
+
+	if w.cipher == nil {
+		return ErrWIFsLocked
+	}
+
+	ticket.VotingWIF = w.cipher.encrypt(ticket.Hash, ticket.VotingWIF)
+	return nil
+}
+
+// decryptTicket decrypts the voting WIF of a ticket which has been read from
+// the database. The WIF is left encrypted if the database is not unlocked.
+func (w *wifEncryption) decryptTicket(ticket *Ticket) error {
+	if !IsEncryptedWIF(ticket.VotingWIF) {
+		return nil
+	}
+
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+
+	if w.cipher == nil {
+		return nil
+	}
+
+	wif, err := w.cipher.decrypt(ticket.Hash, ticket.VotingWIF)
+	if err != nil {
+		return err
+	}
+
+	ticket.VotingWIF = wif
+	return nil
+}
+
+// WIFsEncrypted returns true if voting WIFs are encrypted in the database.
+func (vdb *VspDatabase) WIFsEncrypted() bool {
+	return vdb.wifs.encrypted()
+}
+
+// UnlockWIFs derives the voting WIF encryption key from the provided
+// passphrase. Until it is called, tickets read from a database with encrypted
+// WIFs contain the encrypted value, and new WIFs cannot be stored.
+func (vdb *VspDatabase) UnlockWIFs(passphrase []byte) error {
+	return vdb.wifs.unlock(passphrase)
+}
+
+// EncryptWIFs encrypts the voting WIF of every ticket with a key derived from
+// the provided passphrase, and leaves the database unlocked.
+func (vdb *VspDatabase) EncryptWIFs(passphrase []byte) error {
+	if vdb.wifs.encrypted() {
+		return ErrWIFsEncrypted
+	}
+
+	params, c, err := newWIFKey(passphrase)
+	if err != nil {
+		return err
+	}
+
+	err = vdb.reencryptWIFs(params, nil, c)
+	if err != nil {
+		return err
+	}
+
+	vdb.wifs.set(params, c)
+	return nil
+}
+
+// RotateWIFKey re-encrypts the voting WIF of every ticket with a key derived
+// from newPassphrase, and leaves the database unlocked with the new key.
+func (vdb *VspDatabase) RotateWIFKey(oldPassphrase, newPassphrase []byte) error {
+	oldCipher, err := vdb.wifs.verify(oldPassphrase)
+	if err != nil {
+		return err
+	}
+
+	params, newCipher, err := newWIFKey(newPassphrase)
+	if err != nil {
+		return err
+	}
+
+	err = vdb.reencryptWIFs(params, oldCipher, newCipher)
+	if err != nil {
+		return err
+	}
+
+	vdb.wifs.set(params, newCipher)
+	return nil
+}
+
+// reencryptWIFs re-encrypts the voting WIF of every ticket and stores the new
+// key parameters in a single transaction.
+func (vdb *VspDatabase) reencryptWIFs(params *wifKeyParams, from, to *wifCipher) error {
+	return vdb.db.Update(func(tx *bolt.Tx) error {
+		vspBkt := tx.Bucket(vspBktK)
+		ticketBkt := vspBkt.Bucket(ticketBktK)
+
+		// Collect the hashes before modifying any tickets, because buckets
+		// must not be modified while they are being iterated.
+		var hashes [][]byte
+		err := ticketBkt.ForEachBucket(func(k []byte) error {
+			hashes = append(hashes, bytes.Clone(k))
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, hash := range hashes {
+			bkt := ticketBkt.Bucket(hash)
+			value, err := reencryptWIF(string(hash), string(bkt.Get(votingWIFK)), from, to)
+			if err != nil {
+				return err
+			}
+			err = bkt.Put(votingWIFK, []byte(value))
+			if err != nil {
+				return err
+			}
+		}
+
+		return vspBkt.Put(wifKeyK, params.bytes())
+	})
+}
+
+// loadWIFKey reads the voting WIF key parameters from the database.
+func (vdb *VspDatabase) loadWIFKey() error {
+	return vdb.db.View(func(tx *bolt.Tx) error {
+		params, err := bytesToWIFKeyParams(tx.Bucket(vspBktK).Get(wifKeyK))
+		if err != nil {
+			return err
+		}
+		vdb.wifs.set(params, nil)
+		return nil
+	})
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package database
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/decred/slog"
+)
+
+// reopen closes the test database and opens it again, discarding any voting
+// WIF key which was held in memory.
+func reopen(t *testing.T) {
+	t.Helper()
+
+	db.Close(false)
+
+	var err error
+	db, err = backend.Open(testDb, slog.Disabled, maxVoteChangeRecords)
+	if err != nil {
+		t.Fatalf("error reopening test database: %v", err)
+	}
+}
+
+// expectWIF ensures the stored ticket with the provided hash has the expected
+// voting WIF.
+func expectWIF(t *testing.T, hash, expected string) {
+	t.Helper()
+
+	ticket, found, err := db.GetTicketByHash(hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket: %v", err)
+	}
+	if !found {
+		t.Fatal("expected found==true")
+	}
+	if ticket.VotingWIF != expected {
+		t.Fatalf("expected voting WIF %q, got %q", expected, ticket.VotingWIF)
+	}
+}
+
+func testWIFEncryption(t *testing.T) {
+	passphrase := []byte("correct horse battery staple")
+	newPassphrase := []byte("new passphrase")
+
+	if db.WIFsEncrypted() {
+		t.Fatal("new database should not have encrypted voting WIFs")
+	}
+
+	err := db.UnlockWIFs(passphrase)
+	if !errors.Is(err, ErrWIFsNotEncrypted) {
+		t.Fatalf("expected ErrWIFsNotEncrypted unlocking plaintext database, got %v", err)
+	}
+
+	// Store tickets with and without a WIF before encrypting.
+	before := exampleTicket()
+	err = db.InsertNewTicket(before)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+
+	noWIF := exampleTicket()
+	noWIF.VotingWIF = ""
+	err = db.InsertNewTicket(noWIF)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+
+	err = db.EncryptWIFs(passphrase)
+	if err != nil {
+		t.Fatalf("error encrypting voting WIFs: %v", err)
+	}
+
+	if !db.WIFsEncrypted() {
+		t.Fatal("expected voting WIFs to be encrypted")
+	}
+
+	err = db.EncryptWIFs(passphrase)
+	if !errors.Is(err, ErrWIFsEncrypted) {
+		t.Fatalf("expected ErrWIFsEncrypted encrypting twice, got %v", err)
+	}
+
+	// The database is left unlocked, so new WIFs are encrypted transparently.
+	after := exampleTicket()
+	err = db.InsertNewTicket(after)
+	if err != nil {
+		t.Fatalf("error storing ticket in database: %v", err)
+	}
+
+	expectWIF(t, before.Hash, before.VotingWIF)
+	expectWIF(t, after.Hash, after.VotingWIF)
+	expectWIF(t, noWIF.Hash, "")
+
+	// No WIF should appear in plaintext in an export.
+	var buf bytes.Buffer
+	err = db.Export(&buf, true)
+	if err != nil {
+		t.Fatalf("error exporting database: %v", err)
+	}
+	for _, ticket := range []Ticket{before, after} {
+		if bytes.Contains(buf.Bytes(), []byte(ticket.VotingWIF)) {
+			t.Fatal("export contains plaintext voting WIF")
+		}
+	}
+
+	// After reopening, WIFs remain encrypted until the database is unlocked.
+	reopen(t)
+
+	if !db.WIFsEncrypted() {
+		t.Fatal("expected voting WIFs to be encrypted after reopening")
+	}
+
+	locked, _, err := db.GetTicketByHash(before.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving ticket: %v", err)
+	}
+	if !IsEncryptedWIF(locked.VotingWIF) {
+		t.Fatalf("expected encrypted voting WIF from locked database, got %q", locked.VotingWIF)
+	}
+
+	// Updating a ticket read from a locked database keeps its encrypted WIF.
+	locked.FeeTxStatus = FeeConfirmed
+	err = db.UpdateTicket(locked)
+	if err != nil {
+		t.Fatalf("error updating ticket: %v", err)
+	}
+
+	// Plaintext WIFs cannot be stored while locked.
+	err = db.InsertNewTicket(exampleTicket())
+	if !errors.Is(err, ErrWIFsLocked) {
+		t.Fatalf("expected ErrWIFsLocked storing ticket in locked database, got %v", err)
+	}
+
+	err = db.UnlockWIFs(newPassphrase)
+	if !errors.Is(err, ErrWrongWIFPassphrase) {
+		t.Fatalf("expected ErrWrongWIFPassphrase, got %v", err)
+	}
+
+	err = db.UnlockWIFs(passphrase)
+	if err != nil {
+		t.Fatalf("error unlocking voting WIFs: %v", err)
+	}
+
+	expectWIF(t, before.Hash, before.VotingWIF)
+
+	// Rotate the key.
+	err = db.RotateWIFKey(newPassphrase, newPassphrase)
+	if !errors.Is(err, ErrWrongWIFPassphrase) {
+		t.Fatalf("expected ErrWrongWIFPassphrase rotating with wrong passphrase, got %v", err)
+	}
+
+	err = db.RotateWIFKey(passphrase, newPassphrase)
+	if err != nil {
+		t.Fatalf("error rotating voting WIF key: %v", err)
+	}
+
+	reopen(t)
+
+	err = db.UnlockWIFs(passphrase)
+	if !errors.Is(err, ErrWrongWIFPassphrase) {
+		t.Fatalf("expected ErrWrongWIFPassphrase unlocking with old passphrase, got %v", err)
+	}
+
+	err = db.UnlockWIFs(newPassphrase)
+	if err != nil {
+		t.Fatalf("error unlocking voting WIFs: %v", err)
+	}
+
+	tickets, err := db.ListTickets(TicketFilter{})
+	if err != nil {
+		t.Fatalf("error listing tickets: %v", err)
+	}
+	expected := map[string]string{
+		before.Hash: before.VotingWIF,
+		after.Hash:  after.VotingWIF,
+		noWIF.Hash:  "",
+	}
+	if len(tickets) != len(expected) {
+		t.Fatalf("expected %d tickets, got %d", len(expected), len(tickets))
+	}
+	for _, ticket := range tickets {
+		if ticket.VotingWIF != expected[ticket.Hash] {
+			t.Fatalf("expected voting WIF %q, got %q", expected[ticket.Hash], ticket.VotingWIF)
+		}
+	}
+
+	// An imported database keeps the encryption key.
+	defer removeImportDb()
+	imported := importExport(t, true)
+
+	if !imported.WIFsEncrypted() {
+		t.Fatal("expected voting WIFs of imported database to be encrypted")
+	}
+
+	err = imported.UnlockWIFs(newPassphrase)
+	if err != nil {
+		t.Fatalf("error unlocking imported voting WIFs: %v", err)
+	}
+
+	ticket, _, err := imported.GetTicketByHash(after.Hash)
+	if err != nil {
+		t.Fatalf("error retrieving imported ticket: %v", err)
+	}
+	if ticket.VotingWIF != after.VotingWIF {
+		t.Fatalf("expected imported voting WIF %q, got %q", after.VotingWIF, ticket.VotingWIF)
+	}
+}
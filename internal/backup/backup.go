@@ -0,0 +1,278 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package backup keeps timestamped copies of the database backup file written
+// by vspd, deletes them according to a retention policy, and optionally copies
+// them to off-host storage.
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+)
+
+const (
+	// snapshotInterval is the minimum time between two timestamped backups.
+	snapshotInterval = time.Hour
+
+	// sinkTimeout is the maximum time allowed for a single operation on a
+	// sink, eg. uploading a backup.
+	sinkTimeout = 10 * time.Minute
+
+	// timeLayout is the format of the timestamp in backup file names.
+	timeLayout = "20060102T150405Z"
+)
+
+// Retention describes how many timestamped backups are kept. The newest backup
+// of each of the last Hourly hours and of each of the last Daily days is kept,
+// and every other backup is deleted. The most recent backup is always kept.
+type Retention struct {
+	Hourly uint
+	Daily  uint
+}
+
+// expired returns the names of the backups which should be deleted, given the
+// time each backup was taken.
+func (r Retention) expired(backups map[string]time.Time, now time.Time) []string {
+	names := make([]string, 0, len(backups))
+	for name := range backups {
+		names = append(names, name)
+	}
+
+	// Newest first, so that the newest backup of each period is kept.
+	sort.Slice(names, func(i, j int) bool {
+		return backups[names[i]].After(backups[names[j]])
+	})
+
+	hours := make(map[time.Time]bool)
+	days := make(map[time.Time]bool)
+
+	var expired []string
+	for i, name := range names {
+		t := backups[name].UTC()
+		age := now.Sub(t)
+		keep := i == 0
+
+		if age < time.Duration(r.Hourly)*time.Hour {
+			hour := t.Truncate(time.Hour)
+			if !hours[hour] {
+				hours[hour] = true
+				keep = true
+			}
+		}
+
+		if age < time.Duration(r.Daily)*24*time.Hour {
+			day := t.Truncate(24 * time.Hour)
+			if !days[day] {
+				days[day] = true
+				keep = true
+			}
+		}
+
+		if !keep {
+			expired = append(expired, name)
+		}
+	}
+
+	sort.Strings(expired)
+	return expired
+}
+
+// Manager takes timestamped copies of the backup file which is periodically
+// written by the database, storing them in a local directory and in every
+// configured sink.
+type Manager struct {
+	log        slog.Logger
+	hotBackup  string
+	prefix     string
+	ext        string
+	retention  Retention
+	local      *DirSink
+	sinks      []Sink
+	verify     func(file string) error
+	lastBackup time.Time
+}
+
+// New creates a manager for the backups of the database at dbFile. Timestamped
+// backups are stored in a "backups" directory alongside the database file, which
+// is created if it does not exist. If verify is not nil, it is called with the
+// path of the backup file before each timestamped backup is taken, and no
+// backup is taken if it returns an error.
+func New(log slog.Logger, dbFile string, retention Retention, sinks []Sink,
+	verify func(file string) error) (*Manager, error) {
+	dir := filepath.Join(filepath.Dir(dbFile), "backups")
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	base := filepath.Base(dbFile)
+	ext := filepath.Ext(base)
+
+	m := &Manager{
+		log:       log,
+		hotBackup: database.HotBackupFile(dbFile),
+		prefix:    strings.TrimSuffix(base, ext) + "-",
+		ext:       ext,
+		retention: retention,
+		local:     &DirSink{Dir: dir},
+		sinks:     sinks,
+		verify:    verify,
+	}
+
+	// Continue from the most recent existing backup so that restarting vspd
+	// does not cause an additional backup to be taken.
+	backups, err := m.list(context.Background(), m.local)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range backups {
+		if t.After(m.lastBackup) {
+			m.lastBackup = t
+		}
+	}
+
+	return m, nil
+}
+
+// name returns the file name of a backup taken at time t.
+func (m *Manager) name(t time.Time) string {
+	return m.prefix + t.UTC().Format(timeLayout) + m.ext
+}
+
+// parseName returns the time a backup was taken from its file name. The bool is
+// false if the name does not belong to a backup written by this manager.
+func (m *Manager) parseName(name string) (time.Time, bool) {
+	if !strings.HasPrefix(name, m.prefix) || !strings.HasSuffix(name, m.ext) {
+		return time.Time{}, false
+	}
+
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(name, m.prefix), m.ext)
+	t, err := time.Parse(timeLayout, timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// list returns the backups stored in a sink along with the time they were
+// taken. Any other files stored in the sink are ignored.
+func (m *Manager) list(ctx context.Context, sink PrunableSink) (map[string]time.Time, error) {
+	names, err := sink.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backups in %s: %w", sink, err)
+	}
+
+	backups := make(map[string]time.Time)
+	for _, name := range names {
+		if t, ok := m.parseName(name); ok {
+			backups[name] = t
+		}
+	}
+
+	return backups, nil
+}
+
+// prune deletes the backups in a sink which are no longer required by the
+// retention policy.
+func (m *Manager) prune(ctx context.Context, sink PrunableSink, now time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
+	defer cancel()
+
+	backups, err := m.list(ctx, sink)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range m.retention.expired(backups, now) {
+		err = sink.Delete(ctx, name)
+		if err != nil {
+			return fmt.Errorf("failed to delete %s from %s: %w", name, sink, err)
+		}
+		m.log.Debugf("Expired backup %s deleted from %s", name, sink)
+	}
+
+	return nil
+}
+
+// put stores a backup in a sink.
+func (m *Manager) put(ctx context.Context, sink Sink, name string) error {
+	ctx, cancel := context.WithTimeout(ctx, sinkTimeout)
+	defer cancel()
+
+	err := sink.Put(ctx, name, m.hotBackup)
+	if err != nil {
+		return fmt.Errorf("failed to write %s to %s: %w", name, sink, err)
+	}
+
+	m.log.Infof("Backup %s written to %s", name, sink)
+	return nil
+}
+
+// Snapshot should be called every time the database backup file has been
+// written. If an hour has passed since the last timestamped backup was taken and
+// the backup file passes verification, a new one is copied from the backup file
+// to the local backup directory and to every sink, and expired backups are
+// deleted.
+func (m *Manager) Snapshot(ctx context.Context) {
+	m.snapshot(ctx, time.Now())
+}
+
+func (m *Manager) snapshot(ctx context.Context, now time.Time) {
+	if now.Sub(m.lastBackup) < snapshotInterval {
+		return
+	}
+
+	// Verification reads the entire backup, so it is only done for backups
+	// which are about to be kept rather than every time the file is written.
+	// Copies of a backup which could not be restored are not kept.
+	if m.verify != nil {
+		err := m.verify(m.hotBackup)
+		if err != nil {
+			m.log.Errorf("Database backup %s failed verification: %v", m.hotBackup, err)
+			return
+		}
+	}
+
+	name := m.name(now)
+
+	// Failing to write the local copy is reported, but does not prevent the
+	// backup being written to other sinks.
+	err := m.put(ctx, m.local, name)
+	if err != nil {
+		m.log.Errorf("%v", err)
+	} else {
+		m.lastBackup = now
+	}
+
+	for _, sink := range m.sinks {
+		err := m.put(ctx, sink, name)
+		if err != nil {
+			m.log.Errorf("%v", err)
+			continue
+		}
+		m.lastBackup = now
+	}
+
+	for _, sink := range append([]Sink{m.local}, m.sinks...) {
+		prunable, ok := sink.(PrunableSink)
+		if !ok {
+			continue
+		}
+		err := m.prune(ctx, prunable, now)
+		if err != nil && !errors.Is(err, context.Canceled) {
+			m.log.Errorf("%v", err)
+		}
+	}
+}
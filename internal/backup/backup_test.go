@@ -0,0 +1,272 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+)
+
+func TestRetentionExpired(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 30, 0, 0, time.UTC)
+	at := func(ago time.Duration) time.Time { return now.Add(-ago) }
+
+	tests := map[string]struct {
+		retention Retention
+		backups   map[string]time.Time
+		expected  []string
+	}{
+		"newest of each hour kept": {
+			retention: Retention{Hourly: 24},
+			backups: map[string]time.Time{
+				"a": at(10 * time.Minute),
+				"b": at(20 * time.Minute),
+				"c": at(40 * time.Minute),
+				"d": at(50 * time.Minute),
+			},
+			// a and b are both from 12:xx, c and d from 11:xx.
+			expected: []string{"b", "d"},
+		},
+		"hourly backups expire after retention": {
+			retention: Retention{Hourly: 2},
+			backups: map[string]time.Time{
+				"a": at(time.Hour),
+				"b": at(2 * time.Hour),
+				"c": at(3 * time.Hour),
+			},
+			expected: []string{"b", "c"},
+		},
+		"newest of each day kept": {
+			retention: Retention{Hourly: 1, Daily: 30},
+			backups: map[string]time.Time{
+				"a": at(0),
+				"b": at(24 * time.Hour),
+				"c": at(25 * time.Hour),
+				"d": at(29 * 24 * time.Hour),
+				"e": at(31 * 24 * time.Hour),
+			},
+			// b is the newest of 14 March, so c is expired along with e which
+			// is outside the retention period.
+			expected: []string{"c", "e"},
+		},
+		"newest backup always kept": {
+			retention: Retention{},
+			backups: map[string]time.Time{
+				"a": at(100 * 24 * time.Hour),
+				"b": at(200 * 24 * time.Hour),
+			},
+			expected: []string{"b"},
+		},
+		"no backups": {
+			retention: Retention{Hourly: 24, Daily: 30},
+			backups:   map[string]time.Time{},
+			expected:  nil,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			actual := test.retention.expired(test.backups, now)
+			if !reflect.DeepEqual(test.expected, actual) {
+				t.Fatalf("expected expired backups %v, got %v", test.expected, actual)
+			}
+		})
+	}
+}
+
+// listDir returns the sorted names of the files in dir.
+func listDir(t *testing.T, dir string) []string {
+	t.Helper()
+
+	names, err := (&DirSink{Dir: dir}).List(context.Background())
+	if err != nil {
+		t.Fatalf("error listing directory: %v", err)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestManagerSnapshot(t *testing.T) {
+	dataDir := t.TempDir()
+	sinkDir := t.TempDir()
+	cmdDir := t.TempDir()
+
+	dbFile := filepath.Join(dataDir, "vspd.db")
+	err := os.WriteFile(database.HotBackupFile(dbFile), []byte("backup"), 0600)
+	if err != nil {
+		t.Fatalf("error writing backup file: %v", err)
+	}
+
+	cmdSink, err := NewCommandSink("cp {file} " + cmdDir + "/{name}")
+	if err != nil {
+		t.Fatalf("error creating command sink: %v", err)
+	}
+
+	m, err := New(slog.Disabled, dbFile, Retention{Hourly: 2}, []Sink{&DirSink{Dir: sinkDir}, cmdSink}, nil)
+	if err != nil {
+		t.Fatalf("error creating manager: %v", err)
+	}
+
+	localDir := filepath.Join(dataDir, "backups")
+	ctx := context.Background()
+	start := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	m.snapshot(ctx, start)
+	expected := []string{"vspd-20260315T120000Z.db"}
+	for _, dir := range []string{localDir, sinkDir, cmdDir} {
+		if actual := listDir(t, dir); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("expected %v in %s, got %v", expected, dir, actual)
+		}
+	}
+
+	contents, err := os.ReadFile(filepath.Join(localDir, expected[0]))
+	if err != nil {
+		t.Fatalf("error reading backup: %v", err)
+	}
+	if string(contents) != "backup" {
+		t.Fatalf("unexpected backup contents %q", contents)
+	}
+
+	// No new backup is taken within an hour of the last.
+	m.snapshot(ctx, start.Add(59*time.Minute))
+	if actual := listDir(t, localDir); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	// Hourly backups are expired from the local directory and directory sink,
+	// but not from the command sink which cannot list its backups.
+	for i := 1; i <= 3; i++ {
+		m.snapshot(ctx, start.Add(time.Duration(i)*time.Hour))
+	}
+	expected = []string{"vspd-20260315T140000Z.db", "vspd-20260315T150000Z.db"}
+	for _, dir := range []string{localDir, sinkDir} {
+		if actual := listDir(t, dir); !reflect.DeepEqual(expected, actual) {
+			t.Fatalf("expected %v in %s, got %v", expected, dir, actual)
+		}
+	}
+	if actual := listDir(t, cmdDir); len(actual) != 4 {
+		t.Fatalf("expected 4 backups in command sink, got %v", actual)
+	}
+
+	// Files which are not backups are never deleted.
+	err = os.WriteFile(filepath.Join(sinkDir, "other.db"), nil, 0600)
+	if err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	m.snapshot(ctx, start.Add(5*time.Hour))
+	expected = []string{"other.db", "vspd-20260315T170000Z.db"}
+	if actual := listDir(t, sinkDir); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	// A new manager continues from the newest existing backup.
+	m, err = New(slog.Disabled, dbFile, Retention{Hourly: 2}, nil, nil)
+	if err != nil {
+		t.Fatalf("error creating manager: %v", err)
+	}
+	if !m.lastBackup.Equal(start.Add(5 * time.Hour)) {
+		t.Fatalf("expected last backup %v, got %v", start.Add(5*time.Hour), m.lastBackup)
+	}
+}
+
+func TestManagerSnapshotVerify(t *testing.T) {
+	dataDir := t.TempDir()
+
+	dbFile := filepath.Join(dataDir, "vspd.db")
+	err := os.WriteFile(database.HotBackupFile(dbFile), []byte("backup"), 0600)
+	if err != nil {
+		t.Fatalf("error writing backup file: %v", err)
+	}
+
+	var verified int
+	verifyErr := errors.New("corrupt")
+	verify := func(file string) error {
+		if file != database.HotBackupFile(dbFile) {
+			t.Fatalf("unexpected file %s verified", file)
+		}
+		verified++
+		return verifyErr
+	}
+
+	m, err := New(slog.Disabled, dbFile, Retention{Hourly: 2}, nil, verify)
+	if err != nil {
+		t.Fatalf("error creating manager: %v", err)
+	}
+
+	localDir := filepath.Join(dataDir, "backups")
+	ctx := context.Background()
+	start := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+
+	// A backup which fails verification is not kept.
+	m.snapshot(ctx, start)
+	if actual := listDir(t, localDir); len(actual) != 0 {
+		t.Fatalf("expected no backups, got %v", actual)
+	}
+	if verified != 1 {
+		t.Fatalf("expected 1 verification, got %d", verified)
+	}
+
+	// The next backup which passes verification is kept.
+	verifyErr = nil
+	m.snapshot(ctx, start.Add(time.Minute))
+	expected := []string{"vspd-20260315T120100Z.db"}
+	if actual := listDir(t, localDir); !reflect.DeepEqual(expected, actual) {
+		t.Fatalf("expected %v, got %v", expected, actual)
+	}
+
+	// Backups are not verified unless they would be kept.
+	m.snapshot(ctx, start.Add(2*time.Minute))
+	if verified != 2 {
+		t.Fatalf("expected 2 verifications, got %d", verified)
+	}
+}
+
+func TestNewCommandSink(t *testing.T) {
+	tests := map[string]struct {
+		command  string
+		expected []string
+		wantErr  bool
+	}{
+		"placeholders": {
+			command:  "scp {file} host:dir/{name}",
+			expected: []string{"scp", "{file}", "host:dir/{name}"},
+		},
+		"file appended": {
+			command:  "  upload --quiet ",
+			expected: []string{"upload", "--quiet", "{file}"},
+		},
+		"empty": {
+			command: " ",
+			wantErr: true,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			sink, err := NewCommandSink(test.command)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(test.expected, sink.args) {
+				t.Fatalf("expected args %v, got %v", test.expected, sink.args)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/slog"
+)
+
+// TestSignV4 checks the signature of the get-vanilla example from the AWS
+// Signature Version 4 test suite.
+func TestSignV4(t *testing.T) {
+	req, err := http.NewRequest(http.MethodGet, "https://example.amazonaws.com/", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	signV4(req, emptyPayloadHash, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		"us-east-1", "service", now)
+
+	expected := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=host;x-amz-date, " +
+		"Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31"
+	if actual := req.Header.Get("Authorization"); actual != expected {
+		t.Fatalf("expected Authorization header\n%s\ngot\n%s", expected, actual)
+	}
+}
+
+// fakeS3 is a minimal stand-in for an S3-compatible object store, supporting
+// only the requests made by S3Sink.
+type fakeS3 struct {
+	mtx     sync.Mutex
+	bucket  string
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=key/") {
+		http.Error(w, "missing signature", http.StatusForbidden)
+		return
+	}
+
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != f.bucket {
+		http.Error(w, "no such bucket", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		hash := sha256.Sum256(body)
+		if r.Header.Get("X-Amz-Content-Sha256") != hex.EncodeToString(hash[:]) {
+			http.Error(w, "payload hash mismatch", http.StatusBadRequest)
+			return
+		}
+		f.objects[key] = body
+
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+
+	case http.MethodGet:
+		if r.URL.Query().Get("list-type") != "2" {
+			http.Error(w, "unsupported", http.StatusNotImplemented)
+			return
+		}
+		var result listBucketResult
+		prefix := r.URL.Query().Get("prefix")
+		for key := range f.objects {
+			if strings.HasPrefix(key, prefix) {
+				result.Contents = append(result.Contents, struct{ Key string }{key})
+			}
+		}
+		sort.Slice(result.Contents, func(i, j int) bool {
+			return result.Contents[i].Key < result.Contents[j].Key
+		})
+		_ = xml.NewEncoder(w).Encode(result)
+
+	default:
+		http.Error(w, "unsupported", http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Sink(t *testing.T) {
+	fake := &fakeS3{bucket: "bucket", objects: map[string][]byte{
+		"other/vspd-20260101T000000Z.db": []byte("other"),
+	}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	_, err := NewS3Sink(server.URL, "us-east-1", "key", "secret")
+	if err == nil {
+		t.Fatal("expected error creating sink without bucket")
+	}
+
+	sink, err := NewS3Sink(server.URL+"/bucket/vspd", "us-east-1", "key", "secret")
+	if err != nil {
+		t.Fatalf("error creating sink: %v", err)
+	}
+
+	dataDir := t.TempDir()
+	dbFile := filepath.Join(dataDir, "vspd.sqlite")
+	err = os.WriteFile(dbFile+"-backup", []byte("backup"), 0600)
+	if err != nil {
+		t.Fatalf("error writing backup file: %v", err)
+	}
+
+	m, err := New(slog.Disabled, dbFile, Retention{Hourly: 1}, []Sink{sink}, nil)
+	if err != nil {
+		t.Fatalf("error creating manager: %v", err)
+	}
+
+	ctx := context.Background()
+	start := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	m.snapshot(ctx, start)
+	m.snapshot(ctx, start.Add(time.Hour))
+
+	names, err := sink.List(ctx)
+	if err != nil {
+		t.Fatalf("error listing objects: %v", err)
+	}
+	expected := []string{"vspd-20260315T130000Z.sqlite"}
+	if !reflect.DeepEqual(expected, names) {
+		t.Fatalf("expected objects %v, got %v", expected, names)
+	}
+
+	if string(fake.objects["vspd/"+expected[0]]) != "backup" {
+		t.Fatalf("unexpected object contents %q", fake.objects["vspd/"+expected[0]])
+	}
+
+	// Objects outside of the prefix are not touched.
+	if _, ok := fake.objects["other/vspd-20260101T000000Z.db"]; !ok {
+		t.Fatal("object outside of prefix was deleted")
+	}
+}
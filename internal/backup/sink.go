@@ -0,0 +1,149 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// fileMode is the mode of backup files written to directories.
+const fileMode = 0600
+
+// Sink stores timestamped backups, ideally somewhere other than the host vspd
+// is running on.
+type Sink interface {
+	// Put stores a copy of the file at path with the provided name.
+	Put(ctx context.Context, name, path string) error
+	// String describes the sink in log messages. It must not include any
+	// credentials.
+	String() string
+}
+
+// PrunableSink is implemented by sinks which are able to list and delete the
+// backups they store, allowing the retention policy to be applied to them.
+type PrunableSink interface {
+	Sink
+	List(ctx context.Context) ([]string, error)
+	Delete(ctx context.Context, name string) error
+}
+
+// DirSink stores backups in a local directory, which may be a mount of remote
+// storage.
+type DirSink struct {
+	Dir string
+}
+
+// Put copies the file at path into the directory. The copy is written to a
+// temporary file first so that a partially written backup never has the name
+// of a complete one.
+func (s *DirSink) Put(_ context.Context, name, path string) error {
+	from, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer from.Close()
+
+	dest := filepath.Join(s.Dir, name)
+	tempPath := dest + "~"
+
+	to, err := os.OpenFile(tempPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, fileMode)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(to, from)
+	if err == nil {
+		err = to.Sync()
+	}
+	closeErr := to.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tempPath)
+		return err
+	}
+
+	return os.Rename(tempPath, dest)
+}
+
+// List returns the names of every file in the directory.
+func (s *DirSink) List(_ context.Context) ([]string, error) {
+	entries, err := os.ReadDir(s.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// Delete removes the named file from the directory.
+func (s *DirSink) Delete(_ context.Context, name string) error {
+	return os.Remove(filepath.Join(s.Dir, name))
+}
+
+func (s *DirSink) String() string {
+	return s.Dir
+}
+
+// CommandSink stores backups by running an external command, eg. to copy them
+// to another host with scp or rsync. Because the command can only store
+// backups, the retention policy is not applied to them.
+type CommandSink struct {
+	args []string
+}
+
+// NewCommandSink parses a command line into a CommandSink. The command is split
+// on whitespace and run directly rather than by a shell. The placeholders
+// {file} and {name} are replaced with the path of the backup file and the name
+// it should be stored with. If neither placeholder is used, the path of the
+// backup file is appended as the final argument.
+func NewCommandSink(command string) (*CommandSink, error) {
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		return nil, errors.New("backup command is empty")
+	}
+
+	if !strings.Contains(command, "{file}") && !strings.Contains(command, "{name}") {
+		args = append(args, "{file}")
+	}
+
+	return &CommandSink{args: args}, nil
+}
+
+// Put runs the command for the file at path, returning an error including the
+// output of the command if it does not exit successfully.
+func (s *CommandSink) Put(ctx context.Context, name, path string) error {
+	args := make([]string, len(s.args))
+	for i, arg := range s.args {
+		arg = strings.ReplaceAll(arg, "{file}", path)
+		args[i] = strings.ReplaceAll(arg, "{name}", name)
+	}
+
+	output, err := exec.CommandContext(ctx, args[0], args[1:]...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
+
+func (s *CommandSink) String() string {
+	return "command " + s.args[0]
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+const (
+	// WIFPassEnv is the environment variable which can hold the passphrase
+	// used to encrypt voting WIFs in the database.
+	WIFPassEnv = "VSPD_WIF_PASSPHRASE"
+	// NewWIFPassEnv is the environment variable which can hold the new
+	// passphrase when the voting WIF encryption key is rotated.
+	NewWIFPassEnv = "VSPD_NEW_WIF_PASSPHRASE"
+)
+
+// WIFPassphrase returns the passphrase used to encrypt voting WIFs, which can
+// be provided directly, as the path of a key file containing the passphrase, or
+// in the named environment variable. Trailing newlines are removed from the
+// contents of the key file. An error is returned if more than one source is
+// set, and a nil passphrase is returned if none are.
+func WIFPassphrase(pass, keyFile, envVar string) ([]byte, error) {
+	envPass := os.Getenv(envVar)
+
+	var sources int
+	for _, s := range []string{pass, keyFile, envPass} {
+		if s != "" {
+			sources++
+		}
+	}
+	if sources > 1 {
+		return nil, fmt.Errorf("voting WIF passphrase must be provided by only one "+
+			"of the passphrase option, key file, or %s environment variable", envVar)
+	}
+
+	switch {
+	case pass != "":
+		return []byte(pass), nil
+	case envPass != "":
+		return []byte(envPass), nil
+	case keyFile != "":
+		contents, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read voting WIF key file: %w", err)
+		}
+		contents = bytes.TrimRight(contents, "\r\n")
+		if len(contents) == 0 {
+			return nil, errors.New("voting WIF key file is empty")
+		}
+		return contents, nil
+	default:
+		return nil, nil
+	}
+}
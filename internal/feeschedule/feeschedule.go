@@ -0,0 +1,264 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package feeschedule determines the fee percentage charged for each ticket
+// according to a schedule of ticket price tiers, time limited promotional
+// rates, and discounted rates for particular commitment addresses.
+package feeschedule
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+)
+
+// DefaultPolicy is the name of the policy which prices tickets when no tier
+// applies to the ticket price and no promotion or discount offers a lower fee.
+const DefaultPolicy = "default"
+
+// Tier replaces the default fee percentage when the ticket price is at least
+// MinTicketPrice. Only the tier with the highest MinTicketPrice not exceeding
+// the ticket price applies.
+type Tier struct {
+	Name           string
+	MinTicketPrice dcrutil.Amount
+	FeePercentage  float64
+}
+
+// Promotion charges FeePercentage from Start until End.
+type Promotion struct {
+	Name          string
+	Start         time.Time
+	End           time.Time
+	FeePercentage float64
+}
+
+// Discount charges FeePercentage for tickets with one of the listed commitment
+// addresses.
+type Discount struct {
+	Name          string
+	Addresses     map[string]struct{}
+	FeePercentage float64
+}
+
+// Schedule is a validated fee schedule. Schedules are never modified once
+// loaded, so they can be shared between goroutines.
+type Schedule struct {
+	// DefaultFeePercentage is charged when no tier applies.
+	DefaultFeePercentage float64
+	// Tiers are sorted by MinTicketPrice.
+	Tiers      []Tier
+	Promotions []Promotion
+	Discounts  []Discount
+}
+
+// The types of the JSON schedule file. Ticket prices are in DCR and times are
+// in RFC 3339 format.
+type (
+	fileTier struct {
+		Name           string  `json:"name"`
+		MinTicketPrice float64 `json:"minticketprice"`
+		FeePercentage  float64 `json:"feepercentage"`
+	}
+	filePromotion struct {
+		Name          string    `json:"name"`
+		Start         time.Time `json:"start"`
+		End           time.Time `json:"end"`
+		FeePercentage float64   `json:"feepercentage"`
+	}
+	fileDiscount struct {
+		Name          string   `json:"name"`
+		Addresses     []string `json:"addresses"`
+		FeePercentage float64  `json:"feepercentage"`
+	}
+	scheduleFile struct {
+		Tiers      []fileTier      `json:"tiers"`
+		Promotions []filePromotion `json:"promotions"`
+		Discounts  []fileDiscount  `json:"discounts"`
+	}
+)
+
+// ValidFeePercentage returns true if fee is a valid percentage from 0.01% to
+// 100.00%, as required by txrules.
+func ValidFeePercentage(fee float64) bool {
+	test := math.Floor(fee * 100)
+	return test >= 1.0 && test <= 10000.0
+}
+
+// Load reads and validates the schedule file at path. Tickets which are not
+// priced by any policy in the file are charged defaultFee. If path is empty,
+// every ticket is charged defaultFee.
+func Load(path string, defaultFee float64, params *chaincfg.Params) (*Schedule, error) {
+	if path == "" {
+		return parse(bytes.NewReader([]byte("{}")), defaultFee, params)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	schedule, err := parse(f, defaultFee, params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee schedule %s: %w", path, err)
+	}
+
+	return schedule, nil
+}
+
+func parse(r io.Reader, defaultFee float64, params *chaincfg.Params) (*Schedule, error) {
+	if !ValidFeePercentage(defaultFee) {
+		return nil, fmt.Errorf("invalid default fee percentage %v", defaultFee)
+	}
+
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var file scheduleFile
+	err := dec.Decode(&file)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Schedule{DefaultFeePercentage: defaultFee}
+	names := map[string]struct{}{DefaultPolicy: {}}
+
+	// checkPolicy returns an error if a policy has no name, shares its name
+	// with another policy, or has an invalid fee.
+	checkPolicy := func(name string, fee float64) error {
+		if name == "" {
+			return errors.New("every policy must have a name")
+		}
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("policy name %q is used more than once", name)
+		}
+		names[name] = struct{}{}
+		if !ValidFeePercentage(fee) {
+			return fmt.Errorf("policy %q: fee percentage should be greater than 0.01 and less than 100.0", name)
+		}
+		return nil
+	}
+
+	for _, t := range file.Tiers {
+		err := checkPolicy(t.Name, t.FeePercentage)
+		if err != nil {
+			return nil, err
+		}
+		minPrice, err := dcrutil.NewAmount(t.MinTicketPrice)
+		if err != nil || minPrice <= 0 {
+			return nil, fmt.Errorf("tier %q: invalid minimum ticket price %v", t.Name, t.MinTicketPrice)
+		}
+		s.Tiers = append(s.Tiers, Tier{
+			Name:           t.Name,
+			MinTicketPrice: minPrice,
+			FeePercentage:  t.FeePercentage,
+		})
+	}
+	slices.SortFunc(s.Tiers, func(a, b Tier) int {
+		return cmp.Compare(a.MinTicketPrice, b.MinTicketPrice)
+	})
+	for i := 1; i < len(s.Tiers); i++ {
+		if s.Tiers[i].MinTicketPrice == s.Tiers[i-1].MinTicketPrice {
+			return nil, fmt.Errorf("tiers %q and %q have the same minimum ticket price",
+				s.Tiers[i-1].Name, s.Tiers[i].Name)
+		}
+	}
+
+	for _, p := range file.Promotions {
+		err := checkPolicy(p.Name, p.FeePercentage)
+		if err != nil {
+			return nil, err
+		}
+		if p.Start.IsZero() || p.End.IsZero() {
+			return nil, fmt.Errorf("promotion %q: start and end must be set", p.Name)
+		}
+		if !p.End.After(p.Start) {
+			return nil, fmt.Errorf("promotion %q: end must be after start", p.Name)
+		}
+		s.Promotions = append(s.Promotions, Promotion(p))
+	}
+
+	for _, d := range file.Discounts {
+		err := checkPolicy(d.Name, d.FeePercentage)
+		if err != nil {
+			return nil, err
+		}
+		if len(d.Addresses) == 0 {
+			return nil, fmt.Errorf("discount %q: no addresses", d.Name)
+		}
+		addrs := make(map[string]struct{}, len(d.Addresses))
+		for _, addr := range d.Addresses {
+			_, err := stdaddr.DecodeAddress(addr, params)
+			if err != nil {
+				return nil, fmt.Errorf("discount %q: invalid address %q: %w", d.Name, addr, err)
+			}
+			addrs[addr] = struct{}{}
+		}
+		s.Discounts = append(s.Discounts, Discount{
+			Name:          d.Name,
+			Addresses:     addrs,
+			FeePercentage: d.FeePercentage,
+		})
+	}
+
+	return s, nil
+}
+
+// Fee returns the fee percentage to charge at time now for a ticket with the
+// provided commitment address, when the current ticket price is ticketPrice,
+// along with the name of the policy which set it. The fee of the tier for the
+// ticket price, or the default fee if no tier applies, is reduced to the lowest
+// fee of any active promotion or matching discount. If several policies offer
+// the same lowest fee, promotions are preferred over discounts.
+func (s *Schedule) Fee(ticketPrice dcrutil.Amount, commitmentAddress string, now time.Time) (float64, string) {
+	fee, policy := s.DefaultFeePercentage, DefaultPolicy
+	for i := len(s.Tiers) - 1; i >= 0; i-- {
+		if ticketPrice >= s.Tiers[i].MinTicketPrice {
+			fee, policy = s.Tiers[i].FeePercentage, s.Tiers[i].Name
+			break
+		}
+	}
+
+	consider := func(f float64, name string) {
+		if f < fee {
+			fee, policy = f, name
+		}
+	}
+
+	for _, p := range s.Promotions {
+		if !now.Before(p.Start) && now.Before(p.End) {
+			consider(p.FeePercentage, p.Name)
+		}
+	}
+
+	for _, d := range s.Discounts {
+		if _, ok := d.Addresses[commitmentAddress]; ok {
+			consider(d.FeePercentage, d.Name)
+		}
+	}
+
+	return fee, policy
+}
+
+// CurrentPromotions returns the promotions which have not ended by time now.
+func (s *Schedule) CurrentPromotions(now time.Time) []Promotion {
+	current := make([]Promotion, 0, len(s.Promotions))
+	for _, p := range s.Promotions {
+		if now.Before(p.End) {
+			current = append(current, p)
+		}
+	}
+	return current
+}
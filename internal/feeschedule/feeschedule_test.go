@@ -0,0 +1,159 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package feeschedule
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+)
+
+func TestFee(t *testing.T) {
+	params := chaincfg.TestNet3Params()
+
+	address := func(b byte) string {
+		t.Helper()
+		addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(bytes.Repeat([]byte{b}, 20), params)
+		if err != nil {
+			t.Fatalf("error creating address: %v", err)
+		}
+		return addr.String()
+	}
+	partner, other := address(1), address(2)
+
+	file := `{
+		"tiers": [
+			{"name": "large", "minticketprice": 300, "feepercentage": 1},
+			{"name": "small", "minticketprice": 0.0001, "feepercentage": 4}
+		],
+		"promotions": [
+			{"name": "launch", "start": "2026-11-01T00:00:00Z", "end": "2026-12-01T00:00:00Z", "feepercentage": 1.5}
+		],
+		"discounts": [
+			{"name": "partners", "addresses": ["` + partner + `"], "feepercentage": 0.5}
+		]
+	}`
+
+	schedule, err := parse(strings.NewReader(file), 3, params)
+	if err != nil {
+		t.Fatalf("error parsing schedule: %v", err)
+	}
+
+	before := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	during := time.Date(2026, 11, 15, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := map[string]struct {
+		ticketPrice dcrutil.Amount
+		address     string
+		now         time.Time
+		fee         float64
+		policy      string
+	}{
+		"default below every tier": {
+			ticketPrice: 1000,
+			address:     other,
+			now:         before,
+			fee:         3,
+			policy:      DefaultPolicy,
+		},
+		"tier above default": {
+			ticketPrice: 200e8,
+			address:     other,
+			now:         before,
+			fee:         4,
+			policy:      "small",
+		},
+		"highest applicable tier": {
+			ticketPrice: 300e8,
+			address:     other,
+			now:         before,
+			fee:         1,
+			policy:      "large",
+		},
+		"promotion below tier": {
+			ticketPrice: 200e8,
+			address:     other,
+			now:         during,
+			fee:         1.5,
+			policy:      "launch",
+		},
+		"tier below promotion": {
+			ticketPrice: 300e8,
+			address:     other,
+			now:         during,
+			fee:         1,
+			policy:      "large",
+		},
+		"promotion ended": {
+			ticketPrice: 200e8,
+			address:     other,
+			now:         end,
+			fee:         4,
+			policy:      "small",
+		},
+		"discount": {
+			ticketPrice: 300e8,
+			address:     partner,
+			now:         during,
+			fee:         0.5,
+			policy:      "partners",
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			fee, policy := schedule.Fee(test.ticketPrice, test.address, test.now)
+			if fee != test.fee || policy != test.policy {
+				t.Fatalf("expected fee %v from policy %q, got %v from %q",
+					test.fee, test.policy, fee, policy)
+			}
+		})
+	}
+
+	if n := len(schedule.CurrentPromotions(during)); n != 1 {
+		t.Fatalf("expected 1 current promotion, got %d", n)
+	}
+	if n := len(schedule.CurrentPromotions(end)); n != 0 {
+		t.Fatalf("expected no current promotions, got %d", n)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	params := chaincfg.TestNet3Params()
+
+	tests := map[string]string{
+		"unknown field":        `{"tier": []}`,
+		"missing name":         `{"tiers": [{"minticketprice": 1, "feepercentage": 1}]}`,
+		"reserved name":        `{"tiers": [{"name": "default", "minticketprice": 1, "feepercentage": 1}]}`,
+		"duplicate name":       `{"tiers": [{"name": "a", "minticketprice": 1, "feepercentage": 1}], "discounts": [{"name": "a", "addresses": ["x"], "feepercentage": 1}]}`,
+		"invalid fee":          `{"tiers": [{"name": "a", "minticketprice": 1, "feepercentage": 0}]}`,
+		"zero ticket price":    `{"tiers": [{"name": "a", "minticketprice": 0, "feepercentage": 1}]}`,
+		"duplicate price":      `{"tiers": [{"name": "a", "minticketprice": 1, "feepercentage": 1}, {"name": "b", "minticketprice": 1, "feepercentage": 2}]}`,
+		"promotion no end":     `{"promotions": [{"name": "a", "start": "2026-11-01T00:00:00Z", "feepercentage": 1}]}`,
+		"promotion backwards":  `{"promotions": [{"name": "a", "start": "2026-11-01T00:00:00Z", "end": "2026-10-01T00:00:00Z", "feepercentage": 1}]}`,
+		"discount no address":  `{"discounts": [{"name": "a", "feepercentage": 1}]}`,
+		"discount bad address": `{"discounts": [{"name": "a", "addresses": ["notanaddress"], "feepercentage": 1}]}`,
+	}
+
+	for testName, file := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := parse(strings.NewReader(file), 3, params)
+			if err == nil {
+				t.Fatal("expected error parsing schedule")
+			}
+		})
+	}
+
+	_, err := Load("", 0, params)
+	if err == nil {
+		t.Fatal("expected error loading schedule with invalid default fee")
+	}
+}
@@ -0,0 +1,345 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package metrics implements a minimal set of counters and histograms which can
+// be written out in the Prometheus text exposition format.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ContentType is the HTTP content type of the Prometheus text exposition
+// format written by this package.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// defaultBuckets are the upper bounds, in seconds, of the histogram buckets
+// used to record durations.
+var defaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30}
+
+// Metrics holds all of the metrics which are recorded while vspd is running.
+// Values which are already tracked elsewhere (eg. ticket counts or RPC
+// connectivity) are not stored here, they should be written with WriteGauge at
+// the time metrics are requested.
+type Metrics struct {
+	// APIRequests counts requests handled by each API endpoint, labeled by
+	// the route path and the HTTP status of the response.
+	APIRequests *CounterVec
+	// APIRequestDuration records how long each API endpoint took to handle
+	// requests, labeled by the route path.
+	APIRequestDuration *HistogramVec
+	// APIErrors counts error responses sent by the API, labeled by error code.
+	APIErrors *CounterVec
+	// UpdateStepDuration records how long each step of the vspd update process
+	// took, labeled by the name of the step.
+	UpdateStepDuration *HistogramVec
+}
+
+// New creates a new set of empty metrics.
+func New() *Metrics {
+	return &Metrics{
+		APIRequests: newCounterVec("vspd_api_requests_total",
+			"Number of API requests handled.", "path", "status"),
+		APIRequestDuration: newHistogramVec("vspd_api_request_duration_seconds",
+			"Time taken to handle API requests.", "path"),
+		APIErrors: newCounterVec("vspd_api_errors_total",
+			"Number of API error responses sent.", "code"),
+		UpdateStepDuration: newHistogramVec("vspd_update_step_duration_seconds",
+			"Time taken by each step of the vspd update process.", "step"),
+	}
+}
+
+// Write writes all of the recorded metrics to w in the Prometheus text
+// exposition format.
+func (m *Metrics) Write(w io.Writer) error {
+	if err := m.APIRequests.write(w); err != nil {
+		return err
+	}
+	if err := m.APIRequestDuration.write(w); err != nil {
+		return err
+	}
+	if err := m.APIErrors.write(w); err != nil {
+		return err
+	}
+	return m.UpdateStepDuration.write(w)
+}
+
+// Label is a single name/value pair attached to a gauge written by WriteGauge.
+type Label struct {
+	Name  string
+	Value string
+}
+
+// WriteGauge writes a single gauge value to w in the Prometheus text exposition
+// format. It is intended for values which are calculated at the time metrics
+// are requested rather than being recorded by this package.
+func WriteGauge(w io.Writer, name, help string, value float64, labels ...Label) error {
+	if err := writeHeader(w, name, help, "gauge"); err != nil {
+		return err
+	}
+	names := make([]string, len(labels))
+	values := make([]string, len(labels))
+	for i, l := range labels {
+		names[i] = l.Name
+		values[i] = l.Value
+	}
+	_, err := fmt.Fprintf(w, "%s%s %s\n", name, formatLabels(names, values), formatValue(value))
+	return err
+}
+
+// WriteGaugeVec writes a gauge with one value per label set. Every key of
+// values must contain one label value for each of the provided label names.
+func WriteGaugeVec(w io.Writer, name, help string, labelNames []string, values map[string]float64) error {
+	if err := writeHeader(w, name, help, "gauge"); err != nil {
+		return err
+	}
+	for _, key := range sortedKeys(values) {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", name,
+			formatLabels(labelNames, splitKey(key)), formatValue(values[key]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LabelKey joins label values into a single key suitable for use with
+// WriteGaugeVec.
+func LabelKey(labelValues ...string) string {
+	return strings.Join(labelValues, "\xff")
+}
+
+// CounterVec is a set of counters which share a name and label names, but
+// which have different label values.
+type CounterVec struct {
+	name       string
+	help       string
+	labelNames []string
+
+	mtx    sync.Mutex
+	values map[string]float64
+}
+
+func newCounterVec(name, help string, labelNames ...string) *CounterVec {
+	return &CounterVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		values:     make(map[string]float64),
+	}
+}
+
+// Inc increments the counter identified by the provided label values. One
+// label value must be provided for each of the label names of the CounterVec.
+func (c *CounterVec) Inc(labelValues ...string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.values[LabelKey(labelValues...)]++
+}
+
+// Value returns the current value of the counter identified by the provided
+// label values.
+func (c *CounterVec) Value(labelValues ...string) float64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.values[LabelKey(labelValues...)]
+}
+
+func (c *CounterVec) write(w io.Writer) error {
+	if err := writeHeader(w, c.name, c.help, "counter"); err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, key := range sortedKeys(c.values) {
+		_, err := fmt.Fprintf(w, "%s%s %s\n", c.name,
+			formatLabels(c.labelNames, splitKey(key)), formatValue(c.values[key]))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// histogram holds the observations of a single histogram.
+type histogram struct {
+	// counts holds the number of observations in each bucket. Counts are not
+	// cumulative, they are summed when the histogram is written.
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// HistogramVec is a set of histograms which share a name, label names and
+// buckets, but which have different label values.
+type HistogramVec struct {
+	name       string
+	help       string
+	labelNames []string
+	buckets    []float64
+
+	mtx        sync.Mutex
+	histograms map[string]*histogram
+}
+
+func newHistogramVec(name, help string, labelNames ...string) *HistogramVec {
+	return &HistogramVec{
+		name:       name,
+		help:       help,
+		labelNames: labelNames,
+		buckets:    defaultBuckets,
+		histograms: make(map[string]*histogram),
+	}
+}
+
+// Observe adds a single observation to the histogram identified by the
+// provided label values. One label value must be provided for each of the
+// label names of the HistogramVec.
+func (h *HistogramVec) Observe(value float64, labelValues ...string) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	key := LabelKey(labelValues...)
+	hist, ok := h.histograms[key]
+	if !ok {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.histograms[key] = hist
+	}
+
+	for i, upperBound := range h.buckets {
+		if value <= upperBound {
+			hist.counts[i]++
+			break
+		}
+	}
+	hist.count++
+	hist.sum += value
+}
+
+// Count returns the number of observations recorded by the histogram
+// identified by the provided label values.
+func (h *HistogramVec) Count(labelValues ...string) uint64 {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	hist, ok := h.histograms[LabelKey(labelValues...)]
+	if !ok {
+		return 0
+	}
+	return hist.count
+}
+
+func (h *HistogramVec) write(w io.Writer) error {
+	if err := writeHeader(w, h.name, h.help, "histogram"); err != nil {
+		return err
+	}
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	bucketLabels := append(append([]string{}, h.labelNames...), "le")
+
+	for _, key := range sortedKeys(h.histograms) {
+		hist := h.histograms[key]
+		labelValues := splitKey(key)
+
+		var cumulative uint64
+		for i, upperBound := range h.buckets {
+			cumulative += hist.counts[i]
+			values := append(append([]string{}, labelValues...), formatValue(upperBound))
+			_, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name,
+				formatLabels(bucketLabels, values), cumulative)
+			if err != nil {
+				return err
+			}
+		}
+
+		values := append(append([]string{}, labelValues...), "+Inf")
+		_, err := fmt.Fprintf(w, "%s_bucket%s %d\n", h.name,
+			formatLabels(bucketLabels, values), hist.count)
+		if err != nil {
+			return err
+		}
+
+		labels := formatLabels(h.labelNames, labelValues)
+		_, err = fmt.Fprintf(w, "%s_sum%s %s\n%s_count%s %d\n",
+			h.name, labels, formatValue(hist.sum), h.name, labels, hist.count)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeHeader(w io.Writer, name, help, metricType string) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	return err
+}
+
+// formatLabels returns the label names and values formatted as a Prometheus
+// label set, eg. {path="/api",status="200"}. An empty string is returned if
+// there are no labels.
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, name := range names {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		var value string
+		if i < len(values) {
+			value = values[i]
+		}
+		b.WriteString(name)
+		b.WriteString(`="`)
+		b.WriteString(escapeLabelValue(value))
+		b.WriteByte('"')
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+func splitKey(key string) []string {
+	return strings.Split(key, "\xff")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,103 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCounterVec(t *testing.T) {
+	t.Parallel()
+
+	c := newCounterVec("test_total", "Test counter.", "path", "status")
+	c.Inc("/b", "200")
+	c.Inc("/a", "400")
+	c.Inc("/a", "400")
+
+	if v := c.Value("/a", "400"); v != 2 {
+		t.Fatalf("expected counter value 2, got %v", v)
+	}
+
+	var buf bytes.Buffer
+	err := c.write(&buf)
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	expected := `# HELP test_total Test counter.
+# TYPE test_total counter
+test_total{path="/a",status="400"} 2
+test_total{path="/b",status="200"} 1
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestHistogramVec(t *testing.T) {
+	t.Parallel()
+
+	h := newHistogramVec("test_seconds", "Test histogram.", "step")
+	h.buckets = []float64{0.1, 1}
+	h.Observe(0.05, "one")
+	h.Observe(0.5, "one")
+	h.Observe(5, "one")
+
+	if count := h.Count("one"); count != 3 {
+		t.Fatalf("expected 3 observations, got %d", count)
+	}
+
+	var buf bytes.Buffer
+	err := h.write(&buf)
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	expected := `# HELP test_seconds Test histogram.
+# TYPE test_seconds histogram
+test_seconds_bucket{step="one",le="0.1"} 1
+test_seconds_bucket{step="one",le="1"} 2
+test_seconds_bucket{step="one",le="+Inf"} 3
+test_seconds_sum{step="one"} 5.55
+test_seconds_count{step="one"} 3
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
+
+func TestWriteGauge(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	err := WriteGauge(&buf, "test_gauge", "Test gauge.", 7,
+		Label{Name: "host", Value: `wss://"quoted"\`})
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	expected := `test_gauge{host="wss://\"quoted\"\\"} 7`
+	if !strings.Contains(buf.String(), expected) {
+		t.Fatalf("output %q does not contain %q", buf.String(), expected)
+	}
+
+	buf.Reset()
+	err = WriteGaugeVec(&buf, "test_vec", "Test gauge vec.", []string{"host"},
+		map[string]float64{LabelKey("b"): 0, LabelKey("a"): 1})
+	if err != nil {
+		t.Fatalf("write error: %v", err)
+	}
+
+	expected = `# HELP test_vec Test gauge vec.
+# TYPE test_vec gauge
+test_vec{host="a"} 1
+test_vec{host="b"} 0
+`
+	if buf.String() != expected {
+		t.Fatalf("unexpected output:\n%s\nexpected:\n%s", buf.String(), expected)
+	}
+}
@@ -0,0 +1,421 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package openapi generates OpenAPI 3 documents describing the vspd API from
+// the Go types of its requests and responses, and validates JSON against the
+// schemas of generated documents.
+//
+// Schemas are derived from the json struct tags of each type, and from the
+// required, min and max rules of its binding tags. Structs with binding tags
+// are requests, so only the fields with the required rule are required.
+// Structs without binding tags are responses, which always include every field
+// which is not omitted when empty, so those fields are required. Neither
+// requests nor responses may contain fields which are not described by the
+// schema.
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification which generated
+// documents conform to.
+const Version = "3.0.3"
+
+// Document is the root of an OpenAPI document.
+type Document struct {
+	OpenAPI    string               `json:"openapi"`
+	Info       Info                 `json:"info"`
+	Paths      map[string]*PathItem `json:"paths"`
+	Components Components           `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Get  *Operation `json:"get,omitempty"`
+	Post *Operation `json:"post,omitempty"`
+	Put  *Operation `json:"put,omitempty"`
+}
+
+type Operation struct {
+	OperationID string               `json:"operationId"`
+	Summary     string               `json:"summary,omitempty"`
+	Parameters  []Parameter          `json:"parameters,omitempty"`
+	RequestBody *RequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*Response `json:"responses"`
+}
+
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type RequestBody struct {
+	Required bool                  `json:"required,omitempty"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+type Response struct {
+	Description string                `json:"description"`
+	Headers     map[string]*Header    `json:"headers,omitempty"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+type Header struct {
+	Description string  `json:"description,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas,omitempty"`
+}
+
+// Schema is the subset of the OpenAPI schema object needed to describe the
+// vspd API. AdditionalProperties is either false, or the *Schema of every
+// property which is not listed in Properties.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+}
+
+// JSON returns a response or request body content of JSON described by s.
+func JSON(s *Schema) map[string]*MediaType {
+	return map[string]*MediaType{"application/json": {Schema: s}}
+}
+
+// Generator creates schemas from Go types. Named struct types are added to
+// the components of the document and referenced by name, so every named
+// struct type described by a document must have a unique name.
+type Generator struct {
+	doc     *Document
+	types   map[string]reflect.Type
+	defined map[reflect.Type]*Schema
+}
+
+// NewGenerator returns a generator for a document with no paths.
+func NewGenerator(info Info) *Generator {
+	return &Generator{
+		doc: &Document{
+			OpenAPI:    Version,
+			Info:       info,
+			Paths:      make(map[string]*PathItem),
+			Components: Components{Schemas: make(map[string]*Schema)},
+		},
+		types:   make(map[string]reflect.Type),
+		defined: make(map[reflect.Type]*Schema),
+	}
+}
+
+// Define describes the named type of v with s rather than a schema derived
+// from the type. The schema is added to the components of the document when
+// the type is first described.
+func (g *Generator) Define(v any, s *Schema) error {
+	t := reflect.TypeOf(v)
+	if t.Name() == "" {
+		return fmt.Errorf("cannot define unnamed type %s", t)
+	}
+	g.defined[t] = s
+	return nil
+}
+
+// Schema returns a schema describing the JSON encoding of v.
+func (g *Generator) Schema(v any) (*Schema, error) {
+	return g.schema(reflect.TypeOf(v))
+}
+
+// AddOperation adds op to the document as the handler of method on path.
+func (g *Generator) AddOperation(method, path string, op *Operation) error {
+	item, ok := g.doc.Paths[path]
+	if !ok {
+		item = &PathItem{}
+		g.doc.Paths[path] = item
+	}
+
+	var existing **Operation
+	switch method {
+	case "GET":
+		existing = &item.Get
+	case "POST":
+		existing = &item.Post
+	case "PUT":
+		existing = &item.Put
+	default:
+		return fmt.Errorf("unsupported method %s", method)
+	}
+	if *existing != nil {
+		return fmt.Errorf("%s %s is described more than once", method, path)
+	}
+	*existing = op
+
+	return nil
+}
+
+// Document returns the generated document.
+func (g *Generator) Document() *Document {
+	return g.doc
+}
+
+var rawMessageType = reflect.TypeOf(json.RawMessage(nil))
+
+func (g *Generator) schema(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// Raw JSON may contain any value.
+	if t == rawMessageType {
+		return &Schema{}, nil
+	}
+
+	if _, ok := g.defined[t]; ok {
+		return g.componentSchema(t)
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8,
+		reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}, nil
+	case reflect.Int64, reflect.Uint, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}, nil
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}, nil
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Slice, reflect.Array:
+		// Byte slices are encoded as base64 strings.
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte", Nullable: t.Kind() == reflect.Slice}, nil
+		}
+		items, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items, Nullable: t.Kind() == reflect.Slice}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("map %s does not have string keys", t)
+		}
+		values, err := g.schema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values, Nullable: true}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		return g.componentSchema(t)
+	default:
+		return nil, fmt.Errorf("cannot describe %s values", t)
+	}
+}
+
+// componentSchema adds the schema of named struct type t to the components of
+// the document if it is not already there, and returns a reference to it.
+func (g *Generator) componentSchema(t reflect.Type) (*Schema, error) {
+	name := t.Name()
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+
+	if existing, ok := g.types[name]; ok {
+		if existing != t {
+			return nil, fmt.Errorf("types %s and %s have the same name", existing, t)
+		}
+		return ref, nil
+	}
+
+	// Register the type before describing its fields so recursive types
+	// reference themselves.
+	g.types[name] = t
+
+	s, ok := g.defined[t]
+	if !ok {
+		var err error
+		s, err = g.structSchema(t)
+		if err != nil {
+			return nil, err
+		}
+	}
+	g.doc.Components.Schemas[name] = s
+
+	return ref, nil
+}
+
+func (g *Generator) structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+
+	isRequest := false
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup("binding"); ok {
+			isRequest = true
+			break
+		}
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, ok := jsonName(f)
+		if !ok {
+			continue
+		}
+
+		// Fields of embedded structs without a json name are encoded as if
+		// they were fields of the outer struct.
+		if f.Anonymous && f.Tag.Get("json") == "" && f.Type.Kind() == reflect.Struct {
+			embedded, err := g.structSchema(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			for n, p := range embedded.Properties {
+				s.Properties[n] = p
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		prop, err := g.schema(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t, f.Name, err)
+		}
+
+		required, err := applyBinding(prop, f.Type, f.Tag.Get("binding"))
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %w", t, f.Name, err)
+		}
+		if (required || !isRequest) && !omitEmpty {
+			s.Required = append(s.Required, name)
+		}
+
+		s.Properties[name] = prop
+	}
+
+	return s, nil
+}
+
+// jsonName returns the name of the field in its JSON encoding, and whether it
+// is omitted when empty. ok is false if the field is not encoded.
+func jsonName(f reflect.StructField) (name string, omitEmpty bool, ok bool) {
+	tag := f.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = f.Name
+	}
+	for _, opt := range strings.Split(opts, ",") {
+		if opt == "omitempty" {
+			omitEmpty = true
+		}
+	}
+
+	return name, omitEmpty, true
+}
+
+// applyBinding adds the length limits of a binding tag to the schema of a
+// field of type t, and returns whether the tag requires the field.
+func applyBinding(s *Schema, t reflect.Type, tag string) (bool, error) {
+	if tag == "" {
+		return false, nil
+	}
+
+	var required bool
+	for _, rule := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = true
+		case "min", "max":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return false, fmt.Errorf("invalid binding rule %q", rule)
+			}
+			err = setLimit(s, t, key == "min", n)
+			if err != nil {
+				return false, err
+			}
+		case "dive":
+			// Rules after dive apply to the elements of a slice, which are
+			// described by their own schemas.
+			return required, nil
+		default:
+			return false, fmt.Errorf("unsupported binding rule %q", rule)
+		}
+	}
+
+	return required, nil
+}
+
+func setLimit(s *Schema, t reflect.Type, isMin bool, n int) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		if isMin {
+			s.MinLength = &n
+		} else {
+			s.MaxLength = &n
+		}
+	case reflect.Slice, reflect.Array:
+		if isMin {
+			s.MinItems = &n
+		} else {
+			s.MaxItems = &n
+		}
+	case reflect.Map:
+		if isMin {
+			return fmt.Errorf("unsupported min rule on map %s", t)
+		}
+		s.MaxProperties = &n
+	default:
+		return fmt.Errorf("unsupported length rule on %s", t)
+	}
+
+	return nil
+}
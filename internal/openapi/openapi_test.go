@@ -0,0 +1,177 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+type testInner struct {
+	Value float64 `json:"value"`
+}
+
+type testOuter struct {
+	Name     string            `json:"name" binding:"required,min=2,max=8"`
+	Count    int64             `json:"count,omitempty" binding:"required"`
+	Key      []byte            `json:"key"`
+	Inners   []testInner       `json:"inners" binding:"required,dive"`
+	Policy   map[string]string `json:"policy" binding:"max=3"`
+	Raw      json.RawMessage   `json:"raw"`
+	Ptr      *testInner        `json:"ptr,omitempty"`
+	Ignored  string            `json:"-"`
+	unexport string
+}
+
+func TestSchema(t *testing.T) {
+	g := NewGenerator(Info{Title: "test", Version: "1"})
+
+	ref, err := g.Schema(testOuter{})
+	if err != nil {
+		t.Fatalf("error generating schema: %v", err)
+	}
+	if ref.Ref != "#/components/schemas/testOuter" {
+		t.Fatalf("expected reference to testOuter, got %+v", ref)
+	}
+
+	two, eight, three := 2, 8, 3
+	innerRef := &Schema{Ref: "#/components/schemas/testInner"}
+	expected := map[string]*Schema{
+		"testOuter": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"name":   {Type: "string", MinLength: &two, MaxLength: &eight},
+				"count":  {Type: "integer", Format: "int64"},
+				"key":    {Type: "string", Format: "byte", Nullable: true},
+				"inners": {Type: "array", Items: innerRef, Nullable: true},
+				"policy": {
+					Type:                 "object",
+					AdditionalProperties: &Schema{Type: "string"},
+					Nullable:             true,
+					MaxProperties:        &three,
+				},
+				"raw": {},
+				"ptr": innerRef,
+			},
+			// testOuter has binding tags so only the fields with the required
+			// rule are required, and count is not required because it is
+			// omitted when empty.
+			Required:             []string{"name", "inners"},
+			AdditionalProperties: false,
+		},
+		"testInner": {
+			Type: "object",
+			Properties: map[string]*Schema{
+				"value": {Type: "number", Format: "double"},
+			},
+			// testInner has no binding tags so all of its fields are required.
+			Required:             []string{"value"},
+			AdditionalProperties: false,
+		},
+	}
+
+	schemas := g.Document().Components.Schemas
+	if !reflect.DeepEqual(schemas, expected) {
+		got, _ := json.MarshalIndent(schemas, "", "  ")
+		t.Fatalf("unexpected schemas:\n%s", got)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	g := NewGenerator(Info{})
+	s, err := g.Schema(testOuter{})
+	if err != nil {
+		t.Fatalf("error generating schema: %v", err)
+	}
+	doc := g.Document()
+
+	valid := []string{
+		`{"name":"ab","inners":[]}`,
+		`{"name":"abcdefgh","count":1,"key":"AQI=","inners":[{"value":1.5}],` +
+			`"policy":{"a":"b"},"raw":[1,"x"],"ptr":{"value":2}}`,
+		`{"name":"ab","inners":null,"key":null,"policy":null}`,
+	}
+	for _, data := range valid {
+		err := doc.Validate(s, []byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error validating %s: %v", data, err)
+		}
+	}
+
+	invalid := map[string]string{
+		"unknown property":          `{"name":"ab","inners":[],"other":1}`,
+		"missing required property": `{"name":"ab"}`,
+		"missing nested property":   `{"name":"ab","inners":[{}]}`,
+		"wrong type":                `{"name":"ab","inners":[],"count":"1"}`,
+		"non-integer":               `{"name":"ab","inners":[],"count":1.5}`,
+		"too short":                 `{"name":"a","inners":[]}`,
+		"too long":                  `{"name":"abcdefghi","inners":[]}`,
+		"too many properties":       `{"name":"ab","inners":[],"policy":{"a":"","b":"","c":"","d":""}}`,
+		"invalid base64":            `{"name":"ab","inners":[],"key":"!"}`,
+		"null string":               `{"name":null,"inners":[]}`,
+		"invalid json":              `{"name":`,
+	}
+	for testName, data := range invalid {
+		t.Run(testName, func(t *testing.T) {
+			err := doc.Validate(s, []byte(data))
+			if err == nil {
+				t.Fatalf("expected error validating %s", data)
+			}
+		})
+	}
+
+	enum := &Schema{Type: "integer", Enum: []any{1, 2}}
+	if err := doc.Validate(enum, []byte("2")); err != nil {
+		t.Fatalf("unexpected error validating enum: %v", err)
+	}
+	if err := doc.Validate(enum, []byte("3")); err == nil {
+		t.Fatal("expected error validating value not in enum")
+	}
+}
+
+func TestSchemaErrors(t *testing.T) {
+	tests := map[string]any{
+		"non-string map keys": struct {
+			M map[int]string `json:"m"`
+		}{},
+		"unsupported kind": struct {
+			C chan int `json:"c"`
+		}{},
+		"unsupported binding": struct {
+			S string `json:"s" binding:"email"`
+		}{},
+	}
+
+	for testName, v := range tests {
+		t.Run(testName, func(t *testing.T) {
+			_, err := NewGenerator(Info{}).Schema(v)
+			if err == nil {
+				t.Fatal("expected error generating schema")
+			}
+		})
+	}
+}
+
+func TestAddOperation(t *testing.T) {
+	g := NewGenerator(Info{})
+
+	err := g.AddOperation("POST", "/path", &Operation{OperationID: "a"})
+	if err != nil {
+		t.Fatalf("error adding operation: %v", err)
+	}
+	err = g.AddOperation("GET", "/path", &Operation{OperationID: "b"})
+	if err != nil {
+		t.Fatalf("error adding operation: %v", err)
+	}
+	err = g.AddOperation("POST", "/path", &Operation{OperationID: "c"})
+	if err == nil {
+		t.Fatal("expected error adding duplicate operation")
+	}
+	err = g.AddOperation("DELETE", "/other", &Operation{OperationID: "d"})
+	if err == nil {
+		t.Fatal("expected error adding operation with unsupported method")
+	}
+}
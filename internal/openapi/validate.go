@@ -0,0 +1,175 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const componentPrefix = "#/components/schemas/"
+
+// Validate returns an error if data is not the JSON encoding of a value
+// described by s. References in s are resolved using the components of d.
+func (d *Document) Validate(s *Schema, data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	err := dec.Decode(&v)
+	if err != nil {
+		return err
+	}
+
+	return d.validate(s, v, "$")
+}
+
+func (d *Document) validate(s *Schema, v any, path string) error {
+	if s.Ref != "" {
+		ref, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, componentPrefix)]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema %s", path, s.Ref)
+		}
+		return d.validate(ref, v, path)
+	}
+
+	for _, sub := range s.AllOf {
+		err := d.validate(sub, v, path)
+		if err != nil {
+			return err
+		}
+	}
+
+	if v == nil {
+		if s.Type == "" || s.Nullable {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", path)
+	}
+
+	if len(s.Enum) > 0 && !inEnum(s.Enum, v) {
+		return fmt.Errorf("%s: %v is not one of %v", path, v, s.Enum)
+	}
+
+	switch s.Type {
+	case "":
+		return nil
+	case "object":
+		return d.validateObject(s, v, path)
+	case "array":
+		a, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: expected array, got %T", path, v)
+		}
+		if s.MinItems != nil && len(a) < *s.MinItems {
+			return fmt.Errorf("%s: fewer than %d items", path, *s.MinItems)
+		}
+		if s.MaxItems != nil && len(a) > *s.MaxItems {
+			return fmt.Errorf("%s: more than %d items", path, *s.MaxItems)
+		}
+		for i, item := range a {
+			err := d.validate(s.Items, item, fmt.Sprintf("%s[%d]", path, i))
+			if err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected string, got %T", path, v)
+		}
+		if s.Format == "byte" {
+			_, err := base64.StdEncoding.DecodeString(str)
+			if err != nil {
+				return fmt.Errorf("%s: invalid base64: %w", path, err)
+			}
+		}
+		length := utf8.RuneCountInString(str)
+		if s.MinLength != nil && length < *s.MinLength {
+			return fmt.Errorf("%s: shorter than %d characters", path, *s.MinLength)
+		}
+		if s.MaxLength != nil && length > *s.MaxLength {
+			return fmt.Errorf("%s: longer than %d characters", path, *s.MaxLength)
+		}
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: expected integer, got %T", path, v)
+		}
+		_, err := n.Int64()
+		if err != nil {
+			return fmt.Errorf("%s: expected integer, got %s", path, n)
+		}
+	case "number":
+		if _, ok := v.(json.Number); !ok {
+			return fmt.Errorf("%s: expected number, got %T", path, v)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: expected boolean, got %T", path, v)
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, s.Type)
+	}
+
+	return nil
+}
+
+func (d *Document) validateObject(s *Schema, v any, path string) error {
+	m, ok := v.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: expected object, got %T", path, v)
+	}
+
+	for _, name := range s.Required {
+		if _, ok := m[name]; !ok {
+			return fmt.Errorf("%s: missing required property %q", path, name)
+		}
+	}
+
+	if s.MaxProperties != nil && len(m) > *s.MaxProperties {
+		return fmt.Errorf("%s: more than %d properties", path, *s.MaxProperties)
+	}
+
+	for name, value := range m {
+		propPath := path + "." + name
+		if prop, ok := s.Properties[name]; ok {
+			err := d.validate(prop, value, propPath)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch additional := s.AdditionalProperties.(type) {
+		case *Schema:
+			err := d.validate(additional, value, propPath)
+			if err != nil {
+				return err
+			}
+		case bool:
+			if !additional {
+				return fmt.Errorf("%s: unexpected property", propPath)
+			}
+		}
+	}
+
+	return nil
+}
+
+// inEnum returns true if v, decoded from JSON, is equal to one of the values
+// of enum.
+func inEnum(enum []any, v any) bool {
+	for _, e := range enum {
+		if fmt.Sprint(e) == fmt.Sprint(v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package reconcile compares the fees which vspd expects to have been paid by
+// its tickets against the fee payments which are visible on-chain.
+package reconcile
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	dcrdtypes "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/rpc"
+	"github.com/jrick/wsrpc/v2"
+)
+
+// TxSource retrieves transactions from a dcrd instance with the transaction
+// index enabled. It is implemented by *rpc.DcrdRPC.
+type TxSource interface {
+	GetRawTransaction(txHash string) (*dcrdtypes.TxRawResult, error)
+}
+
+// Period is the fee revenue of a single fee xpub in a single calendar month.
+// Fees are assigned to the month, in UTC, of the block which mined their fee
+// tx.
+type Period struct {
+	XPubID   uint32 `json:"xpubid"`
+	Month    string `json:"month"`
+	Tickets  int    `json:"tickets"`
+	Expected int64  `json:"expected"`
+	Received int64  `json:"received"`
+}
+
+// Payment is the fee paid by a single ticket. Received is the total of every
+// output of the fee tx which pays to the fee address of the ticket. Error is
+// only set if the fee tx could not be checked.
+type Payment struct {
+	TicketHash string `json:"tickethash"`
+	XPubID     uint32 `json:"xpubid"`
+	FeeAddress string `json:"feeaddress"`
+	FeeTxHash  string `json:"feetxhash"`
+	Expected   int64  `json:"expected"`
+	Received   int64  `json:"received"`
+	Archived   bool   `json:"archived"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is the result of reconciling fees. Amounts are in atoms.
+type Report struct {
+	// Expected and Received are the total fees of every mined fee tx.
+	Expected int64 `json:"expected"`
+	Received int64 `json:"received"`
+	// Periods are sorted by xpub ID and then by month.
+	Periods []Period `json:"periods"`
+	// Underpaid and Overpaid list mined fee txs which did not pay exactly the
+	// fee expected.
+	Underpaid []Payment `json:"underpaid"`
+	Overpaid  []Payment `json:"overpaid"`
+	// Unconfirmed lists fee txs which are not mined yet.
+	Unconfirmed []Payment `json:"unconfirmed"`
+	// Missing lists fee txs which are unknown to dcrd.
+	Missing []Payment `json:"missing"`
+	// Failed lists fee txs which could not be checked, eg. because of an
+	// error from dcrd. They are not included in any totals.
+	Failed []Payment `json:"failed"`
+}
+
+// Fees reconciles the fee expected from every confirmed ticket with a
+// broadcast fee tx, including archived tickets, against the outputs of its fee
+// tx according to dcrd. A fee tx is requested from dcrd for every ticket, so
+// this can take a long time. Fee txs which cannot be checked are listed in the
+// Failed section of the report rather than failing the whole report. An error
+// is only returned if the fee payments cannot be read from the database or if
+// ctx is canceled.
+func Fees(ctx context.Context, db database.Database, dcrd TxSource,
+	params *chaincfg.Params) (Report, error) {
+
+	payments, err := db.FeePayments()
+	if err != nil {
+		return Report{}, fmt.Errorf("db.FeePayments error: %w", err)
+	}
+
+	return reconcile(ctx, payments, dcrd, params)
+}
+
+// periodKey identifies a Period.
+type periodKey struct {
+	xpubID uint32
+	month  string
+}
+
+func reconcile(ctx context.Context, payments []database.FeePayment, dcrd TxSource,
+	params *chaincfg.Params) (Report, error) {
+
+	report := Report{
+		Periods:     []Period{},
+		Underpaid:   []Payment{},
+		Overpaid:    []Payment{},
+		Unconfirmed: []Payment{},
+		Missing:     []Payment{},
+		Failed:      []Payment{},
+	}
+	periods := make(map[periodKey]*Period)
+
+	for _, fp := range payments {
+		// Exit early if context has been canceled.
+		if ctx.Err() != nil {
+			return Report{}, ctx.Err()
+		}
+
+		payment := Payment{
+			TicketHash: fp.TicketHash,
+			XPubID:     fp.XPubID,
+			FeeAddress: fp.FeeAddress,
+			FeeTxHash:  fp.FeeTxHash,
+			Expected:   fp.FeeAmount,
+			Archived:   fp.Archived,
+		}
+
+		txResult, err := dcrd.GetRawTransaction(fp.FeeTxHash)
+		if err != nil {
+			// ErrNoTxInfo indicates dcrd has never seen the fee tx, or that it
+			// was removed from the mempool without being mined.
+			var e *wsrpc.Error
+			if errors.As(err, &e) && e.Code == rpc.ErrNoTxInfo {
+				report.Missing = append(report.Missing, payment)
+				continue
+			}
+			payment.Error = fmt.Sprintf("dcrd.GetRawTransaction error: %v", err)
+			report.Failed = append(report.Failed, payment)
+			continue
+		}
+
+		payment.Received, err = paidTo(txResult.Hex, fp.FeeAddress, params)
+		if err != nil {
+			payment.Error = err.Error()
+			report.Failed = append(report.Failed, payment)
+			continue
+		}
+
+		if txResult.Confirmations == 0 {
+			report.Unconfirmed = append(report.Unconfirmed, payment)
+			continue
+		}
+
+		report.Expected += payment.Expected
+		report.Received += payment.Received
+
+		key := periodKey{
+			xpubID: fp.XPubID,
+			month:  time.Unix(txResult.Blocktime, 0).UTC().Format("2006-01"),
+		}
+		period, ok := periods[key]
+		if !ok {
+			period = &Period{XPubID: key.xpubID, Month: key.month}
+			periods[key] = period
+		}
+		period.Tickets++
+		period.Expected += payment.Expected
+		period.Received += payment.Received
+
+		switch {
+		case payment.Received < payment.Expected:
+			report.Underpaid = append(report.Underpaid, payment)
+		case payment.Received > payment.Expected:
+			report.Overpaid = append(report.Overpaid, payment)
+		}
+	}
+
+	for _, period := range periods {
+		report.Periods = append(report.Periods, *period)
+	}
+	slices.SortFunc(report.Periods, func(a, b Period) int {
+		return cmp.Or(cmp.Compare(a.XPubID, b.XPubID), cmp.Compare(a.Month, b.Month))
+	})
+
+	return report, nil
+}
+
+// paidTo returns the total value of the outputs of the serialized transaction
+// txHex which pay to the provided address.
+func paidTo(txHex, address string, params *chaincfg.Params) (int64, error) {
+	addr, err := stdaddr.DecodeAddress(address, params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode fee address: %w", err)
+	}
+	wantScriptVer, wantScript := addr.PaymentScript()
+
+	txBytes, err := hex.DecodeString(txHex)
+	if err != nil {
+		return 0, fmt.Errorf("failed to decode fee tx hex: %w", err)
+	}
+	var tx wire.MsgTx
+	err = tx.Deserialize(bytes.NewReader(txBytes))
+	if err != nil {
+		return 0, fmt.Errorf("failed to deserialize fee tx: %w", err)
+	}
+
+	var paid int64
+	for _, txOut := range tx.TxOut {
+		if txOut.Version == wantScriptVer && bytes.Equal(txOut.PkScript, wantScript) {
+			paid += txOut.Value
+		}
+	}
+
+	return paid, nil
+}
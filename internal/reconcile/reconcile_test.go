@@ -0,0 +1,183 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package reconcile
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	dcrdtypes "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/rpc"
+	"github.com/jrick/wsrpc/v2"
+)
+
+// fakeDcrd returns the transactions it contains, and ErrNoTxInfo for any
+// other transaction.
+type fakeDcrd map[string]*dcrdtypes.TxRawResult
+
+func (f fakeDcrd) GetRawTransaction(txHash string) (*dcrdtypes.TxRawResult, error) {
+	tx, ok := f[txHash]
+	if !ok {
+		return nil, &wsrpc.Error{Code: rpc.ErrNoTxInfo, Message: "No information available about transaction"}
+	}
+	return tx, nil
+}
+
+func TestReconcile(t *testing.T) {
+	params := chaincfg.TestNet3Params()
+
+	// address returns a distinct P2PKH address for each value of b.
+	address := func(b byte) stdaddr.Address {
+		t.Helper()
+		addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(bytes.Repeat([]byte{b}, 20), params)
+		if err != nil {
+			t.Fatalf("error creating address: %v", err)
+		}
+		return addr
+	}
+
+	// feeTx returns a transaction with an output of each of the provided
+	// values paying to addr, followed by an output paying elsewhere.
+	feeTx := func(addr stdaddr.Address, confirmations int64, blockTime time.Time, values ...int64) *dcrdtypes.TxRawResult {
+		t.Helper()
+		tx := wire.NewMsgTx()
+		tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{}, 0, wire.TxTreeRegular), 0, nil))
+		scriptVer, script := addr.PaymentScript()
+		for _, value := range values {
+			tx.AddTxOut(&wire.TxOut{Value: value, Version: scriptVer, PkScript: script})
+		}
+		scriptVer, script = address(0xff).PaymentScript()
+		tx.AddTxOut(&wire.TxOut{Value: 1e8, Version: scriptVer, PkScript: script})
+
+		var buf bytes.Buffer
+		if err := tx.Serialize(&buf); err != nil {
+			t.Fatalf("error serializing tx: %v", err)
+		}
+
+		result := &dcrdtypes.TxRawResult{
+			Hex:           hex.EncodeToString(buf.Bytes()),
+			Txid:          tx.TxHash().String(),
+			Confirmations: confirmations,
+		}
+		if confirmations > 0 {
+			result.Blocktime = blockTime.Unix()
+		}
+		return result
+	}
+
+	march := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	april := time.Date(2026, 4, 1, 0, 30, 0, 0, time.UTC)
+
+	exact := feeTx(address(1), 6, march, 1000)
+	split := feeTx(address(2), 6, march, 600, 600)
+	under := feeTx(address(3), 6, april, 900)
+	otherXPub := feeTx(address(4), 6, april, 1000)
+	unconfirmed := feeTx(address(5), 0, time.Time{}, 1000)
+	dcrd := fakeDcrd{}
+	for _, tx := range []*dcrdtypes.TxRawResult{exact, split, under, otherXPub, unconfirmed} {
+		dcrd[tx.Txid] = tx
+	}
+
+	payment := func(hash string, xpubID uint32, addr byte, txHash string) database.FeePayment {
+		return database.FeePayment{
+			TicketHash:  hash,
+			XPubID:      xpubID,
+			FeeAddress:  address(addr).String(),
+			FeeAmount:   1000,
+			FeeTxHash:   txHash,
+			FeeTxStatus: database.FeeConfirmed,
+		}
+	}
+	payments := []database.FeePayment{
+		payment("exact", 0, 1, exact.Txid),
+		payment("split", 0, 2, split.Txid),
+		payment("under", 0, 3, under.Txid),
+		payment("otherxpub", 1, 4, otherXPub.Txid),
+		payment("unconfirmed", 1, 5, unconfirmed.Txid),
+		payment("missing", 1, 6, "missing"),
+	}
+	payments[1].Archived = true
+
+	ctx := context.Background()
+	report, err := reconcile(ctx, payments, dcrd, params)
+	if err != nil {
+		t.Fatalf("error reconciling fees: %v", err)
+	}
+
+	result := func(fp database.FeePayment, received int64) Payment {
+		return Payment{
+			TicketHash: fp.TicketHash,
+			XPubID:     fp.XPubID,
+			FeeAddress: fp.FeeAddress,
+			FeeTxHash:  fp.FeeTxHash,
+			Expected:   fp.FeeAmount,
+			Received:   received,
+			Archived:   fp.Archived,
+		}
+	}
+	expected := Report{
+		Expected: 4000,
+		Received: 4100,
+		Periods: []Period{
+			{XPubID: 0, Month: "2026-03", Tickets: 2, Expected: 2000, Received: 2200},
+			{XPubID: 0, Month: "2026-04", Tickets: 1, Expected: 1000, Received: 900},
+			{XPubID: 1, Month: "2026-04", Tickets: 1, Expected: 1000, Received: 1000},
+		},
+		Underpaid:   []Payment{result(payments[2], 900)},
+		Overpaid:    []Payment{result(payments[1], 1200)},
+		Unconfirmed: []Payment{result(payments[4], 1000)},
+		Missing:     []Payment{result(payments[5], 0)},
+		Failed:      []Payment{},
+	}
+	if !reflect.DeepEqual(expected, report) {
+		t.Fatalf("expected report\n%+v\ngot\n%+v", expected, report)
+	}
+
+	// Errors other than ErrNoTxInfo are recorded for each payment without
+	// failing the report.
+	report, err = reconcile(ctx, payments, failingDcrd{}, params)
+	if err != nil {
+		t.Fatalf("error reconciling fees: %v", err)
+	}
+	if len(report.Failed) != len(payments) {
+		t.Fatalf("expected %d failed payments, got %d", len(payments), len(report.Failed))
+	}
+	for _, p := range report.Failed {
+		if !strings.Contains(p.Error, errDcrd.Error()) {
+			t.Fatalf("expected dcrd error for ticket %s, got %q", p.TicketHash, p.Error)
+		}
+	}
+	if report.Expected != 0 || len(report.Periods) != 0 {
+		t.Fatalf("expected failed payments to be excluded from totals, got %+v", report)
+	}
+
+	// Reconciliation stops when the context is canceled.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = reconcile(canceled, payments, dcrd, params)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context canceled error, got %v", err)
+	}
+}
+
+var errDcrd = errors.New("connection lost")
+
+// failingDcrd returns errDcrd for every transaction.
+type failingDcrd struct{}
+
+func (failingDcrd) GetRawTransaction(string) (*dcrdtypes.TxRawResult, error) {
+	return nil, errDcrd
+}
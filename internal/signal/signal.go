@@ -1,5 +1,5 @@
 // Copyright (c) 2013-2014 The btcsuite developers
-// Copyright (c) 2021-2023 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -17,6 +17,10 @@ import (
 // shutdown. This may be modified during init depending on the platform.
 var interruptSignals = []os.Signal{os.Interrupt}
 
+// reloadSignals defines the signals to catch in order to reload the config.
+// This is populated during init depending on the platform.
+var reloadSignals []os.Signal
+
 // ShutdownListener listens for OS Signals such as SIGINT (Ctrl+C) and shutdown
 // requests from requestShutdown. It returns a context that is canceled when
 // either signal is received.
@@ -41,3 +45,34 @@ func ShutdownListener(log slog.Logger) context.Context {
 	}()
 	return ctx
 }
+
+// ReloadListener listens for OS Signals such as SIGHUP which request the config
+// to be reloaded. A value is sent on the returned channel for each request,
+// although requests received while a previous request is still waiting to be
+// read are dropped. Listening stops when ctx is canceled.
+func ReloadListener(ctx context.Context, log slog.Logger) <-chan struct{} {
+	reload := make(chan struct{}, 1)
+	if len(reloadSignals) == 0 {
+		return reload
+	}
+
+	go func() {
+		reloadChannel := make(chan os.Signal, 1)
+		signal.Notify(reloadChannel, reloadSignals...)
+		defer signal.Stop(reloadChannel)
+
+		for {
+			select {
+			case sig := <-reloadChannel:
+				log.Infof("Received signal (%s). Reloading config...", sig)
+				select {
+				case reload <- struct{}{}:
+				default:
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return reload
+}
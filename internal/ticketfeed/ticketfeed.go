@@ -0,0 +1,129 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package ticketfeed notifies subscribers within vspd when the state of a
+// ticket changes, so that the new state can be pushed to API clients as soon
+// as it is written to the database.
+package ticketfeed
+
+import (
+	"slices"
+	"sync"
+)
+
+// Feed delivers the hashes of tickets which have changed to the subscriptions
+// watching them. It is safe for concurrent use.
+type Feed struct {
+	mtx  sync.Mutex
+	subs map[string]map[*Subscription]struct{}
+}
+
+func New() *Feed {
+	return &Feed{
+		subs: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Subscribe returns a subscription to changes of the tickets with the provided
+// hashes. The subscription must be closed when it is no longer needed.
+func (f *Feed) Subscribe(ticketHashes []string) *Subscription {
+	s := &Subscription{
+		feed:    f,
+		tickets: slices.Clone(ticketHashes),
+		pending: make(map[string]struct{}),
+		ready:   make(chan struct{}, 1),
+	}
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for _, hash := range ticketHashes {
+		subs, ok := f.subs[hash]
+		if !ok {
+			subs = make(map[*Subscription]struct{})
+			f.subs[hash] = subs
+		}
+		subs[s] = struct{}{}
+	}
+
+	return s
+}
+
+// Publish notifies every subscription watching ticketHash that the state of
+// the ticket has changed. It never blocks, so slow subscribers cannot delay the
+// caller.
+func (f *Feed) Publish(ticketHash string) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for s := range f.subs[ticketHash] {
+		s.add(ticketHash)
+	}
+}
+
+// Subscription collects the hashes of changed tickets until they are read with
+// Changed. Repeated changes of a ticket which has not been read yet are only
+// reported once.
+type Subscription struct {
+	feed    *Feed
+	tickets []string
+
+	mtx     sync.Mutex
+	changed []string
+	pending map[string]struct{}
+
+	// ready receives a value when changed is no longer empty.
+	ready chan struct{}
+}
+
+func (s *Subscription) add(ticketHash string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if _, ok := s.pending[ticketHash]; ok {
+		return
+	}
+	s.pending[ticketHash] = struct{}{}
+	s.changed = append(s.changed, ticketHash)
+
+	select {
+	case s.ready <- struct{}{}:
+	default:
+	}
+}
+
+// Ready returns a channel which receives a value when tickets have changed
+// since Changed was last called.
+func (s *Subscription) Ready() <-chan struct{} {
+	return s.ready
+}
+
+// Changed returns the hashes of the tickets which have changed since it was
+// last called, in the order they first changed.
+func (s *Subscription) Changed() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	changed := s.changed
+	s.changed = nil
+	clear(s.pending)
+
+	return changed
+}
+
+// Close stops the delivery of changes to the subscription.
+func (s *Subscription) Close() {
+	f := s.feed
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	for _, hash := range s.tickets {
+		subs := f.subs[hash]
+		delete(subs, s)
+		if len(subs) == 0 {
+			delete(f.subs, hash)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketfeed
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFeed(t *testing.T) {
+	f := New()
+
+	a := f.Subscribe([]string{"one", "two"})
+	b := f.Subscribe([]string{"two"})
+
+	// Nothing is ready before a change is published.
+	select {
+	case <-a.Ready():
+		t.Fatal("unexpected change before publish")
+	default:
+	}
+
+	f.Publish("two")
+	f.Publish("three")
+	f.Publish("one")
+	f.Publish("two")
+
+	<-a.Ready()
+	if changed := a.Changed(); !reflect.DeepEqual(changed, []string{"two", "one"}) {
+		t.Fatalf("unexpected changes %v", changed)
+	}
+	<-b.Ready()
+	if changed := b.Changed(); !reflect.DeepEqual(changed, []string{"two"}) {
+		t.Fatalf("unexpected changes %v", changed)
+	}
+
+	// Changes are only reported once.
+	if changed := a.Changed(); changed != nil {
+		t.Fatalf("unexpected changes %v", changed)
+	}
+
+	// Closed subscriptions receive no more changes, and are removed from the
+	// feed.
+	a.Close()
+	f.Publish("one")
+	f.Publish("two")
+	if changed := a.Changed(); changed != nil {
+		t.Fatalf("unexpected changes after close %v", changed)
+	}
+	if changed := b.Changed(); !reflect.DeepEqual(changed, []string{"two"}) {
+		t.Fatalf("unexpected changes %v", changed)
+	}
+
+	b.Close()
+	if len(f.subs) != 0 {
+		t.Fatalf("expected no subscriptions, got %d tickets", len(f.subs))
+	}
+}
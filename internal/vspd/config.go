@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -7,49 +7,71 @@ package vspd
 import (
 	"errors"
 	"fmt"
-	"math"
 	"net"
+	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
+	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/backup"
 	"github.com/decred/vspd/internal/config"
+	"github.com/decred/vspd/internal/feeschedule"
 	"github.com/decred/vspd/internal/version"
 	flags "github.com/jessevdk/go-flags"
 )
 
 const (
-	configFilename = "vspd.conf"
-	dbFilename     = "vspd.db"
+	configFilename   = "vspd.conf"
+	dbFilename       = "vspd.db"
+	sqliteDBFilename = "vspd.sqlite"
 )
 
 // Config defines the configuration options for the vspd process.
 type Config struct {
-	Listen          string        `long:"listen" ini-name:"listen" description:"The ip:port to listen for API requests."`
-	LogLevel        string        `long:"loglevel" ini-name:"loglevel" description:"Logging level." choice:"trace" choice:"debug" choice:"info" choice:"warn" choice:"error" choice:"critical"`
-	MaxLogSize      int64         `long:"maxlogsize" ini-name:"maxlogsize" description:"File size threshold for log file rotation (MB)."`
-	LogsToKeep      int           `long:"logstokeep" ini-name:"logstokeep" description:"The number of rotated log files to keep."`
-	NetworkName     string        `long:"network" ini-name:"network" description:"Decred network to use." choice:"testnet" choice:"mainnet" choice:"simnet"`
-	VSPFee          float64       `long:"vspfee" ini-name:"vspfee" description:"Fee percentage charged for VSP use. eg. 2.0 (2%), 0.5 (0.5%)."`
-	DcrdHost        string        `long:"dcrdhost" ini-name:"dcrdhost" description:"The ip:port to establish a JSON-RPC connection with dcrd. Should be the same host where vspd is running."`
-	DcrdUser        string        `long:"dcrduser" ini-name:"dcrduser" description:"Username for dcrd RPC connections."`
-	DcrdPass        string        `long:"dcrdpass" ini-name:"dcrdpass" description:"Password for dcrd RPC connections."`
-	DcrdCert        string        `long:"dcrdcert" ini-name:"dcrdcert" description:"The dcrd RPC certificate file."`
-	WalletHosts     string        `long:"wallethost" ini-name:"wallethost" description:"Comma separated list of ip:port to establish JSON-RPC connections with voting dcrwallet."`
-	WalletUsers     string        `long:"walletuser" ini-name:"walletuser" description:"Comma separated list of username for dcrwallet RPC connections."`
-	WalletPasswords string        `long:"walletpass" ini-name:"walletpass" description:"Comma separated list of password for dcrwallet RPC connections."`
-	WalletCerts     string        `long:"walletcert" ini-name:"walletcert" description:"Comma separated list of dcrwallet RPC certificate files."`
-	WebServerDebug  bool          `long:"webserverdebug" ini-name:"webserverdebug" description:"Enable web server debug mode (verbose logging to terminal and live-reloading templates)."`
-	SupportEmail    string        `long:"supportemail" ini-name:"supportemail" description:"Email address for users in need of support."`
-	BackupInterval  time.Duration `long:"backupinterval" ini-name:"backupinterval" description:"Time period between automatic database backups. Valid time units are {s,m,h}. Minimum 30 seconds."`
-	VspClosed       bool          `long:"vspclosed" ini-name:"vspclosed" description:"Closed prevents the VSP from accepting new tickets."`
-	VspClosedMsg    string        `long:"vspclosedmsg" ini-name:"vspclosedmsg" description:"A short message displayed on the webpage and returned by the status API endpoint if vspclosed is true."`
-	AdminPass       string        `long:"adminpass" ini-name:"adminpass" description:"Password for accessing admin page."`
-	Designation     string        `long:"designation" ini-name:"designation" description:"Short name for the VSP. Customizes the logo in the top toolbar."`
+	Listen             string        `long:"listen" ini-name:"listen" description:"The ip:port to listen for API requests."`
+	LogLevel           string        `long:"loglevel" ini-name:"loglevel" description:"Logging level." choice:"trace" choice:"debug" choice:"info" choice:"warn" choice:"error" choice:"critical"`
+	MaxLogSize         int64         `long:"maxlogsize" ini-name:"maxlogsize" description:"File size threshold for log file rotation (MB)."`
+	LogsToKeep         int           `long:"logstokeep" ini-name:"logstokeep" description:"The number of rotated log files to keep."`
+	NetworkName        string        `long:"network" ini-name:"network" description:"Decred network to use." choice:"testnet" choice:"mainnet" choice:"simnet"`
+	VSPFee             float64       `long:"vspfee" ini-name:"vspfee" description:"Fee percentage charged for VSP use. eg. 2.0 (2%), 0.5 (0.5%)."`
+	FeeSchedule        string        `long:"feeschedule" ini-name:"feeschedule" description:"Path to a JSON file describing fee tiers by ticket price, promotional rates and discounted rates for particular commitment addresses. Tickets not priced by the schedule are charged vspfee."`
+	DcrdHost           string        `long:"dcrdhost" ini-name:"dcrdhost" description:"The ip:port to establish a JSON-RPC connection with dcrd. Should be the same host where vspd is running."`
+	DcrdUser           string        `long:"dcrduser" ini-name:"dcrduser" description:"Username for dcrd RPC connections."`
+	DcrdPass           string        `long:"dcrdpass" ini-name:"dcrdpass" description:"Password for dcrd RPC connections."`
+	DcrdCert           string        `long:"dcrdcert" ini-name:"dcrdcert" description:"The dcrd RPC certificate file."`
+	WalletHosts        string        `long:"wallethost" ini-name:"wallethost" description:"Comma separated list of ip:port to establish JSON-RPC connections with voting dcrwallet."`
+	WalletUsers        string        `long:"walletuser" ini-name:"walletuser" description:"Comma separated list of username for dcrwallet RPC connections."`
+	WalletPasswords    string        `long:"walletpass" ini-name:"walletpass" description:"Comma separated list of password for dcrwallet RPC connections."`
+	WalletCerts        string        `long:"walletcert" ini-name:"walletcert" description:"Comma separated list of dcrwallet RPC certificate files."`
+	WebServerDebug     bool          `long:"webserverdebug" ini-name:"webserverdebug" description:"Enable web server debug mode (verbose logging to terminal and live-reloading templates)."`
+	SupportEmail       string        `long:"supportemail" ini-name:"supportemail" description:"Email address for users in need of support."`
+	BackupInterval     time.Duration `long:"backupinterval" ini-name:"backupinterval" description:"Time period between automatic database backups. Valid time units are {s,m,h}. Minimum 30 seconds."`
+	BackupKeepHourly   uint          `long:"backupkeephourly" ini-name:"backupkeephourly" description:"Number of hours for which an hourly timestamped backup is kept."`
+	BackupKeepDaily    uint          `long:"backupkeepdaily" ini-name:"backupkeepdaily" description:"Number of days for which a daily timestamped backup is kept."`
+	BackupSinkDir      string        `long:"backupsinkdir" ini-name:"backupsinkdir" description:"Directory, ideally on a remote mount, to which timestamped backups are also copied."`
+	BackupSinkCmd      string        `long:"backupsinkcmd" ini-name:"backupsinkcmd" description:"Command run to copy each timestamped backup off-host, eg. scp {file} backup@example.com:vspd/{name}. If neither {file} nor {name} are used, the path of the backup is appended."`
+	BackupSinkS3URL    string        `long:"backupsinks3url" ini-name:"backupsinks3url" description:"URL of an S3-compatible bucket to which timestamped backups are also uploaded, in the form https://host/bucket/prefix."`
+	BackupSinkS3Region string        `long:"backupsinks3region" ini-name:"backupsinks3region" description:"Region of the S3 bucket."`
+	BackupSinkS3Key    string        `long:"backupsinks3key" ini-name:"backupsinks3key" description:"Access key ID for the S3 bucket."`
+	BackupSinkS3Secret string        `long:"backupsinks3secret" ini-name:"backupsinks3secret" description:"Secret access key for the S3 bucket."`
+	VspClosed          bool          `long:"vspclosed" ini-name:"vspclosed" description:"Closed prevents the VSP from accepting new tickets."`
+	VspClosedMsg       string        `long:"vspclosedmsg" ini-name:"vspclosedmsg" description:"A short message displayed on the webpage and returned by the status API endpoint if vspclosed is true."`
+	AdminPass          string        `long:"adminpass" ini-name:"adminpass" description:"Password for accessing admin page."`
+	Designation        string        `long:"designation" ini-name:"designation" description:"Short name for the VSP. Customizes the logo in the top toolbar."`
+	Webhooks           string        `long:"webhook" ini-name:"webhook" description:"Comma separated list of URLs which will receive a signed POST request when the state of a ticket changes."`
+	DBBackend          string        `long:"dbbackend" ini-name:"dbbackend" description:"Storage backend for the vspd database." choice:"bolt" choice:"sqlite"`
+	ArchiveAfter       uint32        `long:"archiveafter" ini-name:"archiveafter" description:"Number of blocks after a ticket is voted or revoked before it is archived, which removes its voting key and raw fee tx from the database. Zero disables archiving."`
+	WIFPass            string        `long:"wifpass" ini-name:"wifpass" description:"Passphrase used to encrypt voting WIFs in the database. May instead be provided with wifkeyfile or the VSPD_WIF_PASSPHRASE environment variable."`
+	WIFKeyFile         string        `long:"wifkeyfile" ini-name:"wifkeyfile" description:"Path to a file containing the passphrase used to encrypt voting WIFs in the database."`
+	FeeXPubPolicy      string        `long:"feexpubpolicy" ini-name:"feexpubpolicy" description:"How the fee xpub used by each new ticket is selected when there are several active fee xpubs." choice:"roundrobin" choice:"weighted"`
+	FeeXPubWeights     string        `long:"feexpubweights" ini-name:"feexpubweights" description:"Comma separated list of id:weight pairs setting the weight of each active fee xpub, eg. 0:3,1:1. Used with feexpubpolicy=weighted. Xpubs which are not listed have a weight of 1."`
 
 	// The following flags should be set on CLI only, not via config file.
 	ShowVersion bool   `long:"version" no-ini:"true" description:"Display version information and exit."`
@@ -61,6 +83,11 @@ type Config struct {
 	network       *config.Network
 	dcrdDetails   *DcrdDetails
 	walletDetails *WalletDetails
+	webhookURLs   []string
+	dbBackend     database.Backend
+	wifPassphrase []byte
+	backupSinks   []backup.Sink
+	xPubWeights   map[uint32]uint32
 }
 
 type DcrdDetails struct {
@@ -86,7 +113,32 @@ func (cfg *Config) LogDir() string {
 }
 
 func (cfg *Config) DatabaseFile() string {
-	return filepath.Join(cfg.HomeDir, "data", cfg.network.Name, dbFilename)
+	filename := dbFilename
+	if cfg.dbBackend == database.SQLiteBackend {
+		filename = sqliteDBFilename
+	}
+	return filepath.Join(cfg.HomeDir, "data", cfg.network.Name, filename)
+}
+
+func (cfg *Config) DatabaseBackend() database.Backend {
+	return cfg.dbBackend
+}
+
+// WIFPassphrase returns the passphrase used to encrypt voting WIFs in the
+// database, or nil if voting WIFs should not be encrypted.
+func (cfg *Config) WIFPassphrase() []byte {
+	return cfg.wifPassphrase
+}
+
+// BackupRetention returns the retention policy of timestamped backups.
+func (cfg *Config) BackupRetention() backup.Retention {
+	return backup.Retention{Hourly: cfg.BackupKeepHourly, Daily: cfg.BackupKeepDaily}
+}
+
+// BackupSinks returns the configured off-host destinations of timestamped
+// backups.
+func (cfg *Config) BackupSinks() []backup.Sink {
+	return cfg.backupSinks
 }
 
 func (cfg *Config) DcrdDetails() *DcrdDetails {
@@ -97,20 +149,85 @@ func (cfg *Config) WalletDetails() *WalletDetails {
 	return cfg.walletDetails
 }
 
+func (cfg *Config) WebhookURLs() []string {
+	return cfg.webhookURLs
+}
+
+// XPubWeights returns the weight of each fee xpub configured with
+// feexpubweights, keyed by xpub ID.
+func (cfg *Config) XPubWeights() map[uint32]uint32 {
+	return cfg.xPubWeights
+}
+
+// liveOptions are the options which can be applied to a running vspd when its
+// config is reloaded. Changes to any other option require a restart.
+var liveOptions = map[string]struct{}{
+	"vspfee":       {},
+	"feeschedule":  {},
+	"supportemail": {},
+	"vspclosed":    {},
+	"vspclosedmsg": {},
+	"adminpass":    {},
+	"wallethost":   {},
+	"walletuser":   {},
+	"walletpass":   {},
+	"walletcert":   {},
+}
+
+// LiveChanges returns the names of the options which differ between cfg and
+// newCfg and can be applied while vspd is running.
+func (cfg *Config) LiveChanges(newCfg *Config) []string {
+	return cfg.changedOptions(newCfg, true)
+}
+
+// RestartRequired returns the names of the options which differ between cfg
+// and newCfg but can only be applied by restarting vspd.
+func (cfg *Config) RestartRequired(newCfg *Config) []string {
+	return cfg.changedOptions(newCfg, false)
+}
+
+// changedOptions returns the names of either the live or the other options
+// which differ between cfg and newCfg.
+func (cfg *Config) changedOptions(newCfg *Config, live bool) []string {
+	oldVal := reflect.ValueOf(cfg).Elem()
+	newVal := reflect.ValueOf(newCfg).Elem()
+
+	var changed []string
+	for i := range oldVal.NumField() {
+		name := oldVal.Type().Field(i).Tag.Get("long")
+		if name == "" {
+			continue
+		}
+		if _, ok := liveOptions[name]; ok != live {
+			continue
+		}
+		if !reflect.DeepEqual(oldVal.Field(i).Interface(), newVal.Field(i).Interface()) {
+			changed = append(changed, name)
+		}
+	}
+
+	return changed
+}
+
 var DefaultConfig = Config{
-	Listen:         ":8800",
-	LogLevel:       "debug",
-	MaxLogSize:     int64(10),
-	LogsToKeep:     20,
-	NetworkName:    "testnet",
-	VSPFee:         3.0,
-	HomeDir:        dcrutil.AppDataDir("vspd", false),
-	DcrdHost:       "127.0.0.1",
-	WalletHosts:    "127.0.0.1",
-	WebServerDebug: false,
-	BackupInterval: time.Minute * 3,
-	VspClosed:      false,
-	Designation:    "Voting Service Provider",
+	Listen:             ":8800",
+	LogLevel:           "debug",
+	MaxLogSize:         int64(10),
+	LogsToKeep:         20,
+	NetworkName:        "testnet",
+	VSPFee:             3.0,
+	HomeDir:            dcrutil.AppDataDir("vspd", false),
+	DcrdHost:           "127.0.0.1",
+	WalletHosts:        "127.0.0.1",
+	WebServerDebug:     false,
+	BackupInterval:     time.Minute * 3,
+	BackupKeepHourly:   24,
+	BackupKeepDaily:    30,
+	BackupSinkS3Region: "us-east-1",
+	VspClosed:          false,
+	DBBackend:          string(database.BoltBackend),
+	Designation:        "Voting Service Provider",
+	FeeXPubPolicy:      "roundrobin",
 }
 
 // fileExists reports whether the named file or directory exists.
@@ -185,6 +302,31 @@ func normalizeAddress(addr, defaultPort string) string {
 	return addr
 }
 
+// parseXPubWeights parses a comma separated list of id:weight pairs. Every
+// weight must be positive.
+func parseXPubWeights(s string) (map[uint32]uint32, error) {
+	weights := make(map[uint32]uint32)
+	for _, pair := range strings.Split(s, ",") {
+		idStr, weightStr, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid fee xpub weight %q, expected id:weight", pair)
+		}
+		id, err := strconv.ParseUint(idStr, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fee xpub ID in %q: %w", pair, err)
+		}
+		weight, err := strconv.ParseUint(weightStr, 10, 32)
+		if err != nil || weight == 0 {
+			return nil, fmt.Errorf("invalid fee xpub weight in %q, must be a positive integer", pair)
+		}
+		if _, ok := weights[uint32(id)]; ok {
+			return nil, fmt.Errorf("fee xpub %d has more than one weight", id)
+		}
+		weights[uint32(id)] = uint32(weight)
+	}
+	return weights, nil
+}
+
 // LoadConfig initializes and parses the config using a config file and command
 // line options.
 //
@@ -268,24 +410,54 @@ func LoadConfig() (*Config, error) {
 		return nil, err
 	}
 
+	cfg.dbBackend, err = database.ParseBackend(cfg.DBBackend)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.WIFKeyFile != "" {
+		cfg.WIFKeyFile = cleanAndExpandPath(cfg.WIFKeyFile)
+	}
+	cfg.wifPassphrase, err = config.WIFPassphrase(cfg.WIFPass, cfg.WIFKeyFile, config.WIFPassEnv)
+	if err != nil {
+		return nil, err
+	}
+
 	// Ensure backup interval is greater than 30 seconds.
 	if cfg.BackupInterval < time.Second*30 {
 		return nil, errors.New("minimum backupinterval is 30 seconds")
 	}
 
-	// validPoolFeeRate tests to see if a pool fee is a valid percentage from
-	// 0.01% to 100.00%.
-	validPoolFeeRate := func(feeRate float64) bool {
-		poolFeeRateTest := feeRate * 100
-		poolFeeRateTest = math.Floor(poolFeeRateTest)
-		return poolFeeRateTest >= 1.0 && poolFeeRateTest <= 10000.0
+	// Create the off-host backup sinks.
+	if cfg.BackupSinkDir != "" {
+		cfg.BackupSinkDir = cleanAndExpandPath(cfg.BackupSinkDir)
+		cfg.backupSinks = append(cfg.backupSinks, &backup.DirSink{Dir: cfg.BackupSinkDir})
+	}
+	if cfg.BackupSinkCmd != "" {
+		sink, err := backup.NewCommandSink(cfg.BackupSinkCmd)
+		if err != nil {
+			return nil, err
+		}
+		cfg.backupSinks = append(cfg.backupSinks, sink)
+	}
+	if cfg.BackupSinkS3URL != "" {
+		sink, err := backup.NewS3Sink(cfg.BackupSinkS3URL, cfg.BackupSinkS3Region,
+			cfg.BackupSinkS3Key, cfg.BackupSinkS3Secret)
+		if err != nil {
+			return nil, err
+		}
+		cfg.backupSinks = append(cfg.backupSinks, sink)
 	}
 
 	// Ensure the fee percentage is valid per txrules.
-	if !validPoolFeeRate(cfg.VSPFee) {
+	if !feeschedule.ValidFeePercentage(cfg.VSPFee) {
 		return nil, errors.New("invalid vspfee - should be greater than 0.01 and less than 100.0")
 	}
 
+	if cfg.FeeSchedule != "" {
+		cfg.FeeSchedule = cleanAndExpandPath(cfg.FeeSchedule)
+	}
+
 	// If VSP is not closed, ignore any provided closure message.
 	if !cfg.VspClosed {
 		cfg.VspClosedMsg = ""
@@ -406,6 +578,28 @@ func LoadConfig() (*Config, error) {
 		Certs:     walletCerts,
 	}
 
+	// Parse and validate list of webhook URLs.
+	if cfg.Webhooks != "" {
+		for _, webhook := range strings.Split(cfg.Webhooks, ",") {
+			u, err := url.Parse(webhook)
+			if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				return nil, fmt.Errorf("invalid webhook URL: %q", webhook)
+			}
+			cfg.webhookURLs = append(cfg.webhookURLs, webhook)
+		}
+	}
+
+	// Parse and validate fee xpub weights.
+	if cfg.FeeXPubWeights != "" {
+		if cfg.FeeXPubPolicy != "weighted" {
+			return nil, errors.New("feexpubweights can only be set when feexpubpolicy is weighted")
+		}
+		cfg.xPubWeights, err = parseXPubWeights(cfg.FeeXPubWeights)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// If database does not exist, return error.
 	if !fileExists(cfg.DatabaseFile()) {
 		return nil, fmt.Errorf("no %s database exists in %s. A new database can"+
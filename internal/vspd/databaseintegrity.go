@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2023 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -24,6 +24,11 @@ func (v *Vspd) checkDatabaseIntegrity(ctx context.Context) error {
 		return fmt.Errorf("checkRevoked error: %w", err)
 	}
 
+	err = v.checkFeeAddresses()
+	if err != nil {
+		return fmt.Errorf("checkFeeAddresses error: %w", err)
+	}
+
 	return nil
 }
 
@@ -119,6 +124,7 @@ func (v *Vspd) checkRevoked(ctx context.Context) error {
 			spentTicket.dbTicket.Outcome = database.Expired
 			fixedExpired++
 		}
+		spentTicket.dbTicket.OutcomeHeight = spentTicket.heightSpent
 
 		err = v.db.UpdateTicket(spentTicket.dbTicket)
 		if err != nil {
@@ -132,3 +138,30 @@ func (v *Vspd) checkRevoked(ctx context.Context) error {
 
 	return nil
 }
+
+// checkFeeAddresses ensures that the fee address of every ticket can be
+// re-derived from the xpub and index recorded with it, so that every fee paid
+// to the VSP can be accounted for using only the fee xpubs.
+func (v *Vspd) checkFeeAddresses() error {
+	report, err := database.CheckFeeAddresses(v.db, v.network.Params)
+	if err != nil {
+		return err
+	}
+
+	for _, gap := range report.Gaps {
+		v.log.Debugf("Fee address indexes %d to %d of xpub %d are not used by any ticket",
+			gap.From, gap.To, gap.XPubID)
+	}
+
+	if len(report.Problems) > 0 {
+		for _, problem := range report.Problems {
+			v.log.Errorf("Fee address check: %s", problem)
+		}
+		return fmt.Errorf("%s found in the fee addresses of %s",
+			pluralize(len(report.Problems), "problem"), pluralize(report.Checked, "ticket"))
+	}
+
+	v.log.Debugf("Verified the fee addresses of %s", pluralize(report.Checked, "ticket"))
+
+	return nil
+}
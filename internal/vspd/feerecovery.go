@@ -0,0 +1,202 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package vspd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/webhook"
+	"github.com/decred/vspd/rpc"
+	"github.com/jrick/wsrpc/v2"
+)
+
+// errPermanentFeeFailure is returned by checkFeeTx when a fee tx can never be
+// broadcast successfully.
+var errPermanentFeeFailure = errors.New("permanent fee failure")
+
+// recoverFees re-checks tickets with fee txs which previously could not be
+// broadcast. Tickets will be moved back to broadcast status if dcrd already has
+// the fee tx or if it can now be broadcast successfully. Tickets with fee txs
+// which can never be broadcast are marked as permanently failed, and the client
+// will need to provide a new fee tx.
+func (v *Vspd) recoverFees(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
+	const funcName = "recoverFees"
+
+	errored, err := v.db.GetRecoverableFees()
+	if err != nil {
+		v.log.Errorf("%s: db.GetRecoverableFees error: %v", funcName, err)
+		return
+	}
+
+	if len(errored) == 0 {
+		return
+	}
+
+	bestHeight, err := dcrdClient.GetBlockCount()
+	if err != nil {
+		v.log.Errorf("%s: dcrd.GetBlockCount error: %v", funcName, err)
+		return
+	}
+
+	for _, ticket := range errored {
+		// Exit early if context has been canceled.
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Fee txs are not broadcast until the ticket is confirmed.
+		if !ticket.Confirmed {
+			continue
+		}
+
+		reason, err := v.checkFeeTx(dcrdClient, ticket, bestHeight)
+		switch {
+		case err == nil:
+			v.log.Infof("Fee tx recovered from error state (ticketHash=%s, feeHash=%s)",
+				ticket.Hash, ticket.FeeTxHash)
+			ticket.FeeTxStatus = database.FeeBroadcast
+			ticket.FeeErrorReason = ""
+
+		case errors.Is(err, errPermanentFeeFailure):
+			v.log.Infof("Fee tx permanently failed (ticketHash=%s, feeHash=%s): %s",
+				ticket.Hash, ticket.FeeTxHash, reason)
+			ticket.FeeErrorReason = reason
+			ticket.FeeErrorPermanent = true
+
+		default:
+			// The fee tx may still succeed in future, but store the reason it
+			// failed this time so it is visible to the client.
+			v.log.Debugf("%s: Fee tx still cannot be broadcast (ticketHash=%s): %v",
+				funcName, ticket.Hash, err)
+			if reason == "" || reason == ticket.FeeErrorReason {
+				continue
+			}
+			ticket.FeeErrorReason = reason
+		}
+
+		err = v.db.UpdateTicket(ticket)
+		if err != nil {
+			v.log.Errorf("%s: db.UpdateTicket error, failed to update fee error (ticketHash=%s): %v",
+				funcName, ticket.Hash, err)
+			continue
+		}
+
+		switch {
+		case ticket.FeeTxStatus == database.FeeBroadcast:
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventFeeBroadcast,
+				"Recovered from error")
+			v.notify(webhook.NewEvent(webhook.FeeBroadcast, ticket))
+		case ticket.FeeErrorPermanent:
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventFeeError,
+				fmt.Sprintf("Permanently failed: %s", reason))
+			v.notify(webhook.NewEvent(webhook.FeeError, ticket))
+		default:
+			// Only the reason of the error changed, which is not reported to
+			// webhooks but is visible in the status of the ticket.
+			v.feed.Publish(ticket.Hash)
+		}
+	}
+}
+
+// checkFeeTx determines whether the fee tx of a ticket in the error state can
+// be recovered. A nil error indicates that dcrd now has the fee tx, either
+// because it was found or because it was successfully broadcast. If the fee tx
+// can never be broadcast, errPermanentFeeFailure is returned along with a
+// reason suitable for displaying to the client. Any other error indicates the
+// fee tx may still be broadcast successfully in future, and the reason may be
+// empty if the error is not caused by the fee tx itself (eg. RPC failures).
+func (v *Vspd) checkFeeTx(dcrdClient *rpc.DcrdRPC, ticket database.Ticket,
+	bestHeight int64) (string, error) {
+
+	// The fee tx may have been mined or added to the mempool despite the
+	// error, for example if it was also broadcast by the client wallet.
+	_, err := dcrdClient.GetRawTransaction(ticket.FeeTxHash)
+	if err == nil {
+		return "", nil
+	}
+	var e *wsrpc.Error
+	if !errors.As(err, &e) || e.Code != rpc.ErrNoTxInfo {
+		return "", fmt.Errorf("dcrd.GetRawTransaction for fee tx failed: %w", err)
+	}
+
+	// Stop trying once the ticket has expired because the fee no longer serves
+	// any purpose.
+	expiryHeight := ticket.PurchaseHeight + int64(v.network.TicketMaturity) +
+		int64(v.network.TicketExpiry)
+	if bestHeight > expiryHeight {
+		return "Ticket expired before fee tx could be broadcast", errPermanentFeeFailure
+	}
+
+	// The raw fee tx is required to broadcast it again.
+	if ticket.FeeTxHex == "" {
+		return "Fee tx is not available to broadcast", errPermanentFeeFailure
+	}
+
+	broadcastErr := dcrdClient.SendRawTransaction(ticket.FeeTxHex)
+	if broadcastErr == nil {
+		return "", nil
+	}
+
+	if !rpc.ErrOrphan.MatchString(broadcastErr.Error()) {
+		// Only a fee tx which dcrd cannot deserialize will never be accepted.
+		// Any other error may be caused by dcrd being unavailable or by policy
+		// rules which can change, so the fee tx is tried again on the next
+		// block until the ticket expires.
+		if errors.As(broadcastErr, &e) && e.Code == rpc.ErrRPCDeserialization {
+			return "Fee tx could not be decoded", errPermanentFeeFailure
+		}
+		return "Fee tx could not be broadcast",
+			fmt.Errorf("dcrd.SendRawTransaction for fee tx failed: %w", broadcastErr)
+	}
+
+	// An orphan error means at least one input of the fee tx is either spent
+	// or it references a tx which dcrd doesn't know about yet. Check each
+	// input to find out which.
+	txBytes, err := hex.DecodeString(ticket.FeeTxHex)
+	if err != nil {
+		return "Fee tx could not be decoded", errPermanentFeeFailure
+	}
+	var feeTx wire.MsgTx
+	err = feeTx.Deserialize(bytes.NewReader(txBytes))
+	if err != nil {
+		return "Fee tx could not be decoded", errPermanentFeeFailure
+	}
+
+	for _, txIn := range feeTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		prevHash := prevOut.Hash.String()
+
+		txOut, err := dcrdClient.GetTxOut(prevHash, prevOut.Index, prevOut.Tree)
+		if err != nil {
+			return "", fmt.Errorf("dcrd.GetTxOut failed: %w", err)
+		}
+		if txOut != nil {
+			// Output is unspent.
+			continue
+		}
+
+		// Output is either spent or its tx is unknown. If the tx is known then
+		// the output must have been spent by a different transaction.
+		_, err = dcrdClient.GetRawTransaction(prevHash)
+		if err == nil {
+			return fmt.Sprintf("Fee tx input %s is double spent", prevOut),
+				errPermanentFeeFailure
+		}
+		if !errors.As(err, &e) || e.Code != rpc.ErrNoTxInfo {
+			return "", fmt.Errorf("dcrd.GetRawTransaction for fee tx input failed: %w", err)
+		}
+	}
+
+	// None of the inputs are double spent, so one of the parents of the fee tx
+	// is not yet known to dcrd. It may be broadcast later.
+	return "Fee tx references unknown outputs, the tx which created them may not be broadcast yet",
+		broadcastErr
+}
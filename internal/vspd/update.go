@@ -7,9 +7,12 @@ package vspd
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
+	"time"
 
 	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/webhook"
 	"github.com/decred/vspd/rpc"
 	"github.com/jrick/wsrpc/v2"
 )
@@ -26,31 +29,57 @@ func (v *Vspd) update(ctx context.Context) {
 		return
 	}
 
-	// Step 1/4: Update the database with any tickets which now have 6+
+	// Step 1/6: Update the database with any tickets which now have 6+
 	// confirmations.
-	v.updateUnconfirmed(ctx, dcrdClient)
+	v.timeStep("updateUnconfirmed", func() { v.updateUnconfirmed(ctx, dcrdClient) })
 	if ctx.Err() != nil {
 		return
 	}
 
-	// Step 2/4: Broadcast fee tx for tickets which are confirmed.
-	v.broadcastFees(ctx, dcrdClient)
+	// Step 2/6: Retry fee txs which previously could not be broadcast.
+	v.timeStep("recoverFees", func() { v.recoverFees(ctx, dcrdClient) })
 	if ctx.Err() != nil {
 		return
 	}
 
-	// Step 3/4: Add tickets with confirmed fees to voting wallets.
-	v.addToWallets(ctx, dcrdClient)
+	// Step 3/6: Broadcast fee tx for tickets which are confirmed.
+	v.timeStep("broadcastFees", func() { v.broadcastFees(ctx, dcrdClient) })
 	if ctx.Err() != nil {
 		return
 	}
 
-	// Step 4/4: Set ticket outcome in database if any tickets are
+	// Step 4/6: Add tickets with confirmed fees to voting wallets.
+	v.timeStep("addToWallets", func() { v.addToWallets(ctx, dcrdClient) })
+	if ctx.Err() != nil {
+		return
+	}
+
+	// Step 5/6: Set ticket outcome in database if any tickets are
 	// voted/revoked.
-	v.setOutcomes(ctx, dcrdClient)
+	v.timeStep("setOutcomes", func() { v.setOutcomes(ctx, dcrdClient) })
 	if ctx.Err() != nil {
 		return
 	}
+
+	// Step 6/6: Archive tickets which were voted/revoked long enough ago.
+	if v.archiveAfter > 0 {
+		v.timeStep("archiveTickets", func() { v.archiveTickets(dcrdClient) })
+	}
+}
+
+// timeStep runs a single step of the update process and records how long it
+// took to complete.
+func (v *Vspd) timeStep(step string, fn func()) {
+	start := time.Now()
+	fn()
+	v.metrics.UpdateStepDuration.Observe(time.Since(start).Seconds(), step)
+}
+
+// notify sends event to the configured webhooks, and to the API clients which
+// are streaming the status of its ticket.
+func (v *Vspd) notify(event webhook.Event) {
+	v.webhook.Notify(event)
+	v.feed.Publish(event.TicketHash)
 }
 
 func (v *Vspd) updateUnconfirmed(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
@@ -79,25 +108,15 @@ func (v *Vspd) updateUnconfirmed(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 				v.log.Infof("Removing unconfirmed ticket from db - no information available "+
 					"about transaction (ticketHash=%s)", ticket.Hash)
 
-				err = v.db.DeleteTicket(ticket)
+				err = v.db.PurgeTicket(ticket)
 				if err != nil {
-					v.log.Errorf("%s: db.DeleteTicket error (ticketHash=%s): %v",
+					v.log.Errorf("%s: db.PurgeTicket error (ticketHash=%s): %v",
 						funcName, ticket.Hash, err)
 				}
 
-				err = v.db.DeleteVoteChanges(ticket.Hash)
-				if err != nil {
-					v.log.Errorf("%s: db.DeleteVoteChanges error (ticketHash=%s): %v",
-						funcName, ticket.Hash, err)
-				}
-
-				// This will not error if an alternate signing address does not
-				// exist for ticket.
-				err = v.db.DeleteAltSignAddr(ticket.Hash)
-				if err != nil {
-					v.log.Errorf("%s: db.DeleteAltSignAddr error (ticketHash=%s): %v",
-						funcName, ticket.Hash, err)
-				}
+				// Clients streaming the status of the ticket will find it is
+				// no longer known.
+				v.feed.Publish(ticket.Hash)
 			} else {
 				v.log.Errorf("%s: dcrd.GetRawTransaction for ticket failed (ticketHash=%s): %v",
 					funcName, ticket.Hash, err)
@@ -117,6 +136,12 @@ func (v *Vspd) updateUnconfirmed(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 			}
 
 			v.log.Infof("Ticket confirmed (ticketHash=%s)", ticket.Hash)
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventTicketConfirmed,
+				fmt.Sprintf("Purchase height %d", ticket.PurchaseHeight))
+
+			event := webhook.NewEvent(webhook.TicketConfirmed, ticket)
+			event.Height = ticket.PurchaseHeight
+			v.notify(event)
 		}
 	}
 }
@@ -136,11 +161,14 @@ func (v *Vspd) broadcastFees(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 			return
 		}
 
+		var broadcastErr error
 		err = dcrdClient.SendRawTransaction(ticket.FeeTxHex)
 		if err != nil {
 			v.log.Errorf("%s: dcrd.SendRawTransaction for fee tx failed (ticketHash=%s): %v",
 				funcName, ticket.Hash, err)
 			ticket.FeeTxStatus = database.FeeError
+			ticket.FeeErrorReason = "Fee tx could not be broadcast"
+			broadcastErr = err
 		} else {
 			v.log.Infof("Fee tx broadcast for ticket (ticketHash=%s, feeHash=%s)",
 				ticket.Hash, ticket.FeeTxHash)
@@ -151,6 +179,16 @@ func (v *Vspd) broadcastFees(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 		if err != nil {
 			v.log.Errorf("%s: db.UpdateTicket error, failed to set fee tx as broadcast (ticketHash=%s): %v",
 				funcName, ticket.Hash, err)
+			continue
+		}
+
+		if ticket.FeeTxStatus == database.FeeBroadcast {
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventFeeBroadcast, "")
+			v.notify(webhook.NewEvent(webhook.FeeBroadcast, ticket))
+		} else {
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventFeeError,
+				fmt.Sprintf("Broadcast failed: %v", broadcastErr))
+			v.notify(webhook.NewEvent(webhook.FeeError, ticket))
 		}
 	}
 }
@@ -184,13 +222,19 @@ func (v *Vspd) addToWallets(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 		if err != nil {
 			v.log.Errorf("%s: dcrd.GetRawTransaction for fee tx failed (feeTxHash=%s, ticketHash=%s): %v",
 				funcName, ticket.FeeTxHash, ticket.Hash, err)
+			feeErr := err
 
 			ticket.FeeTxStatus = database.FeeError
+			ticket.FeeErrorReason = "Fee tx was broadcast but can no longer be found"
 			err = v.db.UpdateTicket(ticket)
 			if err != nil {
 				v.log.Errorf("%s: db.UpdateTicket error, failed to set fee tx status to error (ticketHash=%s): %v",
 					funcName, ticket.Hash, err)
+				continue
 			}
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventFeeError,
+				fmt.Sprintf("Fee tx not found: %v", feeErr))
+			v.notify(webhook.NewEvent(webhook.FeeError, ticket))
 			continue
 		}
 
@@ -207,6 +251,8 @@ func (v *Vspd) addToWallets(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 				continue
 			}
 			v.log.Infof("Fee tx confirmed (ticketHash=%s)", ticket.Hash)
+			database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventFeeConfirmed, "")
+			v.notify(webhook.NewEvent(webhook.FeeConfirmed, ticket))
 
 			// Add ticket to the voting wallet.
 
@@ -227,6 +273,8 @@ func (v *Vspd) addToWallets(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 					continue
 				}
 				added++
+				database.AddTicketEvent(v.db, v.log, ticket.Hash, database.EventAddedToWallet,
+					walletClient.String())
 
 				// Set consensus vote choices on voting wallets.
 				for agenda, choice := range ticket.VoteChoices {
@@ -327,6 +375,7 @@ func (v *Vspd) setOutcomes(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 		default:
 			dbTicket.Outcome = database.Expired
 		}
+		dbTicket.OutcomeHeight = spentTicket.heightSpent
 
 		err = v.db.UpdateTicket(dbTicket)
 		if err != nil {
@@ -337,5 +386,38 @@ func (v *Vspd) setOutcomes(ctx context.Context, dcrdClient *rpc.DcrdRPC) {
 
 		v.log.Infof("Ticket %s at height %d (ticketHash=%s)",
 			dbTicket.Outcome, spentTicket.heightSpent, dbTicket.Hash)
+
+		database.AddTicketEvent(v.db, v.log, dbTicket.Hash, database.EventOutcome,
+			fmt.Sprintf("Ticket %s at height %d", dbTicket.Outcome, spentTicket.heightSpent))
+
+		eventType := webhook.TicketRevoked
+		if dbTicket.Outcome == database.Voted {
+			eventType = webhook.TicketVoted
+		}
+		event := webhook.NewEvent(eventType, dbTicket)
+		event.Height = spentTicket.heightSpent
+		v.notify(event)
+	}
+}
+
+// archiveTickets moves tickets into the database archive once their outcome
+// has been set for at least archiveAfter blocks.
+func (v *Vspd) archiveTickets(dcrdClient *rpc.DcrdRPC) {
+	const funcName = "archiveTickets"
+
+	bestHeight, err := dcrdClient.GetBlockCount()
+	if err != nil {
+		v.log.Errorf("%s: dcrd.GetBlockCount error: %v", funcName, err)
+		return
+	}
+
+	archived, err := v.db.ArchiveTickets(bestHeight, v.archiveAfter)
+	if err != nil {
+		v.log.Errorf("%s: db.ArchiveTickets error: %v", funcName, err)
+		return
+	}
+
+	if archived > 0 {
+		v.log.Infof("Archived %s", pluralize(archived, "ticket"))
 	}
 }
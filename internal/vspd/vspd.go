@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2023 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -13,6 +13,9 @@ import (
 	"github.com/decred/slog"
 	"github.com/decred/vspd/database"
 	"github.com/decred/vspd/internal/config"
+	"github.com/decred/vspd/internal/metrics"
+	"github.com/decred/vspd/internal/ticketfeed"
+	"github.com/decred/vspd/internal/webhook"
 	"github.com/decred/vspd/rpc"
 )
 
@@ -31,19 +34,35 @@ const (
 type Vspd struct {
 	network *config.Network
 	log     slog.Logger
-	db      *database.VspDatabase
+	db      database.Database
 	dcrd    rpc.DcrdConnect
 	wallets rpc.WalletConnect
+	metrics *metrics.Metrics
+	webhook *webhook.Notifier
+
+	// feed is notified of every change to the state of a ticket made by
+	// update.
+	feed *ticketfeed.Feed
+
+	// archiveAfter is the number of blocks after their outcome that tickets
+	// are archived. Zero disables archiving.
+	archiveAfter int64
 
 	blockNotifChan chan *wire.BlockHeader
 
+	// walletsUpdated receives a value when the voting wallets are replaced by
+	// UpdateWallets.
+	walletsUpdated chan struct{}
+
 	// lastScannedBlock is the height of the most recent block which has been
 	// scanned for spent tickets.
 	lastScannedBlock int64
 }
 
-func New(network *config.Network, log slog.Logger, db *database.VspDatabase,
-	dcrd rpc.DcrdConnect, wallets rpc.WalletConnect, blockNotifChan chan *wire.BlockHeader) *Vspd {
+func New(network *config.Network, log slog.Logger, db database.Database,
+	dcrd rpc.DcrdConnect, wallets rpc.WalletConnect, metrics *metrics.Metrics,
+	webhook *webhook.Notifier, feed *ticketfeed.Feed, archiveAfter uint32,
+	blockNotifChan chan *wire.BlockHeader) *Vspd {
 
 	v := &Vspd{
 		network: network,
@@ -51,13 +70,31 @@ func New(network *config.Network, log slog.Logger, db *database.VspDatabase,
 		db:      db,
 		dcrd:    dcrd,
 		wallets: wallets,
+		metrics: metrics,
+		webhook: webhook,
+		feed:    feed,
+
+		archiveAfter: int64(archiveAfter),
 
 		blockNotifChan: blockNotifChan,
+		walletsUpdated: make(chan struct{}, 1),
 	}
 
 	return v
 }
 
+// UpdateWallets replaces the connections to the voting wallets, which are
+// shared with the webapi, and checks the consistency of the new wallets as soon
+// as possible.
+func (v *Vspd) UpdateWallets(user, pass, addrs []string, cert [][]byte) {
+	v.wallets.Update(user, pass, addrs, cert)
+
+	select {
+	case v.walletsUpdated <- struct{}{}:
+	default:
+	}
+}
+
 func (v *Vspd) Run(ctx context.Context) {
 	// Run database integrity checks to ensure all data in database is present
 	// and up-to-date.
@@ -107,6 +144,10 @@ func (v *Vspd) Run(ctx context.Context) {
 		case <-consistencyTicker.C:
 			v.checkWalletConsistency(ctx)
 
+		// Run voting wallet consistency check when the wallets are replaced.
+		case <-v.walletsUpdated:
+			v.checkWalletConsistency(ctx)
+
 		// Ensure dcrd client is connected so notifications are received.
 		case <-dcrdTicker.C:
 			_, _, err := v.dcrd.Client()
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -6,6 +6,7 @@ package webapi
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/dcrd/hdkeychain/v3"
@@ -90,3 +91,79 @@ func (m *addressGenerator) nextAddress() (string, uint32, error) {
 
 	return addr.String(), m.lastUsedIndex, nil
 }
+
+// XPubPolicy determines which of the active fee xpubs is used to derive the fee
+// address of each new ticket.
+type XPubPolicy string
+
+const (
+	// RoundRobin uses each active xpub in turn.
+	RoundRobin XPubPolicy = "roundrobin"
+	// Weighted uses each active xpub in proportion to its configured weight.
+	Weighted XPubPolicy = "weighted"
+)
+
+// addressSelector derives fee addresses from every active fee xpub. The xpub
+// used for each new address is chosen with smooth weighted round-robin, so each
+// xpub is used in proportion to its weight and the xpubs are interleaved as
+// evenly as possible. With equal weights this is plain round-robin.
+type addressSelector struct {
+	generators []*addressGenerator
+	weights    []int64
+	current    []int64
+	total      int64
+}
+
+// newAddressSelector creates an address selector for the provided active
+// xpubs. Weights are only used by the Weighted policy, and any xpub without a
+// weight has a weight of 1.
+func newAddressSelector(xPubs []database.FeeXPub, policy XPubPolicy, weights map[uint32]uint32,
+	netParams *chaincfg.Params, log slog.Logger) (*addressSelector, error) {
+	if len(xPubs) == 0 {
+		return nil, errors.New("no active fee xpubs")
+	}
+
+	s := &addressSelector{
+		current: make([]int64, len(xPubs)),
+	}
+
+	for _, xPub := range xPubs {
+		gen, err := newAddressGenerator(xPub, netParams, log)
+		if err != nil {
+			return nil, fmt.Errorf("xpub %d: %w", xPub.ID, err)
+		}
+
+		weight := int64(1)
+		if w, ok := weights[xPub.ID]; ok && policy == Weighted {
+			weight = int64(w)
+		}
+
+		s.generators = append(s.generators, gen)
+		s.weights = append(s.weights, weight)
+		s.total += weight
+	}
+
+	return s, nil
+}
+
+// nextAddress selects an xpub and returns a new address derived from it, along
+// with the index of the address and the ID of the xpub. Not safe for
+// concurrent access.
+func (s *addressSelector) nextAddress() (string, uint32, uint32, error) {
+	selected := 0
+	for i, weight := range s.weights {
+		s.current[i] += weight
+		if s.current[i] > s.current[selected] {
+			selected = i
+		}
+	}
+	s.current[selected] -= s.total
+
+	gen := s.generators[selected]
+	addr, idx, err := gen.nextAddress()
+	if err != nil {
+		return "", 0, 0, err
+	}
+
+	return addr, idx, gen.xPubID(), nil
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+)
+
+const testnetXPub = "tpubVhnMyQmZAhoosTJRf8hRxGzMabXgJxf6st2Ch6WcBsf6XiqYX4QKp8n6fcaeVQCeoKqAoUSgbrGhGBiz9Tx1dYVSMZR9UnowKMrefxt8qVC"
+
+func TestAddressSelector(t *testing.T) {
+	params := chaincfg.TestNet3Params()
+
+	// Every xpub uses the same key, but a different ID and last used index so
+	// the addresses they derive can be distinguished.
+	xPubs := []database.FeeXPub{
+		{ID: 0, Key: testnetXPub, LastUsedIdx: 0},
+		{ID: 2, Key: testnetXPub, LastUsedIdx: 100},
+		{ID: 5, Key: testnetXPub, LastUsedIdx: 200},
+	}
+
+	tests := map[string]struct {
+		policy      XPubPolicy
+		weights     map[uint32]uint32
+		expectedIDs []uint32
+	}{
+		"round-robin": {
+			policy:      RoundRobin,
+			expectedIDs: []uint32{0, 2, 5, 0, 2, 5},
+		},
+		"round-robin ignores weights": {
+			policy:      RoundRobin,
+			weights:     map[uint32]uint32{0: 4},
+			expectedIDs: []uint32{0, 2, 5, 0, 2, 5},
+		},
+		"weighted": {
+			policy:      Weighted,
+			weights:     map[uint32]uint32{0: 4, 5: 1},
+			expectedIDs: []uint32{0, 0, 2, 0, 5, 0},
+		},
+		"weighted without weights": {
+			policy:      Weighted,
+			expectedIDs: []uint32{0, 2, 5, 0, 2, 5},
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			s, err := newAddressSelector(xPubs, test.policy, test.weights, params, slog.Disabled)
+			if err != nil {
+				t.Fatalf("error creating address selector: %v", err)
+			}
+
+			lastIdx := map[uint32]uint32{0: 0, 2: 100, 5: 200}
+			var ids []uint32
+			for range test.expectedIDs {
+				_, idx, id, err := s.nextAddress()
+				if err != nil {
+					t.Fatalf("error getting address: %v", err)
+				}
+				if idx != lastIdx[id]+1 {
+					t.Fatalf("expected index %d from xpub %d, got %d", lastIdx[id]+1, id, idx)
+				}
+				lastIdx[id] = idx
+				ids = append(ids, id)
+			}
+
+			if !reflect.DeepEqual(test.expectedIDs, ids) {
+				t.Fatalf("expected xpubs %v, got %v", test.expectedIDs, ids)
+			}
+		})
+	}
+
+	_, err := newAddressSelector(nil, RoundRobin, nil, params, slog.Disabled)
+	if err == nil {
+		t.Fatal("expected error creating address selector without xpubs")
+	}
+}
@@ -5,10 +5,15 @@
 package webapi
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
 	"crypto/subtle"
 	"encoding/json"
+	"fmt"
+	"maps"
 	"net/http"
+	"slices"
+	"time"
 
 	"github.com/decred/vspd/database"
 	"github.com/decred/vspd/rpc"
@@ -44,10 +49,25 @@ type searchResult struct {
 	Hash            string
 	Found           bool
 	Ticket          database.Ticket
+	Archived        bool
 	FeeTxDecoded    string
 	AltSignAddrData *database.AltSignAddrData
 	VoteChanges     map[uint32]database.VoteChangeRecord
 	MaxVoteChanges  int
+	Events          []database.TicketEvent
+}
+
+// ticketHistory is the JSON representation of the event history of a ticket
+// returned by the /admin/ticket/history endpoint.
+type ticketHistory struct {
+	TicketHash string               `json:"tickethash"`
+	Events     []ticketHistoryEvent `json:"events"`
+}
+
+type ticketHistoryEvent struct {
+	Timestamp int64  `json:"timestamp"`
+	Type      string `json:"type"`
+	Detail    string `json:"detail,omitempty"`
 }
 
 func (w *WebAPI) dcrdStatus(c *gin.Context) dcrdStatus {
@@ -162,33 +182,45 @@ func (w *WebAPI) renderAdmin(c *gin.Context, searchResult *searchResult) {
 
 	missed.SortByPurchaseHeight()
 
-	currentXPub, err := w.db.FeeXPub()
+	xpubs, err := w.db.AllXPubs()
 	if err != nil {
-		w.log.Errorf("db.FeeXPub error: %v", err)
-		c.String(http.StatusInternalServerError, "Error getting current xpub from db")
+		w.log.Errorf("db.AllXPubs error: %v", err)
+		c.String(http.StatusInternalServerError, "Error getting all xpubs from db")
 		return
 	}
 
-	oldXPubs, err := w.db.AllXPubs()
+	// Split the xpubs into those which are active and those which are retired,
+	// both sorted by ID.
+	var activeXPubs, oldXPubs []database.FeeXPub
+	for _, id := range slices.Sorted(maps.Keys(xpubs)) {
+		if xpubs[id].Retired == 0 {
+			activeXPubs = append(activeXPubs, xpubs[id])
+		} else {
+			oldXPubs = append(oldXPubs, xpubs[id])
+		}
+	}
+
+	vspStatusHistory, err := w.vspStatusHistory()
 	if err != nil {
-		w.log.Errorf("db.AllXPubs error: %v", err)
-		c.String(http.StatusInternalServerError, "Error getting all xpubs from db")
+		w.log.Errorf("db.VspStatusHistory error: %v", err)
+		c.String(http.StatusInternalServerError, "Error getting VSP status history from db")
 		return
 	}
 
-	// Remove current xpub from the list of old xpubs.
-	delete(oldXPubs, currentXPub.ID)
-
 	c.HTML(http.StatusOK, "admin.html", gin.H{
 		"Admin":         true,
 		"SearchResult":  searchResult,
 		"WebApiCache":   cacheData,
-		"WebApiCfg":     w.cfg,
+		"WebApiCfg":     w.config(),
 		"WalletStatus":  w.walletStatus(c),
 		"DcrdStatus":    w.dcrdStatus(c),
 		"MissedTickets": missed,
-		"CurrentXPub":   currentXPub,
+		"ActiveXPubs":   activeXPubs,
 		"OldXPubs":      oldXPubs,
+		"FeeSchedule":   w.feeScheduleRows(),
+		"VspStatus":     vspStatusHistory,
+		"Maintenance":   w.maintenance.Load().upcoming(time.Now()),
+		"FeeReport":     w.feeReports.status(),
 	})
 }
 
@@ -204,6 +236,21 @@ func (w *WebAPI) ticketSearch(c *gin.Context) {
 		return
 	}
 
+	var archived bool
+	if !found {
+		var archivedTicket database.ArchivedTicket
+		archivedTicket, archived, err = w.db.GetArchivedTicket(hash)
+		if err != nil {
+			w.log.Errorf("db.GetArchivedTicket error (ticketHash=%s): %v", hash, err)
+			c.String(http.StatusInternalServerError, "Error getting archived ticket from db")
+			return
+		}
+		if archived {
+			ticket = archivedTicket.Ticket()
+			found = true
+		}
+	}
+
 	voteChanges, err := w.db.GetVoteChanges(hash)
 	if err != nil {
 		w.log.Errorf("db.GetVoteChanges error (ticketHash=%s): %v", hash, err)
@@ -218,6 +265,13 @@ func (w *WebAPI) ticketSearch(c *gin.Context) {
 		return
 	}
 
+	events, err := w.db.TicketEvents(hash)
+	if err != nil {
+		w.log.Errorf("db.TicketEvents error (ticketHash=%s): %v", hash, err)
+		c.String(http.StatusInternalServerError, "Error getting ticket events from db")
+		return
+	}
+
 	// Decode the fee tx so it can be displayed human-readable. Fee tx hex may
 	// be null because it is removed from the DB if the tx is already mined and
 	// confirmed.
@@ -252,13 +306,51 @@ func (w *WebAPI) ticketSearch(c *gin.Context) {
 		Hash:            hash,
 		Found:           found,
 		Ticket:          ticket,
+		Archived:        archived,
 		FeeTxDecoded:    feeTxDecoded,
 		AltSignAddrData: altSignAddrData,
 		VoteChanges:     voteChanges,
 		MaxVoteChanges:  w.cfg.MaxVoteChangeRecords,
+		Events:          events,
 	})
 }
 
+// downloadTicketHistory is the handler for "GET /admin/ticket/history". The
+// event history of the ticket identified by the hash param is returned to the
+// client as a JSON file.
+func (w *WebAPI) downloadTicketHistory(c *gin.Context) {
+	hash := c.Query("hash")
+
+	// The hash is included in the name of the downloaded file.
+	err := validateTicketHash(hash)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid ticket hash")
+		return
+	}
+
+	events, err := w.db.TicketEvents(hash)
+	if err != nil {
+		w.log.Errorf("db.TicketEvents error (ticketHash=%s): %v", hash, err)
+		c.String(http.StatusInternalServerError, "Error getting ticket events from db")
+		return
+	}
+
+	history := ticketHistory{
+		TicketHash: hash,
+		Events:     make([]ticketHistoryEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		history.Events = append(history.Events, ticketHistoryEvent{
+			Timestamp: event.Timestamp,
+			Type:      string(event.Type),
+			Detail:    event.Detail,
+		})
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s-history.json", hash))
+	c.IndentedJSON(http.StatusOK, history)
+}
+
 // adminLogin is the handler for "POST /admin". If a valid password is provided,
 // the current session will be authenticated as an admin.
 func (w *WebAPI) adminLogin(c *gin.Context) {
@@ -266,21 +358,67 @@ func (w *WebAPI) adminLogin(c *gin.Context) {
 
 	password := c.PostForm("password")
 
-	// subtle.ConstantTimeCompare returns immediately if the params are not the
-	// same length. Avoid this by comparing hashes (which will be fixed length)
-	// instead of the raw passwords.
-	passwordHash := sha256.Sum256([]byte(password))
-	if subtle.ConstantTimeCompare(passwordHash[:], w.adminPassHash[:]) != 1 {
+	if !w.isAdminPass(password) {
 		w.log.Warnf("Failed login attempt from %s", c.ClientIP())
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{
 			"WebApiCache":    cacheData,
-			"WebApiCfg":      w.cfg,
+			"WebApiCfg":      w.config(),
 			"FailedLoginMsg": "Incorrect password",
 		})
 		return
 	}
 
-	w.setAdminStatus(true, c)
+	w.setAdminStatus(w.adminSessionValue(), c)
+}
+
+// isAdminPass returns true if password is the current admin password.
+func (w *WebAPI) isAdminPass(password string) bool {
+	w.cfgMtx.RLock()
+	defer w.cfgMtx.RUnlock()
+
+	// subtle.ConstantTimeCompare returns immediately if the params are not the
+	// same length. Avoid this by comparing hashes (which will be fixed length)
+	// instead of the raw passwords.
+	passwordHash := sha256.Sum256([]byte(password))
+	return subtle.ConstantTimeCompare(passwordHash[:], w.adminPassHash[:]) == 1
+}
+
+// adminSessionToken returns the value stored in the sessions of admins who
+// logged in with adminPass. It changes whenever the admin password changes, so
+// sessions created with a previous password are no longer accepted, including
+// after a restart. It is keyed with the cookie secret because the session
+// cookie is signed but not encrypted, so the value must not reveal anything
+// about the password.
+func adminSessionToken(cookieSecret []byte, adminPass string) [sha256.Size]byte {
+	mac := hmac.New(sha256.New, cookieSecret)
+	mac.Write([]byte(adminPass))
+
+	var token [sha256.Size]byte
+	copy(token[:], mac.Sum(nil))
+	return token
+}
+
+// adminSessionValue returns the value to store in the session of an admin who
+// has logged in with the current admin password.
+func (w *WebAPI) adminSessionValue() []byte {
+	w.cfgMtx.RLock()
+	defer w.cfgMtx.RUnlock()
+
+	return append([]byte(nil), w.adminSession[:]...)
+}
+
+// isAdminSession returns true if value was stored in a session by an admin who
+// logged in with the current admin password.
+func (w *WebAPI) isAdminSession(value any) bool {
+	token, ok := value.([]byte)
+	if !ok {
+		return false
+	}
+
+	w.cfgMtx.RLock()
+	defer w.cfgMtx.RUnlock()
+
+	return hmac.Equal(token, w.adminSession[:])
 }
 
 // adminLogout is the handler for "POST /admin/logout". The current session will
@@ -0,0 +1,283 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/vspd/types/v3"
+	"github.com/decred/vspd/types/v3/apiv4"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// maxClockSkew is how far the timestamp of an /api/v4 request or a ticket status
+// batch request may be from the clock of the VSP.
+const maxClockSkew = 5 * time.Minute
+
+// apiV4Request is added to the context of every /api/v4 request.
+type apiV4Request struct {
+	// raw is the request envelope exactly as it was signed by the client.
+	raw      []byte
+	envelope apiv4.RequestEnvelope
+}
+
+// response wraps either the body of a successful response or an error in a
+// response envelope.
+func (r *apiV4Request) response(body json.RawMessage, apiErr *types.ErrorResponse) apiv4.ResponseEnvelope {
+	return apiv4.ResponseEnvelope{
+		RequestID: r.envelope.RequestID,
+		Timestamp: time.Now().Unix(),
+		Request:   r.raw,
+		Body:      body,
+		Error:     apiErr,
+	}
+}
+
+// apiV4 returns the /api/v4 request being handled by c, or false if c is
+// handling a request for a different version of the API.
+func apiV4(c *gin.Context) (*apiV4Request, bool) {
+	req, ok := c.Get(apiV4Key)
+	if !ok {
+		return nil, false
+	}
+	return req.(*apiV4Request), true
+}
+
+// signedRequest returns the bytes signed by the client which sent the request
+// being handled by c. For /api/v4 requests this is the request envelope rather
+// than the version 3 request passed to handlers.
+func signedRequest(c *gin.Context) []byte {
+	if req, ok := apiV4(c); ok {
+		return req.raw
+	}
+	return c.MustGet(requestBytesKey).([]byte)
+}
+
+// apiV4Body converts the response of a version 3 handler into the body of the
+// equivalent /api/v4 response. Fields which are included in the response
+// envelope are dropped.
+func apiV4Body(resp any) any {
+	switch r := resp.(type) {
+	case types.FeeAddressResponse:
+		return apiv4.FeeAddressResponse{
+			FeeAddress: r.FeeAddress,
+			FeeAmount:  r.FeeAmount,
+			Expiration: r.Expiration,
+		}
+	case types.PayFeeResponse:
+		return apiv4.PayFeeResponse{}
+	case types.SetVoteChoicesResponse:
+		return apiv4.SetVoteChoicesResponse{}
+	case types.SetAltSignAddrResponse:
+		return apiv4.SetAltSignAddrResponse{}
+	case types.TicketStatusResponse:
+		return apiv4.TicketStatusResponse{
+			TicketConfirmed: r.TicketConfirmed,
+			FeeTxStatus:     r.FeeTxStatus,
+			FeeTxHash:       r.FeeTxHash,
+			FeeTxError:      r.FeeTxError,
+			AltSignAddress:  r.AltSignAddress,
+			VoteChoices:     r.VoteChoices,
+			TSpendPolicy:    r.TSpendPolicy,
+			TreasuryPolicy:  r.TreasuryPolicy,
+		}
+	default:
+		return resp
+	}
+}
+
+// withAPIV4 middleware marks the request as an /api/v4 request, so responses
+// and errors are wrapped in a response envelope.
+func (w *WebAPI) withAPIV4(c *gin.Context) {
+	c.Set(apiV4Key, &apiV4Request{})
+}
+
+// withEnvelope middleware reads the request envelope of a signed /api/v4
+// request, and ensures it is for the route it was sent to, its timestamp is
+// current and its body is a valid instance of the type of body. The request is
+// then replaced by the equivalent version 3 request, so the same middleware and
+// handlers serve both versions. The signature and nonce of the envelope are checked later by vspAuth and
+// checkNonce.
+func (w *WebAPI) withEnvelope(body any) gin.HandlerFunc {
+	bodyType := reflect.TypeOf(body)
+
+	return func(c *gin.Context) {
+		const funcName = "withEnvelope"
+
+		req, _ := apiV4(c)
+
+		raw, err := drainAndReplaceBody(c.Request)
+		if err != nil {
+			w.log.Warnf("%s: Error reading request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+			w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
+			return
+		}
+		req.raw = raw
+
+		var envelope apiv4.RequestEnvelope
+		if err := binding.JSON.BindBody(raw, &envelope); err != nil {
+			w.log.Warnf("%s: Bad request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+			w.sendErrorWithDetails(err.Error(), types.ErrBadRequest,
+				&types.ErrorDetails{Fields: invalidFields(err, reflect.TypeOf(envelope), "")}, c)
+			return
+		}
+		req.envelope = envelope
+
+		if envelope.Method != c.Request.Method || envelope.Path != c.FullPath() {
+			w.log.Warnf("%s: Request envelope for wrong route (clientIP=%s, method=%s, path=%s)",
+				funcName, c.ClientIP(), envelope.Method, envelope.Path)
+			w.sendErrorWithDetails("request envelope is for a different route", types.ErrBadRequest,
+				&types.ErrorDetails{Fields: []string{"method", "path"}}, c)
+			return
+		}
+
+		// Used nonces are forgotten when vspd restarts, so requests created
+		// before then could otherwise be replayed.
+		now := time.Now()
+		minTimestamp := now.Add(-maxClockSkew).Unix()
+		if started := w.started.Unix(); minTimestamp < started {
+			minTimestamp = started
+		}
+		maxTimestamp := now.Add(maxClockSkew).Unix()
+		if envelope.Timestamp < minTimestamp || envelope.Timestamp > maxTimestamp {
+			w.log.Warnf("%s: Request timestamp out of range (clientIP=%s, timestamp=%d)",
+				funcName, c.ClientIP(), envelope.Timestamp)
+			w.sendErrorWithDetails("request timestamp is too far from the time of the VSP",
+				types.ErrInvalidTimestamp, &types.ErrorDetails{
+					Fields:       []string{"timestamp"},
+					MinTimestamp: minTimestamp,
+					MaxTimestamp: maxTimestamp,
+				}, c)
+			return
+		}
+
+		bodyBytes := envelope.Body
+		if len(bodyBytes) == 0 || bytes.Equal(bodyBytes, []byte("null")) {
+			bodyBytes = []byte("{}")
+		}
+
+		if err := binding.JSON.BindBody(bodyBytes, reflect.New(bodyType).Interface()); err != nil {
+			w.log.Warnf("%s: Bad request body (clientIP=%s): %v", funcName, c.ClientIP(), err)
+			w.sendErrorWithDetails(err.Error(), types.ErrBadRequest,
+				&types.ErrorDetails{Fields: invalidFields(err, bodyType, "body.")}, c)
+			return
+		}
+
+		// Version 3 requests contain the fields of the body along with the
+		// ticket hash and timestamp.
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(bodyBytes, &fields); err != nil {
+			w.log.Warnf("%s: Bad request body (clientIP=%s): %v", funcName, c.ClientIP(), err)
+			w.sendErrorWithDetails("body must be a JSON object", types.ErrBadRequest,
+				&types.ErrorDetails{Fields: []string{"body"}}, c)
+			return
+		}
+		fields["tickethash"], _ = json.Marshal(envelope.TicketHash)
+		fields["timestamp"], _ = json.Marshal(envelope.Timestamp)
+
+		v3Bytes, err := json.Marshal(fields)
+		if err != nil {
+			w.log.Errorf("%s: JSON marshal error: %v", funcName, err)
+			w.sendError(types.ErrInternalError, c)
+			return
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(v3Bytes))
+		c.Request.ContentLength = int64(len(v3Bytes))
+	}
+}
+
+// checkNonce middleware errors out if the nonce of an /api/v4 request has
+// already been used for the same ticket. It must follow vspAuth, so only
+// requests with a valid signature use up their nonce.
+func (w *WebAPI) checkNonce(c *gin.Context) {
+	const funcName = "checkNonce"
+
+	req, _ := apiV4(c)
+	envelope := req.envelope
+
+	// Nonces only need to be remembered until the timestamp of the request is
+	// too old to be accepted.
+	expiry := time.Unix(envelope.Timestamp, 0).Add(maxClockSkew)
+	if !w.nonces.use(envelope.TicketHash+envelope.Nonce, expiry, time.Now()) {
+		w.log.Warnf("%s: Nonce reused (clientIP=%s, ticketHash=%s)",
+			funcName, c.ClientIP(), envelope.TicketHash)
+		w.sendErrorWithDetails("nonce has already been used", types.ErrBadRequest,
+			&types.ErrorDetails{Fields: []string{"nonce"}}, c)
+		return
+	}
+}
+
+// invalidFields returns the JSON names, with prefix, of the fields of
+// structType which caused a binding error.
+func invalidFields(err error, structType reflect.Type, prefix string) []string {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return []string{prefix + typeErr.Field}
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fields := make([]string, 0, len(validationErrs))
+	for _, e := range validationErrs {
+		name := e.StructField()
+		if f, ok := structType.FieldByName(name); ok {
+			if tag, _, _ := strings.Cut(f.Tag.Get("json"), ","); tag != "" {
+				name = tag
+			}
+		}
+		fields = append(fields, prefix+name)
+	}
+
+	return fields
+}
+
+// nonceCache remembers the nonces of /api/v4 requests. The zero value is ready
+// to use.
+type nonceCache struct {
+	mtx       sync.Mutex
+	expiries  map[string]time.Time
+	nextPrune time.Time
+}
+
+// use records that nonce is in use until expiry. It returns false if nonce
+// was already in use at time now.
+func (n *nonceCache) use(nonce string, expiry, now time.Time) bool {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+
+	if n.expiries == nil {
+		n.expiries = make(map[string]time.Time)
+	}
+
+	// Periodically forget nonces which have expired.
+	if now.After(n.nextPrune) {
+		for k, e := range n.expiries {
+			if !e.After(now) {
+				delete(n.expiries, k)
+			}
+		}
+		n.nextPrune = now.Add(maxClockSkew)
+	}
+
+	if e, ok := n.expiries[nonce]; ok && e.After(now) {
+		return false
+	}
+
+	n.expiries[nonce] = expiry
+	return true
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/types/v3"
+	"github.com/decred/vspd/types/v3/apiv4"
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIV4TicketStatus(t *testing.T) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkHash := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+	owner, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, api.cfg.Network)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ticketHash := randString(64, hexCharset)
+	err = api.db.InsertNewTicket(database.Ticket{
+		Hash:              ticketHash,
+		CommitmentAddress: owner.String(),
+		FeeAddress:        randString(35, hexCharset),
+		FeeTxStatus:       database.FeeConfirmed,
+		VoteChoices:       map[string]string{"agenda": "yes"},
+	})
+	if err != nil {
+		t.Fatalf("error storing ticket: %v", err)
+	}
+
+	const path = "/api/v4/ticketstatus"
+
+	envelope := func(timestamp int64, nonce, path string) []byte {
+		t.Helper()
+		b, err := json.Marshal(apiv4.RequestEnvelope{
+			RequestID:  "req-1",
+			Method:     http.MethodPost,
+			Path:       path,
+			Timestamp:  timestamp,
+			Nonce:      nonce,
+			TicketHash: ticketHash,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	// send posts reqBytes signed with signature to the ticket status handler,
+	// and returns the http status and the response envelope.
+	send := func(reqBytes []byte, signature string) (int, apiv4.ResponseEnvelope) {
+		t.Helper()
+
+		w := httptest.NewRecorder()
+		_, r := gin.CreateTestContext(w)
+		r.POST(path, api.withAPIV4, api.withEnvelope(apiv4.TicketStatusRequest{}), api.vspAuth,
+			api.checkNonce, api.ticketStatus)
+
+		req, err := http.NewRequest(http.MethodPost, path, bytes.NewReader(reqBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("VSP-Client-Signature", signature)
+
+		r.ServeHTTP(w, req)
+
+		var resp apiv4.ResponseEnvelope
+		err = json.Unmarshal(w.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("could not unmarshal response: %v", err)
+		}
+		if !bytes.Equal(resp.Request, reqBytes) {
+			t.Fatal("response contains differing request")
+		}
+		return w.Code, resp
+	}
+
+	now := time.Now().Unix()
+	valid := envelope(now, "0123456789abcdef", path)
+
+	status, resp := send(valid, signMessage(t, key, string(valid)))
+	if status != http.StatusOK || resp.Error != nil {
+		t.Fatalf("expected success, got status %d and error %+v", status, resp.Error)
+	}
+	if resp.RequestID != "req-1" {
+		t.Fatalf("expected request id req-1, got %q", resp.RequestID)
+	}
+	var ticketStatus apiv4.TicketStatusResponse
+	err = json.Unmarshal(resp.Body, &ticketStatus)
+	if err != nil {
+		t.Fatalf("could not unmarshal body: %v", err)
+	}
+	if ticketStatus.FeeTxStatus != string(database.FeeConfirmed) ||
+		ticketStatus.VoteChoices["agenda"] != "yes" {
+		t.Fatalf("unexpected ticket status %+v", ticketStatus)
+	}
+
+	tests := []struct {
+		name        string
+		reqBytes    []byte
+		signature   string
+		wantCode    types.ErrorCode
+		wantDetails types.ErrorDetails
+	}{{
+		name:        "replayed request",
+		reqBytes:    valid,
+		signature:   signMessage(t, key, string(valid)),
+		wantCode:    types.ErrBadRequest,
+		wantDetails: types.ErrorDetails{Fields: []string{"nonce"}},
+	}, {
+		name:        "different route",
+		reqBytes:    envelope(now, "fedcba9876543210", "/api/v4/payfee"),
+		wantCode:    types.ErrBadRequest,
+		wantDetails: types.ErrorDetails{Fields: []string{"method", "path"}},
+	}, {
+		name:      "signature of different message",
+		reqBytes:  envelope(now, "fedcba9876543210", path),
+		signature: signMessage(t, key, "something else"),
+		wantCode:  types.ErrBadSignature,
+	}, {
+		name:        "short nonce",
+		reqBytes:    envelope(now, "abc", path),
+		wantCode:    types.ErrBadRequest,
+		wantDetails: types.ErrorDetails{Fields: []string{"nonce"}},
+	}, {
+		name:     "old timestamp",
+		reqBytes: envelope(now-600, "fedcba9876543210", path),
+		wantCode: types.ErrInvalidTimestamp,
+		wantDetails: types.ErrorDetails{
+			Fields:       []string{"timestamp"},
+			MinTimestamp: -1,
+			MaxTimestamp: -1,
+		},
+	}}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, resp := send(test.reqBytes, test.signature)
+			if status != test.wantCode.HTTPStatus() || resp.Error == nil ||
+				resp.Error.Code != test.wantCode {
+				t.Fatalf("expected error %d, got status %d and error %+v", test.wantCode,
+					status, resp.Error)
+			}
+
+			details := resp.Error.Details
+			if details == nil {
+				details = &types.ErrorDetails{}
+			}
+			// Timestamp ranges depend on the time of the request, so only
+			// their presence is checked.
+			if test.wantDetails.MinTimestamp == -1 {
+				if details.MinTimestamp == 0 || details.MaxTimestamp == 0 {
+					t.Fatalf("expected timestamp range, got %+v", details)
+				}
+				test.wantDetails.MinTimestamp = details.MinTimestamp
+				test.wantDetails.MaxTimestamp = details.MaxTimestamp
+			}
+			if !reflect.DeepEqual(*details, test.wantDetails) {
+				t.Fatalf("expected details %+v, got %+v", test.wantDetails, *details)
+			}
+		})
+	}
+
+	// Requests created before vspd was started are rejected, because the
+	// nonces they used may have been forgotten.
+	started := api.started
+	api.started = time.Unix(now, 0)
+	defer func() { api.started = started }()
+
+	beforeStart := envelope(now-60, "0011223344556677", path)
+	status, resp = send(beforeStart, signMessage(t, key, string(beforeStart)))
+	if status != types.ErrInvalidTimestamp.HTTPStatus() || resp.Error == nil ||
+		resp.Error.Code != types.ErrInvalidTimestamp {
+		t.Fatalf("expected invalid timestamp error, got status %d and error %+v", status, resp.Error)
+	}
+	if resp.Error.Details == nil || resp.Error.Details.MinTimestamp != now {
+		t.Fatalf("expected minimum timestamp %d, got %+v", now, resp.Error.Details)
+	}
+}
+
+func TestErrorDetailsOnlyInAPIV4(t *testing.T) {
+	details := &types.ErrorDetails{RetryAfter: 1000}
+
+	for _, v4 := range []bool{false, true} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		if v4 {
+			api.withAPIV4(c)
+		}
+
+		api.sendErrorWithDetails("msg", types.ErrVspMaintenance, details, c)
+
+		var resp struct {
+			types.ErrorResponse
+			Error *types.ErrorResponse `json:"error"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &resp)
+		if err != nil {
+			t.Fatalf("could not unmarshal response: %v", err)
+		}
+
+		if !v4 {
+			if resp.Error != nil || resp.Details != nil || resp.Code != types.ErrVspMaintenance {
+				t.Fatalf("unexpected /api/v3 error %s", w.Body.String())
+			}
+			continue
+		}
+		if resp.Error == nil || !reflect.DeepEqual(resp.Error.Details, details) {
+			t.Fatalf("unexpected /api/v4 error %s", w.Body.String())
+		}
+	}
+}
+
+func TestNonceCache(t *testing.T) {
+	var n nonceCache
+	now := time.Unix(1000, 0)
+	expiry := now.Add(time.Minute)
+
+	if !n.use("a", expiry, now) {
+		t.Fatal("expected unused nonce to be accepted")
+	}
+	if n.use("a", expiry, now.Add(time.Second)) {
+		t.Fatal("expected used nonce to be rejected")
+	}
+	if !n.use("b", expiry, now) {
+		t.Fatal("expected different nonce to be accepted")
+	}
+	if !n.use("a", expiry, expiry) {
+		t.Fatal("expected expired nonce to be accepted")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/slog"
 	"github.com/decred/vspd/database"
 	"github.com/decred/vspd/rpc"
@@ -24,7 +25,7 @@ type cache struct {
 	mtx sync.RWMutex
 
 	log     slog.Logger
-	db      *database.VspDatabase
+	db      database.Database
 	dcrd    rpc.DcrdConnect
 	wallets rpc.WalletConnect
 }
@@ -47,6 +48,7 @@ type cacheData struct {
 	VotingWalletsOnline int64
 	TotalVotingWallets  int64
 	BlockHeight         uint32
+	TicketPrice         dcrutil.Amount
 	NetworkProportion   float32
 	ExpiredProportion   float32
 	MissedProportion    float32
@@ -67,7 +69,7 @@ func (c *cache) getData() cacheData {
 }
 
 // newCache creates a new cache and initializes it with static values.
-func newCache(signPubKey string, log slog.Logger, db *database.VspDatabase,
+func newCache(signPubKey string, log slog.Logger, db database.Database,
 	dcrd rpc.DcrdConnect, wallets rpc.WalletConnect) *cache {
 	return &cache{
 		data: cacheData{
@@ -133,6 +135,7 @@ func (c *cache) update() error {
 	c.data.Revenue28Days = stats.Revenue28Days
 	c.data.Revenue24Hours = stats.Revenue24Hours
 	c.data.BlockHeight = bestBlock.Height
+	c.data.TicketPrice = dcrutil.Amount(bestBlock.SBits)
 	c.data.NetworkProportion = float32(stats.Voting) / float32(bestBlock.PoolSize)
 
 	total := stats.Voted + stats.Expired + stats.Missed
@@ -0,0 +1,169 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decred/vspd/internal/reconcile"
+	"github.com/decred/vspd/rpc"
+	"github.com/gin-gonic/gin"
+)
+
+// feeReports generates fee reconciliation reports in the background. A report
+// requests the fee tx of every ticket from dcrd, which on a VSP with many
+// tickets takes far longer than the server allows for a response, so admins
+// request a report and download it once it is complete.
+type feeReports struct {
+	// requests receives the dcrd client to use for each requested report.
+	requests chan *rpc.DcrdRPC
+
+	mtx      sync.Mutex
+	running  bool
+	started  int64
+	finished int64
+	report   *reconcile.Report
+	err      error
+}
+
+func newFeeReports() *feeReports {
+	return &feeReports{
+		requests: make(chan *rpc.DcrdRPC, 1),
+	}
+}
+
+// feeReportStatus describes the state of fee report generation for the admin
+// page. Times are unix timestamps, and are zero if not applicable. Error is the
+// reason the last report failed, and Available is true if a previous report can
+// be downloaded.
+type feeReportStatus struct {
+	Running   bool
+	Started   int64
+	Finished  int64
+	Error     string
+	Available bool
+}
+
+// request queues the generation of a new report using dcrdClient. It returns
+// false if a report is already being generated.
+func (f *feeReports) request(dcrdClient *rpc.DcrdRPC) bool {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	if f.running {
+		return false
+	}
+
+	select {
+	case f.requests <- dcrdClient:
+	default:
+		return false
+	}
+
+	f.running = true
+	f.started = time.Now().Unix()
+	return true
+}
+
+// finish records the result of generating a report. A failed report does not
+// replace the previous report.
+func (f *feeReports) finish(report reconcile.Report, err error) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	f.running = false
+	f.finished = time.Now().Unix()
+	f.err = err
+	if err == nil {
+		f.report = &report
+	}
+}
+
+// status returns the current state of fee report generation.
+func (f *feeReports) status() feeReportStatus {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	status := feeReportStatus{
+		Running:   f.running,
+		Started:   f.started,
+		Finished:  f.finished,
+		Available: f.report != nil,
+	}
+	if f.err != nil {
+		status.Error = f.err.Error()
+	}
+	return status
+}
+
+// latest returns the most recently completed report along with the time it was
+// finished, or nil if no report has been completed.
+func (f *feeReports) latest() (*reconcile.Report, int64) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	return f.report, f.finished
+}
+
+// runFeeReports generates requested reports until ctx is canceled.
+func (w *WebAPI) runFeeReports(ctx context.Context) {
+	f := w.feeReports
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case dcrdClient := <-f.requests:
+			report, err := reconcile.Fees(ctx, w.db, dcrdClient, w.cfg.Network.Params)
+			if ctx.Err() != nil {
+				return
+			}
+			if err != nil {
+				w.log.Errorf("Fee reconciliation error: %v", err)
+			} else {
+				w.log.Infof("Fee report generated (failed=%d)", len(report.Failed))
+			}
+
+			f.finish(report, err)
+		}
+	}
+}
+
+// generateFeeReport is the handler for "POST /admin/fees". It starts the
+// generation of a new fee reconciliation report in the background, unless one
+// is already being generated.
+func (w *WebAPI) generateFeeReport(c *gin.Context) {
+	dcrdClient := c.MustGet(dcrdKey).(*rpc.DcrdRPC)
+	dcrdErr := c.MustGet(dcrdErrorKey)
+	if dcrdErr != nil {
+		w.log.Errorf("%v", dcrdErr.(error))
+		c.String(http.StatusInternalServerError, "Could not get dcrd client")
+		return
+	}
+
+	if w.feeReports.request(dcrdClient) {
+		w.log.Infof("Fee report requested by %s", c.ClientIP())
+	}
+
+	c.Redirect(http.StatusFound, "/admin")
+	c.Abort()
+}
+
+// downloadFeeReconciliation is the handler for "GET /admin/fees". The most
+// recently generated fee reconciliation report is returned to the client as a
+// JSON file.
+func (w *WebAPI) downloadFeeReconciliation(c *gin.Context) {
+	report, finished := w.feeReports.latest()
+	if report == nil {
+		c.String(http.StatusNotFound, "No fee report has been generated")
+		return
+	}
+
+	filename := "fee-reconciliation-" + time.Unix(finished, 0).UTC().Format("20060102T150405Z") + ".json"
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.IndentedJSON(http.StatusOK, report)
+}
@@ -0,0 +1,58 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/vspd/internal/reconcile"
+	"github.com/decred/vspd/rpc"
+)
+
+func TestFeeReports(t *testing.T) {
+	f := newFeeReports()
+
+	if status := f.status(); status.Running || status.Available {
+		t.Fatalf("expected no report, got %+v", status)
+	}
+
+	client := &rpc.DcrdRPC{}
+	if !f.request(client) {
+		t.Fatal("expected first request to be accepted")
+	}
+	if !f.status().Running {
+		t.Fatal("expected report to be running")
+	}
+
+	// Only one report is generated at a time.
+	if f.request(client) {
+		t.Fatal("expected request to be rejected while a report is running")
+	}
+	if got := <-f.requests; got != client {
+		t.Fatal("expected requested dcrd client to be queued")
+	}
+
+	expected := reconcile.Report{Expected: 100}
+	f.finish(expected, nil)
+	status := f.status()
+	if status.Running || !status.Available || status.Error != "" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+
+	// A failed report does not replace the previous report.
+	if !f.request(client) {
+		t.Fatal("expected request to be accepted after report finished")
+	}
+	<-f.requests
+	f.finish(reconcile.Report{}, errors.New("failed"))
+	status = f.status()
+	if status.Running || !status.Available || status.Error != "failed" {
+		t.Fatalf("unexpected status %+v", status)
+	}
+	if report, _ := f.latest(); report == nil || report.Expected != expected.Expected {
+		t.Fatalf("expected previous report to be kept, got %+v", report)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/decred/vspd/internal/feeschedule"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+)
+
+// feePolicyRow describes a single policy of the fee schedule on the admin
+// page.
+type feePolicyRow struct {
+	Name          string
+	AppliesTo     string
+	FeePercentage float64
+}
+
+// ReloadFeeSchedule reads the fee schedule file again and starts using it to
+// price tickets. The current schedule remains in use if the file is invalid.
+func (w *WebAPI) ReloadFeeSchedule() error {
+	w.cfgMtx.RLock()
+	defer w.cfgMtx.RUnlock()
+
+	schedule, err := feeschedule.Load(w.cfg.FeeSchedule, w.cfg.VSPFee, w.cfg.Network.Params)
+	if err != nil {
+		return err
+	}
+
+	w.feeSchedule.Store(schedule)
+
+	w.log.Infof("Fee schedule loaded (tiers=%d, promotions=%d, discounts=%d)",
+		len(schedule.Tiers), len(schedule.Promotions), len(schedule.Discounts))
+
+	return nil
+}
+
+// publishedFeeSchedule returns the current fee schedule in the form published
+// by /vspinfo.
+func (w *WebAPI) publishedFeeSchedule() types.FeeSchedule {
+	schedule := w.feeSchedule.Load()
+
+	published := types.FeeSchedule{
+		DefaultFeePercentage: schedule.DefaultFeePercentage,
+		Tiers:                make([]types.FeeTier, 0, len(schedule.Tiers)),
+		Promotions:           make([]types.FeePromotion, 0, len(schedule.Promotions)),
+		Discounts:            make([]types.FeeDiscount, 0, len(schedule.Discounts)),
+	}
+	for _, t := range schedule.Tiers {
+		published.Tiers = append(published.Tiers, types.FeeTier{
+			Name:           t.Name,
+			MinTicketPrice: int64(t.MinTicketPrice),
+			FeePercentage:  t.FeePercentage,
+		})
+	}
+	for _, p := range schedule.CurrentPromotions(time.Now()) {
+		published.Promotions = append(published.Promotions, types.FeePromotion{
+			Name:          p.Name,
+			Start:         p.Start.Unix(),
+			End:           p.End.Unix(),
+			FeePercentage: p.FeePercentage,
+		})
+	}
+	for _, d := range schedule.Discounts {
+		published.Discounts = append(published.Discounts, types.FeeDiscount{
+			Name:          d.Name,
+			FeePercentage: d.FeePercentage,
+		})
+	}
+
+	return published
+}
+
+// feeScheduleRows describes every policy of the current fee schedule for the
+// admin page.
+func (w *WebAPI) feeScheduleRows() []feePolicyRow {
+	schedule := w.feeSchedule.Load()
+
+	rows := []feePolicyRow{{
+		Name:          feeschedule.DefaultPolicy,
+		AppliesTo:     "Ticket prices below every tier",
+		FeePercentage: schedule.DefaultFeePercentage,
+	}}
+	for _, t := range schedule.Tiers {
+		rows = append(rows, feePolicyRow{
+			Name:          t.Name,
+			AppliesTo:     fmt.Sprintf("Ticket prices from %v", t.MinTicketPrice),
+			FeePercentage: t.FeePercentage,
+		})
+	}
+	for _, p := range schedule.Promotions {
+		rows = append(rows, feePolicyRow{
+			Name:          p.Name,
+			AppliesTo:     fmt.Sprintf("%s until %s", dateTime(p.Start.Unix()), dateTime(p.End.Unix())),
+			FeePercentage: p.FeePercentage,
+		})
+	}
+	for _, d := range schedule.Discounts {
+		rows = append(rows, feePolicyRow{
+			Name:          d.Name,
+			AppliesTo:     fmt.Sprintf("Tickets with one of %d commitment addresses", len(d.Addresses)),
+			FeePercentage: d.FeePercentage,
+		})
+	}
+
+	return rows
+}
+
+// reloadFeeSchedule is the handler for "POST /admin/feeschedule".
+func (w *WebAPI) reloadFeeSchedule(c *gin.Context) {
+	err := w.ReloadFeeSchedule()
+	if err != nil {
+		w.log.Errorf("Failed to reload fee schedule: %v", err)
+		c.String(http.StatusInternalServerError, "Error reloading fee schedule: %v", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin")
+	c.Abort()
+}
@@ -1,10 +1,11 @@
-// Copyright (c) 2021-2025 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package webapi
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
@@ -20,37 +21,45 @@ import (
 // addrMtx protects getNewFeeAddress.
 var addrMtx sync.Mutex
 
-// getNewFeeAddress gets a new address from the address generator, and updates
-// the last used address index in the database. In order to maintain consistency
-// between the internal counter of address generator and the database, this func
-// uses a mutex to ensure it is not run concurrently.
-func (w *WebAPI) getNewFeeAddress() (string, uint32, error) {
+// getNewFeeAddress gets a new address from the address selector, and updates
+// the last used address index of the selected xpub in the database. Returns the
+// address, its index and the ID of the xpub it was derived from. In order to
+// maintain consistency between the internal counters of the address generators
+// and the database, this func uses a mutex to ensure it is not run
+// concurrently.
+func (w *WebAPI) getNewFeeAddress() (string, uint32, uint32, error) {
 	addrMtx.Lock()
 	defer addrMtx.Unlock()
 
-	addr, idx, err := w.addrGen.nextAddress()
+	addr, idx, xPubID, err := w.addrGen.nextAddress()
 	if err != nil {
-		return "", 0, err
+		return "", 0, 0, err
 	}
 
-	err = w.db.SetLastAddressIndex(idx)
+	err = w.db.SetLastAddressIndex(xPubID, idx)
 	if err != nil {
-		return "", 0, err
+		return "", 0, 0, err
 	}
 
-	return addr, idx, nil
+	return addr, idx, xPubID, nil
 }
 
 // getCurrentFee returns the minimum fee amount a client should pay in order to
-// register a ticket with the VSP at the current block height.
-func (w *WebAPI) getCurrentFee(dcrdClient *rpc.DcrdRPC) (dcrutil.Amount, error) {
+// register a ticket with the provided commitment address with the VSP at the
+// current block height, along with the name of the fee schedule policy which
+// set the fee percentage.
+func (w *WebAPI) getCurrentFee(dcrdClient *rpc.DcrdRPC, commitmentAddress string) (dcrutil.Amount, string, error) {
 	bestBlock, err := dcrdClient.GetBestBlockHeader()
 	if err != nil {
-		return 0, err
+		return 0, "", err
 	}
 
 	sDiff := dcrutil.Amount(bestBlock.SBits)
 
+	// Tiers of the fee schedule are selected by the current ticket price,
+	// which is also the price used to calculate the fee amount.
+	feePercentage, policy := w.feeSchedule.Load().Fee(sDiff, commitmentAddress, time.Now())
+
 	// Using a hard-coded amount for relay fee is acceptable here because this
 	// amount is never actually used to construct or broadcast transactions. It
 	// is only used to calculate the fee charged for adding a ticket to the VSP.
@@ -61,12 +70,13 @@ func (w *WebAPI) getCurrentFee(dcrdClient *rpc.DcrdRPC) (dcrutil.Amount, error)
 	isDCP0012Active := w.cfg.Network.DCP12Active(height)
 
 	fee := txrules.StakePoolTicketFee(sDiff, defaultMinRelayTxFee, int32(bestBlock.Height),
-		w.cfg.VSPFee, w.cfg.Network.Params, isDCP0010Active, isDCP0012Active)
+		feePercentage, w.cfg.Network.Params, isDCP0010Active, isDCP0012Active)
 
-	return fee, nil
+	return fee, policy, nil
 }
 
-// feeAddress is the handler for "POST /api/v3/feeaddress".
+// feeAddress is the handler for "POST /api/v3/feeaddress" and
+// "POST /api/v4/feeaddress".
 func (w *WebAPI) feeAddress(c *gin.Context) {
 
 	const funcName = "feeAddress"
@@ -132,7 +142,7 @@ func (w *WebAPI) feeAddress(c *gin.Context) {
 		// If the expiry period has passed we need to issue a new fee.
 		now := time.Now()
 		if ticket.FeeExpired() {
-			newFee, err := w.getCurrentFee(dcrdClient)
+			newFee, policy, err := w.getCurrentFee(dcrdClient, commitmentAddress)
 			if err != nil {
 				w.log.Errorf("%s: getCurrentFee error (ticketHash=%s): %v", funcName, ticket.Hash, err)
 				w.sendError(types.ErrInternalError, c)
@@ -140,6 +150,7 @@ func (w *WebAPI) feeAddress(c *gin.Context) {
 			}
 			ticket.FeeExpiration = now.Add(feeAddressExpiration).Unix()
 			ticket.FeeAmount = int64(newFee)
+			ticket.FeePolicy = policy
 
 			err = w.db.UpdateTicket(ticket)
 			if err != nil {
@@ -148,8 +159,11 @@ func (w *WebAPI) feeAddress(c *gin.Context) {
 				w.sendError(types.ErrInternalError, c)
 				return
 			}
-			w.log.Debugf("%s: Expired fee updated (newFeeAmt=%s, ticketHash=%s)",
-				funcName, newFee, ticket.Hash)
+			w.log.Debugf("%s: Expired fee updated (newFeeAmt=%s, feePolicy=%s, ticketHash=%s)",
+				funcName, newFee, policy, ticket.Hash)
+			database.AddTicketEvent(w.db, w.log, ticket.Hash, database.EventFeeRepriced,
+				fmt.Sprintf("Fee amount %s (policy %s)", newFee, policy))
+			w.feed.Publish(ticket.Hash)
 		}
 		w.sendJSONResponse(types.FeeAddressResponse{
 			Timestamp:  now.Unix(),
@@ -165,14 +179,14 @@ func (w *WebAPI) feeAddress(c *gin.Context) {
 	// Beyond this point we are processing a new ticket which the VSP has not
 	// seen before.
 
-	fee, err := w.getCurrentFee(dcrdClient)
+	fee, feePolicy, err := w.getCurrentFee(dcrdClient, commitmentAddress)
 	if err != nil {
 		w.log.Errorf("%s: getCurrentFee error (ticketHash=%s): %v", funcName, ticketHash, err)
 		w.sendError(types.ErrInternalError, c)
 		return
 	}
 
-	newAddress, newAddressIdx, err := w.getNewFeeAddress()
+	newAddress, newAddressIdx, newAddressXPubID, err := w.getNewFeeAddress()
 	if err != nil {
 		w.log.Errorf("%s: getNewFeeAddress error (ticketHash=%s): %v", funcName, ticketHash, err)
 		w.sendError(types.ErrInternalError, c)
@@ -196,11 +210,12 @@ func (w *WebAPI) feeAddress(c *gin.Context) {
 		PurchaseHeight:    purchaseHeight,
 		CommitmentAddress: commitmentAddress,
 		FeeAddressIndex:   newAddressIdx,
-		FeeAddressXPubID:  w.addrGen.xPubID(),
+		FeeAddressXPubID:  newAddressXPubID,
 		FeeAddress:        newAddress,
 		Confirmed:         confirmed,
 		FeeAmount:         int64(fee),
 		FeeExpiration:     expire,
+		FeePolicy:         feePolicy,
 		FeeTxStatus:       database.NoFee,
 	}
 
@@ -212,8 +227,11 @@ func (w *WebAPI) feeAddress(c *gin.Context) {
 	}
 
 	w.log.Debugf("%s: Fee address created for new ticket: (tktConfirmed=%t, feeAddrIdx=%d, "+
-		"feeAddr=%s, feeAmt=%s, ticketHash=%s)",
-		funcName, confirmed, newAddressIdx, newAddress, fee, ticketHash)
+		"feeAddr=%s, feeAmt=%s, feePolicy=%s, ticketHash=%s)",
+		funcName, confirmed, newAddressIdx, newAddress, fee, feePolicy, ticketHash)
+	database.AddTicketEvent(w.db, w.log, ticketHash, database.EventFeeAddress,
+		fmt.Sprintf("Fee amount %s (policy %s), fee address %s", fee, feePolicy, newAddress))
+	w.feed.Publish(ticketHash)
 
 	w.sendJSONResponse(types.FeeAddressResponse{
 		Timestamp:  now.Unix(),
@@ -97,7 +97,7 @@ func validPolicyOption(policy string) error {
 }
 
 func validateSignature(hash, commitmentAddress, signature, message string,
-	db *database.VspDatabase, network *config.Network) error {
+	db database.Database, network *config.Network) error {
 
 	firstErr := dcrutil.VerifyMessage(commitmentAddress, signature, message, network)
 	if firstErr != nil {
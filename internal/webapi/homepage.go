@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2022 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -15,6 +15,6 @@ func (w *WebAPI) homepage(c *gin.Context) {
 
 	c.HTML(http.StatusOK, "homepage.html", gin.H{
 		"WebApiCache": cacheData,
-		"WebApiCfg":   w.cfg,
+		"WebApiCfg":   w.config(),
 	})
 }
@@ -0,0 +1,210 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+)
+
+// maintenanceFormLayout is the format of the times submitted by the
+// maintenance window form on the admin page, which are in UTC.
+const maintenanceFormLayout = "2006-01-02T15:04"
+
+// maintenanceWindow is a maintenance window along with its database ID.
+type maintenanceWindow struct {
+	ID uint64
+	database.MaintenanceWindow
+}
+
+// maintenanceSchedule is every stored maintenance window, sorted by start time.
+// Schedules are never modified once loaded, so they can be shared between
+// goroutines.
+type maintenanceSchedule []maintenanceWindow
+
+// active returns the window in progress at time now, if there is one. If
+// several windows overlap, the one which ends last is returned.
+func (s maintenanceSchedule) active(now time.Time) (maintenanceWindow, bool) {
+	var active maintenanceWindow
+	var found bool
+	for _, mw := range s {
+		if now.Unix() >= mw.Start && now.Unix() < mw.End {
+			if !found || mw.End > active.End {
+				active, found = mw, true
+			}
+		}
+	}
+	return active, found
+}
+
+// upcoming returns the windows which have not ended by time now.
+func (s maintenanceSchedule) upcoming(now time.Time) maintenanceSchedule {
+	upcoming := make(maintenanceSchedule, 0, len(s))
+	for _, mw := range s {
+		if now.Unix() < mw.End {
+			upcoming = append(upcoming, mw)
+		}
+	}
+	return upcoming
+}
+
+// loadMaintenanceSchedule reads every maintenance window from the database and
+// starts enforcing them.
+func (w *WebAPI) loadMaintenanceSchedule() error {
+	w.maintenanceMtx.Lock()
+	defer w.maintenanceMtx.Unlock()
+
+	return w.reloadMaintenanceSchedule()
+}
+
+// reloadMaintenanceSchedule is loadMaintenanceSchedule for callers which
+// already hold maintenanceMtx.
+func (w *WebAPI) reloadMaintenanceSchedule() error {
+	stored, err := w.db.MaintenanceWindows()
+	if err != nil {
+		return err
+	}
+
+	schedule := make(maintenanceSchedule, 0, len(stored))
+	for id, mw := range stored {
+		schedule = append(schedule, maintenanceWindow{ID: id, MaintenanceWindow: mw})
+	}
+	slices.SortFunc(schedule, func(a, b maintenanceWindow) int {
+		return cmp.Or(cmp.Compare(a.Start, b.Start), cmp.Compare(a.ID, b.ID))
+	})
+
+	w.maintenance.Store(&schedule)
+
+	return nil
+}
+
+// AddMaintenanceWindow schedules a maintenance window. New tickets are rejected
+// from the start until the end of the window, and the window is advertised by
+// /vspinfo until it ends.
+func (w *WebAPI) AddMaintenanceWindow(mw database.MaintenanceWindow) error {
+	if mw.End <= mw.Start {
+		return errors.New("end must be after start")
+	}
+	if mw.End <= time.Now().Unix() {
+		return errors.New("end must be in the future")
+	}
+
+	w.maintenanceMtx.Lock()
+	defer w.maintenanceMtx.Unlock()
+
+	_, err := w.db.AddMaintenanceWindow(mw)
+	if err != nil {
+		return err
+	}
+
+	w.log.Infof("Maintenance window scheduled from %s until %s (message=%q)",
+		dateTime(mw.Start), dateTime(mw.End), mw.Message)
+
+	return w.reloadMaintenanceSchedule()
+}
+
+// DeleteMaintenanceWindow cancels the maintenance window with the provided ID.
+func (w *WebAPI) DeleteMaintenanceWindow(id uint64) error {
+	w.maintenanceMtx.Lock()
+	defer w.maintenanceMtx.Unlock()
+
+	err := w.db.DeleteMaintenanceWindow(id)
+	if err != nil {
+		return err
+	}
+
+	w.log.Infof("Maintenance window %d deleted", id)
+
+	return w.reloadMaintenanceSchedule()
+}
+
+// publishedMaintenance returns the maintenance windows in the form published
+// by /vspinfo.
+func (w *WebAPI) publishedMaintenance() []types.MaintenanceWindow {
+	upcoming := w.maintenance.Load().upcoming(time.Now())
+
+	published := make([]types.MaintenanceWindow, 0, len(upcoming))
+	for _, mw := range upcoming {
+		published = append(published, types.MaintenanceWindow{
+			Start:   mw.Start,
+			End:     mw.End,
+			Message: mw.Message,
+		})
+	}
+
+	return published
+}
+
+// vspNotInMaintenance rejects requests with ErrVspMaintenance while a
+// maintenance window is in progress.
+func (w *WebAPI) vspNotInMaintenance(c *gin.Context) {
+	mw, ok := w.maintenance.Load().active(time.Now())
+	if !ok {
+		return
+	}
+
+	msg := fmt.Sprintf("%s until %s", types.ErrVspMaintenance.DefaultMessage(),
+		time.Unix(mw.End, 0).UTC().Format(time.RFC3339))
+	if mw.Message != "" {
+		msg += ": " + mw.Message
+	}
+	w.sendErrorWithDetails(msg, types.ErrVspMaintenance, &types.ErrorDetails{RetryAfter: mw.End}, c)
+}
+
+// addMaintenanceWindow is the handler for "POST /admin/maintenance".
+func (w *WebAPI) addMaintenanceWindow(c *gin.Context) {
+	start, err := time.Parse(maintenanceFormLayout, c.PostForm("start"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid start time")
+		return
+	}
+	end, err := time.Parse(maintenanceFormLayout, c.PostForm("end"))
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid end time")
+		return
+	}
+
+	err = w.AddMaintenanceWindow(database.MaintenanceWindow{
+		Start:   start.Unix(),
+		End:     end.Unix(),
+		Message: c.PostForm("message"),
+	})
+	if err != nil {
+		w.log.Errorf("Failed to add maintenance window: %v", err)
+		c.String(http.StatusBadRequest, "Error adding maintenance window: %v", err)
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin")
+	c.Abort()
+}
+
+// deleteMaintenanceWindow is the handler for "POST /admin/maintenance/delete".
+func (w *WebAPI) deleteMaintenanceWindow(c *gin.Context) {
+	id, err := strconv.ParseUint(c.PostForm("id"), 10, 64)
+	if err != nil {
+		c.String(http.StatusBadRequest, "Invalid maintenance window ID")
+		return
+	}
+
+	err = w.DeleteMaintenanceWindow(id)
+	if err != nil {
+		w.log.Errorf("Failed to delete maintenance window: %v", err)
+		c.String(http.StatusInternalServerError, "Error deleting maintenance window")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin")
+	c.Abort()
+}
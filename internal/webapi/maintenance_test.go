@@ -0,0 +1,125 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/decred/vspd/database"
+)
+
+func TestMaintenanceSchedule(t *testing.T) {
+	window := func(id uint64, start, end int64) maintenanceWindow {
+		return maintenanceWindow{
+			ID:                id,
+			MaintenanceWindow: database.MaintenanceWindow{Start: start, End: end},
+		}
+	}
+
+	schedule := maintenanceSchedule{
+		window(1, 1000, 2000),
+		window(2, 1500, 2500),
+		window(3, 4000, 5000),
+	}
+
+	tests := map[string]struct {
+		now         int64
+		activeID    uint64
+		active      bool
+		upcomingIDs []uint64
+	}{
+		"before every window": {
+			now:         500,
+			upcomingIDs: []uint64{1, 2, 3},
+		},
+		"window start is included": {
+			now:         1000,
+			activeID:    1,
+			active:      true,
+			upcomingIDs: []uint64{1, 2, 3},
+		},
+		"overlapping windows": {
+			now:         1800,
+			activeID:    2,
+			active:      true,
+			upcomingIDs: []uint64{1, 2, 3},
+		},
+		"window end is excluded": {
+			now:         2000,
+			activeID:    2,
+			active:      true,
+			upcomingIDs: []uint64{2, 3},
+		},
+		"between windows": {
+			now:         3000,
+			upcomingIDs: []uint64{3},
+		},
+		"after every window": {
+			now: 5000,
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			now := time.Unix(test.now, 0)
+
+			active, ok := schedule.active(now)
+			if ok != test.active || active.ID != test.activeID {
+				t.Fatalf("expected active window %d (%v), got %d (%v)",
+					test.activeID, test.active, active.ID, ok)
+			}
+
+			upcoming := schedule.upcoming(now)
+			if len(upcoming) != len(test.upcomingIDs) {
+				t.Fatalf("expected %d upcoming windows, got %d",
+					len(test.upcomingIDs), len(upcoming))
+			}
+			for i, mw := range upcoming {
+				if mw.ID != test.upcomingIDs[i] {
+					t.Fatalf("expected upcoming window %d to be %d, got %d",
+						i, test.upcomingIDs[i], mw.ID)
+				}
+			}
+		})
+	}
+}
+
+// TestConcurrentMaintenanceChanges ensures the enforced schedule contains every
+// stored window after windows are added concurrently.
+func TestConcurrentMaintenanceChanges(t *testing.T) {
+	const count = 20
+	start := time.Now().Add(time.Hour).Unix()
+
+	var wg sync.WaitGroup
+	for i := range count {
+		wg.Go(func() {
+			err := api.AddMaintenanceWindow(database.MaintenanceWindow{
+				Start: start + int64(i),
+				End:   start + 3600,
+			})
+			if err != nil {
+				t.Errorf("error adding maintenance window: %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	schedule := *api.maintenance.Load()
+	if len(schedule) != count {
+		t.Fatalf("expected %d windows in schedule, got %d", count, len(schedule))
+	}
+
+	for _, mw := range schedule {
+		err := api.DeleteMaintenanceWindow(mw.ID)
+		if err != nil {
+			t.Fatalf("error deleting maintenance window: %v", err)
+		}
+	}
+	if schedule := *api.maintenance.Load(); len(schedule) != 0 {
+		t.Fatalf("expected empty schedule, got %d windows", len(schedule))
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/decred/vspd/internal/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// withMetrics middleware records the number of requests handled by each
+// endpoint, and how long each request took to handle.
+func (w *WebAPI) withMetrics(c *gin.Context) {
+	start := time.Now()
+
+	c.Next()
+
+	path := c.FullPath()
+	w.metrics.APIRequests.Inc(path, strconv.Itoa(c.Writer.Status()))
+	w.metrics.APIRequestDuration.Observe(time.Since(start).Seconds(), path)
+}
+
+// boolToFloat converts a bool into a value suitable for a Prometheus gauge.
+func boolToFloat(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// metricsHandler is the handler for "GET /metrics". It returns the current
+// state of vspd in the Prometheus text exposition format.
+func (w *WebAPI) metricsHandler(c *gin.Context) {
+	var buf bytes.Buffer
+
+	type gauge struct {
+		name  string
+		help  string
+		value float64
+	}
+
+	var gauges []gauge
+
+	// Ticket stats are only available once the cache has been initialized.
+	cacheData := w.cache.getData()
+	if cacheData.Initialized {
+		gauges = append(gauges,
+			gauge{"vspd_tickets_voting", "Number of tickets currently voting.", float64(cacheData.Voting)},
+			gauge{"vspd_tickets_voted", "Number of tickets which have voted.", float64(cacheData.Voted)},
+			gauge{"vspd_tickets_expired", "Number of tickets which have expired.", float64(cacheData.Expired)},
+			gauge{"vspd_tickets_missed", "Number of tickets which have been missed.", float64(cacheData.Missed)},
+			gauge{"vspd_revenue_lifetime_atoms", "Fee revenue earned over the lifetime of the VSP.", float64(cacheData.RevenueLifetime)},
+			gauge{"vspd_revenue_28days_atoms", "Fee revenue earned in the previous 28 days.", float64(cacheData.Revenue28Days)},
+			gauge{"vspd_revenue_24hours_atoms", "Fee revenue earned in the previous 24 hours.", float64(cacheData.Revenue24Hours)},
+			gauge{"vspd_block_height", "Best block height at the last cache update.", float64(cacheData.BlockHeight)},
+			gauge{"vspd_network_proportion", "Proportion of the network ticket pool voting with this VSP.", float64(cacheData.NetworkProportion)},
+			gauge{"vspd_cache_update_timestamp_seconds", "Unix time of the last cache update.", float64(cacheData.UpdateTime.Unix())},
+		)
+	}
+
+	dcrd := w.dcrdStatus(c)
+	gauges = append(gauges,
+		gauge{"vspd_dcrd_connected", "Whether vspd is connected to dcrd.", boolToFloat(dcrd.Connected)},
+		gauge{"vspd_dcrd_best_block_height", "Best block height reported by dcrd.", float64(dcrd.BestBlockHeight)},
+	)
+
+	for _, g := range gauges {
+		err := metrics.WriteGauge(&buf, g.name, g.help, g.value)
+		if err != nil {
+			w.log.Errorf("Error writing metrics: %v", err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Write one value per voting wallet for each wallet status.
+	wallets := w.walletStatus(c)
+	walletGauges := []struct {
+		name  string
+		help  string
+		value func(walletStatus) float64
+	}{
+		{"vspd_wallet_connected", "Whether vspd is connected to the voting wallet.",
+			func(s walletStatus) float64 { return boolToFloat(s.Connected) }},
+		{"vspd_wallet_daemon_connected", "Whether the voting wallet is connected to its dcrd.",
+			func(s walletStatus) float64 { return boolToFloat(s.DaemonConnected) }},
+		{"vspd_wallet_unlocked", "Whether the voting wallet is unlocked.",
+			func(s walletStatus) float64 { return boolToFloat(s.Unlocked) }},
+		{"vspd_wallet_voting", "Whether the voting wallet has voting enabled.",
+			func(s walletStatus) float64 { return boolToFloat(s.Voting) }},
+		{"vspd_wallet_vote_version", "Vote version of the voting wallet.",
+			func(s walletStatus) float64 { return float64(s.VoteVersion) }},
+		{"vspd_wallet_best_block_height", "Best block height reported by the voting wallet.",
+			func(s walletStatus) float64 { return float64(s.BestBlockHeight) }},
+	}
+	for _, g := range walletGauges {
+		values := make(map[string]float64, len(wallets))
+		for host, status := range wallets {
+			values[metrics.LabelKey(host)] = g.value(status)
+		}
+		err := metrics.WriteGaugeVec(&buf, g.name, g.help, []string{"host"}, values)
+		if err != nil {
+			w.log.Errorf("Error writing metrics: %v", err)
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	err := w.metrics.Write(&buf)
+	if err != nil {
+		w.log.Errorf("Error writing metrics: %v", err)
+		c.Status(http.StatusInternalServerError)
+		return
+	}
+
+	c.Data(http.StatusOK, metrics.ContentType, buf.Bytes())
+}
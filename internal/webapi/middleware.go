@@ -132,22 +132,37 @@ func (w *WebAPI) csrf() gin.HandlerFunc {
 }
 
 // requireAdmin will only allow the request to proceed if the current session is
-// authenticated as an admin, otherwise it will render the login template.
+// authenticated as an admin with the current admin password, otherwise it will
+// render the login template.
 func (w *WebAPI) requireAdmin(c *gin.Context) {
 	cacheData := c.MustGet(cacheKey).(cacheData)
 	session := c.MustGet(sessionKey).(*sessions.Session)
-	admin := session.Values["admin"]
 
-	if admin == nil {
+	if !w.isAdminSession(session.Values["admin"]) {
 		c.HTML(http.StatusUnauthorized, "login.html", gin.H{
 			"WebApiCache": cacheData,
-			"WebApiCfg":   w.cfg,
+			"WebApiCfg":   w.config(),
 		})
 		c.Abort()
 		return
 	}
 }
 
+// requireBasicAuth will only allow the request to proceed if it includes HTTP
+// Basic Auth credentials for the admin user, otherwise it responds with 401
+// Unauthorized. The password is checked against the current config so that it
+// can be changed while vspd is running.
+func (w *WebAPI) requireBasicAuth(c *gin.Context) {
+	user, password, ok := c.Request.BasicAuth()
+	if !ok || user != "admin" || !w.isAdminPass(password) {
+		c.Header("WWW-Authenticate", `Basic realm="Authorization Required"`)
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	c.Set(gin.AuthUserKey, user)
+}
+
 // withDcrdClient middleware adds a dcrd client to the request context for
 // downstream handlers to make use of.
 func (w *WebAPI) withDcrdClient(dcrd rpc.DcrdConnect) gin.HandlerFunc {
@@ -193,7 +208,7 @@ func drainAndReplaceBody(req *http.Request) ([]byte, error) {
 }
 
 func (w *WebAPI) vspMustBeOpen(c *gin.Context) {
-	if w.cfg.VspClosed {
+	if w.config().VspClosed {
 		w.sendError(types.ErrVspClosed, c)
 		return
 	}
@@ -388,7 +403,8 @@ func (w *WebAPI) broadcastTicket() gin.HandlerFunc {
 // commitment address for the ticket is retrieved from the database if it is
 // known, or it is retrieved from the chain if not.
 // The middleware errors out if the VSP-Client-Signature header of the request
-// does not contain the request body signed with the commitment address.
+// does not contain the request body, or the request envelope of /api/v4
+// requests, signed with the commitment address.
 // Ticket information is added to the request context for downstream handlers to
 // use.
 func (w *WebAPI) vspAuth(c *gin.Context) {
@@ -494,7 +510,7 @@ func (w *WebAPI) vspAuth(c *gin.Context) {
 	}
 
 	// Validate request signature to ensure ticket ownership.
-	err = validateSignature(hash, commitmentAddress, signature, string(reqBytes), w.db, w.cfg.Network)
+	err = validateSignature(hash, commitmentAddress, signature, string(signedRequest(c)), w.db, w.cfg.Network)
 	if err != nil {
 		w.log.Errorf("%s: Couldn't validate signature (clientIP=%s, ticketHash=%s): %v",
 			funcName, c.ClientIP(), hash, err)
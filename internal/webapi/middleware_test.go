@@ -1,15 +1,20 @@
-// Copyright (c) 2023 The Decred developers
+// Copyright (c) 2023-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
 package webapi
 
 import (
+	"crypto/sha256"
+	"html/template"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 
+	"github.com/decred/vspd/internal/config"
+	"github.com/gin-gonic/gin"
 	"github.com/gorilla/sessions"
 )
 
@@ -65,3 +70,143 @@ func TestCookieSecretError(t *testing.T) {
 			invalidCookieErr, err.Error())
 	}
 }
+
+// TestRequireBasicAuth ensures that Basic Auth credentials are checked against
+// the current admin password, which can be changed by Reconfigure.
+func TestRequireBasicAuth(t *testing.T) {
+	api := &WebAPI{
+		cfg: Config{
+			Network:   &config.MainNet,
+			VSPFee:    3,
+			AdminPass: "oldpass",
+		},
+		adminPassHash: sha256.Sum256([]byte("oldpass")),
+	}
+
+	router := gin.New()
+	router.GET("/", api.requireBasicAuth, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	status := func(user, password string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.SetBasicAuth(user, password)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := status("admin", "oldpass"); code != http.StatusOK {
+		t.Fatalf("expected status %d with correct password, got %d", http.StatusOK, code)
+	}
+	if code := status("notadmin", "oldpass"); code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d with wrong user, got %d", http.StatusUnauthorized, code)
+	}
+
+	err := api.Reconfigure(Config{VSPFee: 3, AdminPass: "newpass"})
+	if err != nil {
+		t.Fatalf("error reconfiguring: %v", err)
+	}
+
+	if code := status("admin", "oldpass"); code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d with replaced password, got %d", http.StatusUnauthorized, code)
+	}
+	if code := status("admin", "newpass"); code != http.StatusOK {
+		t.Fatalf("expected status %d with new password, got %d", http.StatusOK, code)
+	}
+
+	// Nothing should change if the new config is invalid.
+	err = api.Reconfigure(Config{VSPFee: 0, AdminPass: "otherpass"})
+	if err == nil {
+		t.Fatal("expected error reconfiguring with invalid fee")
+	}
+	if code := status("admin", "newpass"); code != http.StatusOK {
+		t.Fatalf("expected status %d after failed reconfigure, got %d", http.StatusOK, code)
+	}
+}
+
+// TestRequireAdmin ensures that admin sessions are only accepted while the
+// admin password they were created with is current.
+func TestRequireAdmin(t *testing.T) {
+	cookieSecret := []byte("cookie secret")
+	api := &WebAPI{
+		cfg: Config{
+			Network:   &config.MainNet,
+			VSPFee:    3,
+			AdminPass: "oldpass",
+		},
+		cookieSecret:  cookieSecret,
+		adminPassHash: sha256.Sum256([]byte("oldpass")),
+		adminSession:  adminSessionToken(cookieSecret, "oldpass"),
+	}
+
+	router := gin.New()
+	router.SetHTMLTemplate(template.Must(template.New("login.html").Parse("login")))
+	router.Use(func(c *gin.Context) {
+		c.Set(cacheKey, cacheData{})
+	}, api.withSession(sessions.NewCookieStore(cookieSecret)))
+	router.POST("/admin", api.adminLogin)
+	router.GET("/admin", api.requireAdmin, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	login := func(password string) *http.Cookie {
+		t.Helper()
+		form := url.Values{"password": {password}}
+		req, err := http.NewRequest(http.MethodPost, "/admin", strings.NewReader(form.Encode()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusFound {
+			t.Fatalf("expected status %d after login, got %d", http.StatusFound, w.Code)
+		}
+		cookies := w.Result().Cookies()
+		if len(cookies) != 1 {
+			t.Fatalf("expected 1 cookie after login, got %d", len(cookies))
+		}
+		return cookies[0]
+	}
+
+	status := func(cookie *http.Cookie) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, "/admin", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := status(nil); code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d without session, got %d", http.StatusUnauthorized, code)
+	}
+
+	oldSession := login("oldpass")
+	if code := status(oldSession); code != http.StatusOK {
+		t.Fatalf("expected status %d with admin session, got %d", http.StatusOK, code)
+	}
+
+	err := api.Reconfigure(Config{VSPFee: 3, AdminPass: "newpass"})
+	if err != nil {
+		t.Fatalf("error reconfiguring: %v", err)
+	}
+
+	if code := status(oldSession); code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d with session from replaced password, got %d",
+			http.StatusUnauthorized, code)
+	}
+	if code := status(login("newpass")); code != http.StatusOK {
+		t.Fatalf("expected status %d with new admin session, got %d", http.StatusOK, code)
+	}
+}
@@ -0,0 +1,314 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/decred/vspd/internal/openapi"
+	"github.com/decred/vspd/types/v3"
+	"github.com/decred/vspd/types/v3/apiv4"
+	"github.com/gin-gonic/gin"
+)
+
+// apiRoute describes a route of the API in its OpenAPI document.
+type apiRoute struct {
+	method  string
+	path    string
+	summary string
+	// signed routes require the VSP-Client-Signature header.
+	signed bool
+	// request is nil for routes without a request body.
+	request any
+	// response is nil for the route which serves the OpenAPI document itself,
+	// which is not signed.
+	response any
+	// errors are the codes of every error the route may respond with.
+	errors []types.ErrorCode
+}
+
+// eventStream is the response of a route which sends a stream of server-sent
+// events. event is the type of the data of each event.
+type eventStream struct {
+	event any
+}
+
+// The errors which may be returned by each route. Errors returned by
+// middleware are included.
+var (
+	setAltSignAddrErrors = []types.ErrorCode{types.ErrBadRequest, types.ErrInternalError,
+		types.ErrVspClosed, types.ErrCannotBroadcastTicket, types.ErrInvalidTicket,
+		types.ErrBadSignature, types.ErrTicketCannotVote}
+	feeAddressErrors = []types.ErrorCode{types.ErrBadRequest, types.ErrInternalError,
+		types.ErrVspClosed, types.ErrVspMaintenance, types.ErrCannotBroadcastTicket,
+		types.ErrInvalidTicket, types.ErrBadSignature, types.ErrFeeAlreadyReceived,
+		types.ErrTicketCannotVote}
+	ticketStatusErrors = []types.ErrorCode{types.ErrBadRequest, types.ErrInternalError,
+		types.ErrInvalidTicket, types.ErrBadSignature, types.ErrUnknownTicket}
+	payFeeErrors = []types.ErrorCode{types.ErrBadRequest, types.ErrInternalError,
+		types.ErrVspClosed, types.ErrVspMaintenance, types.ErrInvalidTicket,
+		types.ErrBadSignature, types.ErrUnknownTicket, types.ErrFeeAlreadyReceived,
+		types.ErrInvalidFeeTx, types.ErrFeeTooSmall, types.ErrFeeExpired,
+		types.ErrTicketCannotVote, types.ErrInvalidPrivKey, types.ErrCannotBroadcastFee,
+		types.ErrCannotBroadcastFeeUnknownOutputs}
+	setVoteChoicesErrors = []types.ErrorCode{types.ErrBadRequest, types.ErrInternalError,
+		types.ErrInvalidTicket, types.ErrBadSignature, types.ErrUnknownTicket,
+		types.ErrFeeNotReceived, types.ErrTicketCannotVote, types.ErrInvalidTimestamp,
+		types.ErrInvalidVoteChoices}
+	ticketStatusBatchErrors = []types.ErrorCode{types.ErrBadRequest, types.ErrInternalError,
+		types.ErrBadSignature, types.ErrInvalidTimestamp}
+)
+
+// apiRoutes are the routes of each version of the API.
+var apiRoutes = map[int][]apiRoute{
+	3: {
+		{http.MethodGet, "/openapi.json", "Get the OpenAPI document describing this API",
+			false, nil, nil, nil},
+		{http.MethodGet, "/vspinfo", "Get information about the VSP",
+			false, nil, types.VspInfoResponse{}, nil},
+		{http.MethodPost, "/setaltsignaddr", "Set an alternate signing address for a ticket",
+			true, types.SetAltSignAddrRequest{}, types.SetAltSignAddrResponse{}, setAltSignAddrErrors},
+		{http.MethodPost, "/feeaddress", "Register a ticket and request its fee address and amount",
+			true, types.FeeAddressRequest{}, types.FeeAddressResponse{}, feeAddressErrors},
+		{http.MethodPost, "/ticketstatus", "Get the status of a ticket",
+			true, types.TicketStatusRequest{}, types.TicketStatusResponse{}, ticketStatusErrors},
+		{http.MethodPost, "/ticketstatusbatch", "Get the status of several tickets",
+			true, types.TicketStatusBatchRequest{}, types.TicketStatusBatchResponse{},
+			ticketStatusBatchErrors},
+		{http.MethodPost, "/ticketstatusstream", "Stream the status of several tickets as they change",
+			true, types.TicketStatusBatchRequest{}, eventStream{types.TicketStatusStreamEvent{}},
+			ticketStatusBatchErrors},
+		{http.MethodPost, "/payfee", "Pay the fee of a ticket and provide its voting key",
+			true, types.PayFeeRequest{}, types.PayFeeResponse{}, payFeeErrors},
+		{http.MethodPost, "/setvotechoices", "Update the vote choices of a ticket",
+			true, types.SetVoteChoicesRequest{}, types.SetVoteChoicesResponse{}, setVoteChoicesErrors},
+		{http.MethodPost, "/setvotechoicesbatch", "Update the vote choices of several tickets",
+			false, types.SetVoteChoicesBatchRequest{}, types.SetVoteChoicesBatchResponse{},
+			[]types.ErrorCode{types.ErrBadRequest, types.ErrInternalError}},
+	},
+	4: {
+		{http.MethodGet, "/openapi.json", "Get the OpenAPI document describing this API",
+			false, nil, nil, nil},
+		{http.MethodGet, "/vspinfo", "Get information about the VSP",
+			false, nil, types.VspInfoResponse{}, nil},
+		{http.MethodPost, "/setaltsignaddr", "Set an alternate signing address for a ticket",
+			true, apiv4.SetAltSignAddrRequest{}, apiv4.SetAltSignAddrResponse{}, setAltSignAddrErrors},
+		{http.MethodPost, "/feeaddress", "Register a ticket and request its fee address and amount",
+			true, apiv4.FeeAddressRequest{}, apiv4.FeeAddressResponse{}, feeAddressErrors},
+		{http.MethodPost, "/ticketstatus", "Get the status of a ticket",
+			true, apiv4.TicketStatusRequest{}, apiv4.TicketStatusResponse{}, ticketStatusErrors},
+		{http.MethodPost, "/payfee", "Pay the fee of a ticket and provide its voting key",
+			true, apiv4.PayFeeRequest{}, apiv4.PayFeeResponse{}, payFeeErrors},
+		{http.MethodPost, "/setvotechoices", "Update the vote choices of a ticket",
+			true, apiv4.SetVoteChoicesRequest{}, apiv4.SetVoteChoicesResponse{}, setVoteChoicesErrors},
+	},
+}
+
+// apiDescriptions introduce the OpenAPI document of each version of the API.
+var apiDescriptions = map[int]string{
+	3: "Requests which reference a ticket are signed by the commitment address or " +
+		"alternate signing address of the ticket. Every JSON response is signed by the VSP.",
+	4: "Every signed request wraps its body in a RequestEnvelope, and every response " +
+		"wraps its body or error in a ResponseEnvelope. The whole envelope is signed.",
+}
+
+// errorCodeSchema describes every error code along with its default message.
+func errorCodeSchema() *openapi.Schema {
+	s := &openapi.Schema{Type: "integer", Format: "int64"}
+
+	unknown := types.ErrorCode(-1).DefaultMessage()
+	var lines []string
+	for code := types.ErrBadRequest; code.DefaultMessage() != unknown; code++ {
+		s.Enum = append(s.Enum, int64(code))
+		lines = append(lines, fmt.Sprintf("%d: %s", code, code.DefaultMessage()))
+	}
+	s.Description = strings.Join(lines, "\n")
+
+	return s
+}
+
+// apiSpec generates the OpenAPI document describing a version of the API. The
+// requests and responses of version 4 are wrapped in envelopes.
+func apiSpec(version int) (*openapi.Document, error) {
+	routes, ok := apiRoutes[version]
+	if !ok {
+		return nil, fmt.Errorf("unknown API version %d", version)
+	}
+
+	g := openapi.NewGenerator(openapi.Info{
+		Title:       "vspd API",
+		Description: apiDescriptions[version],
+		Version:     strconv.Itoa(version),
+	})
+
+	err := g.Define(types.ErrorCode(0), errorCodeSchema())
+	if err != nil {
+		return nil, err
+	}
+	errorResponse, err := g.Schema(types.ErrorResponse{})
+	if err != nil {
+		return nil, err
+	}
+
+	var requestEnvelope, responseEnvelope *openapi.Schema
+	useEnvelopes := version >= 4
+	if useEnvelopes {
+		requestEnvelope, err = g.Schema(apiv4.RequestEnvelope{})
+		if err != nil {
+			return nil, err
+		}
+		responseEnvelope, err = g.Schema(apiv4.ResponseEnvelope{})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// body returns the schema of a request or response body, wrapped in env
+	// if envelopes are used.
+	body := func(env *openapi.Schema, v any) (*openapi.Schema, error) {
+		s, err := g.Schema(v)
+		if err != nil || !useEnvelopes {
+			return s, err
+		}
+		return &openapi.Schema{AllOf: []*openapi.Schema{env, {
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"body": s},
+			Required:   []string{"body"},
+		}}}, nil
+	}
+
+	// errorBody returns the schema of an error response with one of codes.
+	errorBody := func(codes []types.ErrorCode) *openapi.Schema {
+		enum := make([]any, 0, len(codes))
+		for _, code := range codes {
+			enum = append(enum, int64(code))
+		}
+		s := &openapi.Schema{AllOf: []*openapi.Schema{errorResponse, {
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"code": {Enum: enum}},
+		}}}
+		if !useEnvelopes {
+			return s
+		}
+		return &openapi.Schema{AllOf: []*openapi.Schema{responseEnvelope, {
+			Type:       "object",
+			Properties: map[string]*openapi.Schema{"error": s},
+			Required:   []string{"error"},
+		}}}
+	}
+
+	serverSignature := map[string]*openapi.Header{
+		"VSP-Server-Signature": {
+			Description: "Base64 encoded ed25519 signature of the response body by the VSP.",
+			Schema:      &openapi.Schema{Type: "string", Format: "byte"},
+		},
+	}
+
+	for _, route := range routes {
+		op := &openapi.Operation{
+			OperationID: strings.TrimPrefix(route.path, "/"),
+			Summary:     route.summary,
+			Responses:   make(map[string]*openapi.Response),
+		}
+
+		if route.signed {
+			signed := "request body"
+			if useEnvelopes {
+				signed = "request envelope"
+			}
+			op.Parameters = []openapi.Parameter{{
+				Name: "VSP-Client-Signature",
+				In:   "header",
+				Description: fmt.Sprintf("Base64 encoded signature of the %s by the commitment "+
+					"address or alternate signing address of the ticket.", signed),
+				Required: true,
+				Schema:   &openapi.Schema{Type: "string"},
+			}}
+		}
+
+		if route.request != nil {
+			s, err := body(requestEnvelope, route.request)
+			if err != nil {
+				return nil, err
+			}
+			op.RequestBody = &openapi.RequestBody{Required: true, Content: openapi.JSON(s)}
+		}
+
+		if route.response == nil {
+			op.Responses["200"] = &openapi.Response{
+				Description: "Success",
+				Content:     openapi.JSON(&openapi.Schema{Type: "object"}),
+			}
+		} else if stream, ok := route.response.(eventStream); ok {
+			s, err := g.Schema(stream.event)
+			if err != nil {
+				return nil, err
+			}
+			op.Responses["200"] = &openapi.Response{
+				Description: "Server-sent events, each with JSON data described by the schema.",
+				Content:     map[string]*openapi.MediaType{"text/event-stream": {Schema: s}},
+			}
+		} else {
+			s, err := body(responseEnvelope, route.response)
+			if err != nil {
+				return nil, err
+			}
+			op.Responses["200"] = &openapi.Response{
+				Description: "Success",
+				Headers:     serverSignature,
+				Content:     openapi.JSON(s),
+			}
+		}
+
+		// Every signed request may be rejected because of its envelope.
+		codes := route.errors
+		if useEnvelopes && route.signed && !slices.Contains(codes, types.ErrInvalidTimestamp) {
+			codes = append(slices.Clone(codes), types.ErrInvalidTimestamp)
+		}
+
+		// Describe the errors of each HTTP status.
+		byStatus := make(map[int][]types.ErrorCode)
+		for _, code := range codes {
+			byStatus[code.HTTPStatus()] = append(byStatus[code.HTTPStatus()], code)
+		}
+		for status, codes := range byStatus {
+			var desc []string
+			for _, code := range codes {
+				desc = append(desc, fmt.Sprintf("%d (%s)", code, code.DefaultMessage()))
+			}
+			op.Responses[strconv.Itoa(status)] = &openapi.Response{
+				Description: "Error codes " + strings.Join(desc, ", "),
+				Headers:     serverSignature,
+				Content:     openapi.JSON(errorBody(codes)),
+			}
+		}
+
+		err = g.AddOperation(route.method, fmt.Sprintf("/api/v%d%s", version, route.path), op)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g.Document(), nil
+}
+
+// openAPI returns the handler for "GET /api/v3/openapi.json" and
+// "GET /api/v4/openapi.json".
+func (w *WebAPI) openAPI(version int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		spec, err := apiSpec(version)
+		if err != nil {
+			w.log.Errorf("Failed to generate OpenAPI document (version=%d): %v", version, err)
+			w.sendError(types.ErrInternalError, c)
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusOK, spec)
+	}
+}
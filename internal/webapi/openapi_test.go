@@ -0,0 +1,369 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	dcrdtypes "github.com/decred/dcrd/rpc/jsonrpc/types/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/feeschedule"
+	"github.com/decred/vspd/internal/openapi"
+	"github.com/decred/vspd/rpc"
+	"github.com/decred/vspd/types/v3"
+	"github.com/decred/vspd/types/v3/apiv4"
+	"github.com/gin-gonic/gin"
+)
+
+// TestOpenAPIRoutes ensures every API route registered by the router is
+// described by the OpenAPI document of its version, and every route described
+// is registered.
+func TestOpenAPIRoutes(t *testing.T) {
+	// The router loads its templates relative to the root of the repository.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir("../..")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	router := api.router(randBytes(32), rpc.DcrdConnect{}, rpc.WalletConnect{})
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		if strings.HasPrefix(route.Path, "/api/") {
+			registered[route.Method+" "+route.Path] = true
+		}
+	}
+
+	for version := range apiRoutes {
+		spec, err := apiSpec(version)
+		if err != nil {
+			t.Fatalf("error generating version %d document: %v", version, err)
+		}
+
+		for path, item := range spec.Paths {
+			for method, op := range map[string]*openapi.Operation{
+				http.MethodGet:  item.Get,
+				http.MethodPost: item.Post,
+				http.MethodPut:  item.Put,
+			} {
+				if op == nil {
+					continue
+				}
+				route := method + " " + path
+				if !registered[route] {
+					t.Fatalf("%s is described but not registered", route)
+				}
+				delete(registered, route)
+			}
+		}
+	}
+
+	for route := range registered {
+		t.Fatalf("%s is registered but not described", route)
+	}
+}
+
+// checkConformance fails the test if a request to method path, or the response
+// recorded by w, does not match the OpenAPI document of the API.
+func checkConformance(t *testing.T, method, path string, req *http.Request, reqBytes []byte,
+	w *httptest.ResponseRecorder) {
+
+	t.Helper()
+
+	var version int
+	_, err := fmt.Sscanf(path, "/api/v%d/", &version)
+	if err != nil {
+		t.Fatalf("invalid API path %s", path)
+	}
+	spec, err := apiSpec(version)
+	if err != nil {
+		t.Fatalf("error generating version %d document: %v", version, err)
+	}
+
+	item, ok := spec.Paths[path]
+	if !ok {
+		t.Fatalf("%s is not described", path)
+	}
+	op := map[string]*openapi.Operation{
+		http.MethodGet:  item.Get,
+		http.MethodPost: item.Post,
+		http.MethodPut:  item.Put,
+	}[method]
+	if op == nil {
+		t.Fatalf("%s %s is not described", method, path)
+	}
+
+	for _, param := range op.Parameters {
+		if param.In == "header" && param.Required && req.Header.Get(param.Name) == "" {
+			t.Fatalf("request does not include required header %s", param.Name)
+		}
+	}
+	if op.RequestBody != nil {
+		err = spec.Validate(op.RequestBody.Content["application/json"].Schema, reqBytes)
+		if err != nil {
+			t.Fatalf("request does not match the document: %v", err)
+		}
+	}
+
+	resp, ok := op.Responses[strconv.Itoa(w.Code)]
+	if !ok {
+		t.Fatalf("response status %d is not described", w.Code)
+	}
+	for name := range resp.Headers {
+		if w.Header().Get(name) == "" {
+			t.Fatalf("response does not include header %s", name)
+		}
+	}
+	err = spec.Validate(resp.Content["application/json"].Schema, w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("response does not match the document: %v\n%s", err, w.Body.String())
+	}
+}
+
+// TestOpenAPIConformance sends requests built from the types described by the
+// OpenAPI documents to the handlers of each route, and ensures the requests
+// are handled as expected and the responses match the documents.
+func TestOpenAPIConformance(t *testing.T) {
+	if api.feeSchedule.Load() == nil {
+		schedule, err := feeschedule.Load("", 3, api.cfg.Network.Params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		api.feeSchedule.Store(schedule)
+	}
+	if api.maintenance.Load() == nil {
+		api.maintenance.Store(&maintenanceSchedule{})
+	}
+
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkHash := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+	owner, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, api.cfg.Network)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ticketHash := randString(64, hexCharset)
+	err = api.db.InsertNewTicket(database.Ticket{
+		Hash:              ticketHash,
+		CommitmentAddress: owner.String(),
+		FeeAddress:        randString(35, hexCharset),
+		FeeTxStatus:       database.FeeConfirmed,
+	})
+	if err != nil {
+		t.Fatalf("error storing ticket: %v", err)
+	}
+
+	marshal := func(v any) []byte {
+		t.Helper()
+		b, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+	now := time.Now().Unix()
+
+	// Middleware which adds values to the context in place of the middleware
+	// which requires dcrd, voting wallets or the web cache.
+	withCache := func(c *gin.Context) {
+		c.Set(cacheKey, cacheData{Initialized: true})
+	}
+	withoutWallets := func(c *gin.Context) {
+		c.Set(walletsKey, []*rpc.WalletRPC{})
+	}
+	withTestNode := func(c *gin.Context) {
+		reqBytes, err := drainAndReplaceBody(c.Request)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.Set(requestBytesKey, reqBytes)
+		c.Set(dcrdKey, &testNode{
+			getRawTransaction: &dcrdtypes.TxRawResult{Confirmations: 1000},
+			existsLiveTicket:  true,
+		})
+		c.Set(dcrdErrorKey, nil)
+	}
+
+	envelope := func(path string, timestamp int64, hash string, body any) []byte {
+		return marshal(apiv4.RequestEnvelope{
+			RequestID:  "req",
+			Method:     http.MethodPost,
+			Path:       path,
+			Timestamp:  timestamp,
+			Nonce:      randString(32, hexCharset),
+			TicketHash: hash,
+			Body:       marshal(body),
+		})
+	}
+
+	altSignAddrHash := randString(64, hexCharset)
+	altSignAddrHashV4 := randString(64, hexCharset)
+
+	tests := map[string]struct {
+		method     string
+		path       string
+		handlers   []gin.HandlerFunc
+		reqBytes   []byte
+		signer     *secp256k1.PrivateKey
+		wantStatus int
+	}{
+		"v3 openapi.json": {
+			method:     http.MethodGet,
+			path:       "/api/v3/openapi.json",
+			handlers:   []gin.HandlerFunc{api.openAPI(3)},
+			wantStatus: http.StatusOK,
+		},
+		"v3 vspinfo": {
+			method:     http.MethodGet,
+			path:       "/api/v3/vspinfo",
+			handlers:   []gin.HandlerFunc{withCache, api.vspInfo},
+			wantStatus: http.StatusOK,
+		},
+		"v3 setaltsignaddr": {
+			method:   http.MethodPost,
+			path:     "/api/v3/setaltsignaddr",
+			handlers: []gin.HandlerFunc{withTestNode, api.setAltSignAddr},
+			reqBytes: marshal(types.SetAltSignAddrRequest{
+				Timestamp:      now,
+				TicketHash:     altSignAddrHash,
+				TicketHex:      randString(504, hexCharset),
+				ParentHex:      randString(504, hexCharset),
+				AltSignAddress: owner.String(),
+			}),
+			signer:     key,
+			wantStatus: http.StatusOK,
+		},
+		"v3 ticketstatus": {
+			method:   http.MethodPost,
+			path:     "/api/v3/ticketstatus",
+			handlers: []gin.HandlerFunc{api.vspAuth, api.ticketStatus},
+			reqBytes: marshal(types.TicketStatusRequest{
+				TicketHash: ticketHash,
+			}),
+			signer:     key,
+			wantStatus: http.StatusOK,
+		},
+		"v3 ticketstatusbatch": {
+			method:   http.MethodPost,
+			path:     "/api/v3/ticketstatusbatch",
+			handlers: []gin.HandlerFunc{api.ticketStatusBatch},
+			reqBytes: marshal(types.TicketStatusBatchRequest{
+				Timestamp:    now,
+				Address:      owner.String(),
+				TicketHashes: []string{ticketHash, randString(64, hexCharset)},
+			}),
+			signer:     key,
+			wantStatus: http.StatusOK,
+		},
+		"v3 setvotechoices without wallets": {
+			method:   http.MethodPost,
+			path:     "/api/v3/setvotechoices",
+			handlers: []gin.HandlerFunc{withoutWallets, api.vspAuth, api.setVoteChoices},
+			reqBytes: marshal(types.SetVoteChoicesRequest{
+				Timestamp:   now,
+				TicketHash:  ticketHash,
+				VoteChoices: map[string]string{},
+			}),
+			signer:     key,
+			wantStatus: http.StatusInternalServerError,
+		},
+		"v3 setvotechoicesbatch without wallets": {
+			method:   http.MethodPost,
+			path:     "/api/v3/setvotechoicesbatch",
+			handlers: []gin.HandlerFunc{withoutWallets, api.setVoteChoicesBatch},
+			reqBytes: marshal(types.SetVoteChoicesBatchRequest{
+				Tickets: []types.SignedRequest{{Request: "{}", Signature: "sig"}},
+			}),
+			wantStatus: http.StatusInternalServerError,
+		},
+		"v4 openapi.json": {
+			method:     http.MethodGet,
+			path:       "/api/v4/openapi.json",
+			handlers:   []gin.HandlerFunc{api.withAPIV4, api.openAPI(4)},
+			wantStatus: http.StatusOK,
+		},
+		"v4 vspinfo": {
+			method:     http.MethodGet,
+			path:       "/api/v4/vspinfo",
+			handlers:   []gin.HandlerFunc{api.withAPIV4, withCache, api.vspInfo},
+			wantStatus: http.StatusOK,
+		},
+		"v4 setaltsignaddr": {
+			method: http.MethodPost,
+			path:   "/api/v4/setaltsignaddr",
+			handlers: []gin.HandlerFunc{api.withAPIV4, api.withEnvelope(apiv4.SetAltSignAddrRequest{}),
+				withTestNode, api.setAltSignAddr},
+			reqBytes: envelope("/api/v4/setaltsignaddr", now, altSignAddrHashV4, apiv4.SetAltSignAddrRequest{
+				TicketHex:      randString(504, hexCharset),
+				ParentHex:      randString(504, hexCharset),
+				AltSignAddress: owner.String(),
+			}),
+			signer:     key,
+			wantStatus: http.StatusOK,
+		},
+		"v4 ticketstatus": {
+			method: http.MethodPost,
+			path:   "/api/v4/ticketstatus",
+			handlers: []gin.HandlerFunc{api.withAPIV4, api.withEnvelope(apiv4.TicketStatusRequest{}),
+				api.vspAuth, api.checkNonce, api.ticketStatus},
+			reqBytes:   envelope("/api/v4/ticketstatus", now, ticketHash, apiv4.TicketStatusRequest{}),
+			signer:     key,
+			wantStatus: http.StatusOK,
+		},
+		"v4 ticketstatus with old timestamp": {
+			method: http.MethodPost,
+			path:   "/api/v4/ticketstatus",
+			handlers: []gin.HandlerFunc{api.withAPIV4, api.withEnvelope(apiv4.TicketStatusRequest{}),
+				api.vspAuth, api.checkNonce, api.ticketStatus},
+			reqBytes:   envelope("/api/v4/ticketstatus", now-3600, ticketHash, apiv4.TicketStatusRequest{}),
+			signer:     key,
+			wantStatus: types.ErrInvalidTimestamp.HTTPStatus(),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.Handle(test.method, test.path, test.handlers...)
+
+			req, err := http.NewRequest(test.method, test.path, bytes.NewReader(test.reqBytes))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.signer != nil {
+				req.Header.Set("VSP-Client-Signature", signMessage(t, test.signer, string(test.reqBytes)))
+			}
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != test.wantStatus {
+				t.Fatalf("expected http status %d, got %d: %s", test.wantStatus, w.Code, w.Body.String())
+			}
+
+			checkConformance(t, test.method, test.path, req, test.reqBytes, w)
+		})
+	}
+}
@@ -13,13 +13,15 @@ import (
 	"github.com/decred/dcrd/dcrutil/v4"
 	"github.com/decred/dcrd/txscript/v4/stdaddr"
 	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/internal/webhook"
 	"github.com/decred/vspd/rpc"
 	"github.com/decred/vspd/types/v3"
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
 )
 
-// payFee is the handler for "POST /api/v3/payfee".
+// payFee is the handler for "POST /api/v3/payfee" and
+// "POST /api/v4/payfee".
 func (w *WebAPI) payFee(c *gin.Context) {
 	const funcName = "payFee"
 
@@ -224,6 +226,8 @@ func (w *WebAPI) payFee(c *gin.Context) {
 	ticket.FeeTxHex = request.FeeTx
 	ticket.FeeTxHash = feeTx.TxHash().String()
 	ticket.FeeTxStatus = database.FeeReceieved
+	ticket.FeeErrorReason = ""
+	ticket.FeeErrorPermanent = false
 
 	if validVoteChoices {
 		ticket.VoteChoices = request.VoteChoices
@@ -247,6 +251,9 @@ func (w *WebAPI) payFee(c *gin.Context) {
 
 	w.log.Debugf("%s: Fee tx received for ticket (minExpectedFee=%v, feePaid=%v, ticketHash=%s)",
 		funcName, minFee, feePaid, ticket.Hash)
+	database.AddTicketEvent(w.db, w.log, ticket.Hash, database.EventFeeReceived,
+		fmt.Sprintf("Fee paid %v, fee tx %s", feePaid, ticket.FeeTxHash))
+	w.feed.Publish(ticket.Hash)
 
 	if ticket.Confirmed {
 		err = dcrdClient.SendRawTransaction(request.FeeTx)
@@ -255,6 +262,8 @@ func (w *WebAPI) payFee(c *gin.Context) {
 				funcName, ticket.Hash, err)
 
 			ticket.FeeTxStatus = database.FeeError
+			ticket.FeeErrorReason = "Fee tx could not be broadcast"
+			broadcastErr := err
 
 			// Send the client an explicit error if the issue is unknown outputs.
 			if rpc.ErrOrphan.MatchString(err.Error()) {
@@ -267,8 +276,13 @@ func (w *WebAPI) payFee(c *gin.Context) {
 			if err != nil {
 				w.log.Errorf("%s: db.UpdateTicket error, failed to set fee tx error (ticketHash=%s): %v",
 					funcName, ticket.Hash, err)
+				return
 			}
 
+			database.AddTicketEvent(w.db, w.log, ticket.Hash, database.EventFeeError,
+				fmt.Sprintf("Broadcast failed: %v", broadcastErr))
+			w.notify(webhook.NewEvent(webhook.FeeError, ticket))
+
 			return
 		}
 
@@ -284,6 +298,9 @@ func (w *WebAPI) payFee(c *gin.Context) {
 
 		w.log.Debugf("%s: Fee tx broadcast for ticket (ticketHash=%s, feeHash=%s)",
 			funcName, ticket.Hash, ticket.FeeTxHash)
+
+		database.AddTicketEvent(w.db, w.log, ticket.Hash, database.EventFeeBroadcast, "")
+		w.notify(webhook.NewEvent(webhook.FeeBroadcast, ticket))
 	}
 
 	// Send success response to client.
@@ -296,7 +313,7 @@ func (w *WebAPI) payFee(c *gin.Context) {
 	err = w.db.SaveVoteChange(
 		ticket.Hash,
 		database.VoteChangeRecord{
-			Request:           string(reqBytes),
+			Request:           string(signedRequest(c)),
 			RequestSignature:  c.GetHeader("VSP-Client-Signature"),
 			Response:          resp,
 			ResponseSignature: respSig,
@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -25,7 +25,8 @@ type node interface {
 	GetRawTransaction(txHash string) (*dcrdtypes.TxRawResult, error)
 }
 
-// setAltSignAddr is the handler for "POST /api/v3/setaltsignaddr".
+// setAltSignAddr is the handler for "POST /api/v3/setaltsignaddr" and
+// "POST /api/v4/setaltsignaddr".
 func (w *WebAPI) setAltSignAddr(c *gin.Context) {
 
 	const funcName = "setAltSignAddr"
@@ -106,7 +107,7 @@ func (w *WebAPI) setAltSignAddr(c *gin.Context) {
 
 	data := &database.AltSignAddrData{
 		AltSignAddr: altSignAddr,
-		Req:         string(reqBytes),
+		Req:         string(signedRequest(c)),
 		ReqSig:      c.GetHeader("VSP-Client-Signature"),
 		Resp:        resp,
 		RespSig:     respSig,
@@ -119,5 +120,7 @@ func (w *WebAPI) setAltSignAddr(c *gin.Context) {
 		return
 	}
 
+	w.feed.Publish(ticketHash)
+
 	w.log.Debugf("%s: New alt sign address set for ticket: (ticketHash=%s)", funcName, ticketHash)
 }
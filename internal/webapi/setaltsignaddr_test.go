@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2024 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -22,6 +22,8 @@ import (
 	"github.com/decred/slog"
 	"github.com/decred/vspd/database"
 	"github.com/decred/vspd/internal/config"
+	"github.com/decred/vspd/internal/metrics"
+	"github.com/decred/vspd/internal/ticketfeed"
 	"github.com/decred/vspd/types/v3"
 	"github.com/gin-gonic/gin"
 )
@@ -89,6 +91,8 @@ func TestMain(m *testing.M) {
 		signPrivKey: signPrivKey,
 		db:          db,
 		log:         log,
+		metrics:     metrics.New(),
+		feed:        ticketfeed.New(),
 	}
 
 	// Run tests.
@@ -276,12 +280,28 @@ func TestSetAltSignAddress(t *testing.T) {
 
 			c.Request.Header.Set("VSP-Client-Signature", reqSig)
 
+			sub := api.feed.Subscribe([]string{ticketHash})
+			defer sub.Close()
+
 			r.ServeHTTP(w, c.Request)
 
 			if test.wantHTTPStatus != w.Code {
 				t.Fatalf("expected http status %d, got %d", test.wantHTTPStatus, w.Code)
 			}
 
+			// Streams of the ticket status are only notified when the alt sign
+			// address is set.
+			select {
+			case <-sub.Ready():
+				if test.wantHTTPStatus != http.StatusOK {
+					t.Fatal("unexpected ticket change published")
+				}
+			default:
+				if test.wantHTTPStatus == http.StatusOK {
+					t.Fatal("expected ticket change to be published")
+				}
+			}
+
 			if test.wantHTTPStatus != http.StatusOK {
 				respBytes, err := io.ReadAll(w.Body)
 				if err != nil {
@@ -16,7 +16,8 @@ import (
 	"github.com/gin-gonic/gin/binding"
 )
 
-// setVoteChoices is the handler for "POST /api/v3/setvotechoices".
+// setVoteChoices is the handler for "POST /api/v3/setvotechoices" and
+// "POST /api/v4/setvotechoices".
 func (w *WebAPI) setVoteChoices(c *gin.Context) {
 	const funcName = "setVoteChoices"
 
@@ -39,37 +40,89 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 		return
 	}
 
-	if ticket.FeeTxStatus == database.NoFee {
-		w.log.Warnf("%s: No fee tx for ticket (clientIP=%s, ticketHash=%s)",
-			funcName, c.ClientIP(), ticket.Hash)
-		w.sendError(types.ErrFeeNotReceived, c)
+	var request types.SetVoteChoicesRequest
+	if err := binding.JSON.BindBody(reqBytes, &request); err != nil {
+		w.log.Warnf("%s: Bad request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+		w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
 		return
 	}
 
-	// Only allow vote choices to be updated for mempool/immature/live tickets.
-	if ticket.Outcome != "" {
-		w.log.Warnf("%s: Ticket not eligible to vote (clientIP=%s, ticketHash=%s)",
-			funcName, c.ClientIP(), ticket.Hash)
-		w.sendErrorWithMsg(fmt.Sprintf("ticket not eligible to vote (status=%s)", ticket.Outcome),
-			types.ErrTicketCannotVote, c)
+	apiErr, err := w.checkVoteChoices(ticket, request)
+	if err != nil {
+		w.log.Errorf("%s: Failed to check vote choices (ticketHash=%s): %v",
+			funcName, ticket.Hash, err)
+		w.sendError(types.ErrInternalError, c)
+		return
+	}
+	if apiErr != nil {
+		w.log.Warnf("%s: Cannot update vote choices (clientIP=%s, ticketHash=%s): %s",
+			funcName, c.ClientIP(), ticket.Hash, apiErr.Message)
+		w.sendErrorWithMsg(apiErr.Message, apiErr.Code, c)
 		return
 	}
 
-	var request types.SetVoteChoicesRequest
-	if err := binding.JSON.BindBody(reqBytes, &request); err != nil {
-		w.log.Warnf("%s: Bad request (clientIP=%s): %v", funcName, c.ClientIP(), err)
-		w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
+	// Update voting preferences in the database before updating the wallets. DB
+	// is the source of truth, and also is less likely to error.
+
+	applyVoteChoices(&ticket, request)
+
+	err = w.db.UpdateTicket(ticket)
+	if err != nil {
+		w.log.Errorf("%s: db.UpdateTicket error, failed to set consensus vote choices (ticketHash=%s): %v",
+			funcName, ticket.Hash, err)
+		w.sendError(types.ErrInternalError, c)
 		return
 	}
 
+	w.feed.Publish(ticket.Hash)
+
+	w.setWalletVoteChoices(funcName, walletClients, []database.Ticket{ticket})
+
+	w.log.Debugf("%s: Vote choices updated (ticketHash=%s)", funcName, ticket.Hash)
+
+	// Send success response to client.
+	resp, respSig := w.sendJSONResponse(types.SetVoteChoicesResponse{
+		Timestamp: time.Now().Unix(),
+		Request:   reqBytes,
+	}, c)
+
+	// Store a record of the vote choice change.
+	err = w.db.SaveVoteChange(
+		ticket.Hash,
+		database.VoteChangeRecord{
+			Request:           string(signedRequest(c)),
+			RequestSignature:  c.GetHeader("VSP-Client-Signature"),
+			Response:          resp,
+			ResponseSignature: respSig,
+		})
+	if err != nil {
+		w.log.Errorf("%s: Failed to store vote change record (ticketHash=%s): %v",
+			funcName, ticket.Hash, err)
+	}
+}
+
+// checkVoteChoices returns an API error if the vote choices of ticket cannot be
+// updated by request. Only internal errors are returned as errors.
+func (w *WebAPI) checkVoteChoices(ticket database.Ticket, request types.SetVoteChoicesRequest) (*types.ErrorResponse, error) {
+	apiError := func(code types.ErrorCode, msg string) (*types.ErrorResponse, error) {
+		return &types.ErrorResponse{Code: code, Message: msg}, nil
+	}
+
+	if ticket.FeeTxStatus == database.NoFee {
+		return apiError(types.ErrFeeNotReceived, types.ErrFeeNotReceived.DefaultMessage())
+	}
+
+	// Only allow vote choices to be updated for mempool/immature/live tickets.
+	if ticket.Outcome != "" {
+		return apiError(types.ErrTicketCannotVote,
+			fmt.Sprintf("ticket not eligible to vote (status=%s)", ticket.Outcome))
+	}
+
 	// Return an error if this request has a timestamp older than any previous
 	// vote change requests. This is to prevent requests from being replayed.
 	previousChanges, err := w.db.GetVoteChanges(ticket.Hash)
 	if err != nil {
-		w.log.Errorf("%s: db.GetVoteChanges error (ticketHash=%s): %v",
-			funcName, ticket.Hash, err)
-		w.sendError(types.ErrInternalError, c)
-		return
+		return nil, fmt.Errorf("db.GetVoteChanges error: %w", err)
 	}
 
 	for _, change := range previousChanges {
@@ -78,18 +131,11 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 		}
 		err := json.Unmarshal([]byte(change.Request), &prevReq)
 		if err != nil {
-			w.log.Errorf("%s: Could not unmarshal vote change record (ticketHash=%s): %v",
-				funcName, ticket.Hash, err)
-			w.sendError(types.ErrInternalError, c)
-			return
+			return nil, fmt.Errorf("could not unmarshal vote change record: %w", err)
 		}
 
 		if request.Timestamp <= prevReq.Timestamp {
-			w.log.Warnf("%s: Request uses invalid timestamp, %d is not greater "+
-				"than %d (ticketHash=%s)",
-				funcName, request.Timestamp, prevReq.Timestamp, ticket.Hash)
-			w.sendError(types.ErrInvalidTimestamp, c)
-			return
+			return apiError(types.ErrInvalidTimestamp, types.ErrInvalidTimestamp.DefaultMessage())
 		}
 	}
 
@@ -97,31 +143,24 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 
 	err = validConsensusVoteChoices(w.cfg.Network, w.cfg.Network.CurrentVoteVersion(), request.VoteChoices)
 	if err != nil {
-		w.log.Warnf("%s: Invalid consensus vote choices (clientIP=%s, ticketHash=%s): %v",
-			funcName, c.ClientIP(), ticket.Hash, err)
-		w.sendErrorWithMsg(err.Error(), types.ErrInvalidVoteChoices, c)
-		return
+		return apiError(types.ErrInvalidVoteChoices, err.Error())
 	}
 
 	err = validTreasuryPolicy(request.TreasuryPolicy)
 	if err != nil {
-		w.log.Warnf("%s: Invalid treasury policy (clientIP=%s, ticketHash=%s): %v",
-			funcName, c.ClientIP(), ticket.Hash, err)
-		w.sendErrorWithMsg(err.Error(), types.ErrInvalidVoteChoices, c)
-		return
+		return apiError(types.ErrInvalidVoteChoices, err.Error())
 	}
 
 	err = validTSpendPolicy(request.TSpendPolicy)
 	if err != nil {
-		w.log.Warnf("%s: Invalid tspend policy (clientIP=%s, ticketHash=%s): %v",
-			funcName, c.ClientIP(), ticket.Hash, err)
-		w.sendErrorWithMsg(err.Error(), types.ErrInvalidVoteChoices, c)
-		return
+		return apiError(types.ErrInvalidVoteChoices, err.Error())
 	}
 
-	// Update voting preferences in the database before updating the wallets. DB
-	// is the source of truth, and also is less likely to error.
+	return nil, nil
+}
 
+// applyVoteChoices updates the vote choices of ticket with those of request.
+func applyVoteChoices(ticket *database.Ticket, request types.SetVoteChoicesRequest) {
 	for newAgenda, newChoice := range request.VoteChoices {
 		ticket.VoteChoices[newAgenda] = newChoice
 	}
@@ -133,26 +172,23 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 	for newTreasuryKey, newChoice := range request.TreasuryPolicy {
 		ticket.TreasuryPolicy[newTreasuryKey] = newChoice
 	}
+}
 
-	err = w.db.UpdateTicket(ticket)
-	if err != nil {
-		w.log.Errorf("%s: db.UpdateTicket error, failed to set consensus vote choices (ticketHash=%s): %v",
-			funcName, ticket.Hash, err)
-		w.sendError(types.ErrInternalError, c)
-		return
-	}
-
-	// Update vote choices on voting wallets. Tickets are only added to voting
-	// wallets if their fee is confirmed.
-	if ticket.FeeTxStatus == database.FeeConfirmed {
-
-		// Just log any errors which occur while setting vote choices. We want
-		// to attempt to update as much as possible regardless of any errors.
-		for _, walletClient := range walletClients {
+// setWalletVoteChoices updates the vote choices of tickets on every voting
+// wallet. Tickets are only added to voting wallets if their fee is confirmed,
+// so other tickets are skipped.
+func (w *WebAPI) setWalletVoteChoices(funcName string, walletClients []*rpc.WalletRPC, tickets []database.Ticket) {
+	// Just log any errors which occur while setting vote choices. We want
+	// to attempt to update as much as possible regardless of any errors.
+	for _, walletClient := range walletClients {
+		for _, ticket := range tickets {
+			if ticket.FeeTxStatus != database.FeeConfirmed {
+				continue
+			}
 
 			// Set consensus vote choices.
 			for agenda, choice := range ticket.VoteChoices {
-				err = walletClient.SetVoteChoice(agenda, choice, ticket.Hash)
+				err := walletClient.SetVoteChoice(agenda, choice, ticket.Hash)
 				if err != nil {
 					w.log.Errorf("%s: dcrwallet.SetVoteChoice failed (wallet=%s, ticketHash=%s): %v",
 						funcName, walletClient.String(), ticket.Hash, err)
@@ -161,7 +197,7 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 
 			// Update tspend policy.
 			for tspend, policy := range ticket.TSpendPolicy {
-				err = walletClient.SetTSpendPolicy(tspend, policy, ticket.Hash)
+				err := walletClient.SetTSpendPolicy(tspend, policy, ticket.Hash)
 				if err != nil {
 					w.log.Errorf("%s: dcrwallet.SetTSpendPolicy failed (wallet=%s, ticketHash=%s): %v",
 						funcName, walletClient.String(), ticket.Hash, err)
@@ -170,7 +206,7 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 
 			// Update treasury policy.
 			for key, policy := range ticket.TreasuryPolicy {
-				err = walletClient.SetTreasuryPolicy(key, policy, ticket.Hash)
+				err := walletClient.SetTreasuryPolicy(key, policy, ticket.Hash)
 				if err != nil {
 					w.log.Errorf("%s: dcrwallet.SetTreasuryPolicy failed (wallet=%s, ticketHash=%s): %v",
 						funcName, walletClient.String(), ticket.Hash, err)
@@ -178,26 +214,4 @@ func (w *WebAPI) setVoteChoices(c *gin.Context) {
 			}
 		}
 	}
-
-	w.log.Debugf("%s: Vote choices updated (ticketHash=%s)", funcName, ticket.Hash)
-
-	// Send success response to client.
-	resp, respSig := w.sendJSONResponse(types.SetVoteChoicesResponse{
-		Timestamp: time.Now().Unix(),
-		Request:   reqBytes,
-	}, c)
-
-	// Store a record of the vote choice change.
-	err = w.db.SaveVoteChange(
-		ticket.Hash,
-		database.VoteChangeRecord{
-			Request:           string(reqBytes),
-			RequestSignature:  c.GetHeader("VSP-Client-Signature"),
-			Response:          resp,
-			ResponseSignature: respSig,
-		})
-	if err != nil {
-		w.log.Errorf("%s: Failed to store vote change record (ticketHash=%s): %v",
-			funcName, ticket.Hash, err)
-	}
 }
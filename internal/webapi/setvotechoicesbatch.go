@@ -0,0 +1,198 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/rpc"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxSetVoteChoicesBatch is the maximum number of tickets which can be included
+// in a single request to /setvotechoicesbatch.
+const maxSetVoteChoicesBatch = 100
+
+// setVoteChoicesBatch is the handler for "POST /api/v3/setvotechoicesbatch".
+// Each ticket in the batch is checked in the same way as /setvotechoices, using
+// its own signed request. The changes to every ticket which passes the checks
+// are stored in a single database transaction, then applied to the voting
+// wallets in one pass.
+func (w *WebAPI) setVoteChoicesBatch(c *gin.Context) {
+	const funcName = "setVoteChoicesBatch"
+
+	walletClients := c.MustGet(walletsKey).([]*rpc.WalletRPC)
+
+	// If we cannot set the vote choices on at least one voting wallet right
+	// now, don't update the database, just return an error.
+	if len(walletClients) == 0 {
+		w.sendError(types.ErrInternalError, c)
+		return
+	}
+
+	reqBytes, err := drainAndReplaceBody(c.Request)
+	if err != nil {
+		w.log.Warnf("%s: Error reading request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+		w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
+		return
+	}
+
+	var request types.SetVoteChoicesBatchRequest
+	if err := binding.JSON.BindBody(reqBytes, &request); err != nil {
+		w.log.Warnf("%s: Bad request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+		w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
+		return
+	}
+
+	if len(request.Tickets) == 0 || len(request.Tickets) > maxSetVoteChoicesBatch {
+		w.log.Warnf("%s: Bad request (clientIP=%s): %d tickets requested",
+			funcName, c.ClientIP(), len(request.Tickets))
+		w.sendErrorWithMsg(fmt.Sprintf("between 1 and %d tickets must be requested",
+			maxSetVoteChoicesBatch), types.ErrBadRequest, c)
+		return
+	}
+
+	timestamp := time.Now().Unix()
+	results := make([]types.SetVoteChoicesResult, 0, len(request.Tickets))
+	updates := make([]database.VoteChoicesUpdate, 0, len(request.Tickets))
+	seen := make(map[string]struct{}, len(request.Tickets))
+
+	for _, signed := range request.Tickets {
+		result, update, err := w.batchVoteChoicesUpdate(signed, timestamp, seen)
+		if err != nil {
+			w.log.Errorf("%s: Failed to check vote choices (ticketHash=%s): %v",
+				funcName, result.TicketHash, err)
+			w.sendError(types.ErrInternalError, c)
+			return
+		}
+
+		if result.Error != nil {
+			w.log.Warnf("%s: Cannot update vote choices (clientIP=%s, ticketHash=%s): %s",
+				funcName, c.ClientIP(), result.TicketHash, result.Error.Message)
+		} else {
+			updates = append(updates, update)
+		}
+
+		results = append(results, result)
+	}
+
+	// Update voting preferences in the database before updating the wallets.
+	// Either every ticket is updated or none are.
+	if len(updates) > 0 {
+		err = w.db.UpdateVoteChoices(updates)
+		if err != nil {
+			w.log.Errorf("%s: db.UpdateVoteChoices error: %v", funcName, err)
+			w.sendError(types.ErrInternalError, c)
+			return
+		}
+	}
+
+	tickets := make([]database.Ticket, 0, len(updates))
+	for _, update := range updates {
+		tickets = append(tickets, update.Ticket)
+		w.feed.Publish(update.Ticket.Hash)
+	}
+	w.setWalletVoteChoices(funcName, walletClients, tickets)
+
+	w.log.Debugf("%s: Vote choices updated for %d of %d tickets", funcName,
+		len(updates), len(results))
+
+	w.sendJSONResponse(types.SetVoteChoicesBatchResponse{
+		Timestamp: timestamp,
+		Tickets:   results,
+		Request:   reqBytes,
+	}, c)
+}
+
+// batchVoteChoicesUpdate checks the signed request for a single ticket of a
+// batch. If the vote choices of the ticket can be updated, the returned update
+// contains the updated ticket and the record of the change, and the result
+// contains the signed response for the ticket. Otherwise the Error field of
+// the result describes the problem. Only internal errors are returned. The
+// hashes of tickets which can be updated are added to seen so that duplicates
+// can be rejected.
+func (w *WebAPI) batchVoteChoicesUpdate(signed types.SignedRequest, timestamp int64,
+	seen map[string]struct{}) (types.SetVoteChoicesResult, database.VoteChoicesUpdate, error) {
+
+	var result types.SetVoteChoicesResult
+	var update database.VoteChoicesUpdate
+	setError := func(code types.ErrorCode, msg string) {
+		result.Error = &types.ErrorResponse{Code: code, Message: msg}
+	}
+
+	var request types.SetVoteChoicesRequest
+	if err := binding.JSON.BindBody([]byte(signed.Request), &request); err != nil {
+		setError(types.ErrBadRequest, err.Error())
+		return result, update, nil
+	}
+	result.TicketHash = request.TicketHash
+
+	err := validateTicketHash(request.TicketHash)
+	if err != nil {
+		setError(types.ErrBadRequest, "invalid ticket hash")
+		return result, update, nil
+	}
+
+	if _, ok := seen[request.TicketHash]; ok {
+		setError(types.ErrBadRequest, "ticket included more than once")
+		return result, update, nil
+	}
+
+	ticket, found, err := w.db.GetTicketByHash(request.TicketHash)
+	if err != nil {
+		return result, update, fmt.Errorf("db.GetTicketByHash error: %w", err)
+	}
+	if !found {
+		setError(types.ErrUnknownTicket, types.ErrUnknownTicket.DefaultMessage())
+		return result, update, nil
+	}
+
+	err = validateSignature(ticket.Hash, ticket.CommitmentAddress, signed.Signature,
+		signed.Request, w.db, w.cfg.Network)
+	if err != nil {
+		setError(types.ErrBadSignature, types.ErrBadSignature.DefaultMessage())
+		return result, update, nil
+	}
+
+	apiErr, err := w.checkVoteChoices(ticket, request)
+	if err != nil {
+		return result, update, err
+	}
+	if apiErr != nil {
+		result.Error = apiErr
+		return result, update, nil
+	}
+
+	seen[ticket.Hash] = struct{}{}
+	applyVoteChoices(&ticket, request)
+
+	// Sign a response for the ticket, identical to a response from
+	// /setvotechoices, so that the change can be audited in the same way.
+	resp, respSig, err := w.signJSON(types.SetVoteChoicesResponse{
+		Timestamp: timestamp,
+		Request:   []byte(signed.Request),
+	})
+	if err != nil {
+		return result, update, fmt.Errorf("JSON marshal error: %w", err)
+	}
+
+	result.Response = resp
+	result.ResponseSignature = respSig
+	update = database.VoteChoicesUpdate{
+		Ticket: ticket,
+		Record: database.VoteChangeRecord{
+			Request:           signed.Request,
+			RequestSignature:  signed.Signature,
+			Response:          resp,
+			ResponseSignature: respSig,
+		},
+	}
+
+	return result, update, nil
+}
@@ -0,0 +1,147 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/types/v3"
+)
+
+func TestBatchVoteChoicesUpdate(t *testing.T) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkHash := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+	owner, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, api.cfg.Network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const other = "DsVoDXNQqyF3V83PJJ5zMdnB4pQuJHBAh15"
+
+	// Store a ticket which can be updated, one without a fee, and one owned
+	// by somebody else.
+	paid := randString(64, hexCharset)
+	unpaid := randString(64, hexCharset)
+	notOwned := randString(64, hexCharset)
+	for _, ticket := range []database.Ticket{
+		{Hash: paid, CommitmentAddress: owner.String(), FeeTxStatus: database.FeeReceieved},
+		{Hash: unpaid, CommitmentAddress: owner.String(), FeeTxStatus: database.NoFee},
+		{Hash: notOwned, CommitmentAddress: other, FeeTxStatus: database.FeeReceieved},
+	} {
+		ticket.FeeAddress = randString(35, hexCharset)
+		ticket.VoteChoices = map[string]string{}
+		ticket.TSpendPolicy = map[string]string{}
+		ticket.TreasuryPolicy = map[string]string{}
+		err := api.db.InsertNewTicket(ticket)
+		if err != nil {
+			t.Fatalf("error storing ticket: %v", err)
+		}
+	}
+
+	// signed returns a request to update the vote choices of ticketHash,
+	// signed by the owner.
+	signed := func(ticketHash string, timestamp int64) types.SignedRequest {
+		reqBytes, err := json.Marshal(types.SetVoteChoicesRequest{
+			Timestamp:      timestamp,
+			TicketHash:     ticketHash,
+			VoteChoices:    map[string]string{},
+			TSpendPolicy:   map[string]string{},
+			TreasuryPolicy: map[string]string{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return types.SignedRequest{
+			Request:   string(reqBytes),
+			Signature: signMessage(t, key, string(reqBytes)),
+		}
+	}
+
+	wrongSig := signed(paid, 100)
+	wrongSig.Signature = signed(paid, 101).Signature
+
+	seen := make(map[string]struct{})
+	tests := []struct {
+		name    string
+		request types.SignedRequest
+		wantErr types.ErrorCode
+		wantOK  bool
+	}{
+		{"invalid request", types.SignedRequest{Request: "{}", Signature: "sig"}, types.ErrBadRequest, false},
+		{"unknown ticket", signed(randString(64, hexCharset), 100), types.ErrUnknownTicket, false},
+		{"signature of different request", wrongSig, types.ErrBadSignature, false},
+		{"valid request", signed(paid, 100), 0, true},
+		{"duplicate ticket", signed(paid, 101), types.ErrBadRequest, false},
+		{"ticket owned by somebody else", signed(notOwned, 100), types.ErrBadSignature, false},
+		{"ticket without fee", signed(unpaid, 100), types.ErrFeeNotReceived, false},
+	}
+
+	var update database.VoteChoicesUpdate
+	for _, test := range tests {
+		result, u, err := api.batchVoteChoicesUpdate(test.request, 1000, seen)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+
+		if !test.wantOK {
+			if result.Error == nil || result.Error.Code != test.wantErr {
+				t.Fatalf("%s: expected error %d, got %+v", test.name, test.wantErr, result)
+			}
+			continue
+		}
+
+		if result.Error != nil {
+			t.Fatalf("%s: unexpected error result: %v", test.name, result.Error)
+		}
+		if result.TicketHash != paid || u.Ticket.Hash != paid {
+			t.Fatalf("%s: expected ticket %s, got result for %s and update for %s",
+				test.name, paid, result.TicketHash, u.Ticket.Hash)
+		}
+		sig, err := base64.StdEncoding.DecodeString(result.ResponseSignature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pubKey := api.signPrivKey.Public().(ed25519.PublicKey)
+		if !ed25519.Verify(pubKey, []byte(result.Response), sig) {
+			t.Fatalf("%s: response has invalid signature", test.name)
+		}
+		if u.Record.Request != test.request.Request || u.Record.Response != result.Response {
+			t.Fatalf("%s: vote change record does not match request and response", test.name)
+		}
+		update = u
+	}
+
+	// Once the update has been stored, a request with the same timestamp is
+	// rejected as a replay, but a later request is accepted.
+	err = api.db.UpdateVoteChoices([]database.VoteChoicesUpdate{update})
+	if err != nil {
+		t.Fatalf("error updating vote choices: %v", err)
+	}
+
+	result, _, err := api.batchVoteChoicesUpdate(signed(paid, 100), 1001, make(map[string]struct{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Error == nil || result.Error.Code != types.ErrInvalidTimestamp {
+		t.Fatalf("expected error %d, got %+v", types.ErrInvalidTimestamp, result)
+	}
+
+	result, _, err = api.batchVoteChoicesUpdate(signed(paid, 101), 1001, make(map[string]struct{}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Error != nil {
+		t.Fatalf("unexpected error result: %v", result.Error)
+	}
+}
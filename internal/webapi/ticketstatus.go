@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -13,7 +13,8 @@ import (
 	"github.com/gin-gonic/gin/binding"
 )
 
-// ticketStatus is the handler for "POST /api/v3/ticketstatus".
+// ticketStatus is the handler for "POST /api/v3/ticketstatus" and
+// "POST /api/v4/ticketstatus".
 func (w *WebAPI) ticketStatus(c *gin.Context) {
 	const funcName = "ticketStatus"
 
@@ -35,28 +36,47 @@ func (w *WebAPI) ticketStatus(c *gin.Context) {
 		return
 	}
 
-	// Get altSignAddress from database
-	altSignAddrData, err := w.db.AltSignAddrData(ticket.Hash)
+	status, err := w.ticketStatusResponse(ticket)
 	if err != nil {
 		w.log.Errorf("%s: db.AltSignAddrData error (ticketHash=%s): %v", funcName, ticket.Hash, err)
 		w.sendError(types.ErrInternalError, c)
 		return
 	}
 
+	status.Timestamp = time.Now().Unix()
+	status.Request = reqBytes
+
+	w.sendJSONResponse(status, c)
+}
+
+// ticketStatusResponse describes the status of ticket. The Timestamp and
+// Request fields of the response are not set.
+func (w *WebAPI) ticketStatusResponse(ticket database.Ticket) (types.TicketStatusResponse, error) {
+	// Get altSignAddress from database
+	altSignAddrData, err := w.db.AltSignAddrData(ticket.Hash)
+	if err != nil {
+		return types.TicketStatusResponse{}, err
+	}
+
 	altSignAddr := ""
 	if altSignAddrData != nil {
 		altSignAddr = altSignAddrData.AltSignAddr
 	}
 
-	w.sendJSONResponse(types.TicketStatusResponse{
-		Timestamp:       time.Now().Unix(),
-		Request:         reqBytes,
+	// Only explain fee errors if the ticket is still in an error state.
+	var feeTxError string
+	if ticket.FeeTxStatus == database.FeeError {
+		feeTxError = ticket.FeeErrorReason
+	}
+
+	return types.TicketStatusResponse{
 		TicketConfirmed: ticket.Confirmed,
 		FeeTxStatus:     string(ticket.FeeTxStatus),
 		FeeTxHash:       ticket.FeeTxHash,
+		FeeTxError:      feeTxError,
 		AltSignAddress:  altSignAddr,
 		VoteChoices:     ticket.VoteChoices,
 		TreasuryPolicy:  ticket.TreasuryPolicy,
 		TSpendPolicy:    ticket.TSpendPolicy,
-	}, c)
+	}, nil
 }
@@ -0,0 +1,159 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// maxTicketStatusBatch is the maximum number of tickets which can be included
+// in a single request to /ticketstatusbatch.
+const maxTicketStatusBatch = 1000
+
+// ticketStatusBatch is the handler for "POST /api/v3/ticketstatusbatch". Unlike
+// the single ticket endpoints it does not use the vspAuth middleware, because
+// one signature covers every ticket in the request.
+func (w *WebAPI) ticketStatusBatch(c *gin.Context) {
+	const funcName = "ticketStatusBatch"
+
+	request, reqBytes, ok := w.bindTicketStatusBatch(c, funcName)
+	if !ok {
+		return
+	}
+
+	results, err := w.ticketStatusResults(request.TicketHashes, request.Address)
+	if err != nil {
+		w.log.Errorf("%s: Failed to get ticket status: %v", funcName, err)
+		w.sendError(types.ErrInternalError, c)
+		return
+	}
+
+	w.sendJSONResponse(types.TicketStatusBatchResponse{
+		Timestamp: time.Now().Unix(),
+		Tickets:   results,
+		Request:   reqBytes,
+	}, c)
+}
+
+// bindTicketStatusBatch reads a TicketStatusBatchRequest from the body of the
+// request and validates its signature. If the request is invalid an error is
+// sent to the client and ok is false.
+func (w *WebAPI) bindTicketStatusBatch(c *gin.Context, funcName string) (
+	request types.TicketStatusBatchRequest, reqBytes []byte, ok bool) {
+
+	reqBytes, err := drainAndReplaceBody(c.Request)
+	if err != nil {
+		w.log.Warnf("%s: Error reading request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+		w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
+		return request, nil, false
+	}
+
+	if err := binding.JSON.BindBody(reqBytes, &request); err != nil {
+		w.log.Warnf("%s: Bad request (clientIP=%s): %v", funcName, c.ClientIP(), err)
+		w.sendErrorWithMsg(err.Error(), types.ErrBadRequest, c)
+		return request, nil, false
+	}
+
+	if len(request.TicketHashes) == 0 || len(request.TicketHashes) > maxTicketStatusBatch {
+		w.log.Warnf("%s: Bad request (clientIP=%s): %d tickets requested",
+			funcName, c.ClientIP(), len(request.TicketHashes))
+		w.sendErrorWithMsg(fmt.Sprintf("between 1 and %d tickets must be requested",
+			maxTicketStatusBatch), types.ErrBadRequest, c)
+		return request, nil, false
+	}
+
+	// Only recent requests are accepted, so a captured request cannot be
+	// replayed later to watch the status of the tickets.
+	now := time.Now()
+	minTimestamp := now.Add(-maxClockSkew).Unix()
+	maxTimestamp := now.Add(maxClockSkew).Unix()
+	if request.Timestamp < minTimestamp || request.Timestamp > maxTimestamp {
+		w.log.Warnf("%s: Request timestamp out of range (clientIP=%s, timestamp=%d)",
+			funcName, c.ClientIP(), request.Timestamp)
+		w.sendErrorWithMsg("request timestamp is too far from the time of the VSP",
+			types.ErrInvalidTimestamp, c)
+		return request, nil, false
+	}
+
+	// Ensure a signature is provided.
+	signature := c.GetHeader("VSP-Client-Signature")
+	if signature == "" {
+		w.log.Warnf("%s: No VSP-Client-Signature header (clientIP=%s)", funcName, c.ClientIP())
+		w.sendErrorWithMsg("no VSP-Client-Signature header", types.ErrBadRequest, c)
+		return request, nil, false
+	}
+
+	// Validate the request signature once. Ownership of each ticket is checked
+	// by comparing the signing address to the addresses of the ticket.
+	err = dcrutil.VerifyMessage(request.Address, signature, string(reqBytes), w.cfg.Network)
+	if err != nil {
+		w.log.Warnf("%s: Couldn't validate signature (clientIP=%s, address=%s): %v",
+			funcName, c.ClientIP(), request.Address, err)
+		w.sendError(types.ErrBadSignature, c)
+		return request, nil, false
+	}
+
+	return request, reqBytes, true
+}
+
+// ticketStatusResults returns the status of each ticket with one of the
+// provided hashes for a batch request signed by address.
+func (w *WebAPI) ticketStatusResults(hashes []string, address string) ([]types.TicketStatusResult, error) {
+	results := make([]types.TicketStatusResult, 0, len(hashes))
+	for _, hash := range hashes {
+		result, err := w.ticketStatusResult(hash, address)
+		if err != nil {
+			return nil, fmt.Errorf("%w (ticketHash=%s)", err, hash)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// ticketStatusResult returns the status of the ticket with the provided hash
+// for a batch request signed by address. Problems with the individual ticket
+// are described by the Error field of the result, and only internal errors are
+// returned.
+func (w *WebAPI) ticketStatusResult(hash, address string) (types.TicketStatusResult, error) {
+	result := types.TicketStatusResult{TicketHash: hash}
+	setError := func(code types.ErrorCode, msg string) {
+		result.Error = &types.ErrorResponse{Code: code, Message: msg}
+	}
+
+	err := validateTicketHash(hash)
+	if err != nil {
+		setError(types.ErrBadRequest, "invalid ticket hash")
+		return result, nil
+	}
+
+	ticket, found, err := w.db.GetTicketByHash(hash)
+	if err != nil {
+		return result, fmt.Errorf("db.GetTicketByHash error: %w", err)
+	}
+	if !found {
+		setError(types.ErrUnknownTicket, types.ErrUnknownTicket.DefaultMessage())
+		return result, nil
+	}
+
+	status, err := w.ticketStatusResponse(ticket)
+	if err != nil {
+		return result, fmt.Errorf("db.AltSignAddrData error: %w", err)
+	}
+
+	// The request must have been signed by the owner of the ticket.
+	if address != ticket.CommitmentAddress && address != status.AltSignAddress {
+		setError(types.ErrBadSignature, types.ErrBadSignature.DefaultMessage())
+		return result, nil
+	}
+
+	result.Status = &status
+	return result, nil
+}
@@ -0,0 +1,178 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+)
+
+// signMessage signs message with key in the format expected by
+// dcrutil.VerifyMessage.
+func signMessage(t *testing.T, key *secp256k1.PrivateKey, message string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := wire.WriteVarString(&buf, 0, "Decred Signed Message:\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = wire.WriteVarString(&buf, 0, message)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig := ecdsa.SignCompact(key, chainhash.HashB(buf.Bytes()), true)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestTicketStatusBatch(t *testing.T) {
+	// Create the key and address of the owner of the tickets, and an address
+	// owned by somebody else.
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkHash := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+	owner, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, api.cfg.Network)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const other = "DsVoDXNQqyF3V83PJJ5zMdnB4pQuJHBAh15"
+
+	// Store one ticket committed to the owner, one committed to somebody else,
+	// and one committed to somebody else but with the owner as its alternate
+	// signing address.
+	owned := randString(64, hexCharset)
+	notOwned := randString(64, hexCharset)
+	altSigned := randString(64, hexCharset)
+	unknown := randString(64, hexCharset)
+	for hash, addr := range map[string]string{owned: owner.String(), notOwned: other, altSigned: other} {
+		err := api.db.InsertNewTicket(database.Ticket{
+			Hash:              hash,
+			CommitmentAddress: addr,
+			FeeAddress:        randString(35, hexCharset),
+			FeeTxStatus:       database.NoFee,
+		})
+		if err != nil {
+			t.Fatalf("error storing ticket: %v", err)
+		}
+	}
+	err = api.db.InsertAltSignAddr(altSigned, &database.AltSignAddrData{
+		AltSignAddr: owner.String(),
+		Req:         randString(100, hexCharset),
+		ReqSig:      randString(88, sigCharset),
+		Resp:        randString(100, hexCharset),
+		RespSig:     randString(88, sigCharset),
+	})
+	if err != nil {
+		t.Fatalf("error storing alt sign addr: %v", err)
+	}
+
+	ticketHashes := []string{owned, notOwned, altSigned, unknown, "invalid"}
+	reqBytes, err := json.Marshal(types.TicketStatusBatchRequest{
+		Timestamp:    time.Now().Unix(),
+		Address:      owner.String(),
+		TicketHashes: ticketHashes,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		signature      string
+		wantHTTPStatus int
+	}{
+		"valid signature": {
+			signature:      signMessage(t, key, string(reqBytes)),
+			wantHTTPStatus: http.StatusOK,
+		},
+		"signature of different message": {
+			signature:      signMessage(t, key, "something else"),
+			wantHTTPStatus: types.ErrBadSignature.HTTPStatus(),
+		},
+		"no signature": {
+			wantHTTPStatus: types.ErrBadRequest.HTTPStatus(),
+		},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.POST("/", api.ticketStatusBatch)
+
+			req, err := http.NewRequest(http.MethodPost, "/", bytes.NewReader(reqBytes))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.signature != "" {
+				req.Header.Set("VSP-Client-Signature", test.signature)
+			}
+
+			r.ServeHTTP(w, req)
+
+			if w.Code != test.wantHTTPStatus {
+				t.Fatalf("expected http status %d, got %d", test.wantHTTPStatus, w.Code)
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var resp types.TicketStatusBatchResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("could not unmarshal response: %v", err)
+			}
+
+			if !bytes.Equal(resp.Request, reqBytes) {
+				t.Fatal("response contains differing request")
+			}
+
+			wantErrs := []types.ErrorCode{-1, types.ErrBadSignature, -1, types.ErrUnknownTicket,
+				types.ErrBadRequest}
+			if len(resp.Tickets) != len(wantErrs) {
+				t.Fatalf("expected %d results, got %d", len(wantErrs), len(resp.Tickets))
+			}
+			for i, result := range resp.Tickets {
+				if result.TicketHash != ticketHashes[i] {
+					t.Fatalf("expected result %d for ticket %s, got %s", i, ticketHashes[i],
+						result.TicketHash)
+				}
+				if wantErrs[i] == -1 {
+					if result.Error != nil || result.Status == nil {
+						t.Fatalf("expected status of ticket %s, got error %v", result.TicketHash,
+							result.Error)
+					}
+					continue
+				}
+				if result.Status != nil || result.Error == nil || result.Error.Code != wantErrs[i] {
+					t.Fatalf("expected error %d for ticket %s, got %+v", wantErrs[i],
+						result.TicketHash, result)
+				}
+			}
+
+			if resp.Tickets[2].Status.AltSignAddress != owner.String() {
+				t.Fatalf("expected alt sign address %s, got %s", owner,
+					resp.Tickets[2].Status.AltSignAddress)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// streamKeepAlive is the time period between comments sent on idle ticket
+	// status streams, so they are not closed by proxies or clients which time
+	// out inactive connections.
+	streamKeepAlive = 30 * time.Second
+
+	// streamWriteTimeout is the maximum time allowed to write a single event to
+	// a ticket status stream. It replaces the write timeout of the server,
+	// which would otherwise end every stream.
+	streamWriteTimeout = 10 * time.Second
+
+	// maxStreams is the maximum number of ticket status streams which can be
+	// open at once, and maxStreamsPerIP is the maximum number which can be
+	// opened by a single client IP. Each stream holds a connection until the
+	// client disconnects.
+	maxStreams      = 1000
+	maxStreamsPerIP = 10
+)
+
+// streamLimiter counts the open ticket status streams, in total and for each
+// client IP. The zero value is ready to use.
+type streamLimiter struct {
+	mtx   sync.Mutex
+	total int
+	perIP map[string]int
+}
+
+// acquire reserves a stream for ip, and returns false if the limit of streams
+// has been reached. Each successful call must be followed by a call to
+// release.
+func (l *streamLimiter) acquire(ip string) bool {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if l.total >= maxStreams || l.perIP[ip] >= maxStreamsPerIP {
+		return false
+	}
+
+	if l.perIP == nil {
+		l.perIP = make(map[string]int)
+	}
+	l.total++
+	l.perIP[ip]++
+	return true
+}
+
+// release frees a stream reserved by acquire.
+func (l *streamLimiter) release(ip string) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.total--
+	l.perIP[ip]--
+	if l.perIP[ip] == 0 {
+		delete(l.perIP, ip)
+	}
+}
+
+// ticketStatusStream is the handler for "POST /api/v3/ticketstatusstream". The
+// request is the same as a request to /ticketstatusbatch, and is authenticated
+// in the same way. The response is a stream of server-sent events. The first
+// event contains the status of every requested ticket, and each further event
+// contains the status of the tickets which have changed since the previous
+// event. Only changes of the tickets owned by the signer of the request are
+// sent. The stream continues until the client disconnects or the server shuts
+// down.
+func (w *WebAPI) ticketStatusStream(c *gin.Context) {
+	const funcName = "ticketStatusStream"
+
+	request, reqBytes, ok := w.bindTicketStatusBatch(c, funcName)
+	if !ok {
+		return
+	}
+
+	// Find the tickets owned by the signer of the request. Changes of any
+	// other ticket must not be sent, or they would reveal which tickets are
+	// managed by the VSP and when their state changes.
+	results, err := w.ticketStatusResults(request.TicketHashes, request.Address)
+	if err != nil {
+		w.log.Errorf("%s: Failed to get ticket status: %v", funcName, err)
+		w.sendError(types.ErrInternalError, c)
+		return
+	}
+	owned := make([]string, 0, len(results))
+	for _, result := range results {
+		if result.Error == nil {
+			owned = append(owned, result.TicketHash)
+		}
+	}
+	if len(owned) == 0 {
+		w.log.Warnf("%s: No requested tickets owned by signer (clientIP=%s, address=%s)",
+			funcName, c.ClientIP(), request.Address)
+		w.sendErrorWithMsg("no requested tickets are owned by the signing address",
+			types.ErrBadSignature, c)
+		return
+	}
+
+	clientIP := c.ClientIP()
+	if !w.streams.acquire(clientIP) {
+		w.log.Warnf("%s: Too many streams (clientIP=%s)", funcName, clientIP)
+		w.sendErrorWithMsg("too many ticket status streams are open", types.ErrBadRequest, c)
+		return
+	}
+	defer w.streams.release(clientIP)
+
+	// Subscribe before reading the status of the tickets again so that no
+	// changes can be missed.
+	sub := w.feed.Subscribe(owned)
+	defer sub.Close()
+
+	results, err = w.ticketStatusResults(request.TicketHashes, request.Address)
+	if err != nil {
+		w.log.Errorf("%s: Failed to get ticket status: %v", funcName, err)
+		w.sendError(types.ErrInternalError, c)
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	// Prevent reverse proxies such as nginx from buffering events.
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+
+	// write sends data to the client without waiting for the response buffer to
+	// fill.
+	write := func(data string) error {
+		err := rc.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		_, err = c.Writer.WriteString(data)
+		if err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	// sendStatus sends an event containing the status of tickets.
+	sendStatus := func(results []types.TicketStatusResult) error {
+		event, err := w.ticketStatusStreamEvent(results, reqBytes)
+		if err != nil {
+			return err
+		}
+		return write(fmt.Sprintf("event: status\ndata: %s\n\n", event))
+	}
+
+	err = sendStatus(results)
+	if err != nil {
+		w.log.Warnf("%s: Failed to send ticket status (clientIP=%s): %v", funcName, c.ClientIP(), err)
+		return
+	}
+
+	keepAlive := time.NewTicker(streamKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-sub.Ready():
+			results, err := w.ticketStatusResults(sub.Changed(), request.Address)
+			if err != nil {
+				w.log.Errorf("%s: Failed to get ticket status: %v", funcName, err)
+				return
+			}
+			err = sendStatus(results)
+			if err != nil {
+				w.log.Warnf("%s: Failed to send ticket status (clientIP=%s): %v",
+					funcName, c.ClientIP(), err)
+				return
+			}
+
+		case <-keepAlive.C:
+			err = write(": keepalive\n\n")
+			if err != nil {
+				w.log.Debugf("%s: Failed to send keepalive (clientIP=%s): %v",
+					funcName, c.ClientIP(), err)
+				return
+			}
+
+		case <-c.Request.Context().Done():
+			return
+
+		case <-w.stopStreams:
+			return
+		}
+	}
+}
+
+// ticketStatusStreamEvent returns the data of a ticket status stream event
+// containing results. The event is signed because, unlike other responses,
+// the events of a stream cannot be signed with a header.
+func (w *WebAPI) ticketStatusStreamEvent(results []types.TicketStatusResult,
+	reqBytes []byte) ([]byte, error) {
+
+	msg, err := json.Marshal(types.TicketStatusBatchResponse{
+		Timestamp: time.Now().Unix(),
+		Tickets:   results,
+		Request:   reqBytes,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	return json.Marshal(types.TicketStatusStreamEvent{
+		Message:   msg,
+		Signature: ed25519.Sign(w.signPrivKey, msg),
+	})
+}
@@ -0,0 +1,287 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/dcrd/txscript/v4/stdaddr"
+	"github.com/decred/vspd/database"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+)
+
+func TestTicketStatusStream(t *testing.T) {
+	// newKey returns a new private key and its address.
+	newKey := func() (*secp256k1.PrivateKey, stdaddr.Address) {
+		t.Helper()
+		key, err := secp256k1.GeneratePrivateKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkHash := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+		addr, err := stdaddr.NewAddressPubKeyHashEcdsaSecp256k1V0(pkHash, api.cfg.Network)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return key, addr
+	}
+
+	key, owner := newKey()
+	otherKey, other := newKey()
+	strangerKey, stranger := newKey()
+
+	tickets := make([]database.Ticket, 2)
+	hashes := make([]string, len(tickets))
+	for i := range tickets {
+		tickets[i] = database.Ticket{
+			Hash:              randString(64, hexCharset),
+			CommitmentAddress: owner.String(),
+			FeeAddress:        randString(35, hexCharset),
+			FeeTxStatus:       database.FeeReceieved,
+		}
+		hashes[i] = tickets[i].Hash
+		err := api.db.InsertNewTicket(tickets[i])
+		if err != nil {
+			t.Fatalf("error storing ticket: %v", err)
+		}
+	}
+
+	// A ticket owned by someone else is also requested. Its status is not
+	// included, and its changes must not be streamed.
+	otherTicket := database.Ticket{
+		Hash:              randString(64, hexCharset),
+		CommitmentAddress: other.String(),
+		FeeAddress:        randString(35, hexCharset),
+		FeeTxStatus:       database.FeeReceieved,
+	}
+	err := api.db.InsertNewTicket(otherTicket)
+	if err != nil {
+		t.Fatalf("error storing ticket: %v", err)
+	}
+	hashes = append(hashes, otherTicket.Hash)
+
+	spec, err := apiSpec(3)
+	if err != nil {
+		t.Fatalf("error generating document: %v", err)
+	}
+	eventSchema := spec.Paths["/api/v3/ticketstatusstream"].Post.
+		Responses["200"].Content["text/event-stream"].Schema
+
+	r := gin.New()
+	r.POST("/", api.ticketStatusStream)
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// post sends a request for the status of every ticket signed by key,
+	// which must be the key of addr.
+	post := func(key *secp256k1.PrivateKey, addr stdaddr.Address,
+		timestamp int64) (*http.Response, []byte) {
+		t.Helper()
+
+		reqBytes, err := json.Marshal(types.TicketStatusBatchRequest{
+			Timestamp:    timestamp,
+			Address:      addr.String(),
+			TicketHashes: hashes,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL,
+			bytes.NewReader(reqBytes))
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("VSP-Client-Signature", signMessage(t, key, string(reqBytes)))
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, reqBytes
+	}
+
+	now := time.Now().Unix()
+
+	// Requests which cannot start a stream are rejected with an error.
+	tests := map[string]struct {
+		key       *secp256k1.PrivateKey
+		addr      stdaddr.Address
+		timestamp int64
+		wantCode  types.ErrorCode
+	}{
+		"signed by a different key": {
+			key:       otherKey,
+			addr:      owner,
+			timestamp: now,
+			wantCode:  types.ErrBadSignature,
+		},
+		"signer owns no tickets": {
+			key:       strangerKey,
+			addr:      stranger,
+			timestamp: now,
+			wantCode:  types.ErrBadSignature,
+		},
+		"old timestamp": {
+			key:       key,
+			addr:      owner,
+			timestamp: now - 600,
+			wantCode:  types.ErrInvalidTimestamp,
+		},
+	}
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			resp, _ := post(test.key, test.addr, test.timestamp)
+			defer resp.Body.Close()
+
+			var apiErr types.ErrorResponse
+			err := json.NewDecoder(resp.Body).Decode(&apiErr)
+			if err != nil {
+				t.Fatalf("could not decode error: %v", err)
+			}
+			if resp.StatusCode != test.wantCode.HTTPStatus() || apiErr.Code != test.wantCode {
+				t.Fatalf("expected error %d, got status %d and error %+v", test.wantCode,
+					resp.StatusCode, apiErr)
+			}
+		})
+	}
+
+	resp, reqBytes := post(key, owner, now)
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "text/event-stream" {
+		t.Fatalf("unexpected content type %q", contentType)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(nil, 1<<20)
+
+	// next reads the next event from the stream, validates it and returns the
+	// status of the tickets it contains.
+	next := func() []types.TicketStatusResult {
+		t.Helper()
+
+		var lines []string
+		for scanner.Scan() {
+			if scanner.Text() == "" {
+				break
+			}
+			lines = append(lines, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			t.Fatalf("error reading stream: %v", err)
+		}
+		if len(lines) != 2 || lines[0] != "event: status" || !strings.HasPrefix(lines[1], "data: ") {
+			t.Fatalf("unexpected event %q", lines)
+		}
+		data := []byte(strings.TrimPrefix(lines[1], "data: "))
+
+		err := spec.Validate(eventSchema, data)
+		if err != nil {
+			t.Fatalf("event does not match the document: %v", err)
+		}
+
+		var event types.TicketStatusStreamEvent
+		err = json.Unmarshal(data, &event)
+		if err != nil {
+			t.Fatalf("could not unmarshal event: %v", err)
+		}
+		if !ed25519.Verify(api.signPrivKey.Public().(ed25519.PublicKey), event.Message, event.Signature) {
+			t.Fatal("invalid event signature")
+		}
+
+		var msg types.TicketStatusBatchResponse
+		err = json.Unmarshal(event.Message, &msg)
+		if err != nil {
+			t.Fatalf("could not unmarshal event message: %v", err)
+		}
+		if !bytes.Equal(msg.Request, reqBytes) {
+			t.Fatal("event contains differing request")
+		}
+		return msg.Tickets
+	}
+
+	// The first event contains a result for every requested ticket, and the
+	// status of every ticket owned by the signer.
+	results := next()
+	if len(results) != len(hashes) {
+		t.Fatalf("expected %d results, got %d", len(hashes), len(results))
+	}
+	for i, result := range results[:len(tickets)] {
+		if result.TicketHash != hashes[i] || result.Status == nil ||
+			result.Status.FeeTxStatus != string(database.FeeReceieved) {
+			t.Fatalf("unexpected result %+v", result)
+		}
+	}
+	if result := results[len(tickets)]; result.TicketHash != otherTicket.Hash ||
+		result.Status != nil || result.Error == nil || result.Error.Code != types.ErrBadSignature {
+		t.Fatalf("unexpected result %+v", result)
+	}
+
+	// Further events contain only the tickets which have changed.
+	changed := tickets[1]
+	changed.FeeTxStatus = database.FeeBroadcast
+	err = api.db.UpdateTicket(changed)
+	if err != nil {
+		t.Fatalf("error updating ticket: %v", err)
+	}
+	api.feed.Publish(otherTicket.Hash)
+	api.feed.Publish(changed.Hash)
+	api.feed.Publish(randString(64, hexCharset))
+
+	results = next()
+	if len(results) != 1 || results[0].TicketHash != changed.Hash || results[0].Status == nil ||
+		results[0].Status.FeeTxStatus != string(database.FeeBroadcast) {
+		t.Fatalf("unexpected results %+v", results)
+	}
+}
+
+func TestStreamLimiter(t *testing.T) {
+	var l streamLimiter
+
+	for i := 0; i < maxStreamsPerIP; i++ {
+		if !l.acquire("1.1.1.1") {
+			t.Fatalf("expected stream %d to be allowed", i)
+		}
+	}
+	if l.acquire("1.1.1.1") {
+		t.Fatal("expected stream over the per IP limit to be rejected")
+	}
+	if !l.acquire("2.2.2.2") {
+		t.Fatal("expected stream from a different IP to be allowed")
+	}
+
+	l.release("1.1.1.1")
+	if !l.acquire("1.1.1.1") {
+		t.Fatal("expected stream to be allowed after one was released")
+	}
+
+	// Streams from every IP count towards the global limit.
+	for i := l.total; i < maxStreams; i++ {
+		if !l.acquire(fmt.Sprintf("10.0.%d.%d", i/256, i%256)) {
+			t.Fatalf("expected stream %d to be allowed", i)
+		}
+	}
+	if l.acquire("3.3.3.3") {
+		t.Fatal("expected stream over the global limit to be rejected")
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -12,18 +12,25 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-// vspInfo is the handler for "GET /api/v3/vspinfo".
+// vspInfo is the handler for "GET /api/v3/vspinfo" and
+// "GET /api/v4/vspinfo".
 func (w *WebAPI) vspInfo(c *gin.Context) {
 	cachedStats := c.MustGet(cacheKey).(cacheData)
+	cfg := w.config()
+
+	// Clients which do not understand the fee schedule only read the fee
+	// percentage, so it is the fee which would be charged for a ticket
+	// without a discount at the current ticket price.
+	feePercentage, _ := w.feeSchedule.Load().Fee(cachedStats.TicketPrice, "", time.Now())
 
 	w.sendJSONResponse(types.VspInfoResponse{
-		APIVersions:         []int64{3},
+		APIVersions:         []int64{3, 4},
 		Timestamp:           time.Now().Unix(),
 		PubKey:              w.signPubKey,
-		FeePercentage:       w.cfg.VSPFee,
-		Network:             w.cfg.Network.Name,
-		VspClosed:           w.cfg.VspClosed,
-		VspClosedMsg:        w.cfg.VspClosedMsg,
+		FeePercentage:       feePercentage,
+		Network:             cfg.Network.Name,
+		VspClosed:           cfg.VspClosed,
+		VspClosedMsg:        cfg.VspClosedMsg,
 		VspdVersion:         version.String(),
 		Voting:              cachedStats.Voting,
 		Voted:               cachedStats.Voted,
@@ -33,5 +40,7 @@ func (w *WebAPI) vspInfo(c *gin.Context) {
 		Missed:              cachedStats.Missed,
 		BlockHeight:         cachedStats.BlockHeight,
 		NetworkProportion:   cachedStats.NetworkProportion,
+		FeeSchedule:         w.publishedFeeSchedule(),
+		Maintenance:         w.publishedMaintenance(),
 	}, c)
 }
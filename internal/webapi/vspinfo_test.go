@@ -0,0 +1,74 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/dcrutil/v4"
+	"github.com/decred/vspd/internal/feeschedule"
+	"github.com/decred/vspd/types/v3"
+	"github.com/gin-gonic/gin"
+)
+
+// TestVspInfoFeePercentage ensures the fee percentage published by /vspinfo is
+// the fee charged by the fee schedule at the current ticket price.
+func TestVspInfoFeePercentage(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeschedule.json")
+	err := os.WriteFile(path, []byte(`{
+		"tiers": [{"name": "large", "minticketprice": 300, "feepercentage": 1}]
+	}`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schedule, err := feeschedule.Load(path, 3, api.cfg.Network.Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := api.feeSchedule.Swap(schedule)
+	defer api.feeSchedule.Store(previous)
+	if api.maintenance.Load() == nil {
+		api.maintenance.Store(&maintenanceSchedule{})
+	}
+
+	tests := map[string]struct {
+		ticketPrice dcrutil.Amount
+		want        float64
+	}{
+		"below tier": {ticketPrice: 100 * dcrutil.AtomsPerCoin, want: 3},
+		"tier":       {ticketPrice: 400 * dcrutil.AtomsPerCoin, want: 1},
+	}
+
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			_, r := gin.CreateTestContext(w)
+			r.GET("/", func(c *gin.Context) {
+				c.Set(cacheKey, cacheData{Initialized: true, TicketPrice: test.ticketPrice})
+			}, api.vspInfo)
+
+			req, err := http.NewRequest(http.MethodGet, "/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.ServeHTTP(w, req)
+
+			var resp types.VspInfoResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			if err != nil {
+				t.Fatalf("could not unmarshal response: %v", err)
+			}
+			if resp.FeePercentage != test.want {
+				t.Fatalf("expected fee percentage %v, got %v", test.want, resp.FeePercentage)
+			}
+		})
+	}
+}
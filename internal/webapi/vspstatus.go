@@ -0,0 +1,150 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webapi
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/decred/vspd/database"
+	"github.com/gin-gonic/gin"
+)
+
+// vspStatusRequest is the body of a request to the /admin/vspstatus endpoint
+// which opens or closes the VSP.
+type vspStatusRequest struct {
+	Closed    *bool  `json:"closed" binding:"required"`
+	ClosedMsg string `json:"closedmsg"`
+}
+
+// vspStatusResponse is the JSON representation of the status of the VSP
+// returned by the /admin/vspstatus endpoint. History lists every change made
+// while vspd was running, newest first.
+type vspStatusResponse struct {
+	Closed    bool                       `json:"closed"`
+	ClosedMsg string                     `json:"closedmsg"`
+	History   []database.VspStatusChange `json:"history"`
+}
+
+// SetVspStatus opens or closes the VSP and records the change, along with who
+// made it, in the database. The new status takes effect immediately and is kept
+// when vspd is restarted.
+func (w *WebAPI) SetVspStatus(closed bool, closedMsg, changedBy string) error {
+	w.cfgMtx.Lock()
+	defer w.cfgMtx.Unlock()
+
+	err := w.db.SetVspStatus(database.VspStatusChange{
+		Timestamp: time.Now().Unix(),
+		Closed:    closed,
+		ClosedMsg: closedMsg,
+		ChangedBy: changedBy,
+	})
+	if err != nil {
+		return err
+	}
+
+	w.cfg.VspClosed = closed
+	w.cfg.VspClosedMsg = closedMsg
+
+	if closed {
+		w.log.Infof("VSP closed by %s (message=%q)", changedBy, closedMsg)
+	} else {
+		w.log.Infof("VSP opened by %s", changedBy)
+	}
+
+	return nil
+}
+
+// applyStoredVspStatus updates cfg with the most recent status set by
+// SetVspStatus, which takes precedence over the vspclosed and vspclosedmsg
+// config options.
+func (w *WebAPI) applyStoredVspStatus(cfg *Config) error {
+	status, found, err := w.db.VspStatus()
+	if err != nil {
+		return err
+	}
+	if !found {
+		return nil
+	}
+
+	if status.Closed != cfg.VspClosed || status.ClosedMsg != cfg.VspClosedMsg {
+		w.log.Warnf("Config options vspclosed and vspclosedmsg are overridden by the "+
+			"status set by %s at %s", status.ChangedBy, dateTime(status.Timestamp))
+	}
+
+	cfg.VspClosed = status.Closed
+	cfg.VspClosedMsg = status.ClosedMsg
+
+	return nil
+}
+
+// vspStatusHistory returns every change to the status of the VSP, newest
+// first.
+func (w *WebAPI) vspStatusHistory() ([]database.VspStatusChange, error) {
+	history, err := w.db.VspStatusHistory()
+	if err != nil {
+		return nil, err
+	}
+	slices.Reverse(history)
+	return history, nil
+}
+
+// setVspStatus is the handler for "POST /admin/vspstatus".
+func (w *WebAPI) setVspStatus(c *gin.Context) {
+	closed := c.PostForm("closed") != ""
+	changedBy := fmt.Sprintf("admin page (%s)", c.ClientIP())
+
+	err := w.SetVspStatus(closed, c.PostForm("closedmsg"), changedBy)
+	if err != nil {
+		w.log.Errorf("Failed to set VSP status: %v", err)
+		c.String(http.StatusInternalServerError, "Error setting VSP status")
+		return
+	}
+
+	c.Redirect(http.StatusFound, "/admin")
+	c.Abort()
+}
+
+// vspStatusJSON is the handler for "GET /admin/vspstatus". It returns a JSON
+// object describing the current status of the VSP and how it was set.
+func (w *WebAPI) vspStatusJSON(c *gin.Context) {
+	history, err := w.vspStatusHistory()
+	if err != nil {
+		w.log.Errorf("db.VspStatusHistory error: %v", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	cfg := w.config()
+	c.AbortWithStatusJSON(http.StatusOK, vspStatusResponse{
+		Closed:    cfg.VspClosed,
+		ClosedMsg: cfg.VspClosedMsg,
+		History:   history,
+	})
+}
+
+// setVspStatusJSON is the handler for "PUT /admin/vspstatus". The request body
+// is a JSON object which sets the new status of the VSP, and the response is
+// the same as "GET /admin/vspstatus".
+func (w *WebAPI) setVspStatusJSON(c *gin.Context) {
+	var request vspStatusRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	changedBy := fmt.Sprintf("admin API (%s)", c.ClientIP())
+	err = w.SetVspStatus(*request.Closed, request.ClosedMsg, changedBy)
+	if err != nil {
+		w.log.Errorf("Failed to set VSP status: %v", err)
+		c.AbortWithStatus(http.StatusInternalServerError)
+		return
+	}
+
+	w.vspStatusJSON(c)
+}
@@ -15,14 +15,22 @@ import (
 	"html/template"
 	"net"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/decred/slog"
 	"github.com/decred/vspd/database"
 	"github.com/decred/vspd/internal/config"
+	"github.com/decred/vspd/internal/feeschedule"
+	"github.com/decred/vspd/internal/metrics"
+	"github.com/decred/vspd/internal/ticketfeed"
+	"github.com/decred/vspd/internal/webhook"
 	"github.com/decred/vspd/rpc"
 	"github.com/decred/vspd/types/v3"
+	"github.com/decred/vspd/types/v3/apiv4"
 	"github.com/dustin/go-humanize"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/sessions"
@@ -41,6 +49,9 @@ type Config struct {
 	Designation          string
 	MaxVoteChangeRecords int
 	VspdVersion          string
+	FeeXPubPolicy        XPubPolicy
+	FeeXPubWeights       map[uint32]uint32
+	FeeSchedule          string
 }
 
 const (
@@ -65,23 +76,54 @@ const (
 	ticketKey            = "Ticket"
 	knownTicketKey       = "KnownTicket"
 	commitmentAddressKey = "CommitmentAddress"
+	apiV4Key             = "APIv4Request"
 )
 
 type WebAPI struct {
+	// cfgMtx protects the fields of cfg which can be changed by Reconfigure,
+	// along with adminPassHash and adminSession.
+	cfgMtx        sync.RWMutex
 	cfg           Config
-	db            *database.VspDatabase
+	db            database.Database
 	log           slog.Logger
-	addrGen       *addressGenerator
+	addrGen       *addressSelector
 	cache         *cache
+	cookieSecret  []byte
 	adminPassHash [sha256.Size]byte
+	adminSession  [sha256.Size]byte
 	signPrivKey   ed25519.PrivateKey
 	signPubKey    ed25519.PublicKey
 	server        *http.Server
 	listener      net.Listener
+	metrics       *metrics.Metrics
+	webhook       *webhook.Notifier
+	feed          *ticketfeed.Feed
+	feeReports    *feeReports
+
+	// stopStreams is closed when the server is shutting down, to end the
+	// ticket status streams which would otherwise never finish.
+	stopStreams chan struct{}
+	// streams limits the number of open ticket status streams.
+	streams streamLimiter
+
+	// feeSchedule is replaced whenever the fee schedule file is reloaded.
+	feeSchedule atomic.Pointer[feeschedule.Schedule]
+	// maintenance is replaced whenever a maintenance window is added or
+	// deleted.
+	maintenance atomic.Pointer[maintenanceSchedule]
+	// maintenanceMtx serializes changes to the stored maintenance windows with
+	// reloading them, so that a stale schedule cannot replace a newer one.
+	maintenanceMtx sync.Mutex
+	// nonces prevents signed /api/v4 requests from being replayed. It is only
+	// held in memory, so requests created before started are rejected to
+	// prevent them being replayed after a restart.
+	nonces  nonceCache
+	started time.Time
 }
 
-func New(vdb *database.VspDatabase, log slog.Logger, dcrd rpc.DcrdConnect,
-	wallets rpc.WalletConnect, cfg Config) (*WebAPI, error) {
+func New(vdb database.Database, log slog.Logger, dcrd rpc.DcrdConnect,
+	wallets rpc.WalletConnect, metrics *metrics.Metrics, webhook *webhook.Notifier,
+	feed *ticketfeed.Feed, cfg Config) (*WebAPI, error) {
 
 	// Get keys for signing API responses from the database.
 	signPrivKey, signPubKey, err := vdb.KeyPair()
@@ -97,29 +139,40 @@ func New(vdb *database.VspDatabase, log slog.Logger, dcrd rpc.DcrdConnect,
 		log.Errorf("Could not initialize VSP stats cache: %v", err)
 	}
 
-	// Get the current fee xpub details from the database.
-	feeXPub, err := vdb.FeeXPub()
+	// Get the details of every active fee xpub from the database.
+	feeXPubs, err := database.ActiveXPubs(vdb)
 	if err != nil {
-		return nil, fmt.Errorf("db.FeeXPub error: %w", err)
+		return nil, fmt.Errorf("db.AllXPubs error: %w", err)
 	}
 
-	// Use the retrieved pubkey to initialize an address generator which can
+	for id := range cfg.FeeXPubWeights {
+		if !slices.ContainsFunc(feeXPubs, func(x database.FeeXPub) bool { return x.ID == id }) {
+			log.Warnf("A weight is configured for fee xpub %d which is not active", id)
+		}
+	}
+
+	// Use the retrieved pubkeys to initialize an address selector which can
 	// later be used to derive new fee addresses.
-	addrGen, err := newAddressGenerator(feeXPub, cfg.Network.Params, log)
+	addrGen, err := newAddressSelector(feeXPubs, cfg.FeeXPubPolicy, cfg.FeeXPubWeights,
+		cfg.Network.Params, log)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize fee address generator: %w", err)
 	}
 
-	// Get the secret key used to initialize the cookie store.
-	cookieSecret, err := vdb.CookieSecret()
+	if len(feeXPubs) > 1 {
+		log.Infof("Deriving fee addresses from %d active xpubs using %s policy",
+			len(feeXPubs), cfg.FeeXPubPolicy)
+	}
+
+	feeSchedule, err := feeschedule.Load(cfg.FeeSchedule, cfg.VSPFee, cfg.Network.Params)
 	if err != nil {
-		return nil, fmt.Errorf("db.GetCookieSecret error: %w", err)
+		return nil, fmt.Errorf("failed to load fee schedule: %w", err)
 	}
 
-	// Create TCP listener.
-	listener, err := net.Listen("tcp", cfg.Listen)
+	// Get the secret key used to initialize the cookie store.
+	cookieSecret, err := vdb.CookieSecret()
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("db.GetCookieSecret error: %w", err)
 	}
 
 	w := &WebAPI{
@@ -128,10 +181,36 @@ func New(vdb *database.VspDatabase, log slog.Logger, dcrd rpc.DcrdConnect,
 		log:           log,
 		addrGen:       addrGen,
 		cache:         cache,
+		cookieSecret:  cookieSecret,
 		adminPassHash: sha256.Sum256([]byte(cfg.AdminPass)),
+		adminSession:  adminSessionToken(cookieSecret, cfg.AdminPass),
 		signPrivKey:   signPrivKey,
 		signPubKey:    signPubKey,
-		listener:      listener,
+		metrics:       metrics,
+		webhook:       webhook,
+		feed:          feed,
+		feeReports:    newFeeReports(),
+		stopStreams:   make(chan struct{}),
+		started:       time.Now(),
+	}
+	w.feeSchedule.Store(feeSchedule)
+
+	// The VSP may have been opened or closed by an admin since the config was
+	// written.
+	err = w.applyStoredVspStatus(&w.cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get VSP status: %w", err)
+	}
+
+	err = w.loadMaintenanceSchedule()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get maintenance windows: %w", err)
+	}
+
+	// Create TCP listener.
+	w.listener, err = net.Listen("tcp", cfg.Listen)
+	if err != nil {
+		return nil, err
 	}
 
 	w.server = &http.Server{
@@ -143,6 +222,40 @@ func New(vdb *database.VspDatabase, log slog.Logger, dcrd rpc.DcrdConnect,
 	return w, nil
 }
 
+// config returns a copy of the current config. It must be used to read any of
+// the options which can be changed by Reconfigure.
+func (w *WebAPI) config() Config {
+	w.cfgMtx.RLock()
+	defer w.cfgMtx.RUnlock()
+	return w.cfg
+}
+
+// Reconfigure applies the options of cfg which can be changed while the server
+// is running. These are VSPFee, FeeSchedule, SupportEmail and AdminPass. All
+// other fields of cfg are ignored, including VspClosed and VspClosedMsg which
+// can only be changed with SetVspStatus. Nothing is changed if the fee schedule
+// cannot be loaded with the new options. Changing AdminPass ends every existing
+// admin session.
+func (w *WebAPI) Reconfigure(cfg Config) error {
+	w.cfgMtx.Lock()
+	defer w.cfgMtx.Unlock()
+
+	schedule, err := feeschedule.Load(cfg.FeeSchedule, cfg.VSPFee, w.cfg.Network.Params)
+	if err != nil {
+		return fmt.Errorf("failed to load fee schedule: %w", err)
+	}
+
+	w.cfg.VSPFee = cfg.VSPFee
+	w.cfg.FeeSchedule = cfg.FeeSchedule
+	w.cfg.SupportEmail = cfg.SupportEmail
+	w.cfg.AdminPass = cfg.AdminPass
+	w.adminPassHash = sha256.Sum256([]byte(cfg.AdminPass))
+	w.adminSession = adminSessionToken(w.cookieSecret, cfg.AdminPass)
+	w.feeSchedule.Store(schedule)
+
+	return nil
+}
+
 func (w *WebAPI) Run(ctx context.Context) {
 	var wg sync.WaitGroup
 
@@ -153,6 +266,8 @@ func (w *WebAPI) Run(ctx context.Context) {
 
 		w.log.Debug("Stopping webserver...")
 
+		close(w.stopStreams)
+
 		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*10)
 		_ = w.server.Shutdown(shutdownCtx)
 		cancel()
@@ -190,6 +305,11 @@ func (w *WebAPI) Run(ctx context.Context) {
 		}
 	})
 
+	// Generate fee reports requested by admins.
+	wg.Go(func() {
+		w.runFeeReports(ctx)
+	})
+
 	wg.Wait()
 }
 
@@ -245,12 +365,35 @@ func (w *WebAPI) router(cookieSecret []byte, dcrd rpc.DcrdConnect, wallets rpc.W
 	broadcastTicket := w.broadcastTicket()
 
 	api := router.Group("/api/v3")
+	api.Use(w.withMetrics)
+	api.GET("/openapi.json", w.openAPI(3))
 	api.GET("/vspinfo", w.requireWebCache, w.vspInfo)
 	api.POST("/setaltsignaddr", w.vspMustBeOpen, w.withDcrdClient(dcrd), broadcastTicket, w.vspAuth, w.setAltSignAddr)
-	api.POST("/feeaddress", w.vspMustBeOpen, w.withDcrdClient(dcrd), broadcastTicket, w.vspAuth, w.feeAddress)
+	api.POST("/feeaddress", w.vspMustBeOpen, w.vspNotInMaintenance, w.withDcrdClient(dcrd), broadcastTicket, w.vspAuth, w.feeAddress)
 	api.POST("/ticketstatus", w.withDcrdClient(dcrd), w.vspAuth, w.ticketStatus)
-	api.POST("/payfee", w.vspMustBeOpen, w.withDcrdClient(dcrd), w.vspAuth, w.payFee)
+	api.POST("/ticketstatusbatch", w.ticketStatusBatch)
+	api.POST("/ticketstatusstream", w.ticketStatusStream)
+	api.POST("/payfee", w.vspMustBeOpen, w.vspNotInMaintenance, w.withDcrdClient(dcrd), w.vspAuth, w.payFee)
 	api.POST("/setvotechoices", w.withDcrdClient(dcrd), w.withWalletClients(wallets), w.vspAuth, w.setVoteChoices)
+	api.POST("/setvotechoicesbatch", w.withWalletClients(wallets), w.setVoteChoicesBatch)
+
+	// Version 4 of the API is served by the same handlers as version 3. The
+	// withEnvelope middleware converts each request into its version 3
+	// equivalent, and sendJSONResponse converts the response.
+	apiV4 := router.Group("/api/v4")
+	apiV4.Use(w.withMetrics, w.withAPIV4)
+	apiV4.GET("/openapi.json", w.openAPI(4))
+	apiV4.GET("/vspinfo", w.requireWebCache, w.vspInfo)
+	apiV4.POST("/setaltsignaddr", w.withEnvelope(apiv4.SetAltSignAddrRequest{}), w.vspMustBeOpen,
+		w.withDcrdClient(dcrd), broadcastTicket, w.vspAuth, w.checkNonce, w.setAltSignAddr)
+	apiV4.POST("/feeaddress", w.withEnvelope(apiv4.FeeAddressRequest{}), w.vspMustBeOpen, w.vspNotInMaintenance,
+		w.withDcrdClient(dcrd), broadcastTicket, w.vspAuth, w.checkNonce, w.feeAddress)
+	apiV4.POST("/ticketstatus", w.withEnvelope(apiv4.TicketStatusRequest{}),
+		w.withDcrdClient(dcrd), w.vspAuth, w.checkNonce, w.ticketStatus)
+	apiV4.POST("/payfee", w.withEnvelope(apiv4.PayFeeRequest{}), w.vspMustBeOpen, w.vspNotInMaintenance,
+		w.withDcrdClient(dcrd), w.vspAuth, w.checkNonce, w.payFee)
+	apiV4.POST("/setvotechoices", w.withEnvelope(apiv4.SetVoteChoicesRequest{}),
+		w.withDcrdClient(dcrd), w.withWalletClients(wallets), w.vspAuth, w.checkNonce, w.setVoteChoices)
 
 	// Website routes.
 
@@ -267,7 +410,7 @@ func (w *WebAPI) router(cookieSecret []byte, dcrd rpc.DcrdConnect, wallets rpc.W
 		w.log.Warnf("Login rate limit exceeded by %s", c.ClientIP())
 		c.HTML(http.StatusTooManyRequests, "login.html", gin.H{
 			"WebApiCache":    cacheData,
-			"WebApiCfg":      w.cfg,
+			"WebApiCfg":      w.config(),
 			"FailedLoginMsg": "Rate limit exceeded",
 		})
 	})
@@ -281,7 +424,14 @@ func (w *WebAPI) router(cookieSecret []byte, dcrd rpc.DcrdConnect, wallets rpc.W
 
 	admin.GET("", w.withDcrdClient(dcrd), w.adminPage)
 	admin.POST("/ticket", w.withDcrdClient(dcrd), w.ticketSearch)
+	admin.GET("/ticket/history", w.downloadTicketHistory)
 	admin.GET("/backup", w.downloadDatabaseBackup)
+	admin.GET("/fees", w.downloadFeeReconciliation)
+	admin.POST("/fees", w.withDcrdClient(dcrd), w.generateFeeReport)
+	admin.POST("/feeschedule", w.reloadFeeSchedule)
+	admin.POST("/vspstatus", w.setVspStatus)
+	admin.POST("/maintenance", w.addMaintenanceWindow)
+	admin.POST("/maintenance/delete", w.deleteMaintenanceWindow)
 	admin.POST("/logout", w.adminLogout)
 
 	// Limit status endpoint attempts to 3 per second.
@@ -295,33 +445,70 @@ func (w *WebAPI) router(cookieSecret []byte, dcrd rpc.DcrdConnect, wallets rpc.W
 		statusRateLmiter,
 		w.withDcrdClient(dcrd),
 		w.withWalletClients(wallets),
-		gin.BasicAuth(gin.Accounts{
-			"admin": w.cfg.AdminPass,
-		}),
+		w.requireBasicAuth,
 	)
 	basic.GET("/status", w.statusJSON)
+	basic.GET("/vspstatus", w.vspStatusJSON)
+	basic.PUT("/vspstatus", w.setVspStatusJSON)
+
+	// Prometheus metrics endpoint also requires Basic HTTP Auth, and uses its
+	// own rate limiter so scraping does not interfere with status checks.
+	metricsRateLimiter := rateLimit(3, func(c *gin.Context) {
+		w.log.Warnf("Metrics rate limit exceeded by %s", c.ClientIP())
+		c.AbortWithStatus(http.StatusTooManyRequests)
+	})
+	// Authentication is checked first so RPC clients are only set up for
+	// authorized requests.
+	router.GET("/metrics",
+		metricsRateLimiter,
+		w.requireBasicAuth,
+		w.withDcrdClient(dcrd),
+		w.withWalletClients(wallets),
+		w.metricsHandler,
+	)
 
 	return router
 }
 
 // sendJSONResponse serializes the provided response, signs it, and sends the
-// response to the client with a 200 OK status. Returns the seralized response
-// and the signature.
+// response to the client with a 200 OK status. Responses to /api/v4 requests
+// are converted to their version 4 body and wrapped in a response envelope.
+// Returns the seralized response and the signature.
 func (w *WebAPI) sendJSONResponse(resp any, c *gin.Context) (string, string) {
-	dec, err := json.Marshal(resp)
+	if req, ok := apiV4(c); ok {
+		body, err := json.Marshal(apiV4Body(resp))
+		if err != nil {
+			w.log.Errorf("JSON marshal error: %v", err)
+			w.sendError(types.ErrInternalError, c)
+			return "", ""
+		}
+		resp = req.response(body, nil)
+	}
+
+	dec, sigStr, err := w.signJSON(resp)
 	if err != nil {
 		w.log.Errorf("JSON marshal error: %v", err)
 		w.sendError(types.ErrInternalError, c)
 		return "", ""
 	}
 
-	sig := ed25519.Sign(w.signPrivKey, dec)
-	sigStr := base64.StdEncoding.EncodeToString(sig)
 	c.Writer.Header().Set("VSP-Server-Signature", sigStr)
 
 	c.AbortWithStatusJSON(http.StatusOK, resp)
 
-	return string(dec), sigStr
+	return dec, sigStr
+}
+
+// signJSON serializes the provided response and signs it. Returns the
+// serialized response and the base64 encoded signature.
+func (w *WebAPI) signJSON(resp any) (string, string, error) {
+	dec, err := json.Marshal(resp)
+	if err != nil {
+		return "", "", err
+	}
+
+	sig := ed25519.Sign(w.signPrivKey, dec)
+	return string(dec), base64.StdEncoding.EncodeToString(sig), nil
 }
 
 // sendError sends an error response with the provided error code and the
@@ -334,13 +521,28 @@ func (w *WebAPI) sendError(e types.ErrorCode, c *gin.Context) {
 // sendErrorWithMsg sends an error response with the provided error code and
 // message.
 func (w *WebAPI) sendErrorWithMsg(msg string, e types.ErrorCode, c *gin.Context) {
+	w.sendErrorWithDetails(msg, e, nil, c)
+}
+
+// sendErrorWithDetails sends an error response with the provided error code,
+// message and details. Details are only sent in response to /api/v4 requests,
+// where the error is wrapped in a response envelope.
+func (w *WebAPI) sendErrorWithDetails(msg string, e types.ErrorCode, details *types.ErrorDetails, c *gin.Context) {
 	status := e.HTTPStatus()
 
-	resp := types.ErrorResponse{
+	w.metrics.APIErrors.Inc(strconv.FormatInt(int64(e), 10))
+
+	errResp := types.ErrorResponse{
 		Code:    e,
 		Message: msg,
 	}
 
+	var resp any = errResp
+	if req, ok := apiV4(c); ok {
+		errResp.Details = details
+		resp = req.response(nil, &errResp)
+	}
+
 	// Try to sign the error response. If it fails, send it without a signature.
 	dec, err := json.Marshal(resp)
 	if err != nil {
@@ -352,3 +554,10 @@ func (w *WebAPI) sendErrorWithMsg(msg string, e types.ErrorCode, c *gin.Context)
 
 	c.AbortWithStatusJSON(status, resp)
 }
+
+// notify sends event to the configured webhooks, and to the API clients which
+// are streaming the status of its ticket.
+func (w *WebAPI) notify(event webhook.Event) {
+	w.webhook.Notify(event)
+	w.feed.Publish(event.TicketHash)
+}
@@ -0,0 +1,272 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package webhook delivers signed notifications of ticket lifecycle events to
+// external HTTP endpoints.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+)
+
+const (
+	// retryInterval is the time period between attempts to deliver any
+	// pending webhooks.
+	retryInterval = 30 * time.Second
+
+	// requestTimeout is the maximum time to wait for a webhook endpoint to
+	// respond.
+	requestTimeout = 10 * time.Second
+
+	// maxAttempts is the number of times delivery of a webhook will be
+	// attempted before it is discarded.
+	maxAttempts = 12
+
+	// maxBackoff is the maximum delay between two delivery attempts.
+	maxBackoff = time.Hour
+)
+
+// EventType identifies the change of ticket state which triggered an event.
+type EventType string
+
+const (
+	TicketConfirmed EventType = "ticketconfirmed"
+	FeeBroadcast    EventType = "feebroadcast"
+	FeeConfirmed    EventType = "feeconfirmed"
+	FeeError        EventType = "feeerror"
+	TicketVoted     EventType = "ticketvoted"
+	TicketRevoked   EventType = "ticketrevoked"
+)
+
+// Event is the JSON payload sent to webhook endpoints.
+type Event struct {
+	Type       EventType `json:"type"`
+	Timestamp  int64     `json:"timestamp"`
+	TicketHash string    `json:"tickethash"`
+	FeeTxHash  string    `json:"feetxhash,omitempty"`
+	Height     int64     `json:"height,omitempty"`
+	Outcome    string    `json:"outcome,omitempty"`
+}
+
+// NewEvent creates an event of the provided type describing the current state
+// of ticket.
+func NewEvent(eventType EventType, ticket database.Ticket) Event {
+	return Event{
+		Type:       eventType,
+		Timestamp:  time.Now().Unix(),
+		TicketHash: ticket.Hash,
+		FeeTxHash:  ticket.FeeTxHash,
+		Outcome:    string(ticket.Outcome),
+	}
+}
+
+// Notifier queues events in the database and delivers them to every
+// configured webhook URL. Deliveries which fail are retried with an
+// exponential backoff, and because the queue is persisted they will also be
+// retried after vspd is restarted.
+type Notifier struct {
+	db      database.Database
+	log     slog.Logger
+	signKey ed25519.PrivateKey
+	urls    []string
+	client  *http.Client
+
+	// wake is used to notify the delivery loop that new events have been
+	// queued.
+	wake chan struct{}
+}
+
+func New(db database.Database, log slog.Logger, urls []string) (*Notifier, error) {
+	// Events are signed with the same key used to sign API responses.
+	signKey, _, err := db.KeyPair()
+	if err != nil {
+		return nil, fmt.Errorf("db.KeyPair error: %w", err)
+	}
+
+	return &Notifier{
+		db:      db,
+		log:     log,
+		signKey: signKey,
+		urls:    urls,
+		client:  &http.Client{Timeout: requestTimeout},
+		wake:    make(chan struct{}, 1),
+	}, nil
+}
+
+// Notify adds one delivery of event to the queue for each configured webhook
+// URL. Errors are logged rather than returned because a failure to send a
+// notification should never interrupt the caller.
+func (n *Notifier) Notify(event Event) {
+	if len(n.urls) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		n.log.Errorf("Failed to marshal webhook event (type=%s, ticketHash=%s): %v",
+			event.Type, event.TicketHash, err)
+		return
+	}
+
+	sig := base64.StdEncoding.EncodeToString(ed25519.Sign(n.signKey, payload))
+
+	for _, url := range n.urls {
+		_, err := n.db.QueueWebhook(database.WebhookDelivery{
+			URL:         url,
+			Payload:     string(payload),
+			Signature:   sig,
+			NextAttempt: time.Now().Unix(),
+		})
+		if err != nil {
+			n.log.Errorf("Failed to queue webhook (type=%s, ticketHash=%s, url=%s): %v",
+				event.Type, event.TicketHash, url, err)
+		}
+	}
+
+	// Wake the delivery loop without blocking if it is already awake.
+	select {
+	case n.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers queued webhooks until the provided context is canceled.
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		n.deliverPending(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-n.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverPending attempts to deliver every queued webhook which is due for
+// delivery, in the order they were queued.
+func (n *Notifier) deliverPending(ctx context.Context) {
+	pending, err := n.db.PendingWebhooks()
+	if err != nil {
+		n.log.Errorf("db.PendingWebhooks error: %v", err)
+		return
+	}
+
+	ids := make([]uint64, 0, len(pending))
+	for id := range pending {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	now := time.Now()
+
+	for _, id := range ids {
+		// Exit early if context has been canceled.
+		if ctx.Err() != nil {
+			return
+		}
+
+		delivery := pending[id]
+
+		// Discard deliveries for URLs which have been removed from config.
+		if !slices.Contains(n.urls, delivery.URL) {
+			n.log.Warnf("Discarding webhook for unconfigured URL (url=%s)", delivery.URL)
+			err = n.db.DeleteWebhook(id)
+			if err != nil {
+				n.log.Errorf("db.DeleteWebhook error: %v", err)
+			}
+			continue
+		}
+
+		if delivery.NextAttempt > now.Unix() {
+			continue
+		}
+
+		err = n.send(ctx, delivery)
+		if err == nil {
+			err = n.db.DeleteWebhook(id)
+			if err != nil {
+				n.log.Errorf("db.DeleteWebhook error: %v", err)
+			}
+			continue
+		}
+
+		// Don't log error or count an attempt if shutdown was requested.
+		if ctx.Err() != nil {
+			return
+		}
+
+		delivery.Attempts++
+		if delivery.Attempts >= maxAttempts {
+			n.log.Errorf("Discarding webhook after %d failed attempts (url=%s): %v",
+				delivery.Attempts, delivery.URL, err)
+			err = n.db.DeleteWebhook(id)
+			if err != nil {
+				n.log.Errorf("db.DeleteWebhook error: %v", err)
+			}
+			continue
+		}
+
+		n.log.Warnf("Webhook delivery failed, will retry (url=%s, attempts=%d): %v",
+			delivery.URL, delivery.Attempts, err)
+
+		delivery.NextAttempt = now.Add(backoff(delivery.Attempts)).Unix()
+		err = n.db.UpdateWebhook(id, delivery)
+		if err != nil {
+			n.log.Errorf("db.UpdateWebhook error: %v", err)
+		}
+	}
+}
+
+// send POSTs a single webhook delivery. An error is returned if the request
+// fails or if the endpoint does not respond with a 2xx status.
+func (n *Notifier) send(ctx context.Context, delivery database.WebhookDelivery) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.URL,
+		bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("VSP-Server-Signature", delivery.Signature)
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// backoff returns the delay before the next delivery attempt after the
+// provided number of failed attempts.
+func backoff(attempts uint32) time.Duration {
+	delay := retryInterval
+	for i := uint32(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package webhook
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/slog"
+	"github.com/decred/vspd/database"
+)
+
+func testDatabase(t *testing.T) *database.VspDatabase {
+	t.Helper()
+
+	dbFile := filepath.Join(t.TempDir(), "test.db")
+	err := database.CreateNew(dbFile, "feexpub")
+	if err != nil {
+		t.Fatalf("error creating test database: %v", err)
+	}
+
+	db, err := database.Open(dbFile, slog.Disabled, 3)
+	if err != nil {
+		t.Fatalf("error opening test database: %v", err)
+	}
+	t.Cleanup(func() { db.Close(false) })
+
+	return db
+}
+
+func TestDelivery(t *testing.T) {
+	db := testDatabase(t)
+
+	_, pubKey, err := db.KeyPair()
+	if err != nil {
+		t.Fatalf("db.KeyPair error: %v", err)
+	}
+
+	received := make(chan Event, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("error reading request body: %v", err)
+		}
+
+		sig, err := base64.StdEncoding.DecodeString(r.Header.Get("VSP-Server-Signature"))
+		if err != nil {
+			t.Errorf("error decoding signature: %v", err)
+		}
+		if !ed25519.Verify(pubKey, body, sig) {
+			t.Error("invalid webhook signature")
+		}
+
+		var event Event
+		err = json.Unmarshal(body, &event)
+		if err != nil {
+			t.Errorf("error unmarshaling event: %v", err)
+		}
+		received <- event
+	}))
+	defer server.Close()
+
+	n, err := New(db, slog.Disabled, []string{server.URL})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	ticket := database.Ticket{Hash: "tickethash", FeeTxHash: "feetxhash"}
+	sent := NewEvent(FeeBroadcast, ticket)
+	n.Notify(sent)
+
+	n.deliverPending(context.Background())
+
+	select {
+	case event := <-received:
+		if event != sent {
+			t.Fatalf("expected event %+v, got %+v", sent, event)
+		}
+	default:
+		t.Fatal("webhook was not delivered")
+	}
+
+	// Successful deliveries should be removed from the queue.
+	pending, err := db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("db.PendingWebhooks error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending webhooks, got %d", len(pending))
+	}
+}
+
+func TestRetry(t *testing.T) {
+	db := testDatabase(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	n, err := New(db, slog.Disabled, []string{server.URL})
+	if err != nil {
+		t.Fatalf("New error: %v", err)
+	}
+
+	n.Notify(NewEvent(TicketConfirmed, database.Ticket{Hash: "tickethash"}))
+
+	n.deliverPending(context.Background())
+
+	// Failed deliveries should remain in the queue with a delayed retry.
+	pending, err := db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("db.PendingWebhooks error: %v", err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("expected 1 pending webhook, got %d", len(pending))
+	}
+	for _, delivery := range pending {
+		if delivery.Attempts != 1 {
+			t.Fatalf("expected 1 attempt, got %d", delivery.Attempts)
+		}
+		if delivery.NextAttempt <= time.Now().Unix() {
+			t.Fatal("expected next attempt to be in the future")
+		}
+	}
+
+	// Deliveries for URLs which are no longer configured should be discarded.
+	n.urls = []string{"http://127.0.0.1:1"}
+	n.deliverPending(context.Background())
+
+	pending, err = db.PendingWebhooks()
+	if err != nil {
+		t.Fatalf("db.PendingWebhooks error: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending webhooks, got %d", len(pending))
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+
+	tests := map[uint32]time.Duration{
+		1:  30 * time.Second,
+		2:  time.Minute,
+		3:  2 * time.Minute,
+		8:  time.Hour,
+		11: time.Hour,
+	}
+
+	for attempts, expected := range tests {
+		if actual := backoff(attempts); actual != expected {
+			t.Fatalf("backoff(%d): expected %v, got %v", attempts, expected, actual)
+		}
+	}
+}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2022 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,6 +8,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"sync"
 
 	"github.com/decred/slog"
@@ -37,6 +38,10 @@ type client struct {
 	authOpt  wsrpc.Option
 	notifier wsrpc.Notifier
 	log      slog.Logger
+
+	// retired is set once the client has been replaced and must not connect
+	// again.
+	retired bool
 }
 
 func setup(user, pass, addr string, cert []byte, log slog.Logger) *client {
@@ -65,7 +70,7 @@ func setup(user, pass, addr string, cert []byte, log slog.Logger) *client {
 	var mu sync.Mutex
 	var c *wsrpc.Client
 	fullAddr := "wss://" + addr + "/ws"
-	return &client{&mu, c, fullAddr, tlsOpt, authOpt, nil, log}
+	return &client{&mu, c, fullAddr, tlsOpt, authOpt, nil, log, false}
 }
 
 func (c *client) Close() {
@@ -84,6 +89,15 @@ func (c *client) Close() {
 	}
 }
 
+// retire closes the client and prevents it from connecting again.
+func (c *client) retire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.retired = true
+	c.Close()
+}
+
 // dial will return a connect rpc client if one exists, or attempt to create a
 // new one if not. A boolean indicates whether this connection is new (true), or
 // if it is an existing connection which is being reused (false).
@@ -91,6 +105,10 @@ func (c *client) dial(ctx context.Context) (Caller, bool, error) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if c.retired {
+		return nil, false, fmt.Errorf("RPC client %s has been retired", c.addr)
+	}
+
 	if c.client != nil {
 		select {
 		case <-c.client.Done():
@@ -27,8 +27,9 @@ import (
 const (
 	// These numerical error codes are defined in dcrd/dcrjson. Copied here so
 	// we dont need to import the whole package.
-	ErrRPCDuplicateTx = -40
-	ErrNoTxInfo       = -5
+	ErrRPCDuplicateTx     = -40
+	ErrNoTxInfo           = -5
+	ErrRPCDeserialization = -22
 )
 
 // ErrOrphan error string is defined in dcrd/internal/mempool. Copied here
@@ -51,9 +52,13 @@ func SetupDcrd(user, pass, addr string, cert []byte, params *chaincfg.Params, lo
 	blockConnectedChan chan *wire.BlockHeader) DcrdConnect {
 	client := setup(user, pass, addr, cert, log)
 
-	client.notifier = &blockConnectedHandler{
-		blockConnected: blockConnectedChan,
-		log:            log,
+	// Block notifications are only requested if there is a channel to receive
+	// them.
+	if blockConnectedChan != nil {
+		client.notifier = &blockConnectedHandler{
+			blockConnected: blockConnectedChan,
+			log:            log,
+		}
 	}
 
 	return DcrdConnect{
@@ -223,6 +228,19 @@ func (c *DcrdRPC) SendRawTransaction(txHex string) error {
 	return nil
 }
 
+// GetTxOut uses gettxout RPC to retrieve details about an unspent transaction
+// output. Outputs spent by transactions in the mempool are considered spent.
+// A nil result is returned if the output is spent or does not exist.
+func (c *DcrdRPC) GetTxOut(txHash string, index uint32, tree int8) (*dcrdtypes.GetTxOutResult, error) {
+	const includeMempool = true
+	var resp *dcrdtypes.GetTxOutResult
+	err := c.Call(context.TODO(), "gettxout", &resp, txHash, index, tree, includeMempool)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
 // NotifyBlocks uses notifyblocks RPC to request new block notifications from dcrd.
 func (c *DcrdRPC) NotifyBlocks() error {
 	return c.Call(context.TODO(), "notifyblocks", nil)
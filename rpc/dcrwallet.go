@@ -1,4 +1,4 @@
-// Copyright (c) 2021-2025 The Decred developers
+// Copyright (c) 2021-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -8,6 +8,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	wallettypes "decred.org/dcrwallet/v5/rpc/jsonrpc/types"
 	"github.com/decred/dcrd/chaincfg/v3"
@@ -23,27 +24,57 @@ type WalletRPC struct {
 }
 
 type WalletConnect struct {
-	clients []*client
+	clients *walletClients
 	params  *chaincfg.Params
 	log     slog.Logger
 }
 
+// walletClients is shared by every copy of a WalletConnect so that the wallets
+// can be replaced by Update while vspd is running.
+type walletClients struct {
+	mu      sync.RWMutex
+	clients []*client
+}
+
 func SetupWallet(user, pass, addrs []string, cert [][]byte, params *chaincfg.Params, log slog.Logger) WalletConnect {
+	return WalletConnect{
+		clients: &walletClients{clients: setupWallets(user, pass, addrs, cert, log)},
+		params:  params,
+		log:     log,
+	}
+}
+
+func setupWallets(user, pass, addrs []string, cert [][]byte, log slog.Logger) []*client {
 	clients := make([]*client, len(addrs))
 
 	for i := range len(addrs) {
 		clients[i] = setup(user[i], pass[i], addrs[i], cert[i], log)
 	}
 
-	return WalletConnect{
-		clients: clients,
-		params:  params,
-		log:     log,
+	return clients
+}
+
+// Update replaces every wallet client with clients for the provided wallets.
+// The connections of the replaced clients are closed.
+func (w *WalletConnect) Update(user, pass, addrs []string, cert [][]byte) {
+	clients := setupWallets(user, pass, addrs, cert, w.log)
+
+	w.clients.mu.Lock()
+	old := w.clients.clients
+	w.clients.clients = clients
+	w.clients.mu.Unlock()
+
+	for _, client := range old {
+		client.retire()
 	}
+	w.log.Debugf("dcrwallet clients replaced (%d wallets)", len(clients))
 }
 
 func (w *WalletConnect) Close() {
-	for _, client := range w.clients {
+	w.clients.mu.RLock()
+	defer w.clients.mu.RUnlock()
+
+	for _, client := range w.clients.clients {
 		client.Close()
 	}
 	w.log.Debug("dcrwallet clients closed")
@@ -56,7 +87,11 @@ func (w *WalletConnect) Clients() ([]*WalletRPC, []string) {
 	walletClients := make([]*WalletRPC, 0)
 	failedConnections := make([]string, 0)
 
-	for _, connect := range w.clients {
+	w.clients.mu.RLock()
+	clients := w.clients.clients
+	w.clients.mu.RUnlock()
+
+	for _, connect := range clients {
 
 		c, newConnection, err := connect.dial(context.TODO())
 		if err != nil {
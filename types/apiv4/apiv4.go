@@ -0,0 +1,103 @@
+// Copyright (c) 2026 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package apiv4 contains the types of the vspd /api/v4 routes. Every request
+// and response is wrapped in an envelope carrying the fields which are common
+// to all routes, so the types of individual routes only describe their body.
+package apiv4
+
+import (
+	"encoding/json"
+
+	"github.com/decred/vspd/types/v3"
+)
+
+// RequestEnvelope wraps the body of every signed request. The entire
+// serialized envelope is signed with the commitment address or alternate
+// signing address of the ticket, and the signature is sent in the
+// VSP-Client-Signature header.
+type RequestEnvelope struct {
+	// RequestID is chosen by the client and is echoed in the response.
+	RequestID string `json:"requestid" binding:"required,max=64"`
+	// Method and Path identify the route the request is sent to, eg. "POST"
+	// and "/api/v4/payfee", so a signed request cannot be replayed against a
+	// different route.
+	Method string `json:"method" binding:"required"`
+	Path   string `json:"path" binding:"required"`
+	// Timestamp is the unix time at which the request was created. Requests
+	// which are more than five minutes older or newer than the clock of the
+	// VSP, or which were created before the VSP was started, are rejected.
+	Timestamp int64 `json:"timestamp" binding:"required"`
+	// Nonce is a random string which must not be reused, so that a signed
+	// request is only processed once.
+	Nonce      string          `json:"nonce" binding:"required,min=16,max=64"`
+	TicketHash string          `json:"tickethash" binding:"required"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// ResponseEnvelope wraps the body of every response, or the error which
+// prevented the request from succeeding. The entire serialized envelope is
+// signed by the VSP, and the signature is sent in the VSP-Server-Signature
+// header.
+type ResponseEnvelope struct {
+	RequestID string `json:"requestid"`
+	Timestamp int64  `json:"timestamp"`
+	// Request is the serialized request envelope, so the client can prove
+	// what the VSP responded to.
+	Request []byte               `json:"request"`
+	Body    json.RawMessage      `json:"body,omitempty"`
+	Error   *types.ErrorResponse `json:"error,omitempty"`
+}
+
+type FeeAddressRequest struct {
+	TicketHex string `json:"tickethex" binding:"required"`
+	ParentHex string `json:"parenthex" binding:"required"`
+}
+
+type FeeAddressResponse struct {
+	FeeAddress string `json:"feeaddress"`
+	FeeAmount  int64  `json:"feeamount"`
+	Expiration int64  `json:"expiration"`
+}
+
+type PayFeeRequest struct {
+	FeeTx          string            `json:"feetx" binding:"required"`
+	VotingKey      string            `json:"votingkey" binding:"required"`
+	VoteChoices    map[string]string `json:"votechoices" binding:"required"`
+	TSpendPolicy   map[string]string `json:"tspendpolicy" binding:"max=3"`
+	TreasuryPolicy map[string]string `json:"treasurypolicy" binding:"max=3"`
+}
+
+type PayFeeResponse struct{}
+
+type SetVoteChoicesRequest struct {
+	VoteChoices    map[string]string `json:"votechoices" binding:"required"`
+	TSpendPolicy   map[string]string `json:"tspendpolicy" binding:"max=3"`
+	TreasuryPolicy map[string]string `json:"treasurypolicy" binding:"max=3"`
+}
+
+type SetVoteChoicesResponse struct{}
+
+// TicketStatusRequest has no fields because the ticket hash is included in
+// the envelope.
+type TicketStatusRequest struct{}
+
+type TicketStatusResponse struct {
+	TicketConfirmed bool              `json:"ticketconfirmed"`
+	FeeTxStatus     string            `json:"feetxstatus"`
+	FeeTxHash       string            `json:"feetxhash"`
+	FeeTxError      string            `json:"feetxerror,omitempty"`
+	AltSignAddress  string            `json:"altsignaddress"`
+	VoteChoices     map[string]string `json:"votechoices"`
+	TSpendPolicy    map[string]string `json:"tspendpolicy"`
+	TreasuryPolicy  map[string]string `json:"treasurypolicy"`
+}
+
+type SetAltSignAddrRequest struct {
+	TicketHex      string `json:"tickethex" binding:"required"`
+	ParentHex      string `json:"parenthex" binding:"required"`
+	AltSignAddress string `json:"altsignaddress" binding:"required"`
+}
+
+type SetAltSignAddrResponse struct{}
@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2022 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -29,6 +29,7 @@ const (
 	ErrCannotBroadcastFee
 	ErrCannotBroadcastFeeUnknownOutputs
 	ErrInvalidTimestamp
+	ErrVspMaintenance
 )
 
 // HTTPStatus returns a corresponding HTTP status code for a given error code.
@@ -70,6 +71,8 @@ func (e ErrorCode) HTTPStatus() int {
 		return http.StatusPreconditionRequired
 	case ErrInvalidTimestamp:
 		return http.StatusBadRequest
+	case ErrVspMaintenance:
+		return http.StatusServiceUnavailable
 	default:
 		return http.StatusInternalServerError
 	}
@@ -114,6 +117,8 @@ func (e ErrorCode) DefaultMessage() string {
 		return "fee transaction could not be broadcast due to unknown outputs"
 	case ErrInvalidTimestamp:
 		return "old or reused timestamp"
+	case ErrVspMaintenance:
+		return "vsp is down for scheduled maintenance"
 	default:
 		return "unknown error"
 	}
@@ -1,4 +1,4 @@
-// Copyright (c) 2022 The Decred developers
+// Copyright (c) 2022-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -34,6 +34,7 @@ func TestErrorDefaultMessages(t *testing.T) {
 		{ErrCannotBroadcastFee, "fee transaction could not be broadcast"},
 		{ErrCannotBroadcastFeeUnknownOutputs, "fee transaction could not be broadcast due to unknown outputs"},
 		{ErrInvalidTimestamp, "old or reused timestamp"},
+		{ErrVspMaintenance, "vsp is down for scheduled maintenance"},
 		{ErrorCode(9999), "unknown error"},
 	}
 
@@ -71,6 +72,7 @@ func TestErrorHTTPStatus(t *testing.T) {
 		{ErrCannotBroadcastFee, http.StatusInternalServerError},
 		{ErrCannotBroadcastFeeUnknownOutputs, http.StatusPreconditionRequired},
 		{ErrInvalidTimestamp, http.StatusBadRequest},
+		{ErrVspMaintenance, http.StatusServiceUnavailable},
 		{ErrorCode(9999), http.StatusInternalServerError},
 	}
 
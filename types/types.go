@@ -1,4 +1,4 @@
-// Copyright (c) 2020-2024 The Decred developers
+// Copyright (c) 2020-2026 The Decred developers
 // Use of this source code is governed by an ISC
 // license that can be found in the LICENSE file.
 
@@ -7,27 +7,93 @@ package types
 type ErrorResponse struct {
 	Code    ErrorCode `json:"code"`
 	Message string    `json:"message"`
+	// Details is only included in /api/v4 responses.
+	Details *ErrorDetails `json:"details,omitempty"`
+}
+
+// ErrorDetails contains machine-readable information about an error, so that
+// clients can react to it without parsing the message. Only the fields which
+// are relevant to the error are set.
+type ErrorDetails struct {
+	// Fields are the JSON names of the request fields which are missing or
+	// invalid.
+	Fields []string `json:"fields,omitempty"`
+	// MinTimestamp and MaxTimestamp are the range of request timestamps which
+	// the VSP would currently accept.
+	MinTimestamp int64 `json:"mintimestamp,omitempty"`
+	MaxTimestamp int64 `json:"maxtimestamp,omitempty"`
+	// RetryAfter is the unix timestamp after which the request may succeed if
+	// it is sent again, such as the end of a maintenance window.
+	RetryAfter int64 `json:"retryafter,omitempty"`
 }
 
 func (e ErrorResponse) Error() string { return e.Message }
 
 type VspInfoResponse struct {
-	APIVersions         []int64 `json:"apiversions"`
-	Timestamp           int64   `json:"timestamp"`
-	PubKey              []byte  `json:"pubkey"`
-	FeePercentage       float64 `json:"feepercentage"`
-	VspClosed           bool    `json:"vspclosed"`
-	VspClosedMsg        string  `json:"vspclosedmsg"`
-	Network             string  `json:"network"`
-	VspdVersion         string  `json:"vspdversion"`
-	Voting              int64   `json:"voting"`
-	Voted               int64   `json:"voted"`
-	TotalVotingWallets  int64   `json:"totalvotingwallets"`
-	VotingWalletsOnline int64   `json:"votingwalletsonline"`
-	Expired             int64   `json:"expired"`
-	Missed              int64   `json:"missed"`
-	BlockHeight         uint32  `json:"blockheight"`
-	NetworkProportion   float32 `json:"estimatednetworkproportion"`
+	APIVersions []int64 `json:"apiversions"`
+	Timestamp   int64   `json:"timestamp"`
+	PubKey      []byte  `json:"pubkey"`
+	// FeePercentage is the fee percentage currently charged for tickets which
+	// do not qualify for a discount. FeeSchedule describes how it is set.
+	FeePercentage       float64             `json:"feepercentage"`
+	VspClosed           bool                `json:"vspclosed"`
+	VspClosedMsg        string              `json:"vspclosedmsg"`
+	Network             string              `json:"network"`
+	VspdVersion         string              `json:"vspdversion"`
+	Voting              int64               `json:"voting"`
+	Voted               int64               `json:"voted"`
+	TotalVotingWallets  int64               `json:"totalvotingwallets"`
+	VotingWalletsOnline int64               `json:"votingwalletsonline"`
+	Expired             int64               `json:"expired"`
+	Missed              int64               `json:"missed"`
+	BlockHeight         uint32              `json:"blockheight"`
+	NetworkProportion   float32             `json:"estimatednetworkproportion"`
+	FeeSchedule         FeeSchedule         `json:"feeschedule"`
+	Maintenance         []MaintenanceWindow `json:"maintenance"`
+}
+
+// MaintenanceWindow is a period from the Start until the End unix timestamp
+// during which the VSP will not accept new tickets. Windows are listed earliest
+// first, and windows which have already ended are not included.
+type MaintenanceWindow struct {
+	Start   int64  `json:"start"`
+	End     int64  `json:"end"`
+	Message string `json:"message"`
+}
+
+// FeeSchedule describes how the fee percentage charged for a ticket is
+// determined. The fee percentage of the tier for the current ticket price, or
+// DefaultFeePercentage if no tier applies, is reduced to the lowest fee
+// percentage of any active promotion or discount which applies to the ticket.
+type FeeSchedule struct {
+	DefaultFeePercentage float64        `json:"defaultfeepercentage"`
+	Tiers                []FeeTier      `json:"tiers"`
+	Promotions           []FeePromotion `json:"promotions"`
+	Discounts            []FeeDiscount  `json:"discounts"`
+}
+
+// FeeTier applies when the current ticket price is at least MinTicketPrice
+// atoms. Only the tier with the highest MinTicketPrice applies.
+type FeeTier struct {
+	Name           string  `json:"name"`
+	MinTicketPrice int64   `json:"minticketprice"`
+	FeePercentage  float64 `json:"feepercentage"`
+}
+
+// FeePromotion applies from the Start until the End unix timestamp. Promotions
+// which have already ended are not included.
+type FeePromotion struct {
+	Name          string  `json:"name"`
+	Start         int64   `json:"start"`
+	End           int64   `json:"end"`
+	FeePercentage float64 `json:"feepercentage"`
+}
+
+// FeeDiscount applies to tickets with particular commitment addresses, which
+// are not published.
+type FeeDiscount struct {
+	Name          string  `json:"name"`
+	FeePercentage float64 `json:"feepercentage"`
 }
 
 type FeeAddressRequest struct {
@@ -73,6 +139,43 @@ type SetVoteChoicesResponse struct {
 	Request   []byte `json:"request"`
 }
 
+// SetVoteChoicesBatchRequest updates the vote choices of several tickets with a
+// single request. Each ticket has its own SetVoteChoicesRequest, signed by the
+// commitment address or alternate signing address of the ticket, so the batch
+// request itself is not signed.
+type SetVoteChoicesBatchRequest struct {
+	Tickets []SignedRequest `json:"tickets" binding:"required,dive"`
+}
+
+// SignedRequest is a request for a single ticket encoded as JSON, along with
+// the base64 encoded signature which would otherwise be sent in the
+// VSP-Client-Signature header.
+type SignedRequest struct {
+	Request   string `json:"request" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// SetVoteChoicesBatchResponse contains a result for each ticket of the request,
+// in the same order.
+type SetVoteChoicesBatchResponse struct {
+	Timestamp int64                  `json:"timestamp"`
+	Tickets   []SetVoteChoicesResult `json:"tickets"`
+	Request   []byte                 `json:"request"`
+}
+
+// SetVoteChoicesResult is the result for a single ticket of a batch request. If
+// the vote choices of the ticket were updated, Response is a
+// SetVoteChoicesResponse for the ticket encoded as JSON, and ResponseSignature
+// is its base64 encoded signature by the VSP. Otherwise Error describes why
+// they were not updated. TicketHash is empty if the request for the ticket
+// could not be parsed.
+type SetVoteChoicesResult struct {
+	TicketHash        string         `json:"tickethash"`
+	Response          string         `json:"response,omitempty"`
+	ResponseSignature string         `json:"responsesignature,omitempty"`
+	Error             *ErrorResponse `json:"error,omitempty"`
+}
+
 type TicketStatusRequest struct {
 	TicketHash string `json:"tickethash" binding:"required"`
 }
@@ -82,6 +185,7 @@ type TicketStatusResponse struct {
 	TicketConfirmed bool              `json:"ticketconfirmed"`
 	FeeTxStatus     string            `json:"feetxstatus"`
 	FeeTxHash       string            `json:"feetxhash"`
+	FeeTxError      string            `json:"feetxerror,omitempty"`
 	AltSignAddress  string            `json:"altsignaddress"`
 	VoteChoices     map[string]string `json:"votechoices"`
 	TSpendPolicy    map[string]string `json:"tspendpolicy"`
@@ -89,6 +193,44 @@ type TicketStatusResponse struct {
 	Request         []byte            `json:"request"`
 }
 
+// TicketStatusBatchRequest requests the status of several tickets at once. The
+// request must be signed by Address, which should be the commitment address or
+// the alternate signing address of each ticket. Requests with a Timestamp more
+// than five minutes from the clock of the VSP are rejected, so a captured
+// request cannot be replayed later.
+type TicketStatusBatchRequest struct {
+	Timestamp    int64    `json:"timestamp" binding:"required"`
+	Address      string   `json:"address" binding:"required"`
+	TicketHashes []string `json:"tickethashes" binding:"required"`
+}
+
+// TicketStatusBatchResponse contains a result for each ticket hash of the
+// request, in the same order.
+type TicketStatusBatchResponse struct {
+	Timestamp int64                `json:"timestamp"`
+	Tickets   []TicketStatusResult `json:"tickets"`
+	Request   []byte               `json:"request"`
+}
+
+// TicketStatusStreamEvent is the data of each event sent by
+// /ticketstatusstream. Message is the JSON encoding of a
+// TicketStatusBatchResponse containing the tickets which have changed, and
+// Signature is the signature of Message by the VSP which other responses send
+// in the VSP-Server-Signature header.
+type TicketStatusStreamEvent struct {
+	Message   []byte `json:"message"`
+	Signature []byte `json:"signature"`
+}
+
+// TicketStatusResult is the result for a single ticket of a batch request.
+// Exactly one of Status and Error is set. The Request field of Status is not
+// set because it is included once in the batch response.
+type TicketStatusResult struct {
+	TicketHash string                `json:"tickethash"`
+	Status     *TicketStatusResponse `json:"status,omitempty"`
+	Error      *ErrorResponse        `json:"error,omitempty"`
+}
+
 type SetAltSignAddrRequest struct {
 	Timestamp      int64  `json:"timestamp" binding:"required"`
 	TicketHash     string `json:"tickethash" binding:"required"`